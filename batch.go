@@ -0,0 +1,72 @@
+package goseidon
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ValidateDeleteFiles check the batch itself, invalid ids are reported per item
+func ValidateDeleteFiles(p DeleteFilesParam) error {
+	if len(p.Ids) == 0 {
+		return NewError(ErrInvalidArgument, fmt.Errorf("ids are empty"))
+	}
+	return nil
+}
+
+// DeleteEach delete every id with its own DeleteFile call,
+// running at most concurrency calls at once (at least one).
+// ids not started before ctx is done are reported with the context error
+func DeleteEach(ctx context.Context, d Deleter, p DeleteFilesParam, concurrency int) (*DeleteFilesResult, error) {
+	if ctx == nil {
+		return nil, NewError(ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := ValidateDeleteFiles(p)
+	if err != nil {
+		return nil, err
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	items := make([]DeleteFileItem, len(p.Ids))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, id := range p.Ids {
+		items[i].Id = id
+
+		select {
+		case <-ctx.Done():
+			items[i].Error = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+		// a free slot may win the select over a done context
+		if ctx.Err() != nil {
+			<-sem
+			items[i].Error = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(item *DeleteFileItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			res, err := d.DeleteFile(ctx, DeleteFileParam{
+				Id: item.Id,
+			})
+			if err != nil {
+				item.Error = err
+				return
+			}
+			item.DeletedAt = res.DeletedAt
+		}(&items[i])
+	}
+	wg.Wait()
+
+	res := &DeleteFilesResult{
+		Items: items,
+	}
+	return res, nil
+}
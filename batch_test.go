@@ -0,0 +1,130 @@
+package goseidon_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type countingDeleter struct {
+	running int32
+	peak    int32
+	fail    map[string]error
+	now     time.Time
+}
+
+func (d *countingDeleter) DeleteFile(ctx context.Context, p goseidon.DeleteFileParam) (*goseidon.DeleteFileResult, error) {
+	n := atomic.AddInt32(&d.running, 1)
+	defer atomic.AddInt32(&d.running, -1)
+	for {
+		peak := atomic.LoadInt32(&d.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&d.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+
+	err, ok := d.fail[p.Id]
+	if ok {
+		return nil, err
+	}
+	return &goseidon.DeleteFileResult{Id: p.Id, DeletedAt: d.now}, nil
+}
+
+var _ = Describe("Batch", func() {
+	var (
+		ctx  context.Context
+		ctrl *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl = gomock.NewController(GinkgoT())
+	})
+
+	Context("DeleteEach function", func() {
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := goseidon.DeleteEach(nil, nil, goseidon.DeleteFilesParam{Ids: []string{"a"}}, 1)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("ids are empty", func() {
+			It("should return error", func() {
+				res, err := goseidon.DeleteEach(ctx, nil, goseidon.DeleteFilesParam{}, 1)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("ids are empty"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("some ids fail", func() {
+			It("should report every id in order", func() {
+				now := time.Now()
+				d := &countingDeleter{
+					fail: map[string]error{"b": goseidon.ErrNotFound},
+					now:  now,
+				}
+
+				res, err := goseidon.DeleteEach(ctx, d, goseidon.DeleteFilesParam{Ids: []string{"a", "b", "c"}}, 2)
+
+				eRes := &goseidon.DeleteFilesResult{
+					Items: []goseidon.DeleteFileItem{
+						{Id: "a", DeletedAt: now},
+						{Id: "b", Error: goseidon.ErrNotFound},
+						{Id: "c", DeletedAt: now},
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("concurrency is bounded", func() {
+			It("should never exceed the bound", func() {
+				d := &countingDeleter{}
+				ids := []string{}
+				for i := 0; i < 20; i++ {
+					ids = append(ids, string(rune('a'+i)))
+				}
+
+				res, err := goseidon.DeleteEach(ctx, d, goseidon.DeleteFilesParam{Ids: ids}, 3)
+
+				Expect(err).To(BeNil())
+				Expect(res.Items).To(HaveLen(20))
+				Expect(d.peak).To(BeNumerically("<=", 3))
+			})
+		})
+
+		When("context is cancelled midway", func() {
+			It("should report remaining ids with the context error", func() {
+				cctx, cancel := context.WithCancel(ctx)
+				d := goseidon.NewMockDeleter(ctrl)
+				d.EXPECT().
+					DeleteFile(gomock.Any(), gomock.Eq(goseidon.DeleteFileParam{Id: "a"})).
+					DoAndReturn(func(ctx context.Context, p goseidon.DeleteFileParam) (*goseidon.DeleteFileResult, error) {
+						cancel()
+						return &goseidon.DeleteFileResult{Id: p.Id}, nil
+					}).
+					Times(1)
+
+				res, err := goseidon.DeleteEach(cctx, d, goseidon.DeleteFilesParam{Ids: []string{"a", "b", "c"}}, 1)
+
+				Expect(err).To(BeNil())
+				Expect(res.Items[0].Error).To(BeNil())
+				Expect(res.Items[1].Error).To(Equal(context.Canceled))
+				Expect(res.Items[2].Error).To(Equal(context.Canceled))
+			})
+		})
+	})
+})
@@ -0,0 +1,156 @@
+package goseidon
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ChecksumMismatchError report content that doesn't match its expected digest,
+// errors.Is match it with ErrChecksumMismatch
+type ChecksumMismatchError struct {
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch: expected %s, got %s", e.Algorithm, e.Expected, e.Actual)
+}
+
+func (e *ChecksumMismatchError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// CompareChecksum compare every digest known by both expected and actual,
+// the first different one is returned as ChecksumMismatchError
+func CompareChecksum(expected, actual Checksum) error {
+	pairs := []struct {
+		algorithm string
+		expected  string
+		actual    string
+	}{
+		{"md5", expected.MD5, actual.MD5},
+		{"crc32c", expected.CRC32C, actual.CRC32C},
+		{"sha256", expected.SHA256, actual.SHA256},
+	}
+	for _, pair := range pairs {
+		if pair.expected == "" || pair.actual == "" || pair.expected == pair.actual {
+			continue
+		}
+		return &ChecksumMismatchError{
+			Algorithm: pair.algorithm,
+			Expected:  pair.expected,
+			Actual:    pair.actual,
+		}
+	}
+	return nil
+}
+
+// ValidateVerify reject verification of a partial read,
+// a digest only covers the whole content
+func ValidateVerify(p RetrieveFileParam) error {
+	if p.Verify && (p.Offset != 0 || p.Length != 0) {
+		return NewError(ErrInvalidArgument, fmt.Errorf("checksum can't be verified on a range"))
+	}
+	return nil
+}
+
+// ChecksumReader compute md5, crc32c and sha256 of everything read through it
+type ChecksumReader struct {
+	r      io.Reader
+	md5    hash.Hash
+	crc32c hash.Hash32
+	sha256 hash.Hash
+}
+
+func (r *ChecksumReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.md5.Write(p[:n])
+		r.crc32c.Write(p[:n])
+		r.sha256.Write(p[:n])
+	}
+	return n, err
+}
+
+// Checksum return the digests of the content read so far
+func (r *ChecksumReader) Checksum() Checksum {
+	return Checksum{
+		MD5:    hex.EncodeToString(r.md5.Sum(nil)),
+		CRC32C: fmt.Sprintf("%08x", r.crc32c.Sum32()),
+		SHA256: hex.EncodeToString(r.sha256.Sum(nil)),
+	}
+}
+
+func NewChecksumReader(r io.Reader) *ChecksumReader {
+	return &ChecksumReader{
+		r:      r,
+		md5:    md5.New(),
+		crc32c: crc32.New(crc32cTable),
+		sha256: sha256.New(),
+	}
+}
+
+// ComputeChecksum hash the rest of body then rewind it,
+// so the digests can be sent up front along with the content
+func ComputeChecksum(body io.ReadSeeker) (Checksum, error) {
+	start, err := body.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return Checksum{}, err
+	}
+
+	hashed := NewChecksumReader(body)
+	_, err = io.Copy(io.Discard, hashed)
+	if err != nil {
+		return Checksum{}, err
+	}
+
+	_, err = body.Seek(start, io.SeekStart)
+	if err != nil {
+		return Checksum{}, err
+	}
+	return hashed.Checksum(), nil
+}
+
+// verifyReader return ChecksumMismatchError instead of io.EOF
+// when the content doesn't match the expected checksum
+type verifyReader struct {
+	*ChecksumReader
+	closer   io.Closer
+	expected Checksum
+}
+
+func (r *verifyReader) Read(p []byte) (int, error) {
+	n, err := r.ChecksumReader.Read(p)
+	if err == io.EOF {
+		mismatch := CompareChecksum(r.expected, r.Checksum())
+		if mismatch != nil {
+			return n, mismatch
+		}
+	}
+	return n, err
+}
+
+func (r *verifyReader) Close() error {
+	return r.closer.Close()
+}
+
+// NewVerifyReader wrap rc so reading it until the end verify the content,
+// it's returned as is when expected has no digest to compare with
+func NewVerifyReader(rc io.ReadCloser, expected Checksum) io.ReadCloser {
+	if expected == (Checksum{}) {
+		return rc
+	}
+	return &verifyReader{
+		ChecksumReader: NewChecksumReader(rc),
+		closer:         rc,
+		expected:       expected,
+	}
+}
@@ -0,0 +1,113 @@
+package goseidon_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	goseidon "github.com/go-seidon/core"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checksum", func() {
+	var content goseidon.Checksum
+
+	BeforeEach(func() {
+		content = goseidon.Checksum{
+			MD5:    "9a0364b9e99bb480dd25e1f0284c8555",
+			CRC32C: "61af7533",
+			SHA256: "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73",
+		}
+	})
+
+	Context("CompareChecksum function", func() {
+		DescribeTable("checksum match",
+			func(expected, actual goseidon.Checksum) {
+				err := goseidon.CompareChecksum(expected, actual)
+
+				Expect(err).To(BeNil())
+			},
+			Entry("same digests", goseidon.Checksum{MD5: "a", CRC32C: "b", SHA256: "c"}, goseidon.Checksum{MD5: "a", CRC32C: "b", SHA256: "c"}),
+			Entry("nothing expected", goseidon.Checksum{}, goseidon.Checksum{MD5: "a"}),
+			Entry("digest not known", goseidon.Checksum{SHA256: "c"}, goseidon.Checksum{MD5: "a"}),
+		)
+
+		DescribeTable("checksum doesn't match",
+			func(expected, actual goseidon.Checksum, msg string) {
+				err := goseidon.CompareChecksum(expected, actual)
+
+				Expect(err.Error()).To(Equal(msg))
+				Expect(errors.Is(err, goseidon.ErrChecksumMismatch)).To(BeTrue())
+			},
+			Entry("md5", goseidon.Checksum{MD5: "a"}, goseidon.Checksum{MD5: "b"}, "md5 checksum mismatch: expected a, got b"),
+			Entry("crc32c", goseidon.Checksum{CRC32C: "a"}, goseidon.Checksum{CRC32C: "b"}, "crc32c checksum mismatch: expected a, got b"),
+			Entry("sha256", goseidon.Checksum{MD5: "a", SHA256: "a"}, goseidon.Checksum{MD5: "a", SHA256: "b"}, "sha256 checksum mismatch: expected a, got b"),
+		)
+	})
+
+	Context("ValidateVerify function", func() {
+		When("verify is requested on a range", func() {
+			It("should return error", func() {
+				err := goseidon.ValidateVerify(goseidon.RetrieveFileParam{Id: "id", Offset: 1, Verify: true})
+
+				Expect(err.Error()).To(Equal("checksum can't be verified on a range"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("verify is requested on the whole file", func() {
+			It("should return nil", func() {
+				err := goseidon.ValidateVerify(goseidon.RetrieveFileParam{Id: "id", Verify: true})
+
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ComputeChecksum function", func() {
+		It("should hash the content and rewind it", func() {
+			body := strings.NewReader("content")
+
+			res, err := goseidon.ComputeChecksum(body)
+
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(content))
+			data, _ := io.ReadAll(body)
+			Expect(string(data)).To(Equal("content"))
+		})
+	})
+
+	Context("NewVerifyReader function", func() {
+		When("content match the checksum", func() {
+			It("should read until the end", func() {
+				rc := goseidon.NewVerifyReader(io.NopCloser(strings.NewReader("content")), content)
+
+				data, err := io.ReadAll(rc)
+
+				Expect(err).To(BeNil())
+				Expect(string(data)).To(Equal("content"))
+			})
+		})
+
+		When("content doesn't match the checksum", func() {
+			It("should return error at the end", func() {
+				rc := goseidon.NewVerifyReader(io.NopCloser(strings.NewReader("corrupt")), content)
+
+				_, err := io.ReadAll(rc)
+
+				Expect(errors.Is(err, goseidon.ErrChecksumMismatch)).To(BeTrue())
+			})
+		})
+
+		When("checksum is empty", func() {
+			It("should return the reader as is", func() {
+				rc := io.NopCloser(strings.NewReader("content"))
+
+				res := goseidon.NewVerifyReader(rc, goseidon.Checksum{})
+
+				Expect(res).To(Equal(rc))
+			})
+		})
+	})
+})
@@ -0,0 +1,195 @@
+package goseidon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// ValidateCopy check the source and destination of a copy or move
+func ValidateCopy(sourceId, destinationId string) error {
+	err := ValidateKey(sourceId)
+	if err != nil {
+		return err
+	}
+	err = ValidateKey(destinationId)
+	if err != nil {
+		return err
+	}
+	if sourceId == destinationId {
+		return NewError(ErrInvalidArgument, fmt.Errorf("source and destination are the same file"))
+	}
+	return nil
+}
+
+// CopyAcross copy a file between two storages by streaming it through the caller,
+// the server side copy is used instead when src and dst are the same Copier.
+// an optional interface answering ErrUnsupported, as a decorator does, is skipped
+func CopyAcross(ctx context.Context, src Retriever, dst Uploader, p CopyFileParam) (*CopyFileResult, error) {
+	if ctx == nil {
+		return nil, NewError(ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := ValidateCopy(p.SourceId, p.DestinationId)
+	if err != nil {
+		return nil, err
+	}
+
+	copier, ok := dst.(Copier)
+	if ok && isSame(src, dst) {
+		res, err := copier.CopyFile(ctx, p)
+		if !errors.Is(err, ErrUnsupported) {
+			return res, err
+		}
+	}
+
+	file, err := retrieveStream(ctx, src, p.SourceId)
+	if err != nil {
+		return nil, err
+	}
+	defer file.File.Close()
+
+	upload := UploadStreamParam{
+		FileData:  file.File,
+		FileId:    p.DestinationId,
+		FileName:  file.FileName,
+		FileSize:  file.Length,
+		Overwrite: p.Overwrite,
+
+		ContentType:        file.ContentType,
+		ContentDisposition: file.ContentDisposition,
+		CacheControl:       file.CacheControl,
+		Metadata:           file.Metadata,
+	}
+
+	var res *UploadFileResult
+	uploader, ok := dst.(StreamUploader)
+	if ok {
+		res, err = uploader.UploadStream(ctx, upload)
+	}
+	if !ok || errors.Is(err, ErrUnsupported) {
+		res, err = uploadAll(ctx, dst, upload)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	copyRes := &CopyFileResult{
+		SourceId:      p.SourceId,
+		DestinationId: p.DestinationId,
+		CopiedAt:      res.UploadedAt,
+		Skipped:       res.Skipped,
+	}
+	return copyRes, nil
+}
+
+// MoveAcross copy a file between two storages then delete the source,
+// it isn't atomic: a failed delete leaves the file in both storages
+func MoveAcross(ctx context.Context, src Storage, dst Uploader, p MoveFileParam) (*MoveFileResult, error) {
+	if ctx == nil {
+		return nil, NewError(ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := ValidateCopy(p.SourceId, p.DestinationId)
+	if err != nil {
+		return nil, err
+	}
+
+	mover, ok := dst.(Mover)
+	if ok && isSame(src, dst) {
+		res, err := mover.MoveFile(ctx, p)
+		if !errors.Is(err, ErrUnsupported) {
+			return res, err
+		}
+	}
+
+	copyRes, err := CopyAcross(ctx, src, dst, CopyFileParam{
+		SourceId:      p.SourceId,
+		DestinationId: p.DestinationId,
+		Overwrite:     p.Overwrite,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &MoveFileResult{
+		SourceId:      p.SourceId,
+		DestinationId: p.DestinationId,
+		MovedAt:       copyRes.CopiedAt,
+		Skipped:       copyRes.Skipped,
+	}
+	if copyRes.Skipped {
+		return res, nil
+	}
+
+	_, err = src.DeleteFile(ctx, DeleteFileParam{
+		Id: p.SourceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func retrieveStream(ctx context.Context, src Retriever, id string) (*RetrieveStreamResult, error) {
+	retriever, ok := src.(StreamRetriever)
+	if ok {
+		res, err := retriever.RetrieveStream(ctx, RetrieveFileParam{
+			Id: id,
+		})
+		if !errors.Is(err, ErrUnsupported) {
+			return res, err
+		}
+	}
+
+	file, err := src.RetrieveFile(ctx, RetrieveFileParam{
+		Id: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := &RetrieveStreamResult{
+		File:        io.NopCloser(bytes.NewReader(file.File)),
+		RetrievedAt: file.RetrievedAt,
+		Size:        file.Size,
+		Offset:      file.Offset,
+		Length:      int64(len(file.File)),
+
+		FileName:           file.FileName,
+		ContentType:        file.ContentType,
+		ContentDisposition: file.ContentDisposition,
+		CacheControl:       file.CacheControl,
+		Metadata:           file.Metadata,
+	}
+	return res, nil
+}
+
+func uploadAll(ctx context.Context, dst Uploader, p UploadStreamParam) (*UploadFileResult, error) {
+	data, err := io.ReadAll(p.FileData)
+	if err != nil {
+		return nil, err
+	}
+	return dst.UploadFile(ctx, UploadFileParam{
+		FileData:  data,
+		FileId:    p.FileId,
+		FileName:  p.FileName,
+		FileSize:  int64(len(data)),
+		Overwrite: p.Overwrite,
+
+		ContentType:        p.ContentType,
+		ContentDisposition: p.ContentDisposition,
+		CacheControl:       p.CacheControl,
+		Metadata:           p.Metadata,
+	})
+}
+
+// isSame report whether a and b are the same storage instance,
+// storage of an uncomparable type is never the same
+func isSame(a, b interface{}) bool {
+	t := reflect.TypeOf(a)
+	if t == nil || t != reflect.TypeOf(b) || !t.Comparable() {
+		return false
+	}
+	return a == b
+}
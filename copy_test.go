@@ -0,0 +1,321 @@
+package goseidon_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/pkg/breaker"
+	"github.com/go-seidon/core/pkg/limiter"
+	"github.com/go-seidon/core/pkg/logging"
+	"github.com/go-seidon/core/pkg/metrics"
+	"github.com/go-seidon/core/pkg/retry"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type streamStorage struct {
+	*goseidon.MockStorage
+	*goseidon.MockStreamRetriever
+	*goseidon.MockStreamUploader
+}
+
+type copierStorage struct {
+	*goseidon.MockStorage
+	*goseidon.MockCopier
+	*goseidon.MockMover
+}
+
+var _ = Describe("Copy", func() {
+	var (
+		ctx         context.Context
+		ctrl        *gomock.Controller
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl = gomock.NewController(GinkgoT())
+		currentTime = time.Now()
+	})
+
+	Context("CopyAcross function", func() {
+		var p goseidon.CopyFileParam
+
+		BeforeEach(func() {
+			p = goseidon.CopyFileParam{
+				SourceId:      "image.jpg",
+				DestinationId: "backup/image.jpg",
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := goseidon.CopyAcross(nil, nil, nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("source and destination are the same file", func() {
+			It("should return error", func() {
+				p.DestinationId = p.SourceId
+				res, err := goseidon.CopyAcross(ctx, nil, nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("source and destination are the same file"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("source and destination are the same copier", func() {
+			It("should copy server side", func() {
+				s := &copierStorage{
+					MockStorage: goseidon.NewMockStorage(ctrl),
+					MockCopier:  goseidon.NewMockCopier(ctrl),
+				}
+				eRes := &goseidon.CopyFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					CopiedAt:      currentTime,
+				}
+				s.MockCopier.EXPECT().
+					CopyFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(eRes, nil).
+					Times(1)
+
+				res, err := goseidon.CopyAcross(ctx, s, s, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("storages support streaming", func() {
+			It("should stream the file with its metadata", func() {
+				src := &streamStorage{MockStreamRetriever: goseidon.NewMockStreamRetriever(ctrl)}
+				dst := &streamStorage{MockStreamUploader: goseidon.NewMockStreamUploader(ctrl)}
+				file := io.NopCloser(strings.NewReader("image"))
+				src.MockStreamRetriever.EXPECT().
+					RetrieveStream(gomock.Eq(ctx), gomock.Eq(goseidon.RetrieveFileParam{Id: p.SourceId})).
+					Return(&goseidon.RetrieveStreamResult{
+						File:        file,
+						Size:        5,
+						Length:      5,
+						FileName:    "image.jpg",
+						ContentType: "image/jpeg",
+						Metadata:    map[string]string{"owner": "tenant-1"},
+					}, nil).
+					Times(1)
+				dst.MockStreamUploader.EXPECT().
+					UploadStream(gomock.Eq(ctx), gomock.Eq(goseidon.UploadStreamParam{
+						FileData:    file,
+						FileId:      p.DestinationId,
+						FileName:    "image.jpg",
+						FileSize:    5,
+						ContentType: "image/jpeg",
+						Metadata:    map[string]string{"owner": "tenant-1"},
+					})).
+					Return(&goseidon.UploadFileResult{FileId: p.DestinationId, UploadedAt: currentTime}, nil).
+					Times(1)
+
+				res, err := goseidon.CopyAcross(ctx, src, dst, p)
+
+				eRes := &goseidon.CopyFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					CopiedAt:      currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("storages don't support streaming", func() {
+			It("should copy the file in memory", func() {
+				src := goseidon.NewMockRetriever(ctrl)
+				dst := goseidon.NewMockUploader(ctrl)
+				src.EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(goseidon.RetrieveFileParam{Id: p.SourceId})).
+					Return(&goseidon.RetrieveFileResult{
+						File:     []byte("image"),
+						FileName: "image.jpg",
+					}, nil).
+					Times(1)
+				dst.EXPECT().
+					UploadFile(gomock.Eq(ctx), gomock.Eq(goseidon.UploadFileParam{
+						FileData: []byte("image"),
+						FileId:   p.DestinationId,
+						FileName: "image.jpg",
+						FileSize: 5,
+					})).
+					Return(&goseidon.UploadFileResult{FileId: p.DestinationId, UploadedAt: currentTime, Skipped: true}, nil).
+					Times(1)
+
+				res, err := goseidon.CopyAcross(ctx, src, dst, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Skipped).To(BeTrue())
+			})
+		})
+
+		DescribeTable("storage is wrapped in a decorator",
+			func(decorate func(goseidon.Storage) (goseidon.Storage, error)) {
+				st := goseidon.NewMockStorage(ctrl)
+				st.EXPECT().
+					RetrieveFile(gomock.Any(), gomock.Eq(goseidon.RetrieveFileParam{Id: p.SourceId})).
+					Return(&goseidon.RetrieveFileResult{File: []byte("image")}, nil).
+					Times(1)
+				st.EXPECT().
+					UploadFile(gomock.Any(), gomock.Eq(goseidon.UploadFileParam{
+						FileData: []byte("image"),
+						FileId:   p.DestinationId,
+						FileSize: 5,
+					})).
+					Return(&goseidon.UploadFileResult{FileId: p.DestinationId, UploadedAt: currentTime}, nil).
+					Times(1)
+				s, err := decorate(st)
+				Expect(err).To(BeNil())
+
+				res, err := goseidon.CopyAcross(ctx, s, s, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.CopiedAt).To(Equal(currentTime))
+			},
+			Entry("retry", func(st goseidon.Storage) (goseidon.Storage, error) {
+				return retry.NewRetryStorage(st)
+			}),
+			Entry("breaker", func(st goseidon.Storage) (goseidon.Storage, error) {
+				return breaker.NewBreakerStorage(st)
+			}),
+			Entry("limiter", func(st goseidon.Storage) (goseidon.Storage, error) {
+				return limiter.NewLimiterStorage(st)
+			}),
+			Entry("logging", func(st goseidon.Storage) (goseidon.Storage, error) {
+				logger := logging.NewStdLogger(log.New(io.Discard, "", 0))
+				return logging.NewLoggingStorage(st, logging.WithLogger(logger))
+			}),
+			Entry("metrics", func(st goseidon.Storage) (goseidon.Storage, error) {
+				registry, _ := metrics.NewRegistry()
+				return metrics.NewMetricsStorage(st, metrics.WithRecorder(registry))
+			}),
+		)
+
+		When("failed retrieve source", func() {
+			It("should return error", func() {
+				src := goseidon.NewMockRetriever(ctrl)
+				dst := goseidon.NewMockUploader(ctrl)
+				src.EXPECT().
+					RetrieveFile(gomock.Any(), gomock.Any()).
+					Return(nil, goseidon.ErrNotFound).
+					Times(1)
+
+				res, err := goseidon.CopyAcross(ctx, src, dst, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+	})
+
+	Context("MoveAcross function", func() {
+		var (
+			p   goseidon.MoveFileParam
+			src *goseidon.MockStorage
+			dst *goseidon.MockUploader
+		)
+
+		BeforeEach(func() {
+			p = goseidon.MoveFileParam{
+				SourceId:      "image.jpg",
+				DestinationId: "archive/image.jpg",
+			}
+			src = goseidon.NewMockStorage(ctrl)
+			dst = goseidon.NewMockUploader(ctrl)
+			src.EXPECT().
+				RetrieveFile(gomock.Any(), gomock.Any()).
+				Return(&goseidon.RetrieveFileResult{File: []byte("image")}, nil).
+				AnyTimes()
+		})
+
+		When("source and destination are the same mover", func() {
+			It("should move server side", func() {
+				s := &copierStorage{
+					MockStorage: goseidon.NewMockStorage(ctrl),
+					MockMover:   goseidon.NewMockMover(ctrl),
+				}
+				s.MockMover.EXPECT().
+					MoveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(&goseidon.MoveFileResult{}, nil).
+					Times(1)
+
+				res, err := goseidon.MoveAcross(ctx, s, s, p)
+
+				Expect(res).To(Equal(&goseidon.MoveFileResult{}))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("copy is skipped", func() {
+			It("should keep the source", func() {
+				dst.EXPECT().
+					UploadFile(gomock.Any(), gomock.Any()).
+					Return(&goseidon.UploadFileResult{Skipped: true}, nil).
+					Times(1)
+
+				res, err := goseidon.MoveAcross(ctx, src, dst, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Skipped).To(BeTrue())
+			})
+		})
+
+		When("failed delete source", func() {
+			It("should return error", func() {
+				dst.EXPECT().
+					UploadFile(gomock.Any(), gomock.Any()).
+					Return(&goseidon.UploadFileResult{}, nil).
+					Times(1)
+				src.EXPECT().
+					DeleteFile(gomock.Eq(ctx), gomock.Eq(goseidon.DeleteFileParam{Id: p.SourceId})).
+					Return(nil, fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := goseidon.MoveAcross(ctx, src, dst, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+
+		When("success move file", func() {
+			It("should delete the source", func() {
+				dst.EXPECT().
+					UploadFile(gomock.Any(), gomock.Any()).
+					Return(&goseidon.UploadFileResult{UploadedAt: currentTime}, nil).
+					Times(1)
+				src.EXPECT().
+					DeleteFile(gomock.Eq(ctx), gomock.Eq(goseidon.DeleteFileParam{Id: p.SourceId})).
+					Return(&goseidon.DeleteFileResult{}, nil).
+					Times(1)
+
+				res, err := goseidon.MoveAcross(ctx, src, dst, p)
+
+				eRes := &goseidon.MoveFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					MovedAt:       currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package goseidon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound        = errors.New("file is not found")
+	ErrAlreadyExists   = errors.New("file already exists")
+	ErrPermission      = errors.New("permission denied")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrTransient       = errors.New("transient failure")
+	// ErrChecksumMismatch is matched by every ChecksumMismatchError
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrUnsupported is matched by the error of Unsupported
+	ErrUnsupported = errors.New("not supported by the storage")
+)
+
+// Error classify a provider specific error into one of the sentinel error,
+// errors.Is match the Kind while errors.As and errors.Unwrap reach the Err
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Kind.Error()
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NewError(kind error, err error) error {
+	return &Error{
+		Kind: kind,
+		Err:  err,
+	}
+}
+
+// Unsupported report a call to an optional interface the storage doesn't implement,
+// e.g: a decorator forwarding UploadStream to a storage without streaming.
+// it's an ErrInvalidArgument that also match ErrUnsupported, so a caller can fall back
+func Unsupported(op string) error {
+	return NewError(ErrInvalidArgument, fmt.Errorf("%s is %w", op, ErrUnsupported))
+}
+
+// ErrorClass name the kind of err for logs and metrics, it's empty for nil error,
+// "canceled" or "deadline_exceeded" for a done context and "unknown" for an unclassified one
+func ErrorClass(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrAlreadyExists):
+		return "already_exists"
+	case errors.Is(err, ErrPermission):
+		return "permission"
+	case errors.Is(err, ErrInvalidArgument):
+		return "invalid_argument"
+	case errors.Is(err, ErrTransient):
+		return "transient"
+	case errors.Is(err, ErrChecksumMismatch):
+		return "checksum_mismatch"
+	}
+	return "unknown"
+}
@@ -0,0 +1,96 @@
+package goseidon_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	goseidon "github.com/go-seidon/core"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGoseidon(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Goseidon Package")
+}
+
+var _ = Describe("Error", func() {
+	Context("NewError function", func() {
+		When("cause is specified", func() {
+			It("should use cause message", func() {
+				cause := fmt.Errorf("NoSuchKey: the key does not exist")
+				err := goseidon.NewError(goseidon.ErrNotFound, cause)
+
+				Expect(err.Error()).To(Equal("NoSuchKey: the key does not exist"))
+			})
+		})
+
+		When("cause is not specified", func() {
+			It("should use kind message", func() {
+				err := goseidon.NewError(goseidon.ErrNotFound, nil)
+
+				Expect(err.Error()).To(Equal("file is not found"))
+			})
+		})
+
+		When("error is compared to its kind", func() {
+			It("should match", func() {
+				err := goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("slow down"))
+				wrapped := fmt.Errorf("upload failed: %w", err)
+
+				Expect(errors.Is(err, goseidon.ErrTransient)).To(BeTrue())
+				Expect(errors.Is(wrapped, goseidon.ErrTransient)).To(BeTrue())
+				Expect(errors.Is(err, goseidon.ErrNotFound)).To(BeFalse())
+			})
+		})
+
+		When("error is unwrapped", func() {
+			It("should return the cause", func() {
+				cause := &causeError{}
+				err := goseidon.NewError(goseidon.ErrPermission, cause)
+
+				var target *causeError
+				Expect(errors.As(err, &target)).To(BeTrue())
+				Expect(target).To(Equal(cause))
+				Expect(errors.Unwrap(err)).To(Equal(cause))
+			})
+		})
+	})
+
+	Context("Unsupported function", func() {
+		When("operation is given", func() {
+			It("should return invalid argument error", func() {
+				err := goseidon.Unsupported("stream upload")
+
+				Expect(err.Error()).To(Equal("stream upload is not supported by the storage"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+				Expect(errors.Is(err, goseidon.ErrUnsupported)).To(BeTrue())
+			})
+		})
+	})
+
+	DescribeTable("ErrorClass function",
+		func(err error, class string) {
+			Expect(goseidon.ErrorClass(err)).To(Equal(class))
+		},
+		Entry("no error", nil, ""),
+		Entry("cancelled", fmt.Errorf("failed storing file: %w", context.Canceled), "canceled"),
+		Entry("deadline exceeded", context.DeadlineExceeded, "deadline_exceeded"),
+		Entry("not found", goseidon.ErrNotFound, "not_found"),
+		Entry("already exists", goseidon.ErrAlreadyExists, "already_exists"),
+		Entry("permission", goseidon.NewError(goseidon.ErrPermission, fmt.Errorf("access denied")), "permission"),
+		Entry("invalid argument", goseidon.ErrInvalidArgument, "invalid_argument"),
+		Entry("transient", goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("slow down")), "transient"),
+		Entry("checksum mismatch", &goseidon.ChecksumMismatchError{}, "checksum_mismatch"),
+		Entry("unclassified", fmt.Errorf("unknown"), "unknown"),
+	)
+})
+
+type causeError struct {
+}
+
+func (e *causeError) Error() string {
+	return "cause"
+}
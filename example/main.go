@@ -61,6 +61,7 @@ func main() {
 
 	ctx := context.Background()
 	uploadRes, err := storage.UploadFile(ctx, goseidon.UploadFileParam{
+		FileId:   fileInfo.Name(),
 		FileName: fileInfo.Name(),
 		FileData: fileData,
 		FileSize: fileSize,
@@ -0,0 +1,40 @@
+package goseidon
+
+import (
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFullStorage is a mock of a Storage implementing every optional interface,
+// each interface is answered by its own generated mock e.g: MockFullStorage.MockStater
+type MockFullStorage struct {
+	*MockStorage
+	*MockStreamUploader
+	*MockStreamRetriever
+	*MockStater
+	*MockLister
+	*MockURLSigner
+	*MockMultipartUploader
+	*MockCopier
+	*MockMover
+	*MockBatchDeleter
+	*MockPrefixDeleter
+	*MockVersionLister
+}
+
+// NewMockFullStorage creates a new mock instance.
+func NewMockFullStorage(ctrl *gomock.Controller) *MockFullStorage {
+	return &MockFullStorage{
+		MockStorage:           NewMockStorage(ctrl),
+		MockStreamUploader:    NewMockStreamUploader(ctrl),
+		MockStreamRetriever:   NewMockStreamRetriever(ctrl),
+		MockStater:            NewMockStater(ctrl),
+		MockLister:            NewMockLister(ctrl),
+		MockURLSigner:         NewMockURLSigner(ctrl),
+		MockMultipartUploader: NewMockMultipartUploader(ctrl),
+		MockCopier:            NewMockCopier(ctrl),
+		MockMover:             NewMockMover(ctrl),
+		MockBatchDeleter:      NewMockBatchDeleter(ctrl),
+		MockPrefixDeleter:     NewMockPrefixDeleter(ctrl),
+		MockVersionLister:     NewMockVersionLister(ctrl),
+	}
+}
@@ -2,48 +2,109 @@ package goseidon
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
 type BinaryFile = []byte
 
+// FileNameMetadataKey is the user metadata key used by cloud provider
+// to persist the original file name next to the object
+const FileNameMetadataKey = "goseidon-file-name"
+
+// OverwriteMode decide what upload does when the file id is already taken,
+// the check is done atomically by the storage provider
+type OverwriteMode int
+
+const (
+	// OverwriteFail reject the upload with ErrAlreadyExists
+	OverwriteFail OverwriteMode = iota
+	// OverwriteReplace replace the existing file
+	OverwriteReplace
+	// OverwriteSkip keep the existing file and mark the result as skipped
+	OverwriteSkip
+)
+
+// UploadFileParam may carry the expected Checksum of FileData,
+// each given digest is verified against the uploaded content
 type UploadFileParam struct {
-	FileData BinaryFile
-	FileId   string
-	FileName string
-	FileSize int64
+	FileData  BinaryFile
+	FileId    string
+	FileName  string
+	FileSize  int64
+	Overwrite OverwriteMode
+	Checksum  Checksum
+
+	ContentType        string
+	ContentDisposition string
+	CacheControl       string
+	Metadata           map[string]string
 }
 
+// UploadFileResult hold the digests computed while uploading,
+// Checksum is empty when the upload is skipped.
+// VersionId identify the stored version when the storage support it
 type UploadFileResult struct {
 	FileId     string
 	FileName   string
 	UploadedAt time.Time
+	Skipped    bool
+	Checksum   Checksum
+	VersionId  string
 }
 
 type Uploader interface {
 	UploadFile(ctx context.Context, p UploadFileParam) (*UploadFileResult, error)
 }
 
+// RetrieveFileParam read the whole file unless a range is given,
+// zero Length read from Offset until the end of the file.
+// Verify compare the content with the stored checksum, it can't be used with a range.
+// empty VersionId read the latest version
 type RetrieveFileParam struct {
-	Id string
+	Id        string
+	Offset    int64
+	Length    int64
+	Verify    bool
+	VersionId string
 }
 
+// RetrieveFileResult hold the requested range of the file,
+// Size is the total size so a partial content response can be built
 type RetrieveFileResult struct {
 	File        BinaryFile
 	RetrievedAt time.Time
+	Size        int64
+	Offset      int64
+	Length      int64
+	Checksum    Checksum
+	VersionId   string
+
+	FileName           string
+	ContentType        string
+	ContentDisposition string
+	CacheControl       string
+	Metadata           map[string]string
 }
 
 type Retriever interface {
 	RetrieveFile(ctx context.Context, p RetrieveFileParam) (*RetrieveFileResult, error)
 }
 
+// DeleteFileParam delete the given version for good when VersionId is set,
+// otherwise the latest version is deleted and a versioned storage keep it
+// as a noncurrent version
 type DeleteFileParam struct {
-	Id string
+	Id        string
+	VersionId string
 }
 
+// DeleteFileResult hold the deleted version,
+// or the delete marker created by a provider using them
 type DeleteFileResult struct {
 	Id        string
 	DeletedAt time.Time
+	VersionId string
 }
 
 type Deleter interface {
@@ -55,3 +116,318 @@ type Storage interface {
 	Retriever
 	Deleter
 }
+
+// UploadStreamParam is the streaming counterpart of UploadFileParam,
+// a negative FileSize means the size is unknown, some provider require it when FileData is not seekable
+type UploadStreamParam struct {
+	FileData  io.Reader
+	FileId    string
+	FileName  string
+	FileSize  int64
+	Overwrite OverwriteMode
+	Checksum  Checksum
+
+	ContentType        string
+	ContentDisposition string
+	CacheControl       string
+	Metadata           map[string]string
+}
+
+type StreamUploader interface {
+	UploadStream(ctx context.Context, p UploadStreamParam) (*UploadFileResult, error)
+}
+
+// RetrieveStreamResult hold an open stream of the file content,
+// caller is responsible to close the File once it's done reading
+type RetrieveStreamResult struct {
+	File        io.ReadCloser
+	RetrievedAt time.Time
+	Size        int64
+	Offset      int64
+	Length      int64
+	Checksum    Checksum
+	VersionId   string
+
+	FileName           string
+	ContentType        string
+	ContentDisposition string
+	CacheControl       string
+	Metadata           map[string]string
+}
+
+type StreamRetriever interface {
+	RetrieveStream(ctx context.Context, p RetrieveFileParam) (*RetrieveStreamResult, error)
+}
+
+// Checksum hold hex encoded digest of the file content,
+// empty value means the digest is not known by the provider
+type Checksum struct {
+	MD5    string
+	CRC32C string
+	SHA256 string
+}
+
+type StatFileParam struct {
+	Id string
+}
+
+type StatFileResult struct {
+	Id           string
+	Size         int64
+	Checksum     Checksum
+	LastModified time.Time
+
+	FileName           string
+	ContentType        string
+	ContentDisposition string
+	CacheControl       string
+	Metadata           map[string]string
+}
+
+type Stater interface {
+	StatFile(ctx context.Context, p StatFileParam) (*StatFileResult, error)
+}
+
+// ListFileParam filter stored files by their id prefix,
+// when Delimiter is set ids sharing the same segment after the prefix
+// are grouped into a single entry of ListFileResult.Prefixes
+type ListFileParam struct {
+	Prefix            string
+	Delimiter         string
+	PageSize          int
+	ContinuationToken string
+}
+
+type ListFileItem struct {
+	Id           string
+	Size         int64
+	LastModified time.Time
+}
+
+// ListFileResult hold a single page of listing,
+// NextContinuationToken is empty when there is no more page
+type ListFileResult struct {
+	Files                 []ListFileItem
+	Prefixes              []string
+	NextContinuationToken string
+}
+
+type Lister interface {
+	ListFiles(ctx context.Context, p ListFileParam) (*ListFileResult, error)
+}
+
+// SignURLParam describe a pre-authorized request to a single file,
+// ContentType is only allowed for PUT and binds the url to that content type
+type SignURLParam struct {
+	Id          string
+	Method      string
+	Expiry      time.Duration
+	ContentType string
+}
+
+// SignURLResult hold the signed url, Headers must be sent as is
+// by whoever use the url otherwise the signature doesn't match
+type SignURLResult struct {
+	URL       string
+	Method    string
+	Headers   map[string]string
+	ExpiresAt time.Time
+}
+
+type URLSigner interface {
+	SignURL(ctx context.Context, p SignURLParam) (*SignURLResult, error)
+}
+
+// CreateMultipartParam start an upload sent as separate parts,
+// file attributes are given up front and applied once the upload is completed
+type CreateMultipartParam struct {
+	FileId   string
+	FileName string
+
+	ContentType        string
+	ContentDisposition string
+	CacheControl       string
+	Metadata           map[string]string
+}
+
+type CreateMultipartResult struct {
+	UploadId  string
+	FileId    string
+	CreatedAt time.Time
+}
+
+// UploadPartParam upload a single part, re-uploading the same PartNumber
+// replace the previous part so a failed part can be resumed on its own
+type UploadPartParam struct {
+	UploadId   string
+	FileId     string
+	PartNumber int
+	PartData   io.Reader
+	PartSize   int64
+}
+
+type UploadPartResult struct {
+	PartNumber int
+	ETag       string
+	Size       int64
+	UploadedAt time.Time
+}
+
+type CompletedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+// CompleteMultipartParam assemble the given parts in ascending PartNumber order,
+// Overwrite is enforced at this point since the file only appears on completion
+type CompleteMultipartParam struct {
+	UploadId  string
+	FileId    string
+	Parts     []CompletedPart
+	Overwrite OverwriteMode
+}
+
+type AbortMultipartParam struct {
+	UploadId string
+	FileId   string
+}
+
+type AbortMultipartResult struct {
+	UploadId  string
+	AbortedAt time.Time
+}
+
+type ListPartsParam struct {
+	UploadId string
+	FileId   string
+}
+
+type PartItem struct {
+	PartNumber   int
+	ETag         string
+	Size         int64
+	LastModified time.Time
+}
+
+type ListPartsResult struct {
+	Parts []PartItem
+}
+
+type MultipartUploader interface {
+	CreateMultipartUpload(ctx context.Context, p CreateMultipartParam) (*CreateMultipartResult, error)
+	UploadPart(ctx context.Context, p UploadPartParam) (*UploadPartResult, error)
+	CompleteMultipartUpload(ctx context.Context, p CompleteMultipartParam) (*UploadFileResult, error)
+	AbortMultipartUpload(ctx context.Context, p AbortMultipartParam) (*AbortMultipartResult, error)
+	ListParts(ctx context.Context, p ListPartsParam) (*ListPartsResult, error)
+}
+
+// CopyFileParam copy SourceId into DestinationId together with its metadata,
+// Overwrite decide what happens when DestinationId is already taken
+type CopyFileParam struct {
+	SourceId      string
+	DestinationId string
+	Overwrite     OverwriteMode
+}
+
+type CopyFileResult struct {
+	SourceId      string
+	DestinationId string
+	CopiedAt      time.Time
+	Skipped       bool
+}
+
+type Copier interface {
+	CopyFile(ctx context.Context, p CopyFileParam) (*CopyFileResult, error)
+}
+
+// MoveFileParam move SourceId into DestinationId together with its metadata,
+// the source is kept when the move is skipped
+type MoveFileParam struct {
+	SourceId      string
+	DestinationId string
+	Overwrite     OverwriteMode
+}
+
+type MoveFileResult struct {
+	SourceId      string
+	DestinationId string
+	MovedAt       time.Time
+	Skipped       bool
+}
+
+type Mover interface {
+	MoveFile(ctx context.Context, p MoveFileParam) (*MoveFileResult, error)
+}
+
+// DeleteFilesParam delete every id independently of each other,
+// a failed id doesn't stop the rest of the batch
+type DeleteFilesParam struct {
+	Ids []string
+}
+
+// DeleteFileItem is the outcome of a single id, nil Error means it's deleted.
+// an id left unprocessed by a cancelled context carry the context error
+type DeleteFileItem struct {
+	Id        string
+	DeletedAt time.Time
+	Error     error
+}
+
+// DeleteFilesResult hold one item per requested id, in the requested order
+type DeleteFilesResult struct {
+	Items []DeleteFileItem
+}
+
+type BatchDeleter interface {
+	DeleteFiles(ctx context.Context, p DeleteFilesParam) (*DeleteFilesResult, error)
+}
+
+// DeletePrefixParam delete every file whose id starts with Prefix,
+// DryRun only count the matching files without deleting them
+type DeletePrefixParam struct {
+	Prefix string
+	DryRun bool
+}
+
+// DeletePrefixResult count the files found under the prefix and the ones removed,
+// Failed hold the files that couldn't be deleted.
+// an interrupted delete return the result so far together with its error
+type DeletePrefixResult struct {
+	Prefix  string
+	Matched int
+	Deleted int
+	Failed  []DeleteFileItem
+	DryRun  bool
+}
+
+type PrefixDeleter interface {
+	DeletePrefix(ctx context.Context, p DeletePrefixParam) (*DeletePrefixResult, error)
+}
+
+// ListVersionsParam list the versions of a single file
+type ListVersionsParam struct {
+	Id                string
+	PageSize          int
+	ContinuationToken string
+}
+
+// FileVersion describe a single version, IsDeleteMarker is set on the
+// marker left by deleting the latest version of a provider using them
+type FileVersion struct {
+	VersionId      string
+	Size           int64
+	LastModified   time.Time
+	IsLatest       bool
+	IsDeleteMarker bool
+}
+
+// ListVersionsResult hold a single page of versions, newest first whatever the provider,
+// NextContinuationToken is empty when there is no more page
+type ListVersionsResult struct {
+	Versions              []FileVersion
+	NextContinuationToken string
+}
+
+type VersionLister interface {
+	ListVersions(ctx context.Context, p ListVersionsParam) (*ListVersionsResult, error)
+}
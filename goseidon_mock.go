@@ -5,6 +5,7 @@
 package goseidon
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -34,18 +35,18 @@ func (m *MockUploader) EXPECT() *MockUploaderMockRecorder {
 }
 
 // UploadFile mocks base method.
-func (m *MockUploader) UploadFile(arg0 UploadFileParam) (*UploadFileResult, error) {
+func (m *MockUploader) UploadFile(ctx context.Context, p UploadFileParam) (*UploadFileResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadFile", arg0)
+	ret := m.ctrl.Call(m, "UploadFile", ctx, p)
 	ret0, _ := ret[0].(*UploadFileResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UploadFile indicates an expected call of UploadFile.
-func (mr *MockUploaderMockRecorder) UploadFile(arg0 interface{}) *gomock.Call {
+func (mr *MockUploaderMockRecorder) UploadFile(ctx, p interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockUploader)(nil).UploadFile), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockUploader)(nil).UploadFile), ctx, p)
 }
 
 // MockRetriever is a mock of Retriever interface.
@@ -72,18 +73,18 @@ func (m *MockRetriever) EXPECT() *MockRetrieverMockRecorder {
 }
 
 // RetrieveFile mocks base method.
-func (m *MockRetriever) RetrieveFile(arg0 RetrieveFileParam) (*RetrieveFileResult, error) {
+func (m *MockRetriever) RetrieveFile(ctx context.Context, p RetrieveFileParam) (*RetrieveFileResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RetrieveFile", arg0)
+	ret := m.ctrl.Call(m, "RetrieveFile", ctx, p)
 	ret0, _ := ret[0].(*RetrieveFileResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RetrieveFile indicates an expected call of RetrieveFile.
-func (mr *MockRetrieverMockRecorder) RetrieveFile(arg0 interface{}) *gomock.Call {
+func (mr *MockRetrieverMockRecorder) RetrieveFile(ctx, p interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveFile", reflect.TypeOf((*MockRetriever)(nil).RetrieveFile), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveFile", reflect.TypeOf((*MockRetriever)(nil).RetrieveFile), ctx, p)
 }
 
 // MockDeleter is a mock of Deleter interface.
@@ -110,18 +111,18 @@ func (m *MockDeleter) EXPECT() *MockDeleterMockRecorder {
 }
 
 // DeleteFile mocks base method.
-func (m *MockDeleter) DeleteFile(arg0 DeleteFileParam) (*DeleteFileResult, error) {
+func (m *MockDeleter) DeleteFile(ctx context.Context, p DeleteFileParam) (*DeleteFileResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteFile", arg0)
+	ret := m.ctrl.Call(m, "DeleteFile", ctx, p)
 	ret0, _ := ret[0].(*DeleteFileResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteFile indicates an expected call of DeleteFile.
-func (mr *MockDeleterMockRecorder) DeleteFile(arg0 interface{}) *gomock.Call {
+func (mr *MockDeleterMockRecorder) DeleteFile(ctx, p interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockDeleter)(nil).DeleteFile), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockDeleter)(nil).DeleteFile), ctx, p)
 }
 
 // MockStorage is a mock of Storage interface.
@@ -148,46 +149,524 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 }
 
 // DeleteFile mocks base method.
-func (m *MockStorage) DeleteFile(arg0 DeleteFileParam) (*DeleteFileResult, error) {
+func (m *MockStorage) DeleteFile(ctx context.Context, p DeleteFileParam) (*DeleteFileResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteFile", arg0)
+	ret := m.ctrl.Call(m, "DeleteFile", ctx, p)
 	ret0, _ := ret[0].(*DeleteFileResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteFile indicates an expected call of DeleteFile.
-func (mr *MockStorageMockRecorder) DeleteFile(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) DeleteFile(ctx, p interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockStorage)(nil).DeleteFile), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockStorage)(nil).DeleteFile), ctx, p)
 }
 
 // RetrieveFile mocks base method.
-func (m *MockStorage) RetrieveFile(arg0 RetrieveFileParam) (*RetrieveFileResult, error) {
+func (m *MockStorage) RetrieveFile(ctx context.Context, p RetrieveFileParam) (*RetrieveFileResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RetrieveFile", arg0)
+	ret := m.ctrl.Call(m, "RetrieveFile", ctx, p)
 	ret0, _ := ret[0].(*RetrieveFileResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RetrieveFile indicates an expected call of RetrieveFile.
-func (mr *MockStorageMockRecorder) RetrieveFile(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) RetrieveFile(ctx, p interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveFile", reflect.TypeOf((*MockStorage)(nil).RetrieveFile), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveFile", reflect.TypeOf((*MockStorage)(nil).RetrieveFile), ctx, p)
 }
 
 // UploadFile mocks base method.
-func (m *MockStorage) UploadFile(arg0 UploadFileParam) (*UploadFileResult, error) {
+func (m *MockStorage) UploadFile(ctx context.Context, p UploadFileParam) (*UploadFileResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadFile", arg0)
+	ret := m.ctrl.Call(m, "UploadFile", ctx, p)
 	ret0, _ := ret[0].(*UploadFileResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UploadFile indicates an expected call of UploadFile.
-func (mr *MockStorageMockRecorder) UploadFile(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) UploadFile(ctx, p interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockStorage)(nil).UploadFile), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockStorage)(nil).UploadFile), ctx, p)
+}
+
+// MockStreamUploader is a mock of StreamUploader interface.
+type MockStreamUploader struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamUploaderMockRecorder
+}
+
+// MockStreamUploaderMockRecorder is the mock recorder for MockStreamUploader.
+type MockStreamUploaderMockRecorder struct {
+	mock *MockStreamUploader
+}
+
+// NewMockStreamUploader creates a new mock instance.
+func NewMockStreamUploader(ctrl *gomock.Controller) *MockStreamUploader {
+	mock := &MockStreamUploader{ctrl: ctrl}
+	mock.recorder = &MockStreamUploaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamUploader) EXPECT() *MockStreamUploaderMockRecorder {
+	return m.recorder
+}
+
+// UploadStream mocks base method.
+func (m *MockStreamUploader) UploadStream(ctx context.Context, p UploadStreamParam) (*UploadFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadStream", ctx, p)
+	ret0, _ := ret[0].(*UploadFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadStream indicates an expected call of UploadStream.
+func (mr *MockStreamUploaderMockRecorder) UploadStream(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadStream", reflect.TypeOf((*MockStreamUploader)(nil).UploadStream), ctx, p)
+}
+
+// MockStreamRetriever is a mock of StreamRetriever interface.
+type MockStreamRetriever struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamRetrieverMockRecorder
+}
+
+// MockStreamRetrieverMockRecorder is the mock recorder for MockStreamRetriever.
+type MockStreamRetrieverMockRecorder struct {
+	mock *MockStreamRetriever
+}
+
+// NewMockStreamRetriever creates a new mock instance.
+func NewMockStreamRetriever(ctrl *gomock.Controller) *MockStreamRetriever {
+	mock := &MockStreamRetriever{ctrl: ctrl}
+	mock.recorder = &MockStreamRetrieverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamRetriever) EXPECT() *MockStreamRetrieverMockRecorder {
+	return m.recorder
+}
+
+// RetrieveStream mocks base method.
+func (m *MockStreamRetriever) RetrieveStream(ctx context.Context, p RetrieveFileParam) (*RetrieveStreamResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveStream", ctx, p)
+	ret0, _ := ret[0].(*RetrieveStreamResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveStream indicates an expected call of RetrieveStream.
+func (mr *MockStreamRetrieverMockRecorder) RetrieveStream(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveStream", reflect.TypeOf((*MockStreamRetriever)(nil).RetrieveStream), ctx, p)
+}
+
+// MockStater is a mock of Stater interface.
+type MockStater struct {
+	ctrl     *gomock.Controller
+	recorder *MockStaterMockRecorder
+}
+
+// MockStaterMockRecorder is the mock recorder for MockStater.
+type MockStaterMockRecorder struct {
+	mock *MockStater
+}
+
+// NewMockStater creates a new mock instance.
+func NewMockStater(ctrl *gomock.Controller) *MockStater {
+	mock := &MockStater{ctrl: ctrl}
+	mock.recorder = &MockStaterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStater) EXPECT() *MockStaterMockRecorder {
+	return m.recorder
+}
+
+// StatFile mocks base method.
+func (m *MockStater) StatFile(ctx context.Context, p StatFileParam) (*StatFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatFile", ctx, p)
+	ret0, _ := ret[0].(*StatFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatFile indicates an expected call of StatFile.
+func (mr *MockStaterMockRecorder) StatFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatFile", reflect.TypeOf((*MockStater)(nil).StatFile), ctx, p)
+}
+
+// MockLister is a mock of Lister interface.
+type MockLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockListerMockRecorder
+}
+
+// MockListerMockRecorder is the mock recorder for MockLister.
+type MockListerMockRecorder struct {
+	mock *MockLister
+}
+
+// NewMockLister creates a new mock instance.
+func NewMockLister(ctrl *gomock.Controller) *MockLister {
+	mock := &MockLister{ctrl: ctrl}
+	mock.recorder = &MockListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLister) EXPECT() *MockListerMockRecorder {
+	return m.recorder
+}
+
+// ListFiles mocks base method.
+func (m *MockLister) ListFiles(ctx context.Context, p ListFileParam) (*ListFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFiles", ctx, p)
+	ret0, _ := ret[0].(*ListFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFiles indicates an expected call of ListFiles.
+func (mr *MockListerMockRecorder) ListFiles(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFiles", reflect.TypeOf((*MockLister)(nil).ListFiles), ctx, p)
+}
+
+// MockURLSigner is a mock of URLSigner interface.
+type MockURLSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockURLSignerMockRecorder
+}
+
+// MockURLSignerMockRecorder is the mock recorder for MockURLSigner.
+type MockURLSignerMockRecorder struct {
+	mock *MockURLSigner
+}
+
+// NewMockURLSigner creates a new mock instance.
+func NewMockURLSigner(ctrl *gomock.Controller) *MockURLSigner {
+	mock := &MockURLSigner{ctrl: ctrl}
+	mock.recorder = &MockURLSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockURLSigner) EXPECT() *MockURLSignerMockRecorder {
+	return m.recorder
+}
+
+// SignURL mocks base method.
+func (m *MockURLSigner) SignURL(ctx context.Context, p SignURLParam) (*SignURLResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignURL", ctx, p)
+	ret0, _ := ret[0].(*SignURLResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignURL indicates an expected call of SignURL.
+func (mr *MockURLSignerMockRecorder) SignURL(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignURL", reflect.TypeOf((*MockURLSigner)(nil).SignURL), ctx, p)
+}
+
+// MockMultipartUploader is a mock of MultipartUploader interface.
+type MockMultipartUploader struct {
+	ctrl     *gomock.Controller
+	recorder *MockMultipartUploaderMockRecorder
+}
+
+// MockMultipartUploaderMockRecorder is the mock recorder for MockMultipartUploader.
+type MockMultipartUploaderMockRecorder struct {
+	mock *MockMultipartUploader
+}
+
+// NewMockMultipartUploader creates a new mock instance.
+func NewMockMultipartUploader(ctrl *gomock.Controller) *MockMultipartUploader {
+	mock := &MockMultipartUploader{ctrl: ctrl}
+	mock.recorder = &MockMultipartUploaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMultipartUploader) EXPECT() *MockMultipartUploaderMockRecorder {
+	return m.recorder
+}
+
+// AbortMultipartUpload mocks base method.
+func (m *MockMultipartUploader) AbortMultipartUpload(ctx context.Context, p AbortMultipartParam) (*AbortMultipartResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortMultipartUpload", ctx, p)
+	ret0, _ := ret[0].(*AbortMultipartResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AbortMultipartUpload indicates an expected call of AbortMultipartUpload.
+func (mr *MockMultipartUploaderMockRecorder) AbortMultipartUpload(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortMultipartUpload", reflect.TypeOf((*MockMultipartUploader)(nil).AbortMultipartUpload), ctx, p)
+}
+
+// CompleteMultipartUpload mocks base method.
+func (m *MockMultipartUploader) CompleteMultipartUpload(ctx context.Context, p CompleteMultipartParam) (*UploadFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteMultipartUpload", ctx, p)
+	ret0, _ := ret[0].(*UploadFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteMultipartUpload indicates an expected call of CompleteMultipartUpload.
+func (mr *MockMultipartUploaderMockRecorder) CompleteMultipartUpload(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteMultipartUpload", reflect.TypeOf((*MockMultipartUploader)(nil).CompleteMultipartUpload), ctx, p)
+}
+
+// CreateMultipartUpload mocks base method.
+func (m *MockMultipartUploader) CreateMultipartUpload(ctx context.Context, p CreateMultipartParam) (*CreateMultipartResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMultipartUpload", ctx, p)
+	ret0, _ := ret[0].(*CreateMultipartResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMultipartUpload indicates an expected call of CreateMultipartUpload.
+func (mr *MockMultipartUploaderMockRecorder) CreateMultipartUpload(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMultipartUpload", reflect.TypeOf((*MockMultipartUploader)(nil).CreateMultipartUpload), ctx, p)
+}
+
+// ListParts mocks base method.
+func (m *MockMultipartUploader) ListParts(ctx context.Context, p ListPartsParam) (*ListPartsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListParts", ctx, p)
+	ret0, _ := ret[0].(*ListPartsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListParts indicates an expected call of ListParts.
+func (mr *MockMultipartUploaderMockRecorder) ListParts(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListParts", reflect.TypeOf((*MockMultipartUploader)(nil).ListParts), ctx, p)
+}
+
+// UploadPart mocks base method.
+func (m *MockMultipartUploader) UploadPart(ctx context.Context, p UploadPartParam) (*UploadPartResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadPart", ctx, p)
+	ret0, _ := ret[0].(*UploadPartResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadPart indicates an expected call of UploadPart.
+func (mr *MockMultipartUploaderMockRecorder) UploadPart(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadPart", reflect.TypeOf((*MockMultipartUploader)(nil).UploadPart), ctx, p)
+}
+
+// MockCopier is a mock of Copier interface.
+type MockCopier struct {
+	ctrl     *gomock.Controller
+	recorder *MockCopierMockRecorder
+}
+
+// MockCopierMockRecorder is the mock recorder for MockCopier.
+type MockCopierMockRecorder struct {
+	mock *MockCopier
+}
+
+// NewMockCopier creates a new mock instance.
+func NewMockCopier(ctrl *gomock.Controller) *MockCopier {
+	mock := &MockCopier{ctrl: ctrl}
+	mock.recorder = &MockCopierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCopier) EXPECT() *MockCopierMockRecorder {
+	return m.recorder
+}
+
+// CopyFile mocks base method.
+func (m *MockCopier) CopyFile(ctx context.Context, p CopyFileParam) (*CopyFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFile", ctx, p)
+	ret0, _ := ret[0].(*CopyFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFile indicates an expected call of CopyFile.
+func (mr *MockCopierMockRecorder) CopyFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFile", reflect.TypeOf((*MockCopier)(nil).CopyFile), ctx, p)
+}
+
+// MockMover is a mock of Mover interface.
+type MockMover struct {
+	ctrl     *gomock.Controller
+	recorder *MockMoverMockRecorder
+}
+
+// MockMoverMockRecorder is the mock recorder for MockMover.
+type MockMoverMockRecorder struct {
+	mock *MockMover
+}
+
+// NewMockMover creates a new mock instance.
+func NewMockMover(ctrl *gomock.Controller) *MockMover {
+	mock := &MockMover{ctrl: ctrl}
+	mock.recorder = &MockMoverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMover) EXPECT() *MockMoverMockRecorder {
+	return m.recorder
+}
+
+// MoveFile mocks base method.
+func (m *MockMover) MoveFile(ctx context.Context, p MoveFileParam) (*MoveFileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveFile", ctx, p)
+	ret0, _ := ret[0].(*MoveFileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveFile indicates an expected call of MoveFile.
+func (mr *MockMoverMockRecorder) MoveFile(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveFile", reflect.TypeOf((*MockMover)(nil).MoveFile), ctx, p)
+}
+
+// MockBatchDeleter is a mock of BatchDeleter interface.
+type MockBatchDeleter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchDeleterMockRecorder
+}
+
+// MockBatchDeleterMockRecorder is the mock recorder for MockBatchDeleter.
+type MockBatchDeleterMockRecorder struct {
+	mock *MockBatchDeleter
+}
+
+// NewMockBatchDeleter creates a new mock instance.
+func NewMockBatchDeleter(ctrl *gomock.Controller) *MockBatchDeleter {
+	mock := &MockBatchDeleter{ctrl: ctrl}
+	mock.recorder = &MockBatchDeleterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchDeleter) EXPECT() *MockBatchDeleterMockRecorder {
+	return m.recorder
+}
+
+// DeleteFiles mocks base method.
+func (m *MockBatchDeleter) DeleteFiles(ctx context.Context, p DeleteFilesParam) (*DeleteFilesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFiles", ctx, p)
+	ret0, _ := ret[0].(*DeleteFilesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFiles indicates an expected call of DeleteFiles.
+func (mr *MockBatchDeleterMockRecorder) DeleteFiles(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFiles", reflect.TypeOf((*MockBatchDeleter)(nil).DeleteFiles), ctx, p)
+}
+
+// MockPrefixDeleter is a mock of PrefixDeleter interface.
+type MockPrefixDeleter struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrefixDeleterMockRecorder
+}
+
+// MockPrefixDeleterMockRecorder is the mock recorder for MockPrefixDeleter.
+type MockPrefixDeleterMockRecorder struct {
+	mock *MockPrefixDeleter
+}
+
+// NewMockPrefixDeleter creates a new mock instance.
+func NewMockPrefixDeleter(ctrl *gomock.Controller) *MockPrefixDeleter {
+	mock := &MockPrefixDeleter{ctrl: ctrl}
+	mock.recorder = &MockPrefixDeleterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrefixDeleter) EXPECT() *MockPrefixDeleterMockRecorder {
+	return m.recorder
+}
+
+// DeletePrefix mocks base method.
+func (m *MockPrefixDeleter) DeletePrefix(ctx context.Context, p DeletePrefixParam) (*DeletePrefixResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePrefix", ctx, p)
+	ret0, _ := ret[0].(*DeletePrefixResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePrefix indicates an expected call of DeletePrefix.
+func (mr *MockPrefixDeleterMockRecorder) DeletePrefix(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePrefix", reflect.TypeOf((*MockPrefixDeleter)(nil).DeletePrefix), ctx, p)
+}
+
+// MockVersionLister is a mock of VersionLister interface.
+type MockVersionLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockVersionListerMockRecorder
+}
+
+// MockVersionListerMockRecorder is the mock recorder for MockVersionLister.
+type MockVersionListerMockRecorder struct {
+	mock *MockVersionLister
+}
+
+// NewMockVersionLister creates a new mock instance.
+func NewMockVersionLister(ctrl *gomock.Controller) *MockVersionLister {
+	mock := &MockVersionLister{ctrl: ctrl}
+	mock.recorder = &MockVersionListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVersionLister) EXPECT() *MockVersionListerMockRecorder {
+	return m.recorder
+}
+
+// ListVersions mocks base method.
+func (m *MockVersionLister) ListVersions(ctx context.Context, p ListVersionsParam) (*ListVersionsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions", ctx, p)
+	ret0, _ := ret[0].(*ListVersionsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions indicates an expected call of ListVersions.
+func (mr *MockVersionListerMockRecorder) ListVersions(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockVersionLister)(nil).ListVersions), ctx, p)
 }
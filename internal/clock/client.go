@@ -4,6 +4,8 @@ import "time"
 
 type Clock interface {
 	Now() time.Time
+	// After send the current time once d has elapsed, see time.After
+	After(d time.Duration) <-chan time.Time
 }
 
 type clock struct {
@@ -13,6 +15,10 @@ func (c *clock) Now() time.Time {
 	return time.Now()
 }
 
+func (c *clock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
 func NewClock() (Clock, error) {
 	c := &clock{}
 	return c, nil
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	gstorage "cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
 )
 
 type Writer interface {
@@ -30,10 +31,21 @@ type ReadCloser interface {
 	Closer
 }
 
+// ObjectWriter expose the attributes of the written object once it's closed
+type ObjectWriter interface {
+	WriteCloser
+	Attrs() *gstorage.ObjectAttrs
+}
+
 type GoogleStorageClient interface {
-	NewWriter(ctx context.Context, bucketName, fileId string) WriteCloser
-	NewReader(ctx context.Context, bucketName, fileId string) (ReadCloser, error)
-	Delete(ctx context.Context, bucketName, fileId string) error
+	NewWriter(ctx context.Context, bucketName string, attrs gstorage.ObjectAttrs, conds gstorage.Conditions) ObjectWriter
+	NewReader(ctx context.Context, bucketName, fileId string, generation, offset, length int64) (ReadCloser, error)
+	Delete(ctx context.Context, bucketName, fileId string, generation int64) error
+	Attrs(ctx context.Context, bucketName, fileId string, generation int64) (*gstorage.ObjectAttrs, error)
+	ListObjects(ctx context.Context, bucketName string, q *gstorage.Query, pageSize int, pageToken string) ([]*gstorage.ObjectAttrs, string, error)
+	Compose(ctx context.Context, bucketName string, dst gstorage.ObjectAttrs, conds gstorage.Conditions, srcs []string) (*gstorage.ObjectAttrs, error)
+	CopyObject(ctx context.Context, bucketName, srcId, dstId string, conds gstorage.Conditions) (*gstorage.ObjectAttrs, error)
+	SignedURL(bucketName, fileId string, opts *gstorage.SignedURLOptions) (string, error)
 	Copy(dst Writer, src Reader) (written int64, err error)
 }
 
@@ -41,20 +53,99 @@ type googleStorageClient struct {
 	client *gstorage.Client
 }
 
-func (c *googleStorageClient) NewWriter(ctx context.Context, bucketName, fileId string) WriteCloser {
-	return c.client.Bucket(bucketName).Object(fileId).NewWriter(ctx)
+// NewWriter write the object only when the given preconditions hold,
+// empty conditions write unconditionally. non zero attrs.CRC32C is sent
+// and verified by the server like attrs.MD5 is
+func (c *googleStorageClient) NewWriter(ctx context.Context, bucketName string, attrs gstorage.ObjectAttrs, conds gstorage.Conditions) ObjectWriter {
+	obj := c.client.Bucket(bucketName).Object(attrs.Name)
+	if conds != (gstorage.Conditions{}) {
+		obj = obj.If(conds)
+	}
+	w := obj.NewWriter(ctx)
+	w.ObjectAttrs = attrs
+	w.SendCRC32C = attrs.CRC32C != 0
+	return w
 }
 
-func (c *googleStorageClient) NewReader(ctx context.Context, bucketName, fileId string) (ReadCloser, error) {
-	return c.client.Bucket(bucketName).Object(fileId).NewReader(ctx)
+// NewReader read length bytes from offset of the given object generation,
+// zero generation read the latest one and negative length read until the end
+func (c *googleStorageClient) NewReader(ctx context.Context, bucketName, fileId string, generation, offset, length int64) (ReadCloser, error) {
+	obj := c.client.Bucket(bucketName).Object(fileId)
+	if generation > 0 {
+		obj = obj.Generation(generation)
+	}
+	return obj.NewRangeReader(ctx, offset, length)
 }
 
 func (c *googleStorageClient) Copy(dst Writer, src Reader) (written int64, err error) {
 	return io.Copy(dst, src)
 }
 
-func (c *googleStorageClient) Delete(ctx context.Context, bucketName, fileId string) error {
-	return c.client.Bucket(bucketName).Object(fileId).Delete(ctx)
+// Delete remove the given object generation for good, zero generation
+// remove the live object which is kept as noncurrent by a versioned bucket
+func (c *googleStorageClient) Delete(ctx context.Context, bucketName, fileId string, generation int64) error {
+	obj := c.client.Bucket(bucketName).Object(fileId)
+	if generation > 0 {
+		obj = obj.Generation(generation)
+	}
+	return obj.Delete(ctx)
+}
+
+// Attrs fetch the attributes of the given object generation,
+// zero generation fetch the live one
+func (c *googleStorageClient) Attrs(ctx context.Context, bucketName, fileId string, generation int64) (*gstorage.ObjectAttrs, error) {
+	obj := c.client.Bucket(bucketName).Object(fileId)
+	if generation > 0 {
+		obj = obj.Generation(generation)
+	}
+	return obj.Attrs(ctx)
+}
+
+func (c *googleStorageClient) ListObjects(ctx context.Context, bucketName string, q *gstorage.Query, pageSize int, pageToken string) ([]*gstorage.ObjectAttrs, string, error) {
+	it := c.client.Bucket(bucketName).Objects(ctx, q)
+	pager := iterator.NewPager(it, pageSize, pageToken)
+
+	objects := []*gstorage.ObjectAttrs{}
+	nextToken, err := pager.NextPage(&objects)
+	if err != nil {
+		return nil, "", err
+	}
+	return objects, nextToken, nil
+}
+
+// Compose concatenate srcs into dst within the same bucket,
+// google storage accept at most 32 sources per call
+func (c *googleStorageClient) Compose(ctx context.Context, bucketName string, dst gstorage.ObjectAttrs, conds gstorage.Conditions, srcs []string) (*gstorage.ObjectAttrs, error) {
+	bucket := c.client.Bucket(bucketName)
+	obj := bucket.Object(dst.Name)
+	if conds != (gstorage.Conditions{}) {
+		obj = obj.If(conds)
+	}
+
+	objects := []*gstorage.ObjectHandle{}
+	for _, src := range srcs {
+		objects = append(objects, bucket.Object(src))
+	}
+	composer := obj.ComposerFrom(objects...)
+	composer.ObjectAttrs = dst
+	return composer.Run(ctx)
+}
+
+// CopyObject copy srcId into dstId within the same bucket,
+// the attributes and metadata of srcId are kept as is
+func (c *googleStorageClient) CopyObject(ctx context.Context, bucketName, srcId, dstId string, conds gstorage.Conditions) (*gstorage.ObjectAttrs, error) {
+	bucket := c.client.Bucket(bucketName)
+	dst := bucket.Object(dstId)
+	if conds != (gstorage.Conditions{}) {
+		dst = dst.If(conds)
+	}
+	return dst.CopierFrom(bucket.Object(srcId)).Run(ctx)
+}
+
+// SignedURL sign with the credential of the underlying client
+// unless opts carry their own access id and key
+func (c *googleStorageClient) SignedURL(bucketName, fileId string, opts *gstorage.SignedURLOptions) (string, error) {
+	return c.client.Bucket(bucketName).SignedURL(fileId, opts)
 }
 
 func NewGoogleStorageClient(cl *gstorage.Client) (*googleStorageClient, error) {
@@ -1,13 +1,14 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: pkg/g-cloud/client.go
+// Source: internal/g-cloud/client.go
 
-// Package g_storage is a generated GoMock package.
+// Package g_cloud is a generated GoMock package.
 package g_cloud
 
 import (
 	context "context"
 	reflect "reflect"
 
+	storage "cloud.google.com/go/storage"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -228,6 +229,72 @@ func (mr *MockReadCloserMockRecorder) Read(p interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockReadCloser)(nil).Read), p)
 }
 
+// MockObjectWriter is a mock of ObjectWriter interface.
+type MockObjectWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockObjectWriterMockRecorder
+}
+
+// MockObjectWriterMockRecorder is the mock recorder for MockObjectWriter.
+type MockObjectWriterMockRecorder struct {
+	mock *MockObjectWriter
+}
+
+// NewMockObjectWriter creates a new mock instance.
+func NewMockObjectWriter(ctrl *gomock.Controller) *MockObjectWriter {
+	mock := &MockObjectWriter{ctrl: ctrl}
+	mock.recorder = &MockObjectWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockObjectWriter) EXPECT() *MockObjectWriterMockRecorder {
+	return m.recorder
+}
+
+// Attrs mocks base method.
+func (m *MockObjectWriter) Attrs() *storage.ObjectAttrs {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attrs")
+	ret0, _ := ret[0].(*storage.ObjectAttrs)
+	return ret0
+}
+
+// Attrs indicates an expected call of Attrs.
+func (mr *MockObjectWriterMockRecorder) Attrs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attrs", reflect.TypeOf((*MockObjectWriter)(nil).Attrs))
+}
+
+// Close mocks base method.
+func (m *MockObjectWriter) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockObjectWriterMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockObjectWriter)(nil).Close))
+}
+
+// Write mocks base method.
+func (m *MockObjectWriter) Write(p []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", p)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockObjectWriterMockRecorder) Write(p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockObjectWriter)(nil).Write), p)
+}
+
 // MockGoogleStorageClient is a mock of GoogleStorageClient interface.
 type MockGoogleStorageClient struct {
 	ctrl     *gomock.Controller
@@ -251,6 +318,36 @@ func (m *MockGoogleStorageClient) EXPECT() *MockGoogleStorageClientMockRecorder
 	return m.recorder
 }
 
+// Attrs mocks base method.
+func (m *MockGoogleStorageClient) Attrs(ctx context.Context, bucketName, fileId string, generation int64) (*storage.ObjectAttrs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attrs", ctx, bucketName, fileId, generation)
+	ret0, _ := ret[0].(*storage.ObjectAttrs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Attrs indicates an expected call of Attrs.
+func (mr *MockGoogleStorageClientMockRecorder) Attrs(ctx, bucketName, fileId, generation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attrs", reflect.TypeOf((*MockGoogleStorageClient)(nil).Attrs), ctx, bucketName, fileId, generation)
+}
+
+// Compose mocks base method.
+func (m *MockGoogleStorageClient) Compose(ctx context.Context, bucketName string, dst storage.ObjectAttrs, conds storage.Conditions, srcs []string) (*storage.ObjectAttrs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compose", ctx, bucketName, dst, conds, srcs)
+	ret0, _ := ret[0].(*storage.ObjectAttrs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Compose indicates an expected call of Compose.
+func (mr *MockGoogleStorageClientMockRecorder) Compose(ctx, bucketName, dst, conds, srcs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compose", reflect.TypeOf((*MockGoogleStorageClient)(nil).Compose), ctx, bucketName, dst, conds, srcs)
+}
+
 // Copy mocks base method.
 func (m *MockGoogleStorageClient) Copy(dst Writer, src Reader) (int64, error) {
 	m.ctrl.T.Helper()
@@ -266,45 +363,91 @@ func (mr *MockGoogleStorageClientMockRecorder) Copy(dst, src interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Copy", reflect.TypeOf((*MockGoogleStorageClient)(nil).Copy), dst, src)
 }
 
+// CopyObject mocks base method.
+func (m *MockGoogleStorageClient) CopyObject(ctx context.Context, bucketName, srcId, dstId string, conds storage.Conditions) (*storage.ObjectAttrs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyObject", ctx, bucketName, srcId, dstId, conds)
+	ret0, _ := ret[0].(*storage.ObjectAttrs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyObject indicates an expected call of CopyObject.
+func (mr *MockGoogleStorageClientMockRecorder) CopyObject(ctx, bucketName, srcId, dstId, conds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyObject", reflect.TypeOf((*MockGoogleStorageClient)(nil).CopyObject), ctx, bucketName, srcId, dstId, conds)
+}
+
 // Delete mocks base method.
-func (m *MockGoogleStorageClient) Delete(ctx context.Context, bucketName, fileId string) error {
+func (m *MockGoogleStorageClient) Delete(ctx context.Context, bucketName, fileId string, generation int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, bucketName, fileId)
+	ret := m.ctrl.Call(m, "Delete", ctx, bucketName, fileId, generation)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockGoogleStorageClientMockRecorder) Delete(ctx, bucketName, fileId interface{}) *gomock.Call {
+func (mr *MockGoogleStorageClientMockRecorder) Delete(ctx, bucketName, fileId, generation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockGoogleStorageClient)(nil).Delete), ctx, bucketName, fileId, generation)
+}
+
+// ListObjects mocks base method.
+func (m *MockGoogleStorageClient) ListObjects(ctx context.Context, bucketName string, q *storage.Query, pageSize int, pageToken string) ([]*storage.ObjectAttrs, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListObjects", ctx, bucketName, q, pageSize, pageToken)
+	ret0, _ := ret[0].([]*storage.ObjectAttrs)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListObjects indicates an expected call of ListObjects.
+func (mr *MockGoogleStorageClientMockRecorder) ListObjects(ctx, bucketName, q, pageSize, pageToken interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockGoogleStorageClient)(nil).Delete), ctx, bucketName, fileId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjects", reflect.TypeOf((*MockGoogleStorageClient)(nil).ListObjects), ctx, bucketName, q, pageSize, pageToken)
 }
 
 // NewReader mocks base method.
-func (m *MockGoogleStorageClient) NewReader(ctx context.Context, bucketName, fileId string) (ReadCloser, error) {
+func (m *MockGoogleStorageClient) NewReader(ctx context.Context, bucketName, fileId string, generation, offset, length int64) (ReadCloser, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewReader", ctx, bucketName, fileId)
+	ret := m.ctrl.Call(m, "NewReader", ctx, bucketName, fileId, generation, offset, length)
 	ret0, _ := ret[0].(ReadCloser)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // NewReader indicates an expected call of NewReader.
-func (mr *MockGoogleStorageClientMockRecorder) NewReader(ctx, bucketName, fileId interface{}) *gomock.Call {
+func (mr *MockGoogleStorageClientMockRecorder) NewReader(ctx, bucketName, fileId, generation, offset, length interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewReader", reflect.TypeOf((*MockGoogleStorageClient)(nil).NewReader), ctx, bucketName, fileId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewReader", reflect.TypeOf((*MockGoogleStorageClient)(nil).NewReader), ctx, bucketName, fileId, generation, offset, length)
 }
 
 // NewWriter mocks base method.
-func (m *MockGoogleStorageClient) NewWriter(ctx context.Context, bucketName, fileId string) WriteCloser {
+func (m *MockGoogleStorageClient) NewWriter(ctx context.Context, bucketName string, attrs storage.ObjectAttrs, conds storage.Conditions) ObjectWriter {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewWriter", ctx, bucketName, fileId)
-	ret0, _ := ret[0].(WriteCloser)
+	ret := m.ctrl.Call(m, "NewWriter", ctx, bucketName, attrs, conds)
+	ret0, _ := ret[0].(ObjectWriter)
 	return ret0
 }
 
 // NewWriter indicates an expected call of NewWriter.
-func (mr *MockGoogleStorageClientMockRecorder) NewWriter(ctx, bucketName, fileId interface{}) *gomock.Call {
+func (mr *MockGoogleStorageClientMockRecorder) NewWriter(ctx, bucketName, attrs, conds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewWriter", reflect.TypeOf((*MockGoogleStorageClient)(nil).NewWriter), ctx, bucketName, attrs, conds)
+}
+
+// SignedURL mocks base method.
+func (m *MockGoogleStorageClient) SignedURL(bucketName, fileId string, opts *storage.SignedURLOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignedURL", bucketName, fileId, opts)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignedURL indicates an expected call of SignedURL.
+func (mr *MockGoogleStorageClientMockRecorder) SignedURL(bucketName, fileId, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewWriter", reflect.TypeOf((*MockGoogleStorageClient)(nil).NewWriter), ctx, bucketName, fileId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignedURL", reflect.TypeOf((*MockGoogleStorageClient)(nil).SignedURL), bucketName, fileId, opts)
 }
@@ -2,20 +2,59 @@ package io
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
 )
 
+type File interface {
+	Read(p []byte) (n int, err error)
+	Write(p []byte) (n int, err error)
+	Seek(offset int64, whence int) (int64, error)
+	Close() error
+	Sync() error
+	Name() string
+	Stat() (fs.FileInfo, error)
+}
+
+// FileEntry describe a regular file found by WalkFiles,
+// Path is slash separated and relative to the walked root
+type FileEntry struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// ChunkSize is the buffer size of the cancellable I/O,
+// the context is checked before every chunk
+const ChunkSize = 256 * 1024
+
 type FileManager interface {
 	IsExists(path string) bool
+	Stat(path string) (fs.FileInfo, error)
 	CreateDir(path string, perm fs.FileMode) error
 	WriteFile(name string, data []byte, perm fs.FileMode) error
-	Open(path string) (*os.File, error)
-	ReadFile(file *os.File) ([]byte, error)
+	OpenFile(name string, flag int, perm fs.FileMode) (File, error)
+	Open(path string) (File, error)
+	ReadFile(file io.Reader) ([]byte, error)
+	ReadFileContext(ctx context.Context, file io.Reader) ([]byte, error)
+	CopyContext(ctx context.Context, dst io.Writer, src io.Reader) (written int64, err error)
 	RemoveFile(path string) error
+	RemoveDir(path string) error
+	WalkFiles(root string) ([]FileEntry, error)
+	CreateTemp(dir, pattern string, perm fs.FileMode) (File, error)
+	Chmod(path string, mode fs.FileMode) error
+	Chown(path string, uid, gid int) error
+	Rename(oldPath, newPath string) error
+	Link(oldPath, newPath string) error
+	SyncDir(path string) error
 }
 
 type fileManager struct {
@@ -26,6 +65,10 @@ func (fm *fileManager) IsExists(path string) bool {
 	return !errors.Is(err, os.ErrNotExist)
 }
 
+func (fm *fileManager) Stat(path string) (fs.FileInfo, error) {
+	return os.Stat(path)
+}
+
 func (fm *fileManager) CreateDir(path string, perm fs.FileMode) error {
 	return os.MkdirAll(path, perm)
 }
@@ -34,11 +77,23 @@ func (fm *fileManager) WriteFile(name string, data []byte, perm fs.FileMode) err
 	return os.WriteFile(name, data, perm)
 }
 
-func (fm *fileManager) Open(path string) (*os.File, error) {
-	return os.Open(path)
+func (fm *fileManager) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	file, err := os.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func (fm *fileManager) Open(path string) (File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
 }
 
-func (fm *fileManager) ReadFile(file *os.File) ([]byte, error) {
+func (fm *fileManager) ReadFile(file io.Reader) ([]byte, error) {
 	if file == nil {
 		return nil, fmt.Errorf("invalid file")
 	}
@@ -51,10 +106,142 @@ func (fm *fileManager) ReadFile(file *os.File) ([]byte, error) {
 	return bytes, nil
 }
 
+// ReadFileContext read the whole file in chunks, it stops with ctx.Err() once ctx is done
+func (fm *fileManager) ReadFileContext(ctx context.Context, file io.Reader) ([]byte, error) {
+	if file == nil {
+		return nil, fmt.Errorf("invalid file")
+	}
+	return io.ReadAll(NewContextReader(ctx, file))
+}
+
+// CopyContext copy src into dst in chunks, it stops with ctx.Err() once ctx is done,
+// the bytes written so far are reported along with the error
+func (fm *fileManager) CopyContext(ctx context.Context, dst io.Writer, src io.Reader) (written int64, err error) {
+	buf := make([]byte, ChunkSize)
+	for {
+		err := ctx.Err()
+		if err != nil {
+			return written, err
+		}
+
+		n, rerr := src.Read(buf)
+		if n > 0 {
+			w, werr := dst.Write(buf[:n])
+			written += int64(w)
+			if werr != nil {
+				return written, werr
+			}
+			if w != n {
+				return written, io.ErrShortWrite
+			}
+		}
+		if rerr == io.EOF {
+			return written, nil
+		}
+		if rerr != nil {
+			return written, rerr
+		}
+	}
+}
+
 func (fm *fileManager) RemoveFile(path string) error {
 	return os.Remove(path)
 }
 
+// RemoveDir remove an empty directory, it fails when the directory isn't empty
+func (fm *fileManager) RemoveDir(path string) error {
+	err := syscall.Rmdir(path)
+	if err != nil {
+		return &fs.PathError{Op: "rmdir", Path: path, Err: err}
+	}
+	return nil
+}
+
+func (fm *fileManager) WalkFiles(root string) ([]FileEntry, error) {
+	entries := []FileEntry{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, FileEntry{
+			Path:    filepath.ToSlash(rel),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return []FileEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+	return entries, nil
+}
+
+// CreateTemp create a new file with a random name in dir,
+// see os.CreateTemp for the pattern format
+func (fm *fileManager) CreateTemp(dir, pattern string, perm fs.FileMode) (File, error) {
+	file, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	err = file.Chmod(perm)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return file, nil
+}
+
+func (fm *fileManager) Chmod(path string, mode fs.FileMode) error {
+	return os.Chmod(path, mode)
+}
+
+func (fm *fileManager) Chown(path string, uid, gid int) error {
+	return os.Chown(path, uid, gid)
+}
+
+func (fm *fileManager) Rename(oldPath, newPath string) error {
+	return os.Rename(oldPath, newPath)
+}
+
+// Link create newPath as a hard link to oldPath,
+// it fails with fs.ErrExist when newPath is already taken
+func (fm *fileManager) Link(oldPath, newPath string) error {
+	return os.Link(oldPath, newPath)
+}
+
+// SyncDir flush the directory entries, making a preceding rename durable
+func (fm *fileManager) SyncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
 func NewFileManager() (FileManager, error) {
 	s := &fileManager{}
 	return s, nil
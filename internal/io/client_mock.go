@@ -5,13 +5,139 @@
 package io
 
 import (
+	context "context"
+	io "io"
 	fs "io/fs"
-	os "os"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 )
 
+// MockFile is a mock of File interface.
+type MockFile struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileMockRecorder
+}
+
+// MockFileMockRecorder is the mock recorder for MockFile.
+type MockFileMockRecorder struct {
+	mock *MockFile
+}
+
+// NewMockFile creates a new mock instance.
+func NewMockFile(ctrl *gomock.Controller) *MockFile {
+	mock := &MockFile{ctrl: ctrl}
+	mock.recorder = &MockFileMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFile) EXPECT() *MockFileMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockFile) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockFileMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockFile)(nil).Close))
+}
+
+// Name mocks base method.
+func (m *MockFile) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockFileMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockFile)(nil).Name))
+}
+
+// Read mocks base method.
+func (m *MockFile) Read(p []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", p)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockFileMockRecorder) Read(p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockFile)(nil).Read), p)
+}
+
+// Seek mocks base method.
+func (m *MockFile) Seek(offset int64, whence int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Seek", offset, whence)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Seek indicates an expected call of Seek.
+func (mr *MockFileMockRecorder) Seek(offset, whence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Seek", reflect.TypeOf((*MockFile)(nil).Seek), offset, whence)
+}
+
+// Stat mocks base method.
+func (m *MockFile) Stat() (fs.FileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stat")
+	ret0, _ := ret[0].(fs.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stat indicates an expected call of Stat.
+func (mr *MockFileMockRecorder) Stat() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stat", reflect.TypeOf((*MockFile)(nil).Stat))
+}
+
+// Sync mocks base method.
+func (m *MockFile) Sync() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockFileMockRecorder) Sync() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockFile)(nil).Sync))
+}
+
+// Write mocks base method.
+func (m *MockFile) Write(p []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", p)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockFileMockRecorder) Write(p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockFile)(nil).Write), p)
+}
+
 // MockFileManager is a mock of FileManager interface.
 type MockFileManager struct {
 	ctrl     *gomock.Controller
@@ -35,6 +161,49 @@ func (m *MockFileManager) EXPECT() *MockFileManagerMockRecorder {
 	return m.recorder
 }
 
+// Chmod mocks base method.
+func (m *MockFileManager) Chmod(path string, mode fs.FileMode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Chmod", path, mode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Chmod indicates an expected call of Chmod.
+func (mr *MockFileManagerMockRecorder) Chmod(path, mode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Chmod", reflect.TypeOf((*MockFileManager)(nil).Chmod), path, mode)
+}
+
+// Chown mocks base method.
+func (m *MockFileManager) Chown(path string, uid, gid int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Chown", path, uid, gid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Chown indicates an expected call of Chown.
+func (mr *MockFileManagerMockRecorder) Chown(path, uid, gid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Chown", reflect.TypeOf((*MockFileManager)(nil).Chown), path, uid, gid)
+}
+
+// CopyContext mocks base method.
+func (m *MockFileManager) CopyContext(ctx context.Context, dst io.Writer, src io.Reader) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyContext", ctx, dst, src)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyContext indicates an expected call of CopyContext.
+func (mr *MockFileManagerMockRecorder) CopyContext(ctx, dst, src interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyContext", reflect.TypeOf((*MockFileManager)(nil).CopyContext), ctx, dst, src)
+}
+
 // CreateDir mocks base method.
 func (m *MockFileManager) CreateDir(path string, perm fs.FileMode) error {
 	m.ctrl.T.Helper()
@@ -49,6 +218,21 @@ func (mr *MockFileManagerMockRecorder) CreateDir(path, perm interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDir", reflect.TypeOf((*MockFileManager)(nil).CreateDir), path, perm)
 }
 
+// CreateTemp mocks base method.
+func (m *MockFileManager) CreateTemp(dir, pattern string, perm fs.FileMode) (File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemp", dir, pattern, perm)
+	ret0, _ := ret[0].(File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTemp indicates an expected call of CreateTemp.
+func (mr *MockFileManagerMockRecorder) CreateTemp(dir, pattern, perm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemp", reflect.TypeOf((*MockFileManager)(nil).CreateTemp), dir, pattern, perm)
+}
+
 // IsExists mocks base method.
 func (m *MockFileManager) IsExists(path string) bool {
 	m.ctrl.T.Helper()
@@ -63,11 +247,25 @@ func (mr *MockFileManagerMockRecorder) IsExists(path interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsExists", reflect.TypeOf((*MockFileManager)(nil).IsExists), path)
 }
 
+// Link mocks base method.
+func (m *MockFileManager) Link(oldPath, newPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Link", oldPath, newPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Link indicates an expected call of Link.
+func (mr *MockFileManagerMockRecorder) Link(oldPath, newPath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Link", reflect.TypeOf((*MockFileManager)(nil).Link), oldPath, newPath)
+}
+
 // Open mocks base method.
-func (m *MockFileManager) Open(path string) (*os.File, error) {
+func (m *MockFileManager) Open(path string) (File, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Open", path)
-	ret0, _ := ret[0].(*os.File)
+	ret0, _ := ret[0].(File)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -78,8 +276,23 @@ func (mr *MockFileManagerMockRecorder) Open(path interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockFileManager)(nil).Open), path)
 }
 
+// OpenFile mocks base method.
+func (m *MockFileManager) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenFile", name, flag, perm)
+	ret0, _ := ret[0].(File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenFile indicates an expected call of OpenFile.
+func (mr *MockFileManagerMockRecorder) OpenFile(name, flag, perm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenFile", reflect.TypeOf((*MockFileManager)(nil).OpenFile), name, flag, perm)
+}
+
 // ReadFile mocks base method.
-func (m *MockFileManager) ReadFile(file *os.File) ([]byte, error) {
+func (m *MockFileManager) ReadFile(file io.Reader) ([]byte, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ReadFile", file)
 	ret0, _ := ret[0].([]byte)
@@ -93,6 +306,35 @@ func (mr *MockFileManagerMockRecorder) ReadFile(file interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadFile", reflect.TypeOf((*MockFileManager)(nil).ReadFile), file)
 }
 
+// ReadFileContext mocks base method.
+func (m *MockFileManager) ReadFileContext(ctx context.Context, file io.Reader) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadFileContext", ctx, file)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadFileContext indicates an expected call of ReadFileContext.
+func (mr *MockFileManagerMockRecorder) ReadFileContext(ctx, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadFileContext", reflect.TypeOf((*MockFileManager)(nil).ReadFileContext), ctx, file)
+}
+
+// RemoveDir mocks base method.
+func (m *MockFileManager) RemoveDir(path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDir", path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDir indicates an expected call of RemoveDir.
+func (mr *MockFileManagerMockRecorder) RemoveDir(path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDir", reflect.TypeOf((*MockFileManager)(nil).RemoveDir), path)
+}
+
 // RemoveFile mocks base method.
 func (m *MockFileManager) RemoveFile(path string) error {
 	m.ctrl.T.Helper()
@@ -107,6 +349,64 @@ func (mr *MockFileManagerMockRecorder) RemoveFile(path interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFile", reflect.TypeOf((*MockFileManager)(nil).RemoveFile), path)
 }
 
+// Rename mocks base method.
+func (m *MockFileManager) Rename(oldPath, newPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", oldPath, newPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockFileManagerMockRecorder) Rename(oldPath, newPath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockFileManager)(nil).Rename), oldPath, newPath)
+}
+
+// Stat mocks base method.
+func (m *MockFileManager) Stat(path string) (fs.FileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stat", path)
+	ret0, _ := ret[0].(fs.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stat indicates an expected call of Stat.
+func (mr *MockFileManagerMockRecorder) Stat(path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stat", reflect.TypeOf((*MockFileManager)(nil).Stat), path)
+}
+
+// SyncDir mocks base method.
+func (m *MockFileManager) SyncDir(path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncDir", path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncDir indicates an expected call of SyncDir.
+func (mr *MockFileManagerMockRecorder) SyncDir(path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncDir", reflect.TypeOf((*MockFileManager)(nil).SyncDir), path)
+}
+
+// WalkFiles mocks base method.
+func (m *MockFileManager) WalkFiles(root string) ([]FileEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalkFiles", root)
+	ret0, _ := ret[0].([]FileEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WalkFiles indicates an expected call of WalkFiles.
+func (mr *MockFileManagerMockRecorder) WalkFiles(root interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalkFiles", reflect.TypeOf((*MockFileManager)(nil).WalkFiles), root)
+}
+
 // WriteFile mocks base method.
 func (m *MockFileManager) WriteFile(name string, data []byte, perm fs.FileMode) error {
 	m.ctrl.T.Helper()
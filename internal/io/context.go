@@ -0,0 +1,44 @@
+package io
+
+import (
+	"context"
+	"io"
+)
+
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// NewContextReader return a reader failing with ctx.Err() once ctx is done,
+// a read already in progress is not interrupted
+func NewContextReader(ctx context.Context, r io.Reader) io.Reader {
+	return &contextReader{
+		ctx: ctx,
+		r:   r,
+	}
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	err := cr.ctx.Err()
+	if err != nil {
+		return 0, err
+	}
+	if len(p) > ChunkSize {
+		p = p[:ChunkSize]
+	}
+	return cr.r.Read(p)
+}
+
+type contextReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// NewContextReadCloser is NewContextReader keeping the close method of rc
+func NewContextReadCloser(ctx context.Context, rc io.ReadCloser) io.ReadCloser {
+	return &contextReadCloser{
+		Reader: NewContextReader(ctx, rc),
+		Closer: rc,
+	}
+}
@@ -0,0 +1,61 @@
+package goseidon
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxKeyLength is the maximum byte length of a file id,
+	// it's the object key limit of s3 and google cloud storage
+	MaxKeyLength = 1024
+	// MaxKeySegmentLength is the maximum byte length of a "/" separated segment,
+	// it's the file name limit of most local file system
+	MaxKeySegmentLength = 255
+	// ReservedKeyPrefix mark a segment used internally by storage provider
+	ReservedKeyPrefix = ".goseidon-"
+)
+
+// ValidateKey check a file id against the key grammar shared by every storage provider,
+// a valid key is a relative "/" separated path without empty, "." or ".." segment
+func ValidateKey(key string) error {
+	switch {
+	case key == "":
+		return invalidKey("file id is empty")
+	case len(key) > MaxKeyLength:
+		return invalidKey("file id is longer than %d bytes", MaxKeyLength)
+	case !utf8.ValidString(key):
+		return invalidKey("file id is not a valid utf-8 string")
+	case strings.HasPrefix(key, "/"):
+		return invalidKey("file id must not start with /")
+	}
+
+	for _, r := range key {
+		if unicode.IsControl(r) {
+			return invalidKey("file id contains control character %q", r)
+		}
+		if r == '\\' {
+			return invalidKey("file id contains backslash")
+		}
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		switch {
+		case segment == "":
+			return invalidKey("file id contains empty segment")
+		case segment == "." || segment == "..":
+			return invalidKey("file id contains %q segment", segment)
+		case len(segment) > MaxKeySegmentLength:
+			return invalidKey("file id segment is longer than %d bytes", MaxKeySegmentLength)
+		case strings.HasPrefix(segment, ReservedKeyPrefix):
+			return invalidKey("file id segment %q is reserved", segment)
+		}
+	}
+	return nil
+}
+
+func invalidKey(format string, a ...interface{}) error {
+	return NewError(ErrInvalidArgument, fmt.Errorf(format, a...))
+}
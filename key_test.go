@@ -0,0 +1,51 @@
+package goseidon_test
+
+import (
+	"errors"
+	"strings"
+
+	goseidon "github.com/go-seidon/core"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Key", func() {
+	Context("ValidateKey function", func() {
+		DescribeTable("key is valid",
+			func(key string) {
+				err := goseidon.ValidateKey(key)
+
+				Expect(err).To(BeNil())
+			},
+			Entry("plain name", "image.jpg"),
+			Entry("nested name", "tenant-1/2022/image.jpg"),
+			Entry("hidden file", ".profile"),
+			Entry("dotted name", "..image.jpg"),
+			Entry("unicode name", "gambar/ikan paus 🐳.jpg"),
+			Entry("longest segment", strings.Repeat("a", goseidon.MaxKeySegmentLength)),
+		)
+
+		DescribeTable("key is invalid",
+			func(key string, msg string) {
+				err := goseidon.ValidateKey(key)
+
+				Expect(err.Error()).To(Equal(msg))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			},
+			Entry("empty", "", "file id is empty"),
+			Entry("too long", strings.Repeat("a/", 512)+"a", "file id is longer than 1024 bytes"),
+			Entry("invalid utf-8", "image\xff.jpg", "file id is not a valid utf-8 string"),
+			Entry("absolute path", "/etc/passwd", "file id must not start with /"),
+			Entry("nul byte", "image.jpg\x00.png", `file id contains control character '\x00'`),
+			Entry("new line", "image\n.jpg", `file id contains control character '\n'`),
+			Entry("backslash", `..\..\etc\passwd`, "file id contains backslash"),
+			Entry("parent segment", "../../etc/passwd", `file id contains ".." segment`),
+			Entry("inner parent segment", "tenant-1/../../etc/passwd", `file id contains ".." segment`),
+			Entry("current segment", "./image.jpg", `file id contains "." segment`),
+			Entry("empty segment", "tenant-1//image.jpg", "file id contains empty segment"),
+			Entry("trailing slash", "tenant-1/", "file id contains empty segment"),
+			Entry("long segment", strings.Repeat("a", goseidon.MaxKeySegmentLength+1), "file id segment is longer than 255 bytes"),
+			Entry("reserved segment", "tenant-1/.goseidon-tmp-1", `file id segment ".goseidon-tmp-1" is reserved`),
+		)
+	})
+})
@@ -0,0 +1,41 @@
+package goseidon
+
+import (
+	"fmt"
+)
+
+// MaxPartNumber is the highest part number of a multipart upload,
+// it's the part limit of s3
+const MaxPartNumber = 10000
+
+// ValidatePartNumber check a part number is within 1 and MaxPartNumber
+func ValidatePartNumber(n int) error {
+	if n < 1 || n > MaxPartNumber {
+		return NewError(ErrInvalidArgument, fmt.Errorf("part number must be between 1 and %d", MaxPartNumber))
+	}
+	return nil
+}
+
+// ValidateCompletedParts check the parts of a multipart upload
+// are given in strictly ascending part number order, each with its etag
+func ValidateCompletedParts(parts []CompletedPart) error {
+	if len(parts) == 0 {
+		return NewError(ErrInvalidArgument, fmt.Errorf("parts are empty"))
+	}
+
+	prev := 0
+	for _, part := range parts {
+		err := ValidatePartNumber(part.PartNumber)
+		if err != nil {
+			return err
+		}
+		if part.PartNumber <= prev {
+			return NewError(ErrInvalidArgument, fmt.Errorf("parts are not in ascending order"))
+		}
+		if part.ETag == "" {
+			return NewError(ErrInvalidArgument, fmt.Errorf("part %d has empty etag", part.PartNumber))
+		}
+		prev = part.PartNumber
+	}
+	return nil
+}
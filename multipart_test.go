@@ -0,0 +1,58 @@
+package goseidon_test
+
+import (
+	"errors"
+
+	goseidon "github.com/go-seidon/core"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Multipart", func() {
+	Context("ValidatePartNumber function", func() {
+		DescribeTable("part number",
+			func(n int, valid bool) {
+				err := goseidon.ValidatePartNumber(n)
+
+				if valid {
+					Expect(err).To(BeNil())
+					return
+				}
+				Expect(err.Error()).To(Equal("part number must be between 1 and 10000"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			},
+			Entry("zero", 0, false),
+			Entry("negative", -1, false),
+			Entry("first", 1, true),
+			Entry("last", goseidon.MaxPartNumber, true),
+			Entry("over limit", goseidon.MaxPartNumber+1, false),
+		)
+	})
+
+	Context("ValidateCompletedParts function", func() {
+		When("parts are valid", func() {
+			It("should return nil", func() {
+				err := goseidon.ValidateCompletedParts([]goseidon.CompletedPart{
+					{PartNumber: 1, ETag: "a"},
+					{PartNumber: 3, ETag: "b"},
+				})
+
+				Expect(err).To(BeNil())
+			})
+		})
+
+		DescribeTable("parts are invalid",
+			func(parts []goseidon.CompletedPart, msg string) {
+				err := goseidon.ValidateCompletedParts(parts)
+
+				Expect(err.Error()).To(Equal(msg))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			},
+			Entry("empty", []goseidon.CompletedPart{}, "parts are empty"),
+			Entry("invalid number", []goseidon.CompletedPart{{PartNumber: 0, ETag: "a"}}, "part number must be between 1 and 10000"),
+			Entry("duplicate number", []goseidon.CompletedPart{{PartNumber: 1, ETag: "a"}, {PartNumber: 1, ETag: "b"}}, "parts are not in ascending order"),
+			Entry("descending number", []goseidon.CompletedPart{{PartNumber: 2, ETag: "a"}, {PartNumber: 1, ETag: "b"}}, "parts are not in ascending order"),
+			Entry("empty etag", []goseidon.CompletedPart{{PartNumber: 1}}, "part 1 has empty etag"),
+		)
+	})
+})
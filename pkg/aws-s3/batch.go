@@ -0,0 +1,105 @@
+package aws_s3
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+)
+
+// maxDeleteObjects is the number of keys s3 accept per DeleteObjects request
+const maxDeleteObjects = 1000
+
+// DeleteFiles delete the ids in chunks of 1000 keys per request,
+// like DeleteFile an id that doesn't exist is reported as deleted
+func (s *AwsS3Storage) DeleteFiles(ctx context.Context, p goseidon.DeleteFilesParam) (*goseidon.DeleteFilesResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateDeleteFiles(p)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]goseidon.DeleteFileItem, len(p.Ids))
+	pending := []int{}
+	for i, id := range p.Ids {
+		items[i].Id = id
+		err := goseidon.ValidateKey(id)
+		if err != nil {
+			items[i].Error = err
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	for start := 0; start < len(pending); start += maxDeleteObjects {
+		end := start + maxDeleteObjects
+		if end > len(pending) {
+			end = len(pending)
+		}
+		chunk := pending[start:end]
+
+		if ctx.Err() != nil {
+			for _, i := range chunk {
+				items[i].Error = ctx.Err()
+			}
+			continue
+		}
+		s.deleteChunk(ctx, items, chunk)
+	}
+
+	res := &goseidon.DeleteFilesResult{
+		Items: items,
+	}
+	return res, nil
+}
+
+// deleteChunk delete items at the given indexes with a single request,
+// a failed request is reported on every item of the chunk
+func (s *AwsS3Storage) deleteChunk(ctx context.Context, items []goseidon.DeleteFileItem, chunk []int) {
+	objects := []*s3.ObjectIdentifier{}
+	for _, i := range chunk {
+		objects = append(objects, &s3.ObjectIdentifier{
+			Key: aws.String(items[i].Id),
+		})
+	}
+
+	// the request timeout bound every chunk on its own, not the whole batch
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	// quiet mode only report the failed keys back
+	out, err := s.Client.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+		Bucket: aws.String(s.Config.BucketName),
+		Delete: &s3.Delete{
+			Objects: objects,
+			Quiet:   aws.Bool(true),
+		},
+	}, s.requestOptions()...)
+	if err != nil {
+		err = mapError(err)
+		for _, i := range chunk {
+			items[i].Error = err
+		}
+		return
+	}
+
+	failed := map[string]error{}
+	for _, e := range out.Errors {
+		failed[aws.StringValue(e.Key)] = mapError(awserr.New(aws.StringValue(e.Code), aws.StringValue(e.Message), nil))
+	}
+
+	deletedAt := s.Clock.Now()
+	for _, i := range chunk {
+		err, ok := failed[items[i].Id]
+		if ok {
+			items[i].Error = err
+			continue
+		}
+		items[i].DeletedAt = deletedAt
+	}
+}
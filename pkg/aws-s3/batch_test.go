@@ -0,0 +1,186 @@
+package aws_s3_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+	awsmock "github.com/go-seidon/core/internal/aws"
+	"github.com/go-seidon/core/internal/clock"
+	aws_s3 "github.com/go-seidon/core/pkg/aws-s3"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batch", func() {
+	var (
+		ctx         context.Context
+		s           *aws_s3.AwsS3Storage
+		cfg         *aws_s3.AwsS3Config
+		cl          *awsmock.MockAwsS3Client
+		clo         *clock.MockClock
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		cl = awsmock.NewMockAwsS3Client(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		cfg = &aws_s3.AwsS3Config{
+			BucketName: "mock-bucket-name",
+		}
+		currentTime = time.Now()
+		s = &aws_s3.AwsS3Storage{
+			Config: cfg,
+			Client: cl,
+			Clock:  clo,
+		}
+	})
+
+	Context("DeleteFiles method", func() {
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.DeleteFiles(nil, goseidon.DeleteFilesParam{Ids: []string{"a"}})
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("ids are empty", func() {
+			It("should return error", func() {
+				res, err := s.DeleteFiles(ctx, goseidon.DeleteFilesParam{})
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("some keys fail", func() {
+			It("should report each key outcome", func() {
+				param := &s3.DeleteObjectsInput{
+					Bucket: aws.String(cfg.BucketName),
+					Delete: &s3.Delete{
+						Objects: []*s3.ObjectIdentifier{
+							{Key: aws.String("a.jpg")},
+							{Key: aws.String("b.jpg")},
+						},
+						Quiet: aws.Bool(true),
+					},
+				}
+				cl.EXPECT().
+					DeleteObjectsWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(&s3.DeleteObjectsOutput{
+						Errors: []*s3.Error{
+							{Key: aws.String("b.jpg"), Code: aws.String("AccessDenied"), Message: aws.String("Access Denied")},
+						},
+					}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.DeleteFiles(ctx, goseidon.DeleteFilesParam{Ids: []string{"a.jpg", "../c.jpg", "b.jpg"}})
+
+				Expect(err).To(BeNil())
+				Expect(res.Items).To(HaveLen(3))
+				Expect(res.Items[0]).To(Equal(goseidon.DeleteFileItem{Id: "a.jpg", DeletedAt: currentTime}))
+				Expect(errors.Is(res.Items[1].Error, goseidon.ErrInvalidArgument)).To(BeTrue())
+				Expect(res.Items[2].Id).To(Equal("b.jpg"))
+				Expect(errors.Is(res.Items[2].Error, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("there are more than 1000 keys", func() {
+			It("should delete them in chunks", func() {
+				ids := []string{}
+				for i := 0; i < 1500; i++ {
+					ids = append(ids, fmt.Sprintf("file-%d", i))
+				}
+				cl.EXPECT().
+					DeleteObjectsWithContext(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx aws.Context, p *s3.DeleteObjectsInput, opts ...request.Option) (*s3.DeleteObjectsOutput, error) {
+						Expect(p.Delete.Objects).To(HaveLen(1000))
+						return &s3.DeleteObjectsOutput{}, nil
+					}).
+					Times(1)
+				cl.EXPECT().
+					DeleteObjectsWithContext(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(ctx aws.Context, p *s3.DeleteObjectsInput, opts ...request.Option) (*s3.DeleteObjectsOutput, error) {
+						Expect(p.Delete.Objects).To(HaveLen(500))
+						Expect(aws.StringValue(p.Delete.Objects[0].Key)).To(Equal("file-1000"))
+						return nil, fmt.Errorf("network error")
+					}).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.DeleteFiles(ctx, goseidon.DeleteFilesParam{Ids: ids})
+
+				Expect(err).To(BeNil())
+				Expect(res.Items[999].Error).To(BeNil())
+				Expect(res.Items[1000].Error).To(Equal(fmt.Errorf("network error")))
+				Expect(res.Items[1499].Error).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+
+		When("request timeout is configured", func() {
+			It("should bound every chunk on its own", func() {
+				cfg.RequestTimeout = time.Minute
+				ids := []string{}
+				for i := 0; i < 1001; i++ {
+					ids = append(ids, fmt.Sprintf("file-%d", i))
+				}
+				calls := []aws.Context{}
+				cl.EXPECT().
+					DeleteObjectsWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx aws.Context, p *s3.DeleteObjectsInput, opts ...request.Option) (*s3.DeleteObjectsOutput, error) {
+						_, ok := ctx.Deadline()
+						Expect(ok).To(BeTrue())
+						Expect(ctx.Err()).To(BeNil())
+						calls = append(calls, ctx)
+						return &s3.DeleteObjectsOutput{}, nil
+					}).
+					Times(2)
+				clo.EXPECT().Now().Return(currentTime).Times(2)
+
+				res, err := s.DeleteFiles(ctx, goseidon.DeleteFilesParam{Ids: ids})
+
+				Expect(err).To(BeNil())
+				Expect(res.Items[1000].Error).To(BeNil())
+				Expect(calls).To(HaveLen(2))
+				Expect(calls[0]).ToNot(BeIdenticalTo(calls[1]))
+				Expect(calls[0].Err()).To(Equal(context.Canceled))
+			})
+		})
+
+		When("context is cancelled midway", func() {
+			It("should skip the remaining chunks", func() {
+				cctx, cancel := context.WithCancel(ctx)
+				ids := []string{}
+				for i := 0; i < 1001; i++ {
+					ids = append(ids, fmt.Sprintf("file-%d", i))
+				}
+				cl.EXPECT().
+					DeleteObjectsWithContext(gomock.Eq(cctx), gomock.Any()).
+					DoAndReturn(func(ctx aws.Context, p *s3.DeleteObjectsInput, opts ...request.Option) (*s3.DeleteObjectsOutput, error) {
+						cancel()
+						return &s3.DeleteObjectsOutput{}, nil
+					}).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.DeleteFiles(cctx, goseidon.DeleteFilesParam{Ids: ids})
+
+				Expect(err).To(BeNil())
+				Expect(res.Items[999].Error).To(BeNil())
+				Expect(res.Items[1000].Error).To(Equal(context.Canceled))
+			})
+		})
+	})
+})
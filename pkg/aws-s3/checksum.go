@@ -0,0 +1,46 @@
+package aws_s3
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/aws/aws-sdk-go/aws"
+	goseidon "github.com/go-seidon/core"
+)
+
+// encodeDigest convert hex digest into the base64 form used by s3 headers
+func encodeDigest(digest string) *string {
+	if digest == "" {
+		return nil
+	}
+	raw, err := hex.DecodeString(digest)
+	if err != nil {
+		return nil
+	}
+	return aws.String(base64.StdEncoding.EncodeToString(raw))
+}
+
+// decodeDigest convert base64 digest of s3 headers into hex,
+// composite checksum of multipart object ("<digest>-<parts>") is dropped
+func decodeDigest(digest *string) string {
+	raw, err := base64.StdEncoding.DecodeString(aws.StringValue(digest))
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(raw)
+}
+
+func parseChecksum(etag, crc32c, sha256 *string) goseidon.Checksum {
+	return goseidon.Checksum{
+		MD5:    parseETag(etag),
+		CRC32C: decodeDigest(crc32c),
+		SHA256: decodeDigest(sha256),
+	}
+}
+
+// verifiableChecksum drop the md5 taken from the etag, the etag of
+// kms encrypted object looks like an md5 but isn't one
+func verifiableChecksum(checksum goseidon.Checksum) goseidon.Checksum {
+	checksum.MD5 = ""
+	return checksum
+}
@@ -0,0 +1,161 @@
+package aws_s3_test
+
+import (
+	"context"
+	"errors"
+	goio "io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+	awsmock "github.com/go-seidon/core/internal/aws"
+	"github.com/go-seidon/core/internal/clock"
+	aws_s3 "github.com/go-seidon/core/pkg/aws-s3"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checksum", func() {
+	var (
+		ctx         context.Context
+		s           *aws_s3.AwsS3Storage
+		cfg         *aws_s3.AwsS3Config
+		cl          *awsmock.MockAwsS3Client
+		clo         *clock.MockClock
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		cl = awsmock.NewMockAwsS3Client(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		cfg = &aws_s3.AwsS3Config{
+			BucketName: "mock-bucket-name",
+		}
+		currentTime = time.Now()
+		s = &aws_s3.AwsS3Storage{
+			Config: cfg,
+			Client: cl,
+			Clock:  clo,
+		}
+	})
+
+	Context("UploadStream method", func() {
+		When("seekable content doesn't match given checksum", func() {
+			It("should return error without sending it", func() {
+				res, err := s.UploadStream(ctx, goseidon.UploadStreamParam{
+					FileId:   "mock-file-id",
+					FileData: strings.NewReader("content"),
+					Checksum: goseidon.Checksum{
+						MD5: "d41d8cd98f00b204e9800998ecf8427e",
+					},
+				})
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrChecksumMismatch)).To(BeTrue())
+			})
+		})
+
+		When("unseekable content has a given checksum", func() {
+			It("should send the given digests", func() {
+				cl.EXPECT().
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Do(func(ctx aws.Context, p *s3.PutObjectInput, opts ...request.Option) {
+						Expect(p.ContentMD5).To(Equal(aws.String("mgNkuembtIDdJeHwKEyFVQ==")))
+						Expect(p.ChecksumSHA256).To(BeNil())
+						_, err := goio.Copy(goio.Discard, p.Body)
+						Expect(err).To(BeNil())
+					}).
+					Return(&s3.PutObjectOutput{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				_, err := s.UploadStream(ctx, goseidon.UploadStreamParam{
+					FileId:   "mock-file-id",
+					FileData: goio.NopCloser(strings.NewReader("content")),
+					FileSize: 7,
+					Checksum: goseidon.Checksum{
+						MD5: "9a0364b9e99bb480dd25e1f0284c8555",
+					},
+				})
+
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("RetrieveFile method", func() {
+		When("checksum is verified on a range", func() {
+			It("should return error", func() {
+				res, err := s.RetrieveFile(ctx, goseidon.RetrieveFileParam{
+					Id:     "mock-file-id",
+					Offset: 10,
+					Verify: true,
+				})
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("content doesn't match stored checksum", func() {
+			It("should return checksum mismatch error", func() {
+				out := &s3.GetObjectOutput{
+					Body:           goio.NopCloser(strings.NewReader("corrupt")),
+					ContentLength:  aws.Int64(7),
+					ChecksumSHA256: aws.String("7XACtDnprIRfIjV9giusFERzD722AW0+yUMil7nsn3M="),
+				}
+				cl.EXPECT().
+					GetObjectWithContext(gomock.Eq(ctx), gomock.Any()).
+					Return(out, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveFile(ctx, goseidon.RetrieveFileParam{
+					Id:     "mock-file-id",
+					Verify: true,
+				})
+
+				Expect(res).To(BeNil())
+				var mismatch *goseidon.ChecksumMismatchError
+				Expect(errors.As(err, &mismatch)).To(BeTrue())
+				Expect(mismatch.Algorithm).To(Equal("sha256"))
+			})
+		})
+
+		When("content match stored checksum", func() {
+			It("should ignore the etag and return the checksum", func() {
+				out := &s3.GetObjectOutput{
+					Body:           goio.NopCloser(strings.NewReader("content")),
+					ContentLength:  aws.Int64(7),
+					ETag:           aws.String(`"0123456789abcdef0123456789abcdef"`),
+					ChecksumSHA256: aws.String("7XACtDnprIRfIjV9giusFERzD722AW0+yUMil7nsn3M="),
+				}
+				cl.EXPECT().
+					GetObjectWithContext(gomock.Eq(ctx), gomock.Any()).
+					Return(out, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveFile(ctx, goseidon.RetrieveFileParam{
+					Id:     "mock-file-id",
+					Verify: true,
+				})
+
+				eChecksum := goseidon.Checksum{
+					MD5:    "0123456789abcdef0123456789abcdef",
+					SHA256: "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73",
+				}
+				Expect(err).To(BeNil())
+				Expect(string(res.File)).To(Equal("content"))
+				Expect(res.Checksum).To(Equal(eChecksum))
+			})
+		})
+	})
+})
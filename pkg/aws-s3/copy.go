@@ -0,0 +1,106 @@
+package aws_s3
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+)
+
+// CopyFile copy an object inside the bucket without downloading it,
+// metadata is copied as is. s3 refuse single request copies over 5GB
+func (s *AwsS3Storage) CopyFile(ctx context.Context, p goseidon.CopyFileParam) (*goseidon.CopyFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateCopy(p.SourceId, p.DestinationId)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.CopyObjectInput{
+		Bucket:            aws.String(s.Config.BucketName),
+		Key:               aws.String(p.DestinationId),
+		CopySource:        aws.String(copySource(s.Config.BucketName, p.SourceId)),
+		MetadataDirective: aws.String(s3.MetadataDirectiveCopy),
+	}
+
+	headers := map[string]string{}
+	if p.Overwrite != goseidon.OverwriteReplace {
+		headers["If-None-Match"] = "*"
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	_, err = s.Client.CopyObjectWithContext(ctx, input, s.requestOptions(request.WithSetRequestHeaders(headers))...)
+	if isPreconditionFailed(err) {
+		if p.Overwrite == goseidon.OverwriteSkip {
+			res := &goseidon.CopyFileResult{
+				SourceId:      p.SourceId,
+				DestinationId: p.DestinationId,
+				CopiedAt:      s.Clock.Now(),
+				Skipped:       true,
+			}
+			return res, nil
+		}
+		return nil, goseidon.NewError(goseidon.ErrAlreadyExists, err)
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.CopyFileResult{
+		SourceId:      p.SourceId,
+		DestinationId: p.DestinationId,
+		CopiedAt:      s.Clock.Now(),
+	}
+	return res, nil
+}
+
+// MoveFile copy the object then delete the source,
+// s3 has no rename so a failed delete leaves both objects behind
+func (s *AwsS3Storage) MoveFile(ctx context.Context, p goseidon.MoveFileParam) (*goseidon.MoveFileResult, error) {
+	copyRes, err := s.CopyFile(ctx, goseidon.CopyFileParam{
+		SourceId:      p.SourceId,
+		DestinationId: p.DestinationId,
+		Overwrite:     p.Overwrite,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &goseidon.MoveFileResult{
+		SourceId:      p.SourceId,
+		DestinationId: p.DestinationId,
+		MovedAt:       copyRes.CopiedAt,
+		Skipped:       copyRes.Skipped,
+	}
+	if copyRes.Skipped {
+		return res, nil
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	_, err = s.Client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.Config.BucketName),
+		Key:    aws.String(p.SourceId),
+	}, s.requestOptions()...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return res, nil
+}
+
+// copySource build the url encoded "bucket/key" expected by CopyObject
+func copySource(bucket, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return bucket + "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,252 @@
+package aws_s3_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+	awsmock "github.com/go-seidon/core/internal/aws"
+	"github.com/go-seidon/core/internal/clock"
+	aws_s3 "github.com/go-seidon/core/pkg/aws-s3"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Copy", func() {
+	var (
+		ctx         context.Context
+		s           *aws_s3.AwsS3Storage
+		cfg         *aws_s3.AwsS3Config
+		cl          *awsmock.MockAwsS3Client
+		clo         *clock.MockClock
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		cl = awsmock.NewMockAwsS3Client(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		cfg = &aws_s3.AwsS3Config{
+			BucketName: "mock-bucket-name",
+		}
+		currentTime = time.Now()
+		s = &aws_s3.AwsS3Storage{
+			Config: cfg,
+			Client: cl,
+			Clock:  clo,
+		}
+	})
+
+	Context("CopyFile method", func() {
+		var p goseidon.CopyFileParam
+
+		BeforeEach(func() {
+			p = goseidon.CopyFileParam{
+				SourceId:      "photos/summer 2022.jpg",
+				DestinationId: "backup/summer.jpg",
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.CopyFile(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("source and destination are the same file", func() {
+			It("should return error", func() {
+				p.DestinationId = p.SourceId
+				res, err := s.CopyFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("source doesn't exist", func() {
+			It("should return not found error", func() {
+				reqErr := awserr.NewRequestFailure(awserr.New(s3.ErrCodeNoSuchKey, "", nil), http.StatusNotFound, "mock-request-id")
+				cl.EXPECT().
+					CopyObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.CopyObjectOutput{}, reqErr).
+					Times(1)
+
+				res, err := s.CopyFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrNotFound)).To(BeTrue())
+			})
+		})
+
+		When("destination already exists", func() {
+			It("should return already exists error", func() {
+				reqErr := awserr.NewRequestFailure(awserr.New("PreconditionFailed", "", nil), http.StatusPreconditionFailed, "mock-request-id")
+				cl.EXPECT().
+					CopyObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.CopyObjectOutput{}, reqErr).
+					Times(1)
+
+				res, err := s.CopyFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrAlreadyExists)).To(BeTrue())
+			})
+		})
+
+		When("destination already exists and overwrite mode is skip", func() {
+			It("should return skipped result", func() {
+				p.Overwrite = goseidon.OverwriteSkip
+				reqErr := awserr.NewRequestFailure(awserr.New("PreconditionFailed", "", nil), http.StatusPreconditionFailed, "mock-request-id")
+				cl.EXPECT().
+					CopyObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.CopyObjectOutput{}, reqErr).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CopyFile(ctx, p)
+
+				eRes := &goseidon.CopyFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					CopiedAt:      currentTime,
+					Skipped:       true,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("success copy file", func() {
+			It("should copy object with its metadata", func() {
+				param := &s3.CopyObjectInput{
+					Bucket:            aws.String(cfg.BucketName),
+					Key:               aws.String(p.DestinationId),
+					CopySource:        aws.String("mock-bucket-name/photos/summer%202022.jpg"),
+					MetadataDirective: aws.String(s3.MetadataDirectiveCopy),
+				}
+				req := newRequest(param, &s3.CopyObjectOutput{}, nil)
+				cl.EXPECT().
+					CopyObjectWithContext(gomock.Eq(ctx), gomock.Eq(param), gomock.Any()).
+					Do(applyOptions(req)).
+					Return(&s3.CopyObjectOutput{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CopyFile(ctx, p)
+
+				eRes := &goseidon.CopyFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					CopiedAt:      currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(req.HTTPRequest.Header.Get("If-None-Match")).To(Equal("*"))
+			})
+		})
+
+		When("overwrite mode is replace", func() {
+			It("should copy object unconditionally", func() {
+				p.Overwrite = goseidon.OverwriteReplace
+				req := newRequest(&s3.CopyObjectInput{}, &s3.CopyObjectOutput{}, nil)
+				cl.EXPECT().
+					CopyObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Do(applyOptions(req)).
+					Return(&s3.CopyObjectOutput{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				_, err := s.CopyFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(req.HTTPRequest.Header.Get("If-None-Match")).To(Equal(""))
+			})
+		})
+	})
+
+	Context("MoveFile method", func() {
+		var p goseidon.MoveFileParam
+
+		BeforeEach(func() {
+			p = goseidon.MoveFileParam{
+				SourceId:      "image.jpg",
+				DestinationId: "archive/image.jpg",
+			}
+		})
+
+		When("copy is skipped", func() {
+			It("should keep the source", func() {
+				p.Overwrite = goseidon.OverwriteSkip
+				reqErr := awserr.NewRequestFailure(awserr.New("PreconditionFailed", "", nil), http.StatusPreconditionFailed, "mock-request-id")
+				cl.EXPECT().
+					CopyObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.CopyObjectOutput{}, reqErr).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.MoveFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Skipped).To(BeTrue())
+			})
+		})
+
+		When("failed delete source", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					CopyObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.CopyObjectOutput{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+				cl.EXPECT().
+					DeleteObjectWithContext(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := s.MoveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("success move file", func() {
+			It("should delete the source", func() {
+				cl.EXPECT().
+					CopyObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.CopyObjectOutput{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+				cl.EXPECT().
+					DeleteObjectWithContext(gomock.Eq(ctx), gomock.Eq(&s3.DeleteObjectInput{
+						Bucket: aws.String(cfg.BucketName),
+						Key:    aws.String(p.SourceId),
+					})).
+					Return(&s3.DeleteObjectOutput{}, nil).
+					Times(1)
+
+				res, err := s.MoveFile(ctx, p)
+
+				eRes := &goseidon.MoveFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					MovedAt:       currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,100 @@
+package aws_s3
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+)
+
+var errorCodes = map[string]error{
+	s3.ErrCodeNoSuchKey:    goseidon.ErrNotFound,
+	s3.ErrCodeNoSuchBucket: goseidon.ErrNotFound,
+	s3.ErrCodeNoSuchUpload: goseidon.ErrNotFound,
+	"NoSuchVersion":        goseidon.ErrNotFound,
+	"NotFound":             goseidon.ErrNotFound,
+
+	"AccessDenied":          goseidon.ErrPermission,
+	"AllAccessDisabled":     goseidon.ErrPermission,
+	"Forbidden":             goseidon.ErrPermission,
+	"InvalidAccessKeyId":    goseidon.ErrPermission,
+	"SignatureDoesNotMatch": goseidon.ErrPermission,
+	"ExpiredToken":          goseidon.ErrPermission,
+
+	"InvalidArgument":               goseidon.ErrInvalidArgument,
+	"InvalidRequest":                goseidon.ErrInvalidArgument,
+	"InvalidRange":                  goseidon.ErrInvalidArgument,
+	"KeyTooLongError":               goseidon.ErrInvalidArgument,
+	"EntityTooLarge":                goseidon.ErrInvalidArgument,
+	"EntityTooSmall":                goseidon.ErrInvalidArgument,
+	request.InvalidParameterErrCode: goseidon.ErrInvalidArgument,
+	request.ParamRequiredErrCode:    goseidon.ErrInvalidArgument,
+
+	"BadDigest":                 goseidon.ErrChecksumMismatch,
+	"InvalidDigest":             goseidon.ErrChecksumMismatch,
+	"XAmzContentSHA256Mismatch": goseidon.ErrChecksumMismatch,
+
+	"SlowDown":                     goseidon.ErrTransient,
+	"ConditionalRequestConflict":   goseidon.ErrTransient,
+	"ServiceUnavailable":           goseidon.ErrTransient,
+	"InternalError":                goseidon.ErrTransient,
+	"RequestTimeout":               goseidon.ErrTransient,
+	"Throttling":                   goseidon.ErrTransient,
+	"ThrottlingException":          goseidon.ErrTransient,
+	"RequestLimitExceeded":         goseidon.ErrTransient,
+	request.ErrCodeRequestError:    goseidon.ErrTransient,
+	request.ErrCodeResponseTimeout: goseidon.ErrTransient,
+}
+
+// mapError classify aws sdk error using its error code,
+// falling back to the http status code of the failed request
+func mapError(err error) error {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return err
+	}
+
+	// a cancelled or timed out call keep the context error, so errors.Is can match it
+	if aerr.Code() == request.CanceledErrorCode && aerr.OrigErr() != nil {
+		return fmt.Errorf("%s: %w", aerr.Message(), aerr.OrigErr())
+	}
+
+	kind, ok := errorCodes[aerr.Code()]
+	if ok {
+		return goseidon.NewError(kind, err)
+	}
+
+	var rerr awserr.RequestFailure
+	if !errors.As(err, &rerr) {
+		return err
+	}
+
+	switch status := rerr.StatusCode(); {
+	case status == http.StatusNotFound:
+		return goseidon.NewError(goseidon.ErrNotFound, err)
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return goseidon.NewError(goseidon.ErrPermission, err)
+	case status == http.StatusBadRequest:
+		return goseidon.NewError(goseidon.ErrInvalidArgument, err)
+	case status == http.StatusTooManyRequests, status >= http.StatusInternalServerError:
+		return goseidon.NewError(goseidon.ErrTransient, err)
+	}
+	return err
+}
+
+// isPreconditionFailed report whether a conditional request is rejected
+func isPreconditionFailed(err error) bool {
+	var rerr awserr.RequestFailure
+	if errors.As(err, &rerr) {
+		return rerr.StatusCode() == http.StatusPreconditionFailed
+	}
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		return aerr.Code() == "PreconditionFailed"
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package aws_s3_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+	awsmock "github.com/go-seidon/core/internal/aws"
+	aws_s3 "github.com/go-seidon/core/pkg/aws-s3"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Error", func() {
+	var (
+		ctx context.Context
+		s   *aws_s3.AwsS3Storage
+		cl  *awsmock.MockAwsS3Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		cl = awsmock.NewMockAwsS3Client(ctrl)
+		s = &aws_s3.AwsS3Storage{
+			Config: &aws_s3.AwsS3Config{
+				BucketName: "mock-bucket-name",
+			},
+			Client: cl,
+		}
+	})
+
+	DescribeTable("aws error is classified",
+		func(cause error, kind error) {
+			cl.EXPECT().
+				DeleteObjectWithContext(gomock.Eq(ctx), gomock.Any()).
+				Return(nil, cause).
+				Times(1)
+
+			res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "mock-file-id"})
+
+			Expect(res).To(BeNil())
+			Expect(errors.Is(err, kind)).To(BeTrue())
+
+			var aerr awserr.Error
+			Expect(errors.As(err, &aerr)).To(BeTrue())
+		},
+		Entry("no such key", awserr.New(s3.ErrCodeNoSuchKey, "", nil), goseidon.ErrNotFound),
+		Entry("no such bucket", awserr.New(s3.ErrCodeNoSuchBucket, "", nil), goseidon.ErrNotFound),
+		Entry("access denied", awserr.New("AccessDenied", "", nil), goseidon.ErrPermission),
+		Entry("invalid argument", awserr.New("InvalidArgument", "", nil), goseidon.ErrInvalidArgument),
+		Entry("missing parameter", awserr.New(request.ParamRequiredErrCode, "", nil), goseidon.ErrInvalidArgument),
+		Entry("bad digest", awserr.New("BadDigest", "", nil), goseidon.ErrChecksumMismatch),
+		Entry("invalid digest", awserr.New("InvalidDigest", "", nil), goseidon.ErrChecksumMismatch),
+		Entry("sha256 mismatch", awserr.New("XAmzContentSHA256Mismatch", "", nil), goseidon.ErrChecksumMismatch),
+		Entry("slow down", awserr.New("SlowDown", "", nil), goseidon.ErrTransient),
+		Entry("conditional request conflict", awserr.New("ConditionalRequestConflict", "", nil), goseidon.ErrTransient),
+		Entry("network failure", awserr.New(request.ErrCodeRequestError, "", nil), goseidon.ErrTransient),
+		Entry("status not found", newRequestFailure(http.StatusNotFound), goseidon.ErrNotFound),
+		Entry("status forbidden", newRequestFailure(http.StatusForbidden), goseidon.ErrPermission),
+		Entry("status bad request", newRequestFailure(http.StatusBadRequest), goseidon.ErrInvalidArgument),
+		Entry("status too many requests", newRequestFailure(http.StatusTooManyRequests), goseidon.ErrTransient),
+		Entry("status service unavailable", newRequestFailure(http.StatusServiceUnavailable), goseidon.ErrTransient),
+	)
+
+	When("aws error is not recognized", func() {
+		It("should return unclassified error", func() {
+			cause := newRequestFailure(http.StatusConflict)
+			cl.EXPECT().
+				DeleteObjectWithContext(gomock.Eq(ctx), gomock.Any()).
+				Return(nil, cause).
+				Times(1)
+
+			res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "mock-file-id"})
+
+			Expect(res).To(BeNil())
+			Expect(err).To(Equal(cause))
+		})
+	})
+
+	When("aws error code is unknown and status is absent", func() {
+		It("should return unclassified error", func() {
+			cause := awserr.New("UnknownCode", "", nil)
+			cl.EXPECT().
+				DeleteObjectWithContext(gomock.Eq(ctx), gomock.Any()).
+				Return(nil, cause).
+				Times(1)
+
+			res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "mock-file-id"})
+
+			Expect(res).To(BeNil())
+			Expect(err).To(Equal(cause))
+		})
+	})
+
+	DescribeTable("cancelled request keep the context error",
+		func(cause error) {
+			cl.EXPECT().
+				DeleteObjectWithContext(gomock.Eq(ctx), gomock.Any()).
+				Return(nil, awserr.New(request.CanceledErrorCode, "request context canceled", cause)).
+				Times(1)
+
+			res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "mock-file-id"})
+
+			Expect(res).To(BeNil())
+			Expect(err.Error()).To(Equal("request context canceled: " + cause.Error()))
+			Expect(errors.Is(err, cause)).To(BeTrue())
+			Expect(errors.Is(err, goseidon.ErrTransient)).To(BeFalse())
+		},
+		Entry("context canceled", context.Canceled),
+		Entry("deadline exceeded", context.DeadlineExceeded),
+	)
+
+	When("error is not an aws error", func() {
+		It("should return unclassified error", func() {
+			cause := fmt.Errorf("unknown")
+			cl.EXPECT().
+				DeleteObjectWithContext(gomock.Eq(ctx), gomock.Any()).
+				Return(nil, cause).
+				Times(1)
+
+			res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "mock-file-id"})
+
+			Expect(res).To(BeNil())
+			Expect(err).To(Equal(cause))
+		})
+	})
+})
+
+func newRequestFailure(status int) error {
+	return awserr.NewRequestFailure(awserr.New("UnknownCode", "", nil), status, "mock-request-id")
+}
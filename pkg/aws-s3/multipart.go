@@ -0,0 +1,265 @@
+package aws_s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+)
+
+func (s *AwsS3Storage) CreateMultipartUpload(ctx context.Context, p goseidon.CreateMultipartParam) (*goseidon.CreateMultipartResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateKey(p.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:   aws.String(s.Config.BucketName),
+		Key:      aws.String(p.FileId),
+		Metadata: buildMetadata(p.FileName, p.Metadata),
+	}
+	if p.ContentType != "" {
+		input.ContentType = aws.String(p.ContentType)
+	}
+	if p.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(p.ContentDisposition)
+	}
+	if p.CacheControl != "" {
+		input.CacheControl = aws.String(p.CacheControl)
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	out, err := s.Client.CreateMultipartUploadWithContext(ctx, input, s.requestOptions()...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.CreateMultipartResult{
+		UploadId:  aws.StringValue(out.UploadId),
+		FileId:    p.FileId,
+		CreatedAt: s.Clock.Now(),
+	}
+	return res, nil
+}
+
+// UploadPart send a single part, s3 reject a non last part smaller than 5 MiB on completion
+func (s *AwsS3Storage) UploadPart(ctx context.Context, p goseidon.UploadPartParam) (*goseidon.UploadPartResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	if p.PartData == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid part data"))
+	}
+	err := validateUpload(p.FileId, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+	err = goseidon.ValidatePartNumber(p.PartNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.UploadPartInput{
+		Bucket:     aws.String(s.Config.BucketName),
+		Key:        aws.String(p.FileId),
+		UploadId:   aws.String(p.UploadId),
+		PartNumber: aws.Int64(int64(p.PartNumber)),
+	}
+
+	headers := map[string]string{}
+	size := p.PartSize
+	body, seekable := p.PartData.(io.ReadSeeker)
+	if seekable {
+		input.Body = body
+		if size <= 0 {
+			size, err = aws.SeekerLen(body)
+			if err != nil {
+				return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("failed get part size: %w", err))
+			}
+		}
+	} else {
+		// same as UploadStream, unseekable part is sent unsigned with a known length
+		if p.PartSize <= 0 {
+			return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("part size is required for unseekable part data"))
+		}
+		input.Body = aws.ReadSeekCloser(p.PartData)
+		input.ContentLength = aws.Int64(p.PartSize)
+		headers["X-Amz-Content-Sha256"] = "UNSIGNED-PAYLOAD"
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	out, err := s.Client.UploadPartWithContext(ctx, input, s.requestOptions(request.WithSetRequestHeaders(headers))...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.UploadPartResult{
+		PartNumber: p.PartNumber,
+		ETag:       strings.Trim(aws.StringValue(out.ETag), `"`),
+		Size:       size,
+		UploadedAt: s.Clock.Now(),
+	}
+	return res, nil
+}
+
+func (s *AwsS3Storage) CompleteMultipartUpload(ctx context.Context, p goseidon.CompleteMultipartParam) (*goseidon.UploadFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := validateUpload(p.FileId, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+	err = goseidon.ValidateCompletedParts(p.Parts)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := []*s3.CompletedPart{}
+	for _, part := range p.Parts {
+		parts = append(parts, &s3.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int64(int64(part.PartNumber)),
+		})
+	}
+	input := &s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String(s.Config.BucketName),
+		Key:      aws.String(p.FileId),
+		UploadId: aws.String(p.UploadId),
+		MultipartUpload: &s3.CompletedMultipartUpload{
+			Parts: parts,
+		},
+	}
+
+	headers := map[string]string{}
+	if p.Overwrite != goseidon.OverwriteReplace {
+		headers["If-None-Match"] = "*"
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	out, err := s.Client.CompleteMultipartUploadWithContext(ctx, input, s.requestOptions(request.WithSetRequestHeaders(headers))...)
+	if isPreconditionFailed(err) {
+		if p.Overwrite == goseidon.OverwriteSkip {
+			// the upload is kept by s3 after a rejected completion
+			s.Client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(s.Config.BucketName),
+				Key:      aws.String(p.FileId),
+				UploadId: aws.String(p.UploadId),
+			}, s.requestOptions()...)
+			res := &goseidon.UploadFileResult{
+				FileId:     p.FileId,
+				UploadedAt: s.Clock.Now(),
+				Skipped:    true,
+			}
+			return res, nil
+		}
+		return nil, goseidon.NewError(goseidon.ErrAlreadyExists, err)
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.UploadFileResult{
+		FileId:     p.FileId,
+		UploadedAt: s.Clock.Now(),
+		VersionId:  aws.StringValue(out.VersionId),
+	}
+	return res, nil
+}
+
+func (s *AwsS3Storage) AbortMultipartUpload(ctx context.Context, p goseidon.AbortMultipartParam) (*goseidon.AbortMultipartResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := validateUpload(p.FileId, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	_, err = s.Client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.Config.BucketName),
+		Key:      aws.String(p.FileId),
+		UploadId: aws.String(p.UploadId),
+	}, s.requestOptions()...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.AbortMultipartResult{
+		UploadId:  p.UploadId,
+		AbortedAt: s.Clock.Now(),
+	}
+	return res, nil
+}
+
+// ListParts return every uploaded part, following the part number marker across pages
+func (s *AwsS3Storage) ListParts(ctx context.Context, p goseidon.ListPartsParam) (*goseidon.ListPartsResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := validateUpload(p.FileId, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.ListPartsInput{
+		Bucket:   aws.String(s.Config.BucketName),
+		Key:      aws.String(p.FileId),
+		UploadId: aws.String(p.UploadId),
+	}
+	res := &goseidon.ListPartsResult{
+		Parts: []goseidon.PartItem{},
+	}
+	for {
+		out, err := s.listPartsPage(ctx, input)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		for _, part := range out.Parts {
+			res.Parts = append(res.Parts, goseidon.PartItem{
+				PartNumber:   int(aws.Int64Value(part.PartNumber)),
+				ETag:         strings.Trim(aws.StringValue(part.ETag), `"`),
+				Size:         aws.Int64Value(part.Size),
+				LastModified: aws.TimeValue(part.LastModified),
+			})
+		}
+		if !aws.BoolValue(out.IsTruncated) {
+			break
+		}
+		input.PartNumberMarker = out.NextPartNumberMarker
+	}
+	return res, nil
+}
+
+func validateUpload(fileId, uploadId string) error {
+	err := goseidon.ValidateKey(fileId)
+	if err != nil {
+		return err
+	}
+	if uploadId == "" {
+		return goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid upload id"))
+	}
+	return nil
+}
+
+// listPartsPage fetch a single page of parts,
+// the request timeout bound every page on its own, not the whole listing
+func (s *AwsS3Storage) listPartsPage(ctx context.Context, input *s3.ListPartsInput) (*s3.ListPartsOutput, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.Client.ListPartsWithContext(ctx, input, s.requestOptions()...)
+}
@@ -0,0 +1,473 @@
+package aws_s3_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+	awsmock "github.com/go-seidon/core/internal/aws"
+	"github.com/go-seidon/core/internal/clock"
+	aws_s3 "github.com/go-seidon/core/pkg/aws-s3"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Multipart", func() {
+	var (
+		ctx         context.Context
+		s           *aws_s3.AwsS3Storage
+		cfg         *aws_s3.AwsS3Config
+		cl          *awsmock.MockAwsS3Client
+		clo         *clock.MockClock
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		cl = awsmock.NewMockAwsS3Client(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		cfg = &aws_s3.AwsS3Config{
+			BucketName: "mock-bucket-name",
+		}
+		currentTime = time.Now()
+		s = &aws_s3.AwsS3Storage{
+			Config: cfg,
+			Client: cl,
+			Clock:  clo,
+		}
+	})
+
+	Context("CreateMultipartUpload method", func() {
+		var p goseidon.CreateMultipartParam
+
+		BeforeEach(func() {
+			p = goseidon.CreateMultipartParam{
+				FileId:      "mock-file-id",
+				FileName:    "video.mp4",
+				ContentType: "video/mp4",
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.CreateMultipartUpload(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("file id is invalid", func() {
+			It("should return error", func() {
+				p.FileId = ""
+				res, err := s.CreateMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("failed create upload", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					CreateMultipartUploadWithContext(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := s.CreateMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+
+		When("success create upload", func() {
+			It("should return result", func() {
+				param := &s3.CreateMultipartUploadInput{
+					Bucket:      aws.String(cfg.BucketName),
+					Key:         aws.String(p.FileId),
+					ContentType: aws.String("video/mp4"),
+					Metadata: map[string]*string{
+						goseidon.FileNameMetadataKey: aws.String("video.mp4"),
+					},
+				}
+				cl.EXPECT().
+					CreateMultipartUploadWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(&s3.CreateMultipartUploadOutput{UploadId: aws.String("mock-upload-id")}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CreateMultipartUpload(ctx, p)
+
+				eRes := &goseidon.CreateMultipartResult{
+					UploadId:  "mock-upload-id",
+					FileId:    p.FileId,
+					CreatedAt: currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UploadPart method", func() {
+		var p goseidon.UploadPartParam
+
+		BeforeEach(func() {
+			p = goseidon.UploadPartParam{
+				UploadId:   "mock-upload-id",
+				FileId:     "mock-file-id",
+				PartNumber: 1,
+				PartData:   strings.NewReader("part"),
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.UploadPart(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("part data is invalid", func() {
+			It("should return error", func() {
+				p.PartData = nil
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid part data"))
+			})
+		})
+
+		When("upload id is invalid", func() {
+			It("should return error", func() {
+				p.UploadId = ""
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid upload id"))
+			})
+		})
+
+		When("part number is invalid", func() {
+			It("should return error", func() {
+				p.PartNumber = 0
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("part data is unseekable without size", func() {
+			It("should return error", func() {
+				p.PartData = &readCloser{}
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("part size is required for unseekable part data"))
+			})
+		})
+
+		When("failed upload part", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					UploadPartWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.UploadPartOutput{}, fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+
+		When("success upload part", func() {
+			It("should return result", func() {
+				param := &s3.UploadPartInput{
+					Bucket:     aws.String(cfg.BucketName),
+					Key:        aws.String(p.FileId),
+					UploadId:   aws.String(p.UploadId),
+					PartNumber: aws.Int64(1),
+					Body:       strings.NewReader("part"),
+				}
+				out := &s3.UploadPartOutput{ETag: aws.String(`"mock-etag"`)}
+				cl.EXPECT().
+					UploadPartWithContext(gomock.Eq(ctx), gomock.Eq(param), gomock.Any()).
+					Return(out, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadPart(ctx, p)
+
+				eRes := &goseidon.UploadPartResult{
+					PartNumber: 1,
+					ETag:       "mock-etag",
+					Size:       4,
+					UploadedAt: currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("CompleteMultipartUpload method", func() {
+		var p goseidon.CompleteMultipartParam
+
+		BeforeEach(func() {
+			p = goseidon.CompleteMultipartParam{
+				UploadId: "mock-upload-id",
+				FileId:   "mock-file-id",
+				Parts: []goseidon.CompletedPart{
+					{PartNumber: 1, ETag: "etag-1"},
+					{PartNumber: 2, ETag: "etag-2"},
+				},
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.CompleteMultipartUpload(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("parts are invalid", func() {
+			It("should return error", func() {
+				p.Parts = nil
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("parts are empty"))
+			})
+		})
+
+		When("file already exists", func() {
+			It("should return error", func() {
+				reqErr := awserr.NewRequestFailure(awserr.New("PreconditionFailed", "", nil), http.StatusPreconditionFailed, "mock-request-id")
+				cl.EXPECT().
+					CompleteMultipartUploadWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.CompleteMultipartUploadOutput{}, reqErr).
+					Times(1)
+
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrAlreadyExists)).To(BeTrue())
+			})
+		})
+
+		When("file already exists and overwrite mode is skip", func() {
+			It("should abort upload and return skipped result", func() {
+				p.Overwrite = goseidon.OverwriteSkip
+				reqErr := awserr.NewRequestFailure(awserr.New("PreconditionFailed", "", nil), http.StatusPreconditionFailed, "mock-request-id")
+				cl.EXPECT().
+					CompleteMultipartUploadWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.CompleteMultipartUploadOutput{}, reqErr).
+					Times(1)
+				cl.EXPECT().
+					AbortMultipartUploadWithContext(gomock.Eq(ctx), gomock.Eq(&s3.AbortMultipartUploadInput{
+						Bucket:   aws.String(cfg.BucketName),
+						Key:      aws.String(p.FileId),
+						UploadId: aws.String(p.UploadId),
+					})).
+					Return(&s3.AbortMultipartUploadOutput{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				eRes := &goseidon.UploadFileResult{
+					FileId:     p.FileId,
+					UploadedAt: currentTime,
+					Skipped:    true,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("success complete upload", func() {
+			It("should return result", func() {
+				param := &s3.CompleteMultipartUploadInput{
+					Bucket:   aws.String(cfg.BucketName),
+					Key:      aws.String(p.FileId),
+					UploadId: aws.String(p.UploadId),
+					MultipartUpload: &s3.CompletedMultipartUpload{
+						Parts: []*s3.CompletedPart{
+							{PartNumber: aws.Int64(1), ETag: aws.String("etag-1")},
+							{PartNumber: aws.Int64(2), ETag: aws.String("etag-2")},
+						},
+					},
+				}
+				req := newRequest(param, &s3.CompleteMultipartUploadOutput{}, nil)
+				cl.EXPECT().
+					CompleteMultipartUploadWithContext(gomock.Eq(ctx), gomock.Eq(param), gomock.Any()).
+					Do(applyOptions(req)).
+					Return(&s3.CompleteMultipartUploadOutput{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				Expect(res).To(Equal(&goseidon.UploadFileResult{FileId: p.FileId, UploadedAt: currentTime}))
+				Expect(err).To(BeNil())
+				Expect(req.HTTPRequest.Header.Get("If-None-Match")).To(Equal("*"))
+			})
+		})
+	})
+
+	Context("AbortMultipartUpload method", func() {
+		var p goseidon.AbortMultipartParam
+
+		BeforeEach(func() {
+			p = goseidon.AbortMultipartParam{
+				UploadId: "mock-upload-id",
+				FileId:   "mock-file-id",
+			}
+		})
+
+		When("upload is not found", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					AbortMultipartUploadWithContext(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, awserr.New(s3.ErrCodeNoSuchUpload, "", nil)).
+					Times(1)
+
+				res, err := s.AbortMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrNotFound)).To(BeTrue())
+			})
+		})
+
+		When("success abort upload", func() {
+			It("should return result", func() {
+				cl.EXPECT().
+					AbortMultipartUploadWithContext(gomock.Eq(ctx), gomock.Any()).
+					Return(&s3.AbortMultipartUploadOutput{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.AbortMultipartUpload(ctx, p)
+
+				Expect(res).To(Equal(&goseidon.AbortMultipartResult{UploadId: p.UploadId, AbortedAt: currentTime}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListParts method", func() {
+		var p goseidon.ListPartsParam
+
+		BeforeEach(func() {
+			p = goseidon.ListPartsParam{
+				UploadId: "mock-upload-id",
+				FileId:   "mock-file-id",
+			}
+		})
+
+		When("failed list parts", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					ListPartsWithContext(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := s.ListParts(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+
+		When("parts span multiple pages", func() {
+			It("should return every part", func() {
+				first := &s3.ListPartsInput{
+					Bucket:   aws.String(cfg.BucketName),
+					Key:      aws.String(p.FileId),
+					UploadId: aws.String(p.UploadId),
+				}
+				second := &s3.ListPartsInput{
+					Bucket:           aws.String(cfg.BucketName),
+					Key:              aws.String(p.FileId),
+					UploadId:         aws.String(p.UploadId),
+					PartNumberMarker: aws.Int64(1),
+				}
+				gomock.InOrder(
+					cl.EXPECT().
+						ListPartsWithContext(gomock.Eq(ctx), gomock.Eq(first)).
+						Return(&s3.ListPartsOutput{
+							Parts: []*s3.Part{
+								{PartNumber: aws.Int64(1), ETag: aws.String(`"etag-1"`), Size: aws.Int64(5), LastModified: aws.Time(currentTime)},
+							},
+							IsTruncated:          aws.Bool(true),
+							NextPartNumberMarker: aws.Int64(1),
+						}, nil),
+					cl.EXPECT().
+						ListPartsWithContext(gomock.Eq(ctx), gomock.Eq(second)).
+						Return(&s3.ListPartsOutput{
+							Parts: []*s3.Part{
+								{PartNumber: aws.Int64(2), ETag: aws.String(`"etag-2"`), Size: aws.Int64(3), LastModified: aws.Time(currentTime)},
+							},
+						}, nil),
+				)
+
+				res, err := s.ListParts(ctx, p)
+
+				eRes := &goseidon.ListPartsResult{
+					Parts: []goseidon.PartItem{
+						{PartNumber: 1, ETag: "etag-1", Size: 5, LastModified: currentTime},
+						{PartNumber: 2, ETag: "etag-2", Size: 3, LastModified: currentTime},
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("request timeout is configured", func() {
+			It("should bound every page on its own", func() {
+				cfg.RequestTimeout = time.Minute
+				calls := []aws.Context{}
+				cl.EXPECT().
+					ListPartsWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx aws.Context, p *s3.ListPartsInput, opts ...request.Option) (*s3.ListPartsOutput, error) {
+						_, ok := ctx.Deadline()
+						Expect(ok).To(BeTrue())
+						Expect(ctx.Err()).To(BeNil())
+						calls = append(calls, ctx)
+						return &s3.ListPartsOutput{
+							IsTruncated:          aws.Bool(len(calls) == 1),
+							NextPartNumberMarker: aws.Int64(1),
+						}, nil
+					}).
+					Times(2)
+
+				_, err := s.ListParts(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(calls).To(HaveLen(2))
+				Expect(calls[0]).ToNot(BeIdenticalTo(calls[1]))
+				Expect(calls[0].Err()).To(Equal(context.Canceled))
+			})
+		})
+	})
+})
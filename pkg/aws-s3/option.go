@@ -1,8 +1,12 @@
 package aws_s3
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
@@ -12,6 +16,11 @@ type AwsS3Config struct {
 	BucketName                           string
 
 	Client AwsS3Client
+
+	// RequestTimeout bound every storage call, zero leave the caller context as is
+	RequestTimeout time.Duration
+	// RequestOptions is applied to every s3 request before the call specific options
+	RequestOptions []request.Option
 }
 
 type AwsS3StorageOption interface {
@@ -52,3 +61,45 @@ func WithStatisCredential(region, accessKey, secretKey, bucketName string) AwsS3
 		bucketName: bucketName,
 	}
 }
+
+type withRequestTimeout struct {
+	timeout time.Duration
+}
+
+func (o *withRequestTimeout) Apply(c *AwsS3Config) error {
+	if o.timeout <= 0 {
+		return fmt.Errorf("invalid request timeout")
+	}
+	c.RequestTimeout = o.timeout
+	return nil
+}
+
+// WithRequestTimeout cancel a storage call still running after timeout,
+// a retrieved stream is bounded until it's closed
+func WithRequestTimeout(timeout time.Duration) AwsS3StorageOption {
+	return &withRequestTimeout{
+		timeout: timeout,
+	}
+}
+
+type withRequestOptions struct {
+	opts []request.Option
+}
+
+func (o *withRequestOptions) Apply(c *AwsS3Config) error {
+	for _, opt := range o.opts {
+		if opt == nil {
+			return fmt.Errorf("invalid request option")
+		}
+	}
+	c.RequestOptions = append(c.RequestOptions, o.opts...)
+	return nil
+}
+
+// WithRequestOptions apply the sdk request options to every s3 request,
+// e.g. request.WithResponseReadTimeout or a custom retryer
+func WithRequestOptions(opts ...request.Option) AwsS3StorageOption {
+	return &withRequestOptions{
+		opts: opts,
+	}
+}
@@ -3,7 +3,9 @@ package aws_s3_test
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws/request"
 	aws_s3 "github.com/go-seidon/core/pkg/aws-s3"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -42,4 +44,46 @@ var _ = Describe("Storage Option", func() {
 			})
 		})
 	})
+
+	Context("With request timeout option", func() {
+		When("timeout is invalid", func() {
+			It("should return error", func() {
+				cfg := &aws_s3.AwsS3Config{}
+				err := aws_s3.WithRequestTimeout(0).Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid request timeout")))
+			})
+		})
+
+		When("success apply option", func() {
+			It("should set the timeout", func() {
+				cfg := &aws_s3.AwsS3Config{}
+				err := aws_s3.WithRequestTimeout(time.Minute).Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.RequestTimeout).To(Equal(time.Minute))
+			})
+		})
+	})
+
+	Context("With request options option", func() {
+		When("request option is invalid", func() {
+			It("should return error", func() {
+				cfg := &aws_s3.AwsS3Config{}
+				err := aws_s3.WithRequestOptions(nil).Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid request option")))
+			})
+		})
+
+		When("success apply option", func() {
+			It("should keep the options", func() {
+				cfg := &aws_s3.AwsS3Config{}
+				err := aws_s3.WithRequestOptions(request.WithLogLevel(0), request.WithResponseReadTimeout(time.Second)).Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.RequestOptions).To(HaveLen(2))
+			})
+		})
+	})
 })
@@ -0,0 +1,12 @@
+package aws_s3
+
+import (
+	"context"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// DeletePrefix remove each listed page of up to 1000 keys with a single DeleteObjects request
+func (s *AwsS3Storage) DeletePrefix(ctx context.Context, p goseidon.DeletePrefixParam) (*goseidon.DeletePrefixResult, error) {
+	return goseidon.DeleteByPrefix(ctx, s, p)
+}
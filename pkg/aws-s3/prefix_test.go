@@ -0,0 +1,166 @@
+package aws_s3_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+	awsmock "github.com/go-seidon/core/internal/aws"
+	"github.com/go-seidon/core/internal/clock"
+	aws_s3 "github.com/go-seidon/core/pkg/aws-s3"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Prefix", func() {
+	var (
+		ctx         context.Context
+		s           *aws_s3.AwsS3Storage
+		cfg         *aws_s3.AwsS3Config
+		cl          *awsmock.MockAwsS3Client
+		clo         *clock.MockClock
+		currentTime time.Time
+		firstPage   *s3.ListObjectsV2Input
+		nextPage    *s3.ListObjectsV2Input
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		cl = awsmock.NewMockAwsS3Client(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		cfg = &aws_s3.AwsS3Config{
+			BucketName: "mock-bucket-name",
+		}
+		currentTime = time.Now()
+		s = &aws_s3.AwsS3Storage{
+			Config: cfg,
+			Client: cl,
+			Clock:  clo,
+		}
+		firstPage = &s3.ListObjectsV2Input{
+			Bucket: aws.String(cfg.BucketName),
+			Prefix: aws.String("tenant-1/"),
+		}
+		nextPage = &s3.ListObjectsV2Input{
+			Bucket:            aws.String(cfg.BucketName),
+			Prefix:            aws.String("tenant-1/"),
+			ContinuationToken: aws.String("next-token"),
+		}
+	})
+
+	Context("DeletePrefix method", func() {
+		When("dry run", func() {
+			It("should count every page without deleting", func() {
+				cl.EXPECT().
+					ListObjectsV2WithContext(gomock.Eq(ctx), gomock.Eq(firstPage)).
+					Return(&s3.ListObjectsV2Output{
+						Contents: []*s3.Object{
+							{Key: aws.String("tenant-1/a.jpg")},
+							{Key: aws.String("tenant-1/b.jpg")},
+						},
+						IsTruncated:           aws.Bool(true),
+						NextContinuationToken: aws.String("next-token"),
+					}, nil).
+					Times(1)
+				cl.EXPECT().
+					ListObjectsV2WithContext(gomock.Eq(ctx), gomock.Eq(nextPage)).
+					Return(&s3.ListObjectsV2Output{
+						Contents: []*s3.Object{
+							{Key: aws.String("tenant-1/c.jpg")},
+						},
+					}, nil).
+					Times(1)
+
+				res, err := s.DeletePrefix(ctx, goseidon.DeletePrefixParam{Prefix: "tenant-1/", DryRun: true})
+
+				eRes := &goseidon.DeletePrefixResult{
+					Prefix:  "tenant-1/",
+					Matched: 3,
+					Failed:  []goseidon.DeleteFileItem{},
+					DryRun:  true,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("a key fail to be deleted", func() {
+			It("should delete the page at once and report the failure", func() {
+				cl.EXPECT().
+					ListObjectsV2WithContext(gomock.Eq(ctx), gomock.Eq(firstPage)).
+					Return(&s3.ListObjectsV2Output{
+						Contents: []*s3.Object{
+							{Key: aws.String("tenant-1/a.jpg")},
+							{Key: aws.String("tenant-1/b.jpg")},
+						},
+					}, nil).
+					Times(1)
+				param := &s3.DeleteObjectsInput{
+					Bucket: aws.String(cfg.BucketName),
+					Delete: &s3.Delete{
+						Objects: []*s3.ObjectIdentifier{
+							{Key: aws.String("tenant-1/a.jpg")},
+							{Key: aws.String("tenant-1/b.jpg")},
+						},
+						Quiet: aws.Bool(true),
+					},
+				}
+				cl.EXPECT().
+					DeleteObjectsWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(&s3.DeleteObjectsOutput{
+						Errors: []*s3.Error{
+							{Key: aws.String("tenant-1/b.jpg"), Code: aws.String("AccessDenied"), Message: aws.String("Access Denied")},
+						},
+					}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.DeletePrefix(ctx, goseidon.DeletePrefixParam{Prefix: "tenant-1/"})
+
+				Expect(err).To(BeNil())
+				Expect(res.Matched).To(Equal(2))
+				Expect(res.Deleted).To(Equal(1))
+				Expect(res.Failed).To(HaveLen(1))
+				Expect(res.Failed[0].Id).To(Equal("tenant-1/b.jpg"))
+				Expect(errors.Is(res.Failed[0].Error, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("failed list the next page", func() {
+			It("should return the result so far with the error", func() {
+				cl.EXPECT().
+					ListObjectsV2WithContext(gomock.Eq(ctx), gomock.Eq(firstPage)).
+					Return(&s3.ListObjectsV2Output{
+						Contents: []*s3.Object{
+							{Key: aws.String("tenant-1/a.jpg")},
+						},
+						IsTruncated:           aws.Bool(true),
+						NextContinuationToken: aws.String("next-token"),
+					}, nil).
+					Times(1)
+				cl.EXPECT().
+					DeleteObjectsWithContext(gomock.Eq(ctx), gomock.Any()).
+					Return(&s3.DeleteObjectsOutput{}, nil).
+					Times(1)
+				cl.EXPECT().
+					ListObjectsV2WithContext(gomock.Eq(ctx), gomock.Eq(nextPage)).
+					Return(nil, awserr.New("NoSuchBucket", "", nil)).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.DeletePrefix(ctx, goseidon.DeletePrefixParam{Prefix: "tenant-1/"})
+
+				Expect(errors.Is(err, goseidon.ErrNotFound)).To(BeTrue())
+				Expect(res.Matched).To(Equal(1))
+				Expect(res.Deleted).To(Equal(1))
+			})
+		})
+	})
+})
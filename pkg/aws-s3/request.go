@@ -0,0 +1,35 @@
+package aws_s3
+
+import (
+	"context"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// withTimeout bound ctx with the configured request timeout
+func (s *AwsS3Storage) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.Config.RequestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.Config.RequestTimeout)
+}
+
+// requestOptions put the configured options before the call specific ones
+func (s *AwsS3Storage) requestOptions(opts ...request.Option) []request.Option {
+	res := make([]request.Option, 0, len(s.Config.RequestOptions)+len(opts))
+	res = append(res, s.Config.RequestOptions...)
+	return append(res, opts...)
+}
+
+// cancelReadCloser release the request context once the body is closed
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (rc *cancelReadCloser) Close() error {
+	err := rc.ReadCloser.Close()
+	rc.cancel()
+	return err
+}
@@ -0,0 +1,138 @@
+package aws_s3_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+	awsmock "github.com/go-seidon/core/internal/aws"
+	"github.com/go-seidon/core/internal/clock"
+	aws_s3 "github.com/go-seidon/core/pkg/aws-s3"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Request", func() {
+	var (
+		ctx         context.Context
+		s           *aws_s3.AwsS3Storage
+		cfg         *aws_s3.AwsS3Config
+		cl          *awsmock.MockAwsS3Client
+		clo         *clock.MockClock
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		cfg = &aws_s3.AwsS3Config{
+			BucketName: "mock-bucket-name",
+		}
+		cl = awsmock.NewMockAwsS3Client(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Now()
+		s = &aws_s3.AwsS3Storage{
+			Config: cfg,
+			Client: cl,
+			Clock:  clo,
+		}
+	})
+
+	When("request timeout is configured", func() {
+		It("should bound the call context until it returns", func() {
+			cfg.RequestTimeout = time.Minute
+			var callCtx aws.Context
+			cl.EXPECT().
+				DeleteObjectWithContext(gomock.Any(), gomock.Any()).
+				Do(func(c aws.Context, p *s3.DeleteObjectInput, opts ...request.Option) {
+					callCtx = c
+					_, ok := c.Deadline()
+					Expect(ok).To(BeTrue())
+					Expect(c.Err()).To(BeNil())
+				}).
+				Return(&s3.DeleteObjectOutput{}, nil).
+				Times(1)
+			clo.EXPECT().Now().Return(currentTime)
+
+			_, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "mock-file-id"})
+
+			Expect(err).To(BeNil())
+			Expect(callCtx.Err()).To(Equal(context.Canceled))
+		})
+	})
+
+	When("request timeout is configured on a stream", func() {
+		It("should bound the call context until the stream is closed", func() {
+			cfg.RequestTimeout = time.Minute
+			var callCtx aws.Context
+			cl.EXPECT().
+				GetObjectWithContext(gomock.Any(), gomock.Any()).
+				Do(func(c aws.Context, p *s3.GetObjectInput, opts ...request.Option) {
+					callCtx = c
+				}).
+				Return(&s3.GetObjectOutput{
+					Body:          io.NopCloser(strings.NewReader("content")),
+					ContentLength: aws.Int64(7),
+				}, nil).
+				Times(1)
+			clo.EXPECT().Now().Return(currentTime)
+
+			res, err := s.RetrieveStream(ctx, goseidon.RetrieveFileParam{Id: "mock-file-id"})
+			Expect(err).To(BeNil())
+			Expect(callCtx.Err()).To(BeNil())
+
+			data, err := io.ReadAll(res.File)
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(Equal("content"))
+			Expect(res.File.Close()).To(BeNil())
+			Expect(callCtx.Err()).To(Equal(context.Canceled))
+		})
+	})
+
+	When("request options are configured", func() {
+		It("should apply them before the call options", func() {
+			cfg.RequestOptions = []request.Option{
+				request.WithSetRequestHeaders(map[string]string{"X-Mock": "mock-value"}),
+			}
+			cl.EXPECT().
+				DeleteObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+				Return(&s3.DeleteObjectOutput{}, nil).
+				Times(1)
+			clo.EXPECT().Now().Return(currentTime)
+
+			_, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "mock-file-id"})
+
+			Expect(err).To(BeNil())
+		})
+	})
+
+	When("request options are configured on a conditional write", func() {
+		It("should send them along with the call headers", func() {
+			cfg.RequestOptions = []request.Option{
+				request.WithSetRequestHeaders(map[string]string{"X-Mock": "mock-value"}),
+			}
+			req := newRequest(&s3.PutObjectInput{}, &s3.PutObjectOutput{}, nil)
+			cl.EXPECT().
+				PutObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).
+				Do(applyOptions(req)).
+				Return(&s3.PutObjectOutput{}, nil).
+				Times(1)
+			clo.EXPECT().Now().Return(currentTime)
+
+			_, err := s.UploadFile(ctx, goseidon.UploadFileParam{
+				FileId:   "mock-file-id",
+				FileData: []byte("content"),
+			})
+
+			Expect(err).To(BeNil())
+			Expect(req.HTTPRequest.Header.Get("X-Mock")).To(Equal("mock-value"))
+			Expect(req.HTTPRequest.Header.Get("If-None-Match")).To(Equal("*"))
+		})
+	})
+})
@@ -3,10 +3,15 @@ package aws_s3
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 	goseidon "github.com/go-seidon/core"
 	"github.com/go-seidon/core/internal/clock"
@@ -18,93 +23,444 @@ type AwsS3Storage struct {
 	Clock  clock.Clock
 }
 
+// AwsS3Client is the subset of *s3.S3 used by the storage,
+// every call goes through a ...WithContext method so it carry the caller context
 type AwsS3Client interface {
-	PutObject(*s3.PutObjectInput) (*s3.PutObjectOutput, error)
-	GetObject(*s3.GetObjectInput) (*s3.GetObjectOutput, error)
-	DeleteObject(*s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error)
+	// PutObjectRequest and GetObjectRequest are only used to presign urls, nothing is sent
+	PutObjectRequest(*s3.PutObjectInput) (*request.Request, *s3.PutObjectOutput)
+	GetObjectRequest(*s3.GetObjectInput) (*request.Request, *s3.GetObjectOutput)
+	PutObjectWithContext(aws.Context, *s3.PutObjectInput, ...request.Option) (*s3.PutObjectOutput, error)
+	GetObjectWithContext(aws.Context, *s3.GetObjectInput, ...request.Option) (*s3.GetObjectOutput, error)
+	DeleteObjectWithContext(aws.Context, *s3.DeleteObjectInput, ...request.Option) (*s3.DeleteObjectOutput, error)
+	DeleteObjectsWithContext(aws.Context, *s3.DeleteObjectsInput, ...request.Option) (*s3.DeleteObjectsOutput, error)
+	HeadObjectWithContext(aws.Context, *s3.HeadObjectInput, ...request.Option) (*s3.HeadObjectOutput, error)
+	ListObjectsV2WithContext(aws.Context, *s3.ListObjectsV2Input, ...request.Option) (*s3.ListObjectsV2Output, error)
+	ListObjectVersionsWithContext(aws.Context, *s3.ListObjectVersionsInput, ...request.Option) (*s3.ListObjectVersionsOutput, error)
+	CreateMultipartUploadWithContext(aws.Context, *s3.CreateMultipartUploadInput, ...request.Option) (*s3.CreateMultipartUploadOutput, error)
+	UploadPartWithContext(aws.Context, *s3.UploadPartInput, ...request.Option) (*s3.UploadPartOutput, error)
+	CompleteMultipartUploadWithContext(aws.Context, *s3.CompleteMultipartUploadInput, ...request.Option) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUploadWithContext(aws.Context, *s3.AbortMultipartUploadInput, ...request.Option) (*s3.AbortMultipartUploadOutput, error)
+	ListPartsWithContext(aws.Context, *s3.ListPartsInput, ...request.Option) (*s3.ListPartsOutput, error)
+	CopyObjectWithContext(aws.Context, *s3.CopyObjectInput, ...request.Option) (*s3.CopyObjectOutput, error)
 }
 
 func (s *AwsS3Storage) UploadFile(ctx context.Context, p goseidon.UploadFileParam) (*goseidon.UploadFileResult, error) {
-	if ctx == nil {
-		return nil, fmt.Errorf("invalid context")
-	}
+	return s.UploadStream(ctx, goseidon.UploadStreamParam{
+		FileData:  bytes.NewReader(p.FileData),
+		FileId:    p.FileId,
+		FileName:  p.FileName,
+		FileSize:  p.FileSize,
+		Overwrite: p.Overwrite,
+		Checksum:  p.Checksum,
 
-	_, err := s.Client.PutObject(&s3.PutObjectInput{
-		Body:   bytes.NewReader(p.FileData),
-		Bucket: aws.String(s.Config.BucketName),
-		Key:    aws.String(p.FileId),
+		ContentType:        p.ContentType,
+		ContentDisposition: p.ContentDisposition,
+		CacheControl:       p.CacheControl,
+		Metadata:           p.Metadata,
 	})
+}
+
+func (s *AwsS3Storage) UploadStream(ctx context.Context, p goseidon.UploadStreamParam) (*goseidon.UploadFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	if p.FileData == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid file data"))
+	}
+	err := goseidon.ValidateKey(p.FileId)
 	if err != nil {
 		return nil, err
 	}
 
+	input := &s3.PutObjectInput{
+		Bucket:   aws.String(s.Config.BucketName),
+		Key:      aws.String(p.FileId),
+		Metadata: buildMetadata(p.FileName, p.Metadata),
+	}
+	if p.ContentType != "" {
+		input.ContentType = aws.String(p.ContentType)
+	}
+	if p.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(p.ContentDisposition)
+	}
+	if p.CacheControl != "" {
+		input.CacheControl = aws.String(p.CacheControl)
+	}
+
+	headers := map[string]string{}
+	if p.Overwrite != goseidon.OverwriteReplace {
+		// conditional write, s3 reject the put when the key is already taken
+		headers["If-None-Match"] = "*"
+	}
+
+	// s3 verify the content against the digests sent along with it
+	var hashed *goseidon.ChecksumReader
+	checksum := p.Checksum
+	body, seekable := p.FileData.(io.ReadSeeker)
+	if !seekable && p.FileSize == 0 {
+		// an empty stream is sent as an empty seekable body,
+		// after making sure the stream is really empty
+		err = ensureEmpty(p.FileData)
+		if err != nil {
+			return nil, err
+		}
+		body, seekable = bytes.NewReader(nil), true
+	}
+	if seekable {
+		checksum, err = goseidon.ComputeChecksum(body)
+		if err != nil {
+			return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("failed read file data: %w", err))
+		}
+		err = goseidon.CompareChecksum(p.Checksum, checksum)
+		if err != nil {
+			return nil, err
+		}
+		input.Body = body
+	} else {
+		// unseekable body can't be hashed up front for signing,
+		// so the payload is sent unsigned with a known content length
+		if p.FileSize < 0 {
+			return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("file size is required for unseekable file data"))
+		}
+		// only the digests given by the caller can be sent up front
+		hashed = goseidon.NewChecksumReader(p.FileData)
+		input.Body = aws.ReadSeekCloser(hashed)
+		input.ContentLength = aws.Int64(p.FileSize)
+		headers["X-Amz-Content-Sha256"] = "UNSIGNED-PAYLOAD"
+	}
+	input.ContentMD5 = encodeDigest(checksum.MD5)
+	// s3 accept a single x-amz-checksum header, sha256 is preferred when both are known
+	input.ChecksumSHA256 = encodeDigest(checksum.SHA256)
+	if input.ChecksumSHA256 == nil {
+		input.ChecksumCRC32C = encodeDigest(checksum.CRC32C)
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	out, err := s.Client.PutObjectWithContext(ctx, input, s.requestOptions(request.WithSetRequestHeaders(headers))...)
+	if isPreconditionFailed(err) {
+		if p.Overwrite == goseidon.OverwriteSkip {
+			res := &goseidon.UploadFileResult{
+				FileId:     p.FileId,
+				FileName:   p.FileName,
+				UploadedAt: s.Clock.Now(),
+				Skipped:    true,
+			}
+			return res, nil
+		}
+		return nil, goseidon.NewError(goseidon.ErrAlreadyExists, err)
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if hashed != nil {
+		// s3 verify the digests sent up front, a crc32c given along with a sha256
+		// is only checked once the body is read. the object is stored by then,
+		// so a mismatching one is deleted rather than left in the bucket
+		checksum = hashed.Checksum()
+		err = goseidon.CompareChecksum(p.Checksum, checksum)
+		if err != nil {
+			s.Client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+				Bucket:    aws.String(s.Config.BucketName),
+				Key:       aws.String(p.FileId),
+				VersionId: out.VersionId,
+			}, s.requestOptions()...)
+			return nil, err
+		}
+	}
+
 	uploadedAt := s.Clock.Now()
 	res := &goseidon.UploadFileResult{
 		FileId:     p.FileId,
 		FileName:   p.FileName,
 		UploadedAt: uploadedAt,
+		Checksum:   checksum,
+		VersionId:  aws.StringValue(out.VersionId),
 	}
 	return res, nil
 }
 
 func (s *AwsS3Storage) RetrieveFile(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
-	if ctx == nil {
-		return nil, fmt.Errorf("invalid context")
+	stream, err := s.RetrieveStream(ctx, p)
+	if err != nil {
+		return nil, err
 	}
+	defer stream.File.Close()
 
-	out, err := s.Client.GetObject(&s3.GetObjectInput{
-		Key:    aws.String(p.Id),
-		Bucket: aws.String(s.Config.BucketName),
-	})
+	fileData, err := io.ReadAll(stream.File)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.RetrieveFileResult{
+		File:        fileData,
+		RetrievedAt: stream.RetrievedAt,
+		Size:        stream.Size,
+		Offset:      stream.Offset,
+		Length:      stream.Length,
+		Checksum:    stream.Checksum,
+		VersionId:   stream.VersionId,
+
+		FileName:           stream.FileName,
+		ContentType:        stream.ContentType,
+		ContentDisposition: stream.ContentDisposition,
+		CacheControl:       stream.CacheControl,
+		Metadata:           stream.Metadata,
+	}
+	return res, nil
+}
+
+func (s *AwsS3Storage) RetrieveStream(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveStreamResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateKey(p.Id)
 	if err != nil {
 		return nil, err
 	}
 
-	fileData, err := io.ReadAll(out.Body)
+	err = goseidon.ValidateRange(p.Offset, p.Length)
+	if err != nil {
+		return nil, err
+	}
+	err = goseidon.ValidateVerify(p)
 	if err != nil {
 		return nil, err
 	}
 
+	input := &s3.GetObjectInput{
+		Key:          aws.String(p.Id),
+		Bucket:       aws.String(s.Config.BucketName),
+		ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+	}
+	if p.Offset > 0 || p.Length > 0 {
+		input.Range = aws.String(formatRange(p.Offset, p.Length))
+	}
+	if p.VersionId != "" {
+		input.VersionId = aws.String(p.VersionId)
+	}
+	ctx, cancel := s.withTimeout(ctx)
+	out, err := s.Client.GetObjectWithContext(ctx, input, s.requestOptions()...)
+	if err != nil {
+		cancel()
+		return nil, mapError(err)
+	}
+
+	length := aws.Int64Value(out.ContentLength)
+	size := length
+	if out.ContentRange != nil {
+		size = parseContentRange(aws.StringValue(out.ContentRange), p.Offset+length)
+	}
+
+	checksum := parseChecksum(out.ETag, out.ChecksumCRC32C, out.ChecksumSHA256)
+	file := out.Body
+	if s.Config.RequestTimeout > 0 {
+		// the timeout keep bounding the body until it's closed
+		file = &cancelReadCloser{ReadCloser: file, cancel: cancel}
+	}
+	if p.Verify {
+		file = goseidon.NewVerifyReader(file, verifiableChecksum(checksum))
+	}
+
+	fileName, metadata := parseMetadata(out.Metadata)
 	retrievedAt := s.Clock.Now()
-	res := &goseidon.RetrieveFileResult{
-		File:        fileData,
+	res := &goseidon.RetrieveStreamResult{
+		File:        file,
 		RetrievedAt: retrievedAt,
+		Size:        size,
+		Offset:      p.Offset,
+		Length:      length,
+		Checksum:    checksum,
+		VersionId:   aws.StringValue(out.VersionId),
+
+		FileName:           fileName,
+		ContentType:        aws.StringValue(out.ContentType),
+		ContentDisposition: aws.StringValue(out.ContentDisposition),
+		CacheControl:       aws.StringValue(out.CacheControl),
+		Metadata:           metadata,
 	}
 	return res, nil
 }
 
 func (s *AwsS3Storage) DeleteFile(ctx context.Context, p goseidon.DeleteFileParam) (*goseidon.DeleteFileResult, error) {
 	if ctx == nil {
-		return nil, fmt.Errorf("invalid context")
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateKey(p.Id)
+	if err != nil {
+		return nil, err
 	}
 
-	_, err := s.Client.DeleteObject(&s3.DeleteObjectInput{
+	// without version id a versioned bucket keep the object behind a delete marker
+	input := &s3.DeleteObjectInput{
 		Bucket: aws.String(s.Config.BucketName),
 		Key:    aws.String(p.Id),
-	})
+	}
+	if p.VersionId != "" {
+		input.VersionId = aws.String(p.VersionId)
+	}
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	out, err := s.Client.DeleteObjectWithContext(ctx, input, s.requestOptions()...)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	deletedAt := s.Clock.Now()
 	res := &goseidon.DeleteFileResult{
 		Id:        p.Id,
 		DeletedAt: deletedAt,
+		VersionId: aws.StringValue(out.VersionId),
 	}
 	return res, nil
 }
 
-func NewAwsS3Storage(opt AwsS3StorageOption) (*AwsS3Storage, error) {
-	if opt == nil {
-		return nil, fmt.Errorf("invalid aws s3 option")
+func (s *AwsS3Storage) StatFile(ctx context.Context, p goseidon.StatFileParam) (*goseidon.StatFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateKey(p.Id)
+	if err != nil {
+		return nil, err
 	}
 
-	cfg := &AwsS3Config{}
-	err := opt.Apply(cfg)
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	out, err := s.Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(s.Config.BucketName),
+		Key:          aws.String(p.Id),
+		ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+	}, s.requestOptions()...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	fileName, metadata := parseMetadata(out.Metadata)
+	res := &goseidon.StatFileResult{
+		Id:           p.Id,
+		Size:         aws.Int64Value(out.ContentLength),
+		LastModified: aws.TimeValue(out.LastModified),
+		Checksum:     parseChecksum(out.ETag, out.ChecksumCRC32C, out.ChecksumSHA256),
+
+		FileName:           fileName,
+		ContentType:        aws.StringValue(out.ContentType),
+		ContentDisposition: aws.StringValue(out.ContentDisposition),
+		CacheControl:       aws.StringValue(out.CacheControl),
+		Metadata:           metadata,
+	}
+	return res, nil
+}
+
+func (s *AwsS3Storage) ListFiles(ctx context.Context, p goseidon.ListFileParam) (*goseidon.ListFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.Config.BucketName),
+	}
+	if p.Prefix != "" {
+		input.Prefix = aws.String(p.Prefix)
+	}
+	if p.Delimiter != "" {
+		input.Delimiter = aws.String(p.Delimiter)
+	}
+	if p.PageSize > 0 {
+		input.MaxKeys = aws.Int64(int64(p.PageSize))
+	}
+	if p.ContinuationToken != "" {
+		input.ContinuationToken = aws.String(p.ContinuationToken)
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	out, err := s.Client.ListObjectsV2WithContext(ctx, input, s.requestOptions()...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.ListFileResult{
+		Files:    []goseidon.ListFileItem{},
+		Prefixes: []string{},
+	}
+	for _, obj := range out.Contents {
+		res.Files = append(res.Files, goseidon.ListFileItem{
+			Id:           aws.StringValue(obj.Key),
+			Size:         aws.Int64Value(obj.Size),
+			LastModified: aws.TimeValue(obj.LastModified),
+		})
+	}
+	for _, prefix := range out.CommonPrefixes {
+		res.Prefixes = append(res.Prefixes, aws.StringValue(prefix.Prefix))
+	}
+	if aws.BoolValue(out.IsTruncated) {
+		res.NextContinuationToken = aws.StringValue(out.NextContinuationToken)
+	}
+	return res, nil
+}
+
+func (s *AwsS3Storage) SignURL(ctx context.Context, p goseidon.SignURLParam) (*goseidon.SignURLResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateSignURL(p)
 	if err != nil {
 		return nil, err
 	}
 
+	var req *request.Request
+	if p.Method == http.MethodPut {
+		input := &s3.PutObjectInput{
+			Bucket: aws.String(s.Config.BucketName),
+			Key:    aws.String(p.Id),
+		}
+		if p.ContentType != "" {
+			input.ContentType = aws.String(p.ContentType)
+		}
+		req, _ = s.Client.PutObjectRequest(input)
+	} else {
+		req, _ = s.Client.GetObjectRequest(&s3.GetObjectInput{
+			Bucket: aws.String(s.Config.BucketName),
+			Key:    aws.String(p.Id),
+		})
+	}
+
+	// signed headers aren't hoisted into the query,
+	// the caller has to send them along with the url
+	url, header, err := req.PresignRequest(p.Expiry)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	headers := map[string]string{}
+	for key := range header {
+		headers[key] = header.Get(key)
+	}
+	res := &goseidon.SignURLResult{
+		URL:       url,
+		Method:    p.Method,
+		Headers:   headers,
+		ExpiresAt: s.Clock.Now().Add(p.Expiry),
+	}
+	return res, nil
+}
+
+func NewAwsS3Storage(opts ...AwsS3StorageOption) (*AwsS3Storage, error) {
+	if len(opts) == 0 {
+		return nil, fmt.Errorf("invalid aws s3 option")
+	}
+
+	cfg := &AwsS3Config{}
+	for _, opt := range opts {
+		if opt == nil {
+			return nil, fmt.Errorf("invalid aws s3 option")
+		}
+		err := opt.Apply(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	clock, _ := clock.NewClock()
 
 	storage := &AwsS3Storage{
@@ -114,3 +470,85 @@ func NewAwsS3Storage(opt AwsS3StorageOption) (*AwsS3Storage, error) {
 	}
 	return storage, nil
 }
+
+// ensureEmpty reject a stream holding data while its size is given as zero
+func ensureEmpty(r io.Reader) error {
+	n, err := r.Read(make([]byte, 1))
+	if n > 0 {
+		return goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("file data is larger than file size"))
+	}
+	if err != nil && err != io.EOF {
+		return goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("failed read file data: %w", err))
+	}
+	return nil
+}
+
+// parseETag return md5 digest when the etag is a plain md5 hash,
+// multipart and kms encrypted object has an opaque etag instead
+func parseETag(etag *string) string {
+	tag := strings.Trim(aws.StringValue(etag), `"`)
+	if len(tag) != 32 {
+		return ""
+	}
+	_, err := hex.DecodeString(tag)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(tag)
+}
+
+// formatRange build the http range header of a range read,
+// zero length read until the end of the object
+func formatRange(offset, length int64) string {
+	if length == 0 {
+		return fmt.Sprintf("bytes=%d-", offset)
+	}
+	return fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+}
+
+// parseContentRange return the total size out of "bytes 0-9/100",
+// fallback is returned when the size is unknown
+func parseContentRange(contentRange string, fallback int64) int64 {
+	idx := strings.LastIndex(contentRange, "/")
+	if idx < 0 {
+		return fallback
+	}
+	size, err := strconv.ParseInt(contentRange[idx+1:], 10, 64)
+	if err != nil {
+		return fallback
+	}
+	return size
+}
+
+// buildMetadata put the original file name next to user metadata,
+// nil is returned when there is nothing to store
+func buildMetadata(fileName string, m map[string]string) map[string]*string {
+	if fileName == "" && len(m) == 0 {
+		return nil
+	}
+	res := map[string]*string{}
+	for key, val := range m {
+		res[key] = aws.String(val)
+	}
+	if fileName != "" {
+		res[goseidon.FileNameMetadataKey] = aws.String(fileName)
+	}
+	return res
+}
+
+// parseMetadata normalize canonicalized http header keys
+// back into the lowercased keys stored by s3
+// and split the original file name out of user metadata
+func parseMetadata(m map[string]*string) (string, map[string]string) {
+	fileName := ""
+	res := map[string]string{}
+	for key, val := range m {
+		key = strings.ToLower(key)
+		if key == goseidon.FileNameMetadataKey {
+			fileName = aws.StringValue(val)
+			continue
+		}
+		res[key] = aws.StringValue(val)
+	}
+	return fileName, res
+}
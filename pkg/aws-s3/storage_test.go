@@ -3,12 +3,18 @@ package aws_s3_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 	goseidon "github.com/go-seidon/core"
 	awsmock "github.com/go-seidon/core/internal/aws"
@@ -52,6 +58,24 @@ var _ = Describe("Storage", func() {
 			})
 		})
 
+		When("one of the options is invalid", func() {
+			It("should return error", func() {
+				s, err := aws_s3.NewAwsS3Storage(&withSuccessOption{}, nil)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid aws s3 option")))
+				Expect(s).To(BeNil())
+			})
+		})
+
+		When("success create storage with multiple options", func() {
+			It("should apply every option", func() {
+				s, err := aws_s3.NewAwsS3Storage(&withSuccessOption{}, aws_s3.WithRequestTimeout(time.Second))
+
+				Expect(err).To(BeNil())
+				Expect(s.Config.RequestTimeout).To(Equal(time.Second))
+			})
+		})
+
 		When("success create storage", func() {
 			It("should return aws_s3 storage", func() {
 				s, err := aws_s3.NewAwsS3Storage(&withSuccessOption{})
@@ -91,7 +115,9 @@ var _ = Describe("Storage", func() {
 				Client: cl,
 				Clock:  clo,
 			}
-			p = goseidon.UploadFileParam{}
+			p = goseidon.UploadFileParam{
+				FileId: "mock-file-id",
+			}
 		})
 
 		When("context is invalid", func() {
@@ -109,10 +135,13 @@ var _ = Describe("Storage", func() {
 					Body:   bytes.NewReader(p.FileData),
 					Bucket: aws.String(cfg.BucketName),
 					Key:    aws.String(p.FileId),
+
+					ContentMD5:     aws.String("1B2M2Y8AsgTpgAmY7PhCfg=="),
+					ChecksumSHA256: aws.String("47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="),
 				}
 				cl.EXPECT().
-					PutObject(gomock.Eq(param)).
-					Return(nil, fmt.Errorf("failed upload file")).
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Eq(param), gomock.Any()).
+					Return(&s3.PutObjectOutput{}, fmt.Errorf("failed upload file")).
 					Times(1)
 				res, err := s.UploadFile(ctx, p)
 
@@ -127,11 +156,13 @@ var _ = Describe("Storage", func() {
 					Body:   bytes.NewReader(p.FileData),
 					Bucket: aws.String(cfg.BucketName),
 					Key:    aws.String(p.FileId),
+
+					ContentMD5:     aws.String("1B2M2Y8AsgTpgAmY7PhCfg=="),
+					ChecksumSHA256: aws.String("47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="),
 				}
-				out := &s3.PutObjectOutput{}
 				cl.EXPECT().
-					PutObject(gomock.Eq(param)).
-					Return(out, nil).
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Eq(param), gomock.Any()).
+					Return(&s3.PutObjectOutput{}, nil).
 					Times(1)
 				clo.EXPECT().Now().Return(currentTime)
 
@@ -141,9 +172,396 @@ var _ = Describe("Storage", func() {
 					FileId:     p.FileId,
 					FileName:   p.FileName,
 					UploadedAt: currentTime,
+					Checksum: goseidon.Checksum{
+						MD5:    "d41d8cd98f00b204e9800998ecf8427e",
+						CRC32C: "00000000",
+						SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UploadStream method", func() {
+		var (
+			ctx         context.Context
+			s           *aws_s3.AwsS3Storage
+			p           goseidon.UploadStreamParam
+			cfg         *aws_s3.AwsS3Config
+			cl          *awsmock.MockAwsS3Client
+			clo         *clock.MockClock
+			currentTime time.Time
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(t)
+			cfg = &aws_s3.AwsS3Config{
+				Region:          "mock-region",
+				AccessKeyId:     "mock-access-key-id",
+				SecretAccessKey: "mock-secret-access-key",
+				BucketName:      "mock-bucket-name",
+			}
+			cl = awsmock.NewMockAwsS3Client(ctrl)
+			clo = clock.NewMockClock(ctrl)
+			currentTime = time.Now()
+
+			s = &aws_s3.AwsS3Storage{
+				Config: cfg,
+				Client: cl,
+				Clock:  clo,
+			}
+			p = goseidon.UploadStreamParam{
+				FileId:   "mock-file-id",
+				FileName: "mock-file-name",
+				FileData: strings.NewReader("content"),
+				FileSize: 7,
+
+				ContentType:        "text/plain",
+				ContentDisposition: "attachment",
+				CacheControl:       "no-cache",
+				Metadata: map[string]string{
+					"owner": "tenant-1",
+				},
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.UploadStream(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("file id is invalid", func() {
+			It("should return error", func() {
+				p.FileId = "tenant-1//image.jpg"
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("file id contains empty segment"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("file data is invalid", func() {
+			It("should return error", func() {
+				p.FileData = nil
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid file data"))
+			})
+		})
+
+		When("file data is seekable", func() {
+			It("should put object directly", func() {
+				param := &s3.PutObjectInput{
+					Body:   p.FileData.(io.ReadSeeker),
+					Bucket: aws.String(cfg.BucketName),
+					Key:    aws.String(p.FileId),
+
+					ContentMD5:     aws.String("mgNkuembtIDdJeHwKEyFVQ=="),
+					ChecksumSHA256: aws.String("7XACtDnprIRfIjV9giusFERzD722AW0+yUMil7nsn3M="),
+
+					ContentType:        aws.String("text/plain"),
+					ContentDisposition: aws.String("attachment"),
+					CacheControl:       aws.String("no-cache"),
+					Metadata: map[string]*string{
+						"owner":              aws.String("tenant-1"),
+						"goseidon-file-name": aws.String("mock-file-name"),
+					},
+				}
+				req := newRequest(param, &s3.PutObjectOutput{}, nil)
+				cl.EXPECT().
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Eq(param), gomock.Any()).
+					Do(applyOptions(req)).
+					Return(&s3.PutObjectOutput{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadStream(ctx, p)
+
+				eRes := &goseidon.UploadFileResult{
+					FileId:     p.FileId,
+					FileName:   p.FileName,
+					UploadedAt: currentTime,
+					Checksum: goseidon.Checksum{
+						MD5:    "9a0364b9e99bb480dd25e1f0284c8555",
+						CRC32C: "61af7533",
+						SHA256: "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73",
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(req.HTTPRequest.Header.Get("If-None-Match")).To(Equal("*"))
+				Expect(req.HTTPRequest.Header.Get("X-Amz-Content-Sha256")).To(BeEmpty())
+			})
+		})
+
+		When("file already exists", func() {
+			It("should return error", func() {
+				reqErr := awserr.NewRequestFailure(awserr.New("PreconditionFailed", "", nil), http.StatusPreconditionFailed, "mock-request-id")
+				cl.EXPECT().
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.PutObjectOutput{}, reqErr).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrAlreadyExists)).To(BeTrue())
+			})
+		})
+
+		When("file already exists and overwrite mode is skip", func() {
+			It("should return skipped result", func() {
+				p.Overwrite = goseidon.OverwriteSkip
+				reqErr := awserr.NewRequestFailure(awserr.New("PreconditionFailed", "", nil), http.StatusPreconditionFailed, "mock-request-id")
+				cl.EXPECT().
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.PutObjectOutput{}, reqErr).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadStream(ctx, p)
+
+				eRes := &goseidon.UploadFileResult{
+					FileId:     p.FileId,
+					FileName:   p.FileName,
+					UploadedAt: currentTime,
+					Skipped:    true,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("overwrite mode is replace", func() {
+			It("should put object unconditionally", func() {
+				p.Overwrite = goseidon.OverwriteReplace
+				req := newRequest(&s3.PutObjectInput{}, &s3.PutObjectOutput{}, nil)
+				cl.EXPECT().
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Do(applyOptions(req)).
+					Return(&s3.PutObjectOutput{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Skipped).To(BeFalse())
+				Expect(req.HTTPRequest.Header.Get("If-None-Match")).To(BeEmpty())
+			})
+		})
+
+		When("file size of unseekable data is unknown", func() {
+			It("should return error", func() {
+				p.FileData = &readCloser{}
+				p.FileSize = -1
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("file size is required for unseekable file data"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("unseekable data is empty", func() {
+			It("should put an empty object", func() {
+				p.FileData = &readCloser{}
+				p.FileSize = 0
+				param := &s3.PutObjectInput{
+					Body:   bytes.NewReader(nil),
+					Bucket: aws.String(cfg.BucketName),
+					Key:    aws.String(p.FileId),
+
+					ContentMD5:     aws.String("1B2M2Y8AsgTpgAmY7PhCfg=="),
+					ChecksumSHA256: aws.String("47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="),
+
+					ContentType:        aws.String("text/plain"),
+					ContentDisposition: aws.String("attachment"),
+					CacheControl:       aws.String("no-cache"),
+					Metadata: map[string]*string{
+						"owner":              aws.String("tenant-1"),
+						"goseidon-file-name": aws.String("mock-file-name"),
+					},
+				}
+				req := newRequest(param, &s3.PutObjectOutput{}, nil)
+				cl.EXPECT().
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Eq(param), gomock.Any()).
+					Do(applyOptions(req)).
+					Return(&s3.PutObjectOutput{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadStream(ctx, p)
+
+				eRes := &goseidon.UploadFileResult{
+					FileId:     p.FileId,
+					FileName:   p.FileName,
+					UploadedAt: currentTime,
+					Checksum: goseidon.Checksum{
+						MD5:    "d41d8cd98f00b204e9800998ecf8427e",
+						CRC32C: "00000000",
+						SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(req.HTTPRequest.Header.Get("X-Amz-Content-Sha256")).To(BeEmpty())
+			})
+		})
+
+		When("unseekable data is larger than its zero size", func() {
+			It("should return error", func() {
+				p.FileData = io.MultiReader(strings.NewReader("content"))
+				p.FileSize = 0
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("file data is larger than file size"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("failed read empty unseekable data", func() {
+			It("should return error", func() {
+				p.FileData = &readCloser{readShouldError: true}
+				p.FileSize = 0
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed read file data: failed read file"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("failed send unseekable data", func() {
+			It("should return error", func() {
+				p.FileData = &readCloser{}
+				param := &s3.PutObjectInput{
+					Body:          aws.ReadSeekCloser(goseidon.NewChecksumReader(p.FileData)),
+					Bucket:        aws.String(cfg.BucketName),
+					Key:           aws.String(p.FileId),
+					ContentLength: aws.Int64(p.FileSize),
+
+					ContentType:        aws.String("text/plain"),
+					ContentDisposition: aws.String("attachment"),
+					CacheControl:       aws.String("no-cache"),
+					Metadata: map[string]*string{
+						"owner":              aws.String("tenant-1"),
+						"goseidon-file-name": aws.String("mock-file-name"),
+					},
+				}
+				cl.EXPECT().
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Eq(param), gomock.Any()).
+					Return(&s3.PutObjectOutput{}, fmt.Errorf("failed send request")).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed send request"))
+			})
+		})
+
+		When("success send unseekable data", func() {
+			It("should send unsigned payload", func() {
+				p.FileData = &readCloser{}
+				param := &s3.PutObjectInput{
+					Body:          aws.ReadSeekCloser(goseidon.NewChecksumReader(p.FileData)),
+					Bucket:        aws.String(cfg.BucketName),
+					Key:           aws.String(p.FileId),
+					ContentLength: aws.Int64(p.FileSize),
+
+					ContentType:        aws.String("text/plain"),
+					ContentDisposition: aws.String("attachment"),
+					CacheControl:       aws.String("no-cache"),
+					Metadata: map[string]*string{
+						"owner":              aws.String("tenant-1"),
+						"goseidon-file-name": aws.String("mock-file-name"),
+					},
+				}
+				req := newRequest(param, &s3.PutObjectOutput{}, nil)
+				cl.EXPECT().
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Eq(param), gomock.Any()).
+					Do(applyOptions(req)).
+					Return(&s3.PutObjectOutput{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadStream(ctx, p)
+
+				eRes := &goseidon.UploadFileResult{
+					FileId:     p.FileId,
+					FileName:   p.FileName,
+					UploadedAt: currentTime,
+					Checksum: goseidon.Checksum{
+						MD5:    "d41d8cd98f00b204e9800998ecf8427e",
+						CRC32C: "00000000",
+						SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+					},
 				}
 				Expect(res).To(Equal(eRes))
 				Expect(err).To(BeNil())
+				Expect(req.HTTPRequest.Header.Get("X-Amz-Content-Sha256")).To(Equal("UNSIGNED-PAYLOAD"))
+			})
+		})
+
+		When("unseekable data is sent with its crc32c", func() {
+			It("should let s3 verify it", func() {
+				p.FileData = &readCloser{}
+				p.Checksum = goseidon.Checksum{CRC32C: "deadbeef"}
+				cl.EXPECT().
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ aws.Context, input *s3.PutObjectInput, _ ...request.Option) (*s3.PutObjectOutput, error) {
+						Expect(input.ChecksumCRC32C).To(Equal(aws.String("3q2+7w==")))
+						Expect(input.ChecksumSHA256).To(BeNil())
+						return nil, awserr.New("BadDigest", "", nil)
+					}).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrChecksumMismatch)).To(BeTrue())
+			})
+		})
+
+		When("unseekable data doesn't match its checksum", func() {
+			It("should delete the stored object and return error", func() {
+				p.FileData = &readCloser{}
+				p.Checksum = goseidon.Checksum{
+					CRC32C: "deadbeef",
+					SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+				}
+				cl.EXPECT().
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.PutObjectOutput{VersionId: aws.String("v2")}, nil).
+					Times(1)
+				cl.EXPECT().
+					DeleteObjectWithContext(gomock.Eq(ctx), gomock.Eq(&s3.DeleteObjectInput{
+						Bucket:    aws.String(cfg.BucketName),
+						Key:       aws.String(p.FileId),
+						VersionId: aws.String("v2"),
+					})).
+					Return(&s3.DeleteObjectOutput{}, nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrChecksumMismatch)).To(BeTrue())
+				Expect(err.Error()).To(Equal("crc32c checksum mismatch: expected deadbeef, got 00000000"))
 			})
 		})
 	})
@@ -194,11 +612,12 @@ var _ = Describe("Storage", func() {
 		When("failed retrieve file", func() {
 			It("should return error", func() {
 				param := &s3.GetObjectInput{
-					Key:    aws.String(p.Id),
-					Bucket: aws.String(cfg.BucketName),
+					ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+					Key:          aws.String(p.Id),
+					Bucket:       aws.String(cfg.BucketName),
 				}
 				cl.EXPECT().
-					GetObject(gomock.Eq(param)).
+					GetObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
 					Return(nil, fmt.Errorf("failed retrieve file")).
 					Times(1)
 				res, err := s.RetrieveFile(ctx, p)
@@ -211,8 +630,9 @@ var _ = Describe("Storage", func() {
 		When("failed read file", func() {
 			It("should return error", func() {
 				param := &s3.GetObjectInput{
-					Key:    aws.String(p.Id),
-					Bucket: aws.String(cfg.BucketName),
+					ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+					Key:          aws.String(p.Id),
+					Bucket:       aws.String(cfg.BucketName),
 				}
 
 				out := &s3.GetObjectOutput{
@@ -221,9 +641,10 @@ var _ = Describe("Storage", func() {
 					},
 				}
 				cl.EXPECT().
-					GetObject(gomock.Eq(param)).
+					GetObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
 					Return(out, nil).
 					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
 
 				res, err := s.RetrieveFile(ctx, p)
 
@@ -235,15 +656,16 @@ var _ = Describe("Storage", func() {
 		When("success retrieve file", func() {
 			It("should return result", func() {
 				param := &s3.GetObjectInput{
-					Key:    aws.String(p.Id),
-					Bucket: aws.String(cfg.BucketName),
+					ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+					Key:          aws.String(p.Id),
+					Bucket:       aws.String(cfg.BucketName),
 				}
 
 				out := &s3.GetObjectOutput{
 					Body: &readCloser{},
 				}
 				cl.EXPECT().
-					GetObject(gomock.Eq(param)).
+					GetObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
 					Return(out, nil).
 					Times(1)
 				clo.EXPECT().Now().Return(currentTime)
@@ -253,6 +675,7 @@ var _ = Describe("Storage", func() {
 				eRes := &goseidon.RetrieveFileResult{
 					File:        []byte{},
 					RetrievedAt: currentTime,
+					Metadata:    map[string]string{},
 				}
 				Expect(res).To(Equal(eRes))
 				Expect(err).To(BeNil())
@@ -260,11 +683,11 @@ var _ = Describe("Storage", func() {
 		})
 	})
 
-	Context("DeleteFile method", func() {
+	Context("RetrieveStream method", func() {
 		var (
 			ctx         context.Context
 			s           *aws_s3.AwsS3Storage
-			p           goseidon.DeleteFileParam
+			p           goseidon.RetrieveFileParam
 			cl          *awsmock.MockAwsS3Client
 			cfg         *aws_s3.AwsS3Config
 			clo         *clock.MockClock
@@ -273,55 +696,239 @@ var _ = Describe("Storage", func() {
 
 		BeforeEach(func() {
 			ctx = context.Background()
-			p = goseidon.DeleteFileParam{
-				Id: "mock-file-id",
-			}
 			ctrl := gomock.NewController(t)
 			cl = awsmock.NewMockAwsS3Client(ctrl)
-			clo = clock.NewMockClock(ctrl)
-			currentTime = time.Now()
 			cfg = &aws_s3.AwsS3Config{
 				Region:          "mock-region",
 				AccessKeyId:     "mock-access-key-id",
 				SecretAccessKey: "mock-secret-access-key",
 				BucketName:      "mock-bucket-name",
 			}
+			clo = clock.NewMockClock(ctrl)
+			currentTime = time.Now()
+
 			s = &aws_s3.AwsS3Storage{
 				Config: cfg,
 				Client: cl,
 				Clock:  clo,
 			}
+			p = goseidon.RetrieveFileParam{
+				Id: "mock-file-id",
+			}
 		})
 
 		When("context is invalid", func() {
 			It("should return error", func() {
-				res, err := s.DeleteFile(nil, p)
+				res, err := s.RetrieveStream(nil, p)
 
 				Expect(res).To(BeNil())
 				Expect(err.Error()).To(Equal("invalid context"))
 			})
 		})
 
-		When("failed delete file", func() {
+		When("file id is invalid", func() {
 			It("should return error", func() {
-				param := &s3.DeleteObjectInput{
-					Key:    aws.String(p.Id),
-					Bucket: aws.String(cfg.BucketName),
-				}
-
-				cl.EXPECT().
-					DeleteObject(gomock.Eq(param)).
-					Return(nil, fmt.Errorf("failed delete file")).
-					Times(1)
-
-				res, err := s.DeleteFile(ctx, p)
+				p.Id = "tenant-1//image.jpg"
+				res, err := s.RetrieveStream(ctx, p)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("failed delete file")))
+				Expect(err.Error()).To(Equal("file id contains empty segment"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
 			})
 		})
 
-		When("success delete file", func() {
+		When("failed retrieve file", func() {
+			It("should return error", func() {
+				param := &s3.GetObjectInput{
+					ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+					Key:          aws.String(p.Id),
+					Bucket:       aws.String(cfg.BucketName),
+				}
+				cl.EXPECT().
+					GetObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(nil, fmt.Errorf("failed retrieve file")).
+					Times(1)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed retrieve file"))
+			})
+		})
+
+		When("success retrieve file", func() {
+			It("should return object body", func() {
+				param := &s3.GetObjectInput{
+					ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+					Key:          aws.String(p.Id),
+					Bucket:       aws.String(cfg.BucketName),
+				}
+				body := &readCloser{}
+				out := &s3.GetObjectOutput{
+					Body:               body,
+					ContentLength:      aws.Int64(120),
+					ContentType:        aws.String("image/png"),
+					ContentDisposition: aws.String("inline"),
+					CacheControl:       aws.String("max-age=60"),
+					Metadata: map[string]*string{
+						"Owner":              aws.String("tenant-1"),
+						"Goseidon-File-Name": aws.String("dolphin.png"),
+					},
+				}
+				cl.EXPECT().
+					GetObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(out, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				eRes := &goseidon.RetrieveStreamResult{
+					File:               body,
+					RetrievedAt:        currentTime,
+					Size:               120,
+					Length:             120,
+					FileName:           "dolphin.png",
+					ContentType:        "image/png",
+					ContentDisposition: "inline",
+					CacheControl:       "max-age=60",
+					Metadata: map[string]string{
+						"owner": "tenant-1",
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("range is invalid", func() {
+			It("should return error", func() {
+				p.Offset = -1
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("range offset must not be negative"))
+			})
+		})
+
+		When("range is given", func() {
+			It("should send range header and return total size", func() {
+				p.Offset = 10
+				p.Length = 5
+				param := &s3.GetObjectInput{
+					ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+					Key:          aws.String(p.Id),
+					Bucket:       aws.String(cfg.BucketName),
+					Range:        aws.String("bytes=10-14"),
+				}
+				out := &s3.GetObjectOutput{
+					Body:          &readCloser{},
+					ContentLength: aws.Int64(5),
+					ContentRange:  aws.String("bytes 10-14/120"),
+				}
+				cl.EXPECT().
+					GetObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(out, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Size).To(Equal(int64(120)))
+				Expect(res.Offset).To(Equal(int64(10)))
+				Expect(res.Length).To(Equal(int64(5)))
+			})
+		})
+
+		When("range is open ended", func() {
+			It("should read until the end", func() {
+				p.Offset = 10
+				param := &s3.GetObjectInput{
+					ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+					Key:          aws.String(p.Id),
+					Bucket:       aws.String(cfg.BucketName),
+					Range:        aws.String("bytes=10-"),
+				}
+				out := &s3.GetObjectOutput{
+					Body:          &readCloser{},
+					ContentLength: aws.Int64(110),
+					ContentRange:  aws.String("bytes 10-119/120"),
+				}
+				cl.EXPECT().
+					GetObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(out, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Size).To(Equal(int64(120)))
+				Expect(res.Length).To(Equal(int64(110)))
+			})
+		})
+
+		When("range is not satisfiable", func() {
+			It("should return invalid argument error", func() {
+				p.Offset = 200
+				reqErr := awserr.NewRequestFailure(awserr.New("InvalidRange", "", nil), http.StatusRequestedRangeNotSatisfiable, "mock-request-id")
+				cl.EXPECT().
+					GetObjectWithContext(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, reqErr).
+					Times(1)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("DeleteFile method", func() {
+		var (
+			ctx         context.Context
+			s           *aws_s3.AwsS3Storage
+			p           goseidon.DeleteFileParam
+			cl          *awsmock.MockAwsS3Client
+			cfg         *aws_s3.AwsS3Config
+			clo         *clock.MockClock
+			currentTime time.Time
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			p = goseidon.DeleteFileParam{
+				Id: "mock-file-id",
+			}
+			ctrl := gomock.NewController(t)
+			cl = awsmock.NewMockAwsS3Client(ctrl)
+			clo = clock.NewMockClock(ctrl)
+			currentTime = time.Now()
+			cfg = &aws_s3.AwsS3Config{
+				Region:          "mock-region",
+				AccessKeyId:     "mock-access-key-id",
+				SecretAccessKey: "mock-secret-access-key",
+				BucketName:      "mock-bucket-name",
+			}
+			s = &aws_s3.AwsS3Storage{
+				Config: cfg,
+				Client: cl,
+				Clock:  clo,
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.DeleteFile(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("failed delete file", func() {
 			It("should return error", func() {
 				param := &s3.DeleteObjectInput{
 					Key:    aws.String(p.Id),
@@ -329,8 +936,27 @@ var _ = Describe("Storage", func() {
 				}
 
 				cl.EXPECT().
-					DeleteObject(gomock.Eq(param)).
-					Return(nil, nil).
+					DeleteObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(nil, fmt.Errorf("failed delete file")).
+					Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed delete file")))
+			})
+		})
+
+		When("success delete file", func() {
+			It("should return error", func() {
+				param := &s3.DeleteObjectInput{
+					Key:    aws.String(p.Id),
+					Bucket: aws.String(cfg.BucketName),
+				}
+
+				cl.EXPECT().
+					DeleteObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(&s3.DeleteObjectOutput{}, nil).
 					Times(1)
 
 				clo.EXPECT().Now().Return(currentTime)
@@ -347,6 +973,452 @@ var _ = Describe("Storage", func() {
 		})
 
 	})
+
+	Context("StatFile method", func() {
+		var (
+			ctx         context.Context
+			s           *aws_s3.AwsS3Storage
+			p           goseidon.StatFileParam
+			cl          *awsmock.MockAwsS3Client
+			cfg         *aws_s3.AwsS3Config
+			currentTime time.Time
+			param       *s3.HeadObjectInput
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(t)
+			cl = awsmock.NewMockAwsS3Client(ctrl)
+			cfg = &aws_s3.AwsS3Config{
+				BucketName: "mock-bucket-name",
+			}
+			currentTime = time.Now()
+
+			s = &aws_s3.AwsS3Storage{
+				Config: cfg,
+				Client: cl,
+				Clock:  clock.NewMockClock(ctrl),
+			}
+			p = goseidon.StatFileParam{
+				Id: "mock-file-id",
+			}
+			param = &s3.HeadObjectInput{
+				ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+				Bucket:       aws.String(cfg.BucketName),
+				Key:          aws.String(p.Id),
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.StatFile(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("file id is invalid", func() {
+			It("should return error", func() {
+				p.Id = "../secret.txt"
+				res, err := s.StatFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("file is not found", func() {
+			It("should return not found error", func() {
+				reqErr := awserr.NewRequestFailure(awserr.New("NotFound", "", nil), http.StatusNotFound, "mock-request-id")
+				cl.EXPECT().
+					HeadObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(nil, reqErr).
+					Times(1)
+
+				res, err := s.StatFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrNotFound)).To(BeTrue())
+			})
+		})
+
+		When("object carry additional checksums", func() {
+			It("should return every digest", func() {
+				out := &s3.HeadObjectOutput{
+					ContentLength:  aws.Int64(7),
+					ETag:           aws.String(`"9a0364b9e99bb480dd25e1f0284c8555"`),
+					ChecksumCRC32C: aws.String("Ya91Mw=="),
+					ChecksumSHA256: aws.String("7XACtDnprIRfIjV9giusFERzD722AW0+yUMil7nsn3M="),
+				}
+				cl.EXPECT().
+					HeadObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(out, nil).
+					Times(1)
+
+				res, err := s.StatFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Size).To(Equal(int64(7)))
+				Expect(res.Checksum).To(Equal(goseidon.Checksum{
+					MD5:    "9a0364b9e99bb480dd25e1f0284c8555",
+					CRC32C: "61af7533",
+					SHA256: "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73",
+				}))
+				Expect(res.Metadata).To(Equal(map[string]string{}))
+			})
+		})
+
+		When("failed head object", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					HeadObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(nil, fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := s.StatFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+
+		When("success stat file", func() {
+			It("should return result", func() {
+				out := &s3.HeadObjectOutput{
+					ContentLength:      aws.Int64(120),
+					ContentType:        aws.String("image/png"),
+					ContentDisposition: aws.String("inline"),
+					CacheControl:       aws.String("max-age=60"),
+					LastModified:       aws.Time(currentTime),
+					ETag:               aws.String(`"9E107D9D372BB6826BD81D3542A419D6"`),
+					Metadata: map[string]*string{
+						"Owner":              aws.String("tenant-1"),
+						"Goseidon-File-Name": aws.String("dolphin.png"),
+					},
+				}
+				cl.EXPECT().
+					HeadObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(out, nil).
+					Times(1)
+
+				res, err := s.StatFile(ctx, p)
+
+				eRes := &goseidon.StatFileResult{
+					Id:           p.Id,
+					Size:         120,
+					LastModified: currentTime,
+					Checksum: goseidon.Checksum{
+						MD5: "9e107d9d372bb6826bd81d3542a419d6",
+					},
+					FileName:           "dolphin.png",
+					ContentType:        "image/png",
+					ContentDisposition: "inline",
+					CacheControl:       "max-age=60",
+					Metadata: map[string]string{
+						"owner": "tenant-1",
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("etag is not a plain md5", func() {
+			It("should leave md5 empty", func() {
+				out := &s3.HeadObjectOutput{
+					ETag: aws.String(`"d41d8cd98f00b204e9800998ecf8427e-2"`),
+				}
+				cl.EXPECT().
+					HeadObjectWithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(out, nil).
+					Times(1)
+
+				res, err := s.StatFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Checksum.MD5).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("ListFiles method", func() {
+		var (
+			ctx         context.Context
+			s           *aws_s3.AwsS3Storage
+			p           goseidon.ListFileParam
+			cl          *awsmock.MockAwsS3Client
+			cfg         *aws_s3.AwsS3Config
+			currentTime time.Time
+			param       *s3.ListObjectsV2Input
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(t)
+			cl = awsmock.NewMockAwsS3Client(ctrl)
+			cfg = &aws_s3.AwsS3Config{
+				BucketName: "mock-bucket-name",
+			}
+			currentTime = time.Now()
+
+			s = &aws_s3.AwsS3Storage{
+				Config: cfg,
+				Client: cl,
+				Clock:  clock.NewMockClock(ctrl),
+			}
+			p = goseidon.ListFileParam{
+				Prefix:            "tenant-1/",
+				Delimiter:         "/",
+				PageSize:          2,
+				ContinuationToken: "mock-token",
+			}
+			param = &s3.ListObjectsV2Input{
+				Bucket:            aws.String(cfg.BucketName),
+				Prefix:            aws.String(p.Prefix),
+				Delimiter:         aws.String(p.Delimiter),
+				MaxKeys:           aws.Int64(2),
+				ContinuationToken: aws.String(p.ContinuationToken),
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.ListFiles(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("failed list objects", func() {
+			It("should return error", func() {
+				reqErr := awserr.NewRequestFailure(awserr.New("NoSuchBucket", "", nil), http.StatusNotFound, "mock-request-id")
+				cl.EXPECT().
+					ListObjectsV2WithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(nil, reqErr).
+					Times(1)
+
+				res, err := s.ListFiles(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrNotFound)).To(BeTrue())
+			})
+		})
+
+		When("page is truncated", func() {
+			It("should return files, prefixes and the next token", func() {
+				out := &s3.ListObjectsV2Output{
+					Contents: []*s3.Object{
+						{Key: aws.String("tenant-1/a.jpg"), Size: aws.Int64(2), LastModified: aws.Time(currentTime)},
+						{Key: aws.String("tenant-1/b.jpg"), Size: aws.Int64(3), LastModified: aws.Time(currentTime)},
+					},
+					CommonPrefixes: []*s3.CommonPrefix{
+						{Prefix: aws.String("tenant-1/sub/")},
+					},
+					IsTruncated:           aws.Bool(true),
+					NextContinuationToken: aws.String("next-token"),
+				}
+				cl.EXPECT().
+					ListObjectsV2WithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(out, nil).
+					Times(1)
+
+				res, err := s.ListFiles(ctx, p)
+
+				eRes := &goseidon.ListFileResult{
+					Files: []goseidon.ListFileItem{
+						{Id: "tenant-1/a.jpg", Size: 2, LastModified: currentTime},
+						{Id: "tenant-1/b.jpg", Size: 3, LastModified: currentTime},
+					},
+					Prefixes:              []string{"tenant-1/sub/"},
+					NextContinuationToken: "next-token",
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("last page is listed", func() {
+			It("should not return the next token", func() {
+				out := &s3.ListObjectsV2Output{
+					Contents: []*s3.Object{
+						{Key: aws.String("tenant-1/c.jpg"), Size: aws.Int64(1), LastModified: aws.Time(currentTime)},
+					},
+					IsTruncated:           aws.Bool(false),
+					NextContinuationToken: aws.String("stale-token"),
+				}
+				cl.EXPECT().
+					ListObjectsV2WithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(out, nil).
+					Times(1)
+
+				res, err := s.ListFiles(ctx, p)
+
+				eRes := &goseidon.ListFileResult{
+					Files: []goseidon.ListFileItem{
+						{Id: "tenant-1/c.jpg", Size: 1, LastModified: currentTime},
+					},
+					Prefixes: []string{},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("no filter is given", func() {
+			It("should list the whole bucket with the default page size", func() {
+				param = &s3.ListObjectsV2Input{
+					Bucket: aws.String(cfg.BucketName),
+				}
+				cl.EXPECT().
+					ListObjectsV2WithContext(gomock.Eq(ctx), gomock.Eq(param)).
+					Return(&s3.ListObjectsV2Output{}, nil).
+					Times(1)
+
+				res, err := s.ListFiles(ctx, goseidon.ListFileParam{})
+
+				eRes := &goseidon.ListFileResult{
+					Files:    []goseidon.ListFileItem{},
+					Prefixes: []string{},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("SignURL method", func() {
+		var (
+			ctx         context.Context
+			s           *aws_s3.AwsS3Storage
+			p           goseidon.SignURLParam
+			cl          *awsmock.MockAwsS3Client
+			clo         *clock.MockClock
+			cfg         *aws_s3.AwsS3Config
+			currentTime time.Time
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ctrl := gomock.NewController(t)
+			cl = awsmock.NewMockAwsS3Client(ctrl)
+			clo = clock.NewMockClock(ctrl)
+			cfg = &aws_s3.AwsS3Config{
+				BucketName: "mock-bucket-name",
+			}
+			currentTime = time.Now()
+
+			s = &aws_s3.AwsS3Storage{
+				Config: cfg,
+				Client: cl,
+				Clock:  clo,
+			}
+			p = goseidon.SignURLParam{
+				Id:     "mock-file-id",
+				Method: http.MethodGet,
+				Expiry: 15 * time.Minute,
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.SignURL(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("param is invalid", func() {
+			It("should return error", func() {
+				p.Method = http.MethodDelete
+
+				res, err := s.SignURL(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal(`unsupported sign url method "DELETE"`))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("failed presign request", func() {
+			It("should return error", func() {
+				param := &s3.GetObjectInput{
+					Bucket: aws.String(cfg.BucketName),
+					Key:    aws.String(p.Id),
+				}
+				req := newPresignRequest(param, &s3.GetObjectOutput{}, nil, fmt.Errorf("missing credential"))
+				cl.EXPECT().
+					GetObjectRequest(gomock.Eq(param)).
+					Return(req, &s3.GetObjectOutput{}).
+					Times(1)
+
+				res, err := s.SignURL(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("missing credential")))
+			})
+		})
+
+		When("success sign get url", func() {
+			It("should return result", func() {
+				param := &s3.GetObjectInput{
+					Bucket: aws.String(cfg.BucketName),
+					Key:    aws.String(p.Id),
+				}
+				req := newPresignRequest(param, &s3.GetObjectOutput{}, nil, nil)
+				cl.EXPECT().
+					GetObjectRequest(gomock.Eq(param)).
+					Return(req, &s3.GetObjectOutput{}).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.SignURL(ctx, p)
+
+				eRes := &goseidon.SignURLResult{
+					URL:       "https://mock-bucket-name.s3.amazonaws.com/?X-Amz-Signature=mock-signature",
+					Method:    http.MethodGet,
+					Headers:   map[string]string{},
+					ExpiresAt: currentTime.Add(p.Expiry),
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("success sign put url with content type", func() {
+			It("should return signed headers", func() {
+				p.Method = http.MethodPut
+				p.ContentType = "image/png"
+				param := &s3.PutObjectInput{
+					Bucket:      aws.String(cfg.BucketName),
+					Key:         aws.String(p.Id),
+					ContentType: aws.String(p.ContentType),
+				}
+				signed := http.Header{"Content-Type": []string{p.ContentType}}
+				req := newPresignRequest(param, &s3.PutObjectOutput{}, signed, nil)
+				cl.EXPECT().
+					PutObjectRequest(gomock.Eq(param)).
+					Return(req, &s3.PutObjectOutput{}).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.SignURL(ctx, p)
+
+				eRes := &goseidon.SignURLResult{
+					URL:    "https://mock-bucket-name.s3.amazonaws.com/?X-Amz-Signature=mock-signature",
+					Method: http.MethodPut,
+					Headers: map[string]string{
+						"Content-Type": "image/png",
+					},
+					ExpiresAt: currentTime.Add(p.Expiry),
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
 })
 
 type readCloser struct {
@@ -364,6 +1436,47 @@ func (rc *readCloser) Close() error {
 	return nil
 }
 
+func newRequest(params, data interface{}, sendErr error) *request.Request {
+	op := &request.Operation{
+		Name:       "MockOperation",
+		HTTPMethod: "PUT",
+		HTTPPath:   "/",
+	}
+	handlers := request.Handlers{}
+	handlers.Send.PushBack(func(r *request.Request) {
+		r.Error = sendErr
+	})
+	return request.New(aws.Config{}, metadata.ClientInfo{}, handlers, nil, op, params, data)
+}
+
+// applyOptions apply the call options to req, so the headers they set can be checked
+func applyOptions(req *request.Request) func(aws.Context, interface{}, ...request.Option) {
+	return func(_ aws.Context, _ interface{}, opts ...request.Option) {
+		req.ApplyOptions(opts...)
+	}
+}
+
+func newPresignRequest(params, data interface{}, signed http.Header, signErr error) *request.Request {
+	op := &request.Operation{
+		Name:       "MockOperation",
+		HTTPMethod: "GET",
+		HTTPPath:   "/",
+	}
+	handlers := request.Handlers{}
+	handlers.Sign.PushBack(func(r *request.Request) {
+		if signErr != nil {
+			r.Error = signErr
+			return
+		}
+		r.HTTPRequest.URL.RawQuery = "X-Amz-Signature=mock-signature"
+		r.SignedHeaderVals = signed
+	})
+	info := metadata.ClientInfo{
+		Endpoint: "https://mock-bucket-name.s3.amazonaws.com",
+	}
+	return request.New(aws.Config{}, info, handlers, nil, op, params, data)
+}
+
 type withFailedOption struct {
 }
 
@@ -0,0 +1,77 @@
+package aws_s3
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+)
+
+// ListVersions list the versions and delete markers of a single key, newest first,
+// the continuation token is the version id marker of the next page
+func (s *AwsS3Storage) ListVersions(ctx context.Context, p goseidon.ListVersionsParam) (*goseidon.ListVersionsResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateKey(p.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.ListObjectVersionsInput{
+		Bucket: aws.String(s.Config.BucketName),
+		Prefix: aws.String(p.Id),
+	}
+	if p.PageSize > 0 {
+		input.MaxKeys = aws.Int64(int64(p.PageSize))
+	}
+	if p.ContinuationToken != "" {
+		input.KeyMarker = aws.String(p.Id)
+		input.VersionIdMarker = aws.String(p.ContinuationToken)
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	out, err := s.Client.ListObjectVersionsWithContext(ctx, input, s.requestOptions()...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	// the prefix also match longer keys, they're listed after every version of the key
+	res := &goseidon.ListVersionsResult{
+		Versions: []goseidon.FileVersion{},
+	}
+	for _, version := range out.Versions {
+		if aws.StringValue(version.Key) != p.Id {
+			continue
+		}
+		res.Versions = append(res.Versions, goseidon.FileVersion{
+			VersionId:    aws.StringValue(version.VersionId),
+			Size:         aws.Int64Value(version.Size),
+			LastModified: aws.TimeValue(version.LastModified),
+			IsLatest:     aws.BoolValue(version.IsLatest),
+		})
+	}
+	for _, marker := range out.DeleteMarkers {
+		if aws.StringValue(marker.Key) != p.Id {
+			continue
+		}
+		res.Versions = append(res.Versions, goseidon.FileVersion{
+			VersionId:      aws.StringValue(marker.VersionId),
+			LastModified:   aws.TimeValue(marker.LastModified),
+			IsLatest:       aws.BoolValue(marker.IsLatest),
+			IsDeleteMarker: true,
+		})
+	}
+	sort.SliceStable(res.Versions, func(i, j int) bool {
+		return res.Versions[i].LastModified.After(res.Versions[j].LastModified)
+	})
+
+	if aws.BoolValue(out.IsTruncated) && aws.StringValue(out.NextKeyMarker) == p.Id {
+		res.NextContinuationToken = aws.StringValue(out.NextVersionIdMarker)
+	}
+	return res, nil
+}
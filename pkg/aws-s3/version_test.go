@@ -0,0 +1,223 @@
+package aws_s3_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	goseidon "github.com/go-seidon/core"
+	awsmock "github.com/go-seidon/core/internal/aws"
+	"github.com/go-seidon/core/internal/clock"
+	aws_s3 "github.com/go-seidon/core/pkg/aws-s3"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Version", func() {
+	var (
+		ctx         context.Context
+		s           *aws_s3.AwsS3Storage
+		cfg         *aws_s3.AwsS3Config
+		cl          *awsmock.MockAwsS3Client
+		clo         *clock.MockClock
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		cl = awsmock.NewMockAwsS3Client(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		cfg = &aws_s3.AwsS3Config{
+			BucketName: "mock-bucket-name",
+		}
+		currentTime = time.Now()
+		s = &aws_s3.AwsS3Storage{
+			Config: cfg,
+			Client: cl,
+			Clock:  clo,
+		}
+	})
+
+	Context("UploadFile method", func() {
+		When("bucket is versioned", func() {
+			It("should return the created version", func() {
+				cl.EXPECT().
+					PutObjectWithContext(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+					Return(&s3.PutObjectOutput{VersionId: aws.String("v2")}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadFile(ctx, goseidon.UploadFileParam{FileId: "a.txt"})
+
+				Expect(err).To(BeNil())
+				Expect(res.VersionId).To(Equal("v2"))
+			})
+		})
+	})
+
+	Context("RetrieveStream method", func() {
+		When("version id is given", func() {
+			It("should read the given version", func() {
+				cl.EXPECT().
+					GetObjectWithContext(gomock.Eq(ctx), gomock.Eq(&s3.GetObjectInput{
+						Bucket:       aws.String(cfg.BucketName),
+						Key:          aws.String("a.txt"),
+						ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+						VersionId:    aws.String("v1"),
+					})).
+					Return(&s3.GetObjectOutput{
+						Body:          io.NopCloser(strings.NewReader("old")),
+						ContentLength: aws.Int64(3),
+						VersionId:     aws.String("v1"),
+					}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveStream(ctx, goseidon.RetrieveFileParam{Id: "a.txt", VersionId: "v1"})
+
+				Expect(err).To(BeNil())
+				Expect(res.VersionId).To(Equal("v1"))
+			})
+		})
+	})
+
+	Context("DeleteFile method", func() {
+		When("version id is given", func() {
+			It("should delete the given version", func() {
+				cl.EXPECT().
+					DeleteObjectWithContext(gomock.Eq(ctx), gomock.Eq(&s3.DeleteObjectInput{
+						Bucket:    aws.String(cfg.BucketName),
+						Key:       aws.String("a.txt"),
+						VersionId: aws.String("v1"),
+					})).
+					Return(&s3.DeleteObjectOutput{VersionId: aws.String("v1")}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt", VersionId: "v1"})
+
+				eRes := &goseidon.DeleteFileResult{
+					Id:        "a.txt",
+					DeletedAt: currentTime,
+					VersionId: "v1",
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListVersions method", func() {
+		var (
+			p goseidon.ListVersionsParam
+		)
+
+		BeforeEach(func() {
+			p = goseidon.ListVersionsParam{
+				Id:       "a.txt",
+				PageSize: 2,
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.ListVersions(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("file id is invalid", func() {
+			It("should return error", func() {
+				p.Id = ""
+				res, err := s.ListVersions(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("failed list versions", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					ListObjectVersionsWithContext(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := s.ListVersions(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("network error"))
+			})
+		})
+
+		When("key has versions and delete markers", func() {
+			It("should return them newest first", func() {
+				cl.EXPECT().
+					ListObjectVersionsWithContext(gomock.Eq(ctx), gomock.Eq(&s3.ListObjectVersionsInput{
+						Bucket:  aws.String(cfg.BucketName),
+						Prefix:  aws.String("a.txt"),
+						MaxKeys: aws.Int64(2),
+					})).
+					Return(&s3.ListObjectVersionsOutput{
+						Versions: []*s3.ObjectVersion{
+							{Key: aws.String("a.txt"), VersionId: aws.String("v1"), Size: aws.Int64(3), LastModified: aws.Time(currentTime.Add(-time.Hour))},
+							{Key: aws.String("a.txt.bak"), VersionId: aws.String("v9"), LastModified: aws.Time(currentTime)},
+						},
+						DeleteMarkers: []*s3.DeleteMarkerEntry{
+							{Key: aws.String("a.txt"), VersionId: aws.String("v2"), IsLatest: aws.Bool(true), LastModified: aws.Time(currentTime)},
+						},
+						IsTruncated:         aws.Bool(true),
+						NextKeyMarker:       aws.String("a.txt"),
+						NextVersionIdMarker: aws.String("v1"),
+					}, nil).
+					Times(1)
+
+				res, err := s.ListVersions(ctx, p)
+
+				eRes := &goseidon.ListVersionsResult{
+					Versions: []goseidon.FileVersion{
+						{VersionId: "v2", LastModified: currentTime, IsLatest: true, IsDeleteMarker: true},
+						{VersionId: "v1", Size: 3, LastModified: currentTime.Add(-time.Hour)},
+					},
+					NextContinuationToken: "v1",
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("continuation token is given", func() {
+			It("should resume after the token", func() {
+				p.ContinuationToken = "v1"
+				cl.EXPECT().
+					ListObjectVersionsWithContext(gomock.Eq(ctx), gomock.Eq(&s3.ListObjectVersionsInput{
+						Bucket:          aws.String(cfg.BucketName),
+						Prefix:          aws.String("a.txt"),
+						MaxKeys:         aws.Int64(2),
+						KeyMarker:       aws.String("a.txt"),
+						VersionIdMarker: aws.String("v1"),
+					})).
+					Return(&s3.ListObjectVersionsOutput{
+						IsTruncated:   aws.Bool(true),
+						NextKeyMarker: aws.String("a.txt.bak"),
+					}, nil).
+					Times(1)
+
+				res, err := s.ListVersions(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Versions).To(BeEmpty())
+				Expect(res.NextContinuationToken).To(BeEmpty())
+			})
+		})
+	})
+})
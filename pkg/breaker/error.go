@@ -0,0 +1,25 @@
+package breaker
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrOpen is matched by every OpenError
+var ErrOpen = errors.New("circuit breaker is open")
+
+// OpenError is returned without calling the storage while the breaker reject calls,
+// RetryAt is when the next trial call is let through, it's zero while
+// the half open breaker wait for its trial calls
+type OpenError struct {
+	State   State
+	RetryAt time.Time
+}
+
+func (e *OpenError) Error() string {
+	return ErrOpen.Error()
+}
+
+func (e *OpenError) Is(target error) bool {
+	return target == ErrOpen
+}
@@ -0,0 +1,154 @@
+package breaker
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultFailureRate      = 0.5
+	defaultMinRequests      = 10
+	defaultWindow           = time.Minute
+	defaultCoolDown         = 30 * time.Second
+	defaultHalfOpenRequests = 1
+)
+
+// BreakerConfig hold the breaker policy. the closed breaker open once FailureRate
+// of the calls made in the current Window fail, given at least MinRequests calls.
+// it stays open for CoolDown then let HalfOpenRequests trial calls through
+type BreakerConfig struct {
+	FailureRate      float64
+	MinRequests      int
+	Window           time.Duration
+	CoolDown         time.Duration
+	HalfOpenRequests int
+	IsFailure        func(err error) bool
+	OnStateChange    func(from, to State)
+}
+
+type BreakerStorageOption interface {
+	Apply(c *BreakerConfig) error
+}
+
+type withFailureRate struct {
+	rate        float64
+	minRequests int
+}
+
+func (o *withFailureRate) Apply(c *BreakerConfig) error {
+	if o.rate <= 0 || o.rate > 1 {
+		return fmt.Errorf("invalid failure rate")
+	}
+	if o.minRequests < 1 {
+		return fmt.Errorf("invalid min requests")
+	}
+	c.FailureRate = o.rate
+	c.MinRequests = o.minRequests
+	return nil
+}
+
+// WithFailureRate open the breaker once rate of the calls fail,
+// the rate is ignored until minRequests calls are made in the window
+func WithFailureRate(rate float64, minRequests int) BreakerStorageOption {
+	return &withFailureRate{
+		rate:        rate,
+		minRequests: minRequests,
+	}
+}
+
+type withWindow struct {
+	window time.Duration
+}
+
+func (o *withWindow) Apply(c *BreakerConfig) error {
+	if o.window <= 0 {
+		return fmt.Errorf("invalid window")
+	}
+	c.Window = o.window
+	return nil
+}
+
+// WithWindow set how long the closed breaker count calls before starting over
+func WithWindow(window time.Duration) BreakerStorageOption {
+	return &withWindow{
+		window: window,
+	}
+}
+
+type withCoolDown struct {
+	coolDown time.Duration
+}
+
+func (o *withCoolDown) Apply(c *BreakerConfig) error {
+	if o.coolDown <= 0 {
+		return fmt.Errorf("invalid cool down")
+	}
+	c.CoolDown = o.coolDown
+	return nil
+}
+
+// WithCoolDown set how long the breaker stays open before a trial call
+func WithCoolDown(coolDown time.Duration) BreakerStorageOption {
+	return &withCoolDown{
+		coolDown: coolDown,
+	}
+}
+
+type withHalfOpenRequests struct {
+	requests int
+}
+
+func (o *withHalfOpenRequests) Apply(c *BreakerConfig) error {
+	if o.requests < 1 {
+		return fmt.Errorf("invalid half open requests")
+	}
+	c.HalfOpenRequests = o.requests
+	return nil
+}
+
+// WithHalfOpenRequests set the number of trial calls,
+// the breaker close once they all succeed and open again on the first failure
+func WithHalfOpenRequests(requests int) BreakerStorageOption {
+	return &withHalfOpenRequests{
+		requests: requests,
+	}
+}
+
+type withFailureClassifier struct {
+	isFailure func(err error) bool
+}
+
+func (o *withFailureClassifier) Apply(c *BreakerConfig) error {
+	if o.isFailure == nil {
+		return fmt.Errorf("invalid failure classifier")
+	}
+	c.IsFailure = o.isFailure
+	return nil
+}
+
+// WithFailureClassifier decide which error count as failure, IsFailure is used by default
+func WithFailureClassifier(isFailure func(err error) bool) BreakerStorageOption {
+	return &withFailureClassifier{
+		isFailure: isFailure,
+	}
+}
+
+type withStateChange struct {
+	onStateChange func(from, to State)
+}
+
+func (o *withStateChange) Apply(c *BreakerConfig) error {
+	if o.onStateChange == nil {
+		return fmt.Errorf("invalid state change callback")
+	}
+	c.OnStateChange = o.onStateChange
+	return nil
+}
+
+// WithStateChange call fn after every state change, outside of the breaker lock
+// so fn may use the breaker
+func WithStateChange(fn func(from, to State)) BreakerStorageOption {
+	return &withStateChange{
+		onStateChange: fn,
+	}
+}
@@ -0,0 +1,59 @@
+package breaker_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/core/pkg/breaker"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Breaker Option", func() {
+	var (
+		cfg *breaker.BreakerConfig
+	)
+
+	BeforeEach(func() {
+		cfg = &breaker.BreakerConfig{}
+	})
+
+	DescribeTable("invalid option",
+		func(opt breaker.BreakerStorageOption, eErr error) {
+			err := opt.Apply(cfg)
+
+			Expect(err).To(Equal(eErr))
+		},
+		Entry("failure rate", breaker.WithFailureRate(0, 10), fmt.Errorf("invalid failure rate")),
+		Entry("min requests", breaker.WithFailureRate(0.5, 0), fmt.Errorf("invalid min requests")),
+		Entry("window", breaker.WithWindow(0), fmt.Errorf("invalid window")),
+		Entry("cool down", breaker.WithCoolDown(-time.Second), fmt.Errorf("invalid cool down")),
+		Entry("half open requests", breaker.WithHalfOpenRequests(0), fmt.Errorf("invalid half open requests")),
+		Entry("failure classifier", breaker.WithFailureClassifier(nil), fmt.Errorf("invalid failure classifier")),
+		Entry("state change callback", breaker.WithStateChange(nil), fmt.Errorf("invalid state change callback")),
+	)
+
+	When("success apply options", func() {
+		It("should set the policy", func() {
+			opts := []breaker.BreakerStorageOption{
+				breaker.WithFailureRate(0.25, 20),
+				breaker.WithWindow(time.Second),
+				breaker.WithCoolDown(time.Minute),
+				breaker.WithHalfOpenRequests(3),
+				breaker.WithFailureClassifier(func(error) bool { return true }),
+				breaker.WithStateChange(func(from, to breaker.State) {}),
+			}
+			for _, opt := range opts {
+				Expect(opt.Apply(cfg)).To(BeNil())
+			}
+
+			Expect(cfg.FailureRate).To(Equal(0.25))
+			Expect(cfg.MinRequests).To(Equal(20))
+			Expect(cfg.Window).To(Equal(time.Second))
+			Expect(cfg.CoolDown).To(Equal(time.Minute))
+			Expect(cfg.HalfOpenRequests).To(Equal(3))
+			Expect(cfg.IsFailure(fmt.Errorf("unknown"))).To(BeTrue())
+			Expect(cfg.OnStateChange).ToNot(BeNil())
+		})
+	})
+})
@@ -0,0 +1,265 @@
+package breaker
+
+import (
+	"context"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// every optional call must be admitted by the breaker and report its outcome,
+// so a provider failing on streams or listings open it for the base calls too.
+// the interface is checked first: goseidon.Unsupported is returned even while open
+
+func (s *BreakerStorage) UploadStream(ctx context.Context, p goseidon.UploadStreamParam) (*goseidon.UploadFileResult, error) {
+	storage, ok := s.Storage.(goseidon.StreamUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("stream upload")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.UploadStream(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RetrieveStream count the outcome of opening the stream, reading it isn't counted
+func (s *BreakerStorage) RetrieveStream(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveStreamResult, error) {
+	storage, ok := s.Storage.(goseidon.StreamRetriever)
+	if !ok {
+		return nil, goseidon.Unsupported("stream retrieve")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.RetrieveStream(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) StatFile(ctx context.Context, p goseidon.StatFileParam) (*goseidon.StatFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Stater)
+	if !ok {
+		return nil, goseidon.Unsupported("stat")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.StatFile(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) ListFiles(ctx context.Context, p goseidon.ListFileParam) (*goseidon.ListFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Lister)
+	if !ok {
+		return nil, goseidon.Unsupported("list")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.ListFiles(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) SignURL(ctx context.Context, p goseidon.SignURLParam) (*goseidon.SignURLResult, error) {
+	storage, ok := s.Storage.(goseidon.URLSigner)
+	if !ok {
+		return nil, goseidon.Unsupported("sign url")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.SignURL(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) CreateMultipartUpload(ctx context.Context, p goseidon.CreateMultipartParam) (*goseidon.CreateMultipartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.CreateMultipartUpload(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) UploadPart(ctx context.Context, p goseidon.UploadPartParam) (*goseidon.UploadPartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.UploadPart(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) CompleteMultipartUpload(ctx context.Context, p goseidon.CompleteMultipartParam) (*goseidon.UploadFileResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.CompleteMultipartUpload(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) AbortMultipartUpload(ctx context.Context, p goseidon.AbortMultipartParam) (*goseidon.AbortMultipartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.AbortMultipartUpload(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) ListParts(ctx context.Context, p goseidon.ListPartsParam) (*goseidon.ListPartsResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.ListParts(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) CopyFile(ctx context.Context, p goseidon.CopyFileParam) (*goseidon.CopyFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Copier)
+	if !ok {
+		return nil, goseidon.Unsupported("copy")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.CopyFile(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) MoveFile(ctx context.Context, p goseidon.MoveFileParam) (*goseidon.MoveFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Mover)
+	if !ok {
+		return nil, goseidon.Unsupported("move")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.MoveFile(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) DeleteFiles(ctx context.Context, p goseidon.DeleteFilesParam) (*goseidon.DeleteFilesResult, error) {
+	storage, ok := s.Storage.(goseidon.BatchDeleter)
+	if !ok {
+		return nil, goseidon.Unsupported("batch delete")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.DeleteFiles(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeletePrefix return the result gathered by an interrupted call along with its error
+func (s *BreakerStorage) DeletePrefix(ctx context.Context, p goseidon.DeletePrefixParam) (*goseidon.DeletePrefixResult, error) {
+	storage, ok := s.Storage.(goseidon.PrefixDeleter)
+	if !ok {
+		return nil, goseidon.Unsupported("prefix delete")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.DeletePrefix(ctx, p)
+	s.record(generation, err)
+	return res, err
+}
+
+func (s *BreakerStorage) ListVersions(ctx context.Context, p goseidon.ListVersionsParam) (*goseidon.ListVersionsResult, error) {
+	storage, ok := s.Storage.(goseidon.VersionLister)
+	if !ok {
+		return nil, goseidon.Unsupported("list versions")
+	}
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.ListVersions(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
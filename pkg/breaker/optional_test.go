@@ -0,0 +1,141 @@
+package breaker_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/pkg/breaker"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Optional interfaces", func() {
+	var (
+		ctx       context.Context
+		s         *breaker.BreakerStorage
+		st        *goseidon.MockFullStorage
+		now       time.Time
+		transient error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		st = goseidon.NewMockFullStorage(ctrl)
+		clo := clock.NewMockClock(ctrl)
+		now = time.Now()
+		clo.EXPECT().Now().DoAndReturn(func() time.Time { return now }).AnyTimes()
+		transient = goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("service unavailable"))
+		s = &breaker.BreakerStorage{
+			Config: &breaker.BreakerConfig{
+				FailureRate:      0.5,
+				MinRequests:      2,
+				Window:           time.Minute,
+				CoolDown:         30 * time.Second,
+				HalfOpenRequests: 1,
+				IsFailure:        breaker.IsFailure,
+			},
+			Storage: st,
+			Clock:   clo,
+		}
+	})
+
+	When("wrapped storage doesn't implement the interface", func() {
+		It("should return error even when the breaker is open", func() {
+			p := goseidon.StatFileParam{Id: "a.txt"}
+			st.MockStater.EXPECT().
+				StatFile(gomock.Eq(ctx), gomock.Eq(p)).
+				Return(nil, transient).
+				Times(2)
+			s.StatFile(ctx, p)
+			s.StatFile(ctx, p)
+			ctrl := gomock.NewController(GinkgoT())
+			s.Storage = goseidon.NewMockStorage(ctrl)
+
+			r, err := s.ListFiles(ctx, goseidon.ListFileParam{Prefix: "a/"})
+
+			Expect(r).To(BeNil())
+			Expect(errors.Is(err, goseidon.ErrUnsupported)).To(BeTrue())
+			Expect(errors.Is(err, breaker.ErrOpen)).To(BeFalse())
+		})
+	})
+
+	When("optional calls keep failing", func() {
+		It("should open the breaker and fail fast", func() {
+			p := goseidon.StatFileParam{Id: "a.txt"}
+			st.MockStater.EXPECT().
+				StatFile(gomock.Eq(ctx), gomock.Eq(p)).
+				Return(nil, transient).
+				Times(2)
+
+			s.StatFile(ctx, p)
+			s.StatFile(ctx, p)
+			r, err := s.ListFiles(ctx, goseidon.ListFileParam{Prefix: "a/"})
+
+			Expect(r).To(BeNil())
+			var oerr *breaker.OpenError
+			Expect(errors.As(err, &oerr)).To(BeTrue())
+			Expect(oerr.State).To(Equal(breaker.StateOpen))
+			Expect(s.State()).To(Equal(breaker.StateOpen))
+		})
+	})
+
+	When("breaker is open", func() {
+		It("should not open the stream", func() {
+			p := goseidon.UploadStreamParam{FileId: "a.txt", FileData: strings.NewReader("a")}
+			st.MockStreamUploader.EXPECT().
+				UploadStream(gomock.Eq(ctx), gomock.Eq(p)).
+				Return(nil, transient).
+				Times(2)
+			s.UploadStream(ctx, p)
+			s.UploadStream(ctx, p)
+
+			r, err := s.RetrieveStream(ctx, goseidon.RetrieveFileParam{Id: "a.txt"})
+
+			Expect(r).To(BeNil())
+			var oerr *breaker.OpenError
+			Expect(errors.As(err, &oerr)).To(BeTrue())
+		})
+	})
+
+	When("optional call succeeds", func() {
+		It("should return the wrapped result", func() {
+			p := goseidon.CopyFileParam{SourceId: "a.txt", DestinationId: "b.txt"}
+			res := &goseidon.CopyFileResult{SourceId: "a.txt", DestinationId: "b.txt"}
+			st.MockCopier.EXPECT().
+				CopyFile(gomock.Eq(ctx), gomock.Eq(p)).
+				Return(res, nil).
+				Times(1)
+
+			r, err := s.CopyFile(ctx, p)
+
+			Expect(r).To(Equal(res))
+			Expect(err).To(BeNil())
+			Expect(s.State()).To(Equal(breaker.StateClosed))
+		})
+	})
+
+	Context("DeletePrefix method", func() {
+		When("delete is interrupted", func() {
+			It("should return the partial result", func() {
+				p := goseidon.DeletePrefixParam{Prefix: "a/"}
+				res := &goseidon.DeletePrefixResult{Prefix: "a/", Matched: 2, Deleted: 1}
+				st.MockPrefixDeleter.EXPECT().
+					DeletePrefix(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(res, transient).
+					Times(1)
+
+				r, err := s.DeletePrefix(ctx, p)
+
+				Expect(r).To(Equal(res))
+				Expect(err).To(Equal(transient))
+			})
+		})
+	})
+})
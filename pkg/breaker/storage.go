@@ -0,0 +1,274 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+)
+
+type State int
+
+const (
+	// StateClosed let every call through
+	StateClosed State = iota
+	// StateOpen reject every call with OpenError
+	StateOpen
+	// StateHalfOpen let a limited number of trial calls through
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// BreakerStorage stop calling the wrapped storage once it keeps failing,
+// callers fail fast with OpenError instead of waiting on an unhealthy provider
+type BreakerStorage struct {
+	Config  *BreakerConfig
+	Storage goseidon.Storage
+	Clock   clock.Clock
+
+	mu    sync.Mutex
+	state State
+	// generation change with the state, so a call outliving its state isn't counted
+	generation  uint64
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	trials      int
+	successes   int
+}
+
+type stateChange struct {
+	from, to State
+}
+
+func (s *BreakerStorage) UploadFile(ctx context.Context, p goseidon.UploadFileParam) (*goseidon.UploadFileResult, error) {
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.Storage.UploadFile(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) RetrieveFile(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.Storage.RetrieveFile(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *BreakerStorage) DeleteFile(ctx context.Context, p goseidon.DeleteFileParam) (*goseidon.DeleteFileResult, error) {
+	generation, err := s.admit()
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.Storage.DeleteFile(ctx, p)
+	s.record(generation, err)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// State return the current state, an open breaker turn half open once its cool down is over
+func (s *BreakerStorage) State() State {
+	s.mu.Lock()
+	changes := s.refresh(s.Clock.Now())
+	state := s.state
+	s.mu.Unlock()
+
+	s.notify(changes)
+	return state
+}
+
+// admit decide whether a call is let through,
+// it returns the generation the call's outcome belong to
+func (s *BreakerStorage) admit() (uint64, error) {
+	s.mu.Lock()
+	now := s.Clock.Now()
+	changes := s.refresh(now)
+
+	var err error
+	switch s.state {
+	case StateOpen:
+		err = &OpenError{
+			State:   StateOpen,
+			RetryAt: s.openedAt.Add(s.Config.CoolDown),
+		}
+	case StateHalfOpen:
+		if s.trials >= s.Config.HalfOpenRequests {
+			err = &OpenError{
+				State: StateHalfOpen,
+			}
+		} else {
+			s.trials++
+		}
+	}
+	generation := s.generation
+	s.mu.Unlock()
+
+	s.notify(changes)
+	return generation, err
+}
+
+// record count the outcome of an admitted call
+func (s *BreakerStorage) record(generation uint64, err error) {
+	s.mu.Lock()
+	now := s.Clock.Now()
+	changes := s.refresh(now)
+
+	if generation == s.generation {
+		failed := err != nil && s.Config.IsFailure(err)
+		switch s.state {
+		case StateClosed:
+			s.requests++
+			if failed {
+				s.failures++
+			}
+			if s.requests >= s.Config.MinRequests &&
+				float64(s.failures)/float64(s.requests) >= s.Config.FailureRate {
+				changes = append(changes, s.setState(StateOpen, now))
+			}
+		case StateHalfOpen:
+			if failed {
+				changes = append(changes, s.setState(StateOpen, now))
+				break
+			}
+			// a neutral error tell nothing about the storage health,
+			// its trial slot is given back without counting it as a success
+			if err != nil {
+				s.trials--
+				break
+			}
+			s.successes++
+			if s.successes >= s.Config.HalfOpenRequests {
+				changes = append(changes, s.setState(StateClosed, now))
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	s.notify(changes)
+}
+
+// refresh apply the changes driven by time alone,
+// the closed breaker start a new window and the open one turn half open
+func (s *BreakerStorage) refresh(now time.Time) []stateChange {
+	switch s.state {
+	case StateClosed:
+		if !now.Before(s.windowStart.Add(s.Config.Window)) {
+			s.windowStart = now
+			s.requests = 0
+			s.failures = 0
+		}
+	case StateOpen:
+		if !now.Before(s.openedAt.Add(s.Config.CoolDown)) {
+			return []stateChange{s.setState(StateHalfOpen, now)}
+		}
+	}
+	return nil
+}
+
+func (s *BreakerStorage) setState(state State, now time.Time) stateChange {
+	change := stateChange{
+		from: s.state,
+		to:   state,
+	}
+	s.state = state
+	s.generation++
+
+	switch state {
+	case StateClosed:
+		s.windowStart = now
+		s.requests = 0
+		s.failures = 0
+	case StateOpen:
+		s.openedAt = now
+	case StateHalfOpen:
+		s.trials = 0
+		s.successes = 0
+	}
+	return change
+}
+
+func (s *BreakerStorage) notify(changes []stateChange) {
+	if s.Config.OnStateChange == nil {
+		return
+	}
+	for _, change := range changes {
+		s.Config.OnStateChange(change.from, change.to)
+	}
+}
+
+// IsFailure report whether err tell the storage is unhealthy,
+// errors caused by the request itself or a cancelled caller don't count
+func IsFailure(err error) bool {
+	switch {
+	case errors.Is(err, context.Canceled),
+		errors.Is(err, goseidon.ErrNotFound),
+		errors.Is(err, goseidon.ErrAlreadyExists),
+		errors.Is(err, goseidon.ErrPermission),
+		errors.Is(err, goseidon.ErrInvalidArgument),
+		errors.Is(err, goseidon.ErrChecksumMismatch):
+		return false
+	}
+	return true
+}
+
+func NewBreakerStorage(storage goseidon.Storage, opts ...BreakerStorageOption) (*BreakerStorage, error) {
+	if storage == nil {
+		return nil, fmt.Errorf("invalid storage")
+	}
+
+	cfg := &BreakerConfig{
+		FailureRate:      defaultFailureRate,
+		MinRequests:      defaultMinRequests,
+		Window:           defaultWindow,
+		CoolDown:         defaultCoolDown,
+		HalfOpenRequests: defaultHalfOpenRequests,
+		IsFailure:        IsFailure,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			return nil, fmt.Errorf("invalid breaker option")
+		}
+		err := opt.Apply(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clock, _ := clock.NewClock()
+	s := &BreakerStorage{
+		Config:  cfg,
+		Storage: storage,
+		Clock:   clock,
+	}
+	return s, nil
+}
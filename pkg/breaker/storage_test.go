@@ -0,0 +1,322 @@
+package breaker_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/pkg/breaker"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBreaker(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Breaker Package")
+}
+
+var _ = Describe("Storage", func() {
+	var (
+		ctx       context.Context
+		s         *breaker.BreakerStorage
+		st        *goseidon.MockStorage
+		clo       *clock.MockClock
+		now       time.Time
+		changes   []string
+		p         goseidon.RetrieveFileParam
+		transient error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		st = goseidon.NewMockStorage(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		now = time.Now()
+		clo.EXPECT().Now().DoAndReturn(func() time.Time { return now }).AnyTimes()
+		changes = []string{}
+		p = goseidon.RetrieveFileParam{Id: "a.txt"}
+		transient = goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("service unavailable"))
+		s = &breaker.BreakerStorage{
+			Config: &breaker.BreakerConfig{
+				FailureRate:      0.5,
+				MinRequests:      4,
+				Window:           time.Minute,
+				CoolDown:         30 * time.Second,
+				HalfOpenRequests: 1,
+				IsFailure:        breaker.IsFailure,
+				OnStateChange: func(from, to breaker.State) {
+					changes = append(changes, from.String()+" -> "+to.String())
+				},
+			},
+			Storage: st,
+			Clock:   clo,
+		}
+	})
+
+	// call make one retrieve call answered with err
+	call := func(err error) error {
+		st.EXPECT().
+			RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+			Return(nil, err).
+			Times(1)
+		_, err = s.RetrieveFile(ctx, p)
+		return err
+	}
+
+	open := func() {
+		call(nil)
+		call(nil)
+		call(transient)
+		call(transient)
+	}
+
+	Context("NewBreakerStorage function", func() {
+		When("storage is invalid", func() {
+			It("should return error", func() {
+				s, err := breaker.NewBreakerStorage(nil)
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid storage")))
+			})
+		})
+
+		When("option is invalid", func() {
+			It("should return error", func() {
+				s, err := breaker.NewBreakerStorage(st, nil)
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid breaker option")))
+			})
+		})
+
+		When("failed apply option", func() {
+			It("should return error", func() {
+				s, err := breaker.NewBreakerStorage(st, breaker.WithCoolDown(0))
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid cool down")))
+			})
+		})
+
+		When("option is not given", func() {
+			It("should use the default policy", func() {
+				s, err := breaker.NewBreakerStorage(st)
+
+				Expect(err).To(BeNil())
+				Expect(s.Config.FailureRate).To(Equal(0.5))
+				Expect(s.Config.MinRequests).To(Equal(10))
+				Expect(s.Config.Window).To(Equal(time.Minute))
+				Expect(s.Config.CoolDown).To(Equal(30 * time.Second))
+				Expect(s.Config.HalfOpenRequests).To(Equal(1))
+				Expect(s.State()).To(Equal(breaker.StateClosed))
+			})
+		})
+	})
+
+	Context("closed breaker", func() {
+		When("calls are below min requests", func() {
+			It("should stay closed", func() {
+				call(transient)
+				call(transient)
+				call(transient)
+
+				Expect(s.State()).To(Equal(breaker.StateClosed))
+				Expect(changes).To(BeEmpty())
+			})
+		})
+
+		When("failure rate is reached", func() {
+			It("should open", func() {
+				open()
+
+				Expect(s.State()).To(Equal(breaker.StateOpen))
+				Expect(changes).To(Equal([]string{"closed -> open"}))
+			})
+		})
+
+		When("errors are caused by the request", func() {
+			It("should not count them as failure", func() {
+				call(goseidon.ErrNotFound)
+				call(goseidon.ErrInvalidArgument)
+				call(context.Canceled)
+				call(transient)
+
+				Expect(s.State()).To(Equal(breaker.StateClosed))
+			})
+		})
+
+		When("window is over", func() {
+			It("should start counting over", func() {
+				call(transient)
+				call(transient)
+				call(transient)
+				now = now.Add(time.Minute)
+				call(transient)
+
+				Expect(s.State()).To(Equal(breaker.StateClosed))
+			})
+		})
+
+		When("upload and delete fail", func() {
+			It("should count them as well", func() {
+				st.EXPECT().UploadFile(gomock.Eq(ctx), gomock.Any()).Return(nil, transient).Times(2)
+				st.EXPECT().DeleteFile(gomock.Eq(ctx), gomock.Any()).Return(&goseidon.DeleteFileResult{}, nil).Times(1)
+				st.EXPECT().DeleteFile(gomock.Eq(ctx), gomock.Any()).Return(nil, transient).Times(1)
+
+				s.UploadFile(ctx, goseidon.UploadFileParam{FileId: "a.txt"})
+				s.UploadFile(ctx, goseidon.UploadFileParam{FileId: "a.txt"})
+				res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt"})
+				Expect(res).To(Equal(&goseidon.DeleteFileResult{}))
+				Expect(err).To(BeNil())
+				_, err = s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt"})
+				Expect(err).To(Equal(transient))
+
+				Expect(s.State()).To(Equal(breaker.StateOpen))
+			})
+		})
+	})
+
+	Context("open breaker", func() {
+		When("cool down isn't over", func() {
+			It("should fail fast", func() {
+				open()
+				openedAt := now
+				now = now.Add(10 * time.Second)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, breaker.ErrOpen)).To(BeTrue())
+				Expect(err.Error()).To(Equal("circuit breaker is open"))
+				var oerr *breaker.OpenError
+				Expect(errors.As(err, &oerr)).To(BeTrue())
+				Expect(oerr.State).To(Equal(breaker.StateOpen))
+				Expect(oerr.RetryAt).To(Equal(openedAt.Add(30 * time.Second)))
+			})
+		})
+
+		When("cool down is over", func() {
+			It("should turn half open", func() {
+				open()
+				now = now.Add(30 * time.Second)
+
+				Expect(s.State()).To(Equal(breaker.StateHalfOpen))
+				Expect(changes).To(Equal([]string{"closed -> open", "open -> half-open"}))
+			})
+		})
+	})
+
+	Context("half open breaker", func() {
+		BeforeEach(func() {
+			open()
+			now = now.Add(30 * time.Second)
+		})
+
+		When("trial call succeeds", func() {
+			It("should close", func() {
+				err := call(nil)
+
+				Expect(err).To(BeNil())
+				Expect(s.State()).To(Equal(breaker.StateClosed))
+				Expect(changes).To(Equal([]string{"closed -> open", "open -> half-open", "half-open -> closed"}))
+			})
+		})
+
+		When("trial call fails", func() {
+			It("should open again", func() {
+				err := call(transient)
+
+				Expect(err).To(Equal(transient))
+				Expect(s.State()).To(Equal(breaker.StateOpen))
+				Expect(changes).To(Equal([]string{"closed -> open", "open -> half-open", "half-open -> open"}))
+			})
+		})
+
+		When("trial call fails with a neutral error", func() {
+			It("should give back its slot without closing", func() {
+				err := call(goseidon.ErrNotFound)
+
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+				Expect(s.State()).To(Equal(breaker.StateHalfOpen))
+
+				err = call(nil)
+
+				Expect(err).To(BeNil())
+				Expect(s.State()).To(Equal(breaker.StateClosed))
+				Expect(changes).To(Equal([]string{"closed -> open", "open -> half-open", "half-open -> closed"}))
+			})
+		})
+
+		When("trial call is still running", func() {
+			It("should fail fast", func() {
+				var inner error
+				st.EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					DoAndReturn(func(context.Context, goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+						_, inner = s.RetrieveFile(ctx, p)
+						return &goseidon.RetrieveFileResult{}, nil
+					}).
+					Times(1)
+
+				_, err := s.RetrieveFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				var oerr *breaker.OpenError
+				Expect(errors.As(inner, &oerr)).To(BeTrue())
+				Expect(oerr.State).To(Equal(breaker.StateHalfOpen))
+				Expect(oerr.RetryAt.IsZero()).To(BeTrue())
+			})
+		})
+	})
+
+	When("call outlive the state it started in", func() {
+		It("should not be counted", func() {
+			call(nil)
+			call(nil)
+			call(transient)
+			st.EXPECT().
+				RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+				DoAndReturn(func(context.Context, goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+					// the breaker open while this call is running
+					call(transient)
+					now = now.Add(30 * time.Second)
+					Expect(s.State()).To(Equal(breaker.StateHalfOpen))
+					return nil, transient
+				}).
+				Times(1)
+
+			s.RetrieveFile(ctx, p)
+
+			Expect(s.State()).To(Equal(breaker.StateHalfOpen))
+		})
+	})
+
+	DescribeTable("IsFailure function",
+		func(err error, failure bool) {
+			Expect(breaker.IsFailure(err)).To(Equal(failure))
+		},
+		Entry("transient failure", goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("slow down")), true),
+		Entry("deadline exceeded", context.DeadlineExceeded, true),
+		Entry("unclassified", fmt.Errorf("unknown"), true),
+		Entry("not found", goseidon.ErrNotFound, false),
+		Entry("permission", goseidon.ErrPermission, false),
+		Entry("checksum mismatch", &goseidon.ChecksumMismatchError{}, false),
+		Entry("cancelled", context.Canceled, false),
+	)
+
+	DescribeTable("State string",
+		func(state breaker.State, str string) {
+			Expect(state.String()).To(Equal(str))
+		},
+		Entry("closed", breaker.StateClosed, "closed"),
+		Entry("open", breaker.StateOpen, "open"),
+		Entry("half open", breaker.StateHalfOpen, "half-open"),
+		Entry("unknown", breaker.State(9), "State(9)"),
+	)
+})
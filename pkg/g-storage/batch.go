@@ -0,0 +1,15 @@
+package g_storage
+
+import (
+	"context"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// deleteConcurrency bound the number of in flight delete requests,
+// google storage has no batch delete in its json api client
+const deleteConcurrency = 16
+
+func (s *GoogleStorage) DeleteFiles(ctx context.Context, p goseidon.DeleteFilesParam) (*goseidon.DeleteFilesResult, error) {
+	return goseidon.DeleteEach(ctx, s, p, deleteConcurrency)
+}
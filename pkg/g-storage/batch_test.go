@@ -0,0 +1,68 @@
+package g_storage_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud.google.com/go/storage"
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	g_cloud "github.com/go-seidon/core/internal/g-cloud"
+	g_storage "github.com/go-seidon/core/pkg/g-storage"
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batch", func() {
+	var (
+		ctx         context.Context
+		s           *g_storage.GoogleStorage
+		cfg         *g_storage.GoogleConfig
+		cl          *g_cloud.MockGoogleStorageClient
+		clo         *clock.MockClock
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cfg = &g_storage.GoogleConfig{
+			BucketName:   "bucket-name",
+			GoogleClient: &storage.Client{},
+		}
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		cl = g_cloud.NewMockGoogleStorageClient(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Now()
+		s = &g_storage.GoogleStorage{
+			Config: cfg,
+			Client: cl,
+			Clock:  clo,
+		}
+	})
+
+	Context("DeleteFiles method", func() {
+		When("some objects fail", func() {
+			It("should report each object outcome", func() {
+				cl.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq("a.jpg"), gomock.Eq(int64(0))).
+					Return(nil).
+					Times(1)
+				cl.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq("b.jpg"), gomock.Eq(int64(0))).
+					Return(storage.ErrObjectNotExist).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime).AnyTimes()
+
+				res, err := s.DeleteFiles(ctx, goseidon.DeleteFilesParam{Ids: []string{"a.jpg", "b.jpg"}})
+
+				Expect(err).To(BeNil())
+				Expect(res.Items[0]).To(Equal(goseidon.DeleteFileItem{Id: "a.jpg", DeletedAt: currentTime}))
+				Expect(res.Items[1].Id).To(Equal("b.jpg"))
+				Expect(errors.Is(res.Items[1].Error, goseidon.ErrNotFound)).To(BeTrue())
+			})
+		})
+	})
+})
@@ -0,0 +1,79 @@
+package g_storage
+
+import (
+	"context"
+	"fmt"
+
+	gstorage "cloud.google.com/go/storage"
+	goseidon "github.com/go-seidon/core"
+)
+
+// CopyFile copy an object inside the bucket without downloading it,
+// attributes and metadata are copied from the source
+func (s *GoogleStorage) CopyFile(ctx context.Context, p goseidon.CopyFileParam) (*goseidon.CopyFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateCopy(p.SourceId, p.DestinationId)
+	if err != nil {
+		return nil, err
+	}
+
+	conds := gstorage.Conditions{}
+	if p.Overwrite != goseidon.OverwriteReplace {
+		conds.DoesNotExist = true
+	}
+
+	_, err = s.Client.CopyObject(ctx, s.Config.BucketName, p.SourceId, p.DestinationId, conds)
+	if isPreconditionFailed(err) {
+		if p.Overwrite == goseidon.OverwriteSkip {
+			res := &goseidon.CopyFileResult{
+				SourceId:      p.SourceId,
+				DestinationId: p.DestinationId,
+				CopiedAt:      s.Clock.Now(),
+				Skipped:       true,
+			}
+			return res, nil
+		}
+		return nil, goseidon.NewError(goseidon.ErrAlreadyExists, err)
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.CopyFileResult{
+		SourceId:      p.SourceId,
+		DestinationId: p.DestinationId,
+		CopiedAt:      s.Clock.Now(),
+	}
+	return res, nil
+}
+
+// MoveFile copy the object then delete the source,
+// google storage has no rename so a failed delete leaves both objects behind
+func (s *GoogleStorage) MoveFile(ctx context.Context, p goseidon.MoveFileParam) (*goseidon.MoveFileResult, error) {
+	copyRes, err := s.CopyFile(ctx, goseidon.CopyFileParam{
+		SourceId:      p.SourceId,
+		DestinationId: p.DestinationId,
+		Overwrite:     p.Overwrite,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &goseidon.MoveFileResult{
+		SourceId:      p.SourceId,
+		DestinationId: p.DestinationId,
+		MovedAt:       copyRes.CopiedAt,
+		Skipped:       copyRes.Skipped,
+	}
+	if copyRes.Skipped {
+		return res, nil
+	}
+
+	err = s.Client.Delete(ctx, s.Config.BucketName, p.SourceId, 0)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return res, nil
+}
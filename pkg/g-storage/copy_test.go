@@ -0,0 +1,233 @@
+package g_storage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/storage"
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	g_cloud "github.com/go-seidon/core/internal/g-cloud"
+	g_storage "github.com/go-seidon/core/pkg/g-storage"
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/googleapi"
+)
+
+var _ = Describe("Copy", func() {
+	var (
+		ctx         context.Context
+		s           *g_storage.GoogleStorage
+		cfg         *g_storage.GoogleConfig
+		cl          *g_cloud.MockGoogleStorageClient
+		clo         *clock.MockClock
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cfg = &g_storage.GoogleConfig{
+			BucketName:   "bucket-name",
+			GoogleClient: &storage.Client{},
+		}
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		cl = g_cloud.NewMockGoogleStorageClient(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Now()
+		s = &g_storage.GoogleStorage{
+			Config: cfg,
+			Client: cl,
+			Clock:  clo,
+		}
+	})
+
+	Context("CopyFile method", func() {
+		var p goseidon.CopyFileParam
+
+		BeforeEach(func() {
+			p = goseidon.CopyFileParam{
+				SourceId:      "image.jpg",
+				DestinationId: "backup/image.jpg",
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.CopyFile(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("source and destination are the same file", func() {
+			It("should return error", func() {
+				p.DestinationId = p.SourceId
+				res, err := s.CopyFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("source doesn't exist", func() {
+			It("should return not found error", func() {
+				cl.EXPECT().
+					CopyObject(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, storage.ErrObjectNotExist).
+					Times(1)
+
+				res, err := s.CopyFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrNotFound)).To(BeTrue())
+			})
+		})
+
+		When("destination already exists", func() {
+			It("should return already exists error", func() {
+				cl.EXPECT().
+					CopyObject(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, &googleapi.Error{Code: http.StatusPreconditionFailed}).
+					Times(1)
+
+				res, err := s.CopyFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrAlreadyExists)).To(BeTrue())
+			})
+		})
+
+		When("destination already exists and overwrite mode is skip", func() {
+			It("should return skipped result", func() {
+				p.Overwrite = goseidon.OverwriteSkip
+				cl.EXPECT().
+					CopyObject(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, &googleapi.Error{Code: http.StatusPreconditionFailed}).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CopyFile(ctx, p)
+
+				eRes := &goseidon.CopyFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					CopiedAt:      currentTime,
+					Skipped:       true,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("success copy file", func() {
+			It("should copy only when destination doesn't exist", func() {
+				cl.EXPECT().
+					CopyObject(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.SourceId), gomock.Eq(p.DestinationId), gomock.Eq(storage.Conditions{DoesNotExist: true})).
+					Return(&storage.ObjectAttrs{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CopyFile(ctx, p)
+
+				eRes := &goseidon.CopyFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					CopiedAt:      currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("overwrite mode is replace", func() {
+			It("should copy unconditionally", func() {
+				p.Overwrite = goseidon.OverwriteReplace
+				cl.EXPECT().
+					CopyObject(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.SourceId), gomock.Eq(p.DestinationId), gomock.Eq(storage.Conditions{})).
+					Return(&storage.ObjectAttrs{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				_, err := s.CopyFile(ctx, p)
+
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("MoveFile method", func() {
+		var p goseidon.MoveFileParam
+
+		BeforeEach(func() {
+			p = goseidon.MoveFileParam{
+				SourceId:      "image.jpg",
+				DestinationId: "archive/image.jpg",
+			}
+		})
+
+		When("copy is skipped", func() {
+			It("should keep the source", func() {
+				p.Overwrite = goseidon.OverwriteSkip
+				cl.EXPECT().
+					CopyObject(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, &googleapi.Error{Code: http.StatusPreconditionFailed}).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.MoveFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Skipped).To(BeTrue())
+			})
+		})
+
+		When("failed delete source", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					CopyObject(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&storage.ObjectAttrs{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+				cl.EXPECT().
+					Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.SourceId), gomock.Eq(int64(0))).
+					Return(fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := s.MoveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("success move file", func() {
+			It("should delete the source", func() {
+				cl.EXPECT().
+					CopyObject(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&storage.ObjectAttrs{}, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+				cl.EXPECT().
+					Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.SourceId), gomock.Eq(int64(0))).
+					Return(nil).
+					Times(1)
+
+				res, err := s.MoveFile(ctx, p)
+
+				eRes := &goseidon.MoveFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					MovedAt:       currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
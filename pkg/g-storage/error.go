@@ -0,0 +1,55 @@
+package g_storage
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+
+	gstorage "cloud.google.com/go/storage"
+	goseidon "github.com/go-seidon/core"
+	"google.golang.org/api/googleapi"
+)
+
+// mapError classify google storage error using the sdk sentinel errors
+// and the http status code of the failed api call
+func mapError(err error) error {
+	if errors.Is(err, gstorage.ErrObjectNotExist) || errors.Is(err, gstorage.ErrBucketNotExist) {
+		return goseidon.NewError(goseidon.ErrNotFound, err)
+	}
+
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		switch code := gerr.Code; {
+		case code == http.StatusNotFound:
+			return goseidon.NewError(goseidon.ErrNotFound, err)
+		case code == http.StatusConflict:
+			return goseidon.NewError(goseidon.ErrAlreadyExists, err)
+		case code == http.StatusUnauthorized, code == http.StatusForbidden:
+			return goseidon.NewError(goseidon.ErrPermission, err)
+		case code == http.StatusBadRequest, code == http.StatusRequestedRangeNotSatisfiable:
+			return goseidon.NewError(goseidon.ErrInvalidArgument, err)
+		case code == http.StatusRequestTimeout, code == http.StatusTooManyRequests, code >= http.StatusInternalServerError:
+			return goseidon.NewError(goseidon.ErrTransient, err)
+		}
+		return err
+	}
+
+	var nerr net.Error
+	if errors.As(err, &nerr) && nerr.Timeout() {
+		return goseidon.NewError(goseidon.ErrTransient, err)
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return goseidon.NewError(goseidon.ErrTransient, err)
+	}
+	return err
+}
+
+// isPreconditionFailed report whether a conditional request is rejected
+func isPreconditionFailed(err error) bool {
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code == http.StatusPreconditionFailed
+	}
+	return false
+}
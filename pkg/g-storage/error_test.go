@@ -0,0 +1,94 @@
+package g_storage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"cloud.google.com/go/storage"
+	goseidon "github.com/go-seidon/core"
+	g_cloud "github.com/go-seidon/core/internal/g-cloud"
+	g_storage "github.com/go-seidon/core/pkg/g-storage"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/googleapi"
+)
+
+var _ = Describe("Error", func() {
+	var (
+		ctx context.Context
+		s   *g_storage.GoogleStorage
+		cl  *g_cloud.MockGoogleStorageClient
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		cl = g_cloud.NewMockGoogleStorageClient(ctrl)
+		s = &g_storage.GoogleStorage{
+			Config: &g_storage.GoogleConfig{
+				BucketName: "bucket-name",
+			},
+			Client: cl,
+		}
+	})
+
+	DescribeTable("google error is classified",
+		func(cause error, kind error) {
+			cl.EXPECT().
+				Delete(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(cause).
+				Times(1)
+
+			res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "mock-file-id"})
+
+			Expect(res).To(BeNil())
+			Expect(errors.Is(err, kind)).To(BeTrue())
+			Expect(errors.Unwrap(err)).To(Equal(cause))
+		},
+		Entry("object not exist", storage.ErrObjectNotExist, goseidon.ErrNotFound),
+		Entry("bucket not exist", storage.ErrBucketNotExist, goseidon.ErrNotFound),
+		Entry("status not found", &googleapi.Error{Code: http.StatusNotFound}, goseidon.ErrNotFound),
+		Entry("status conflict", &googleapi.Error{Code: http.StatusConflict}, goseidon.ErrAlreadyExists),
+		Entry("status forbidden", &googleapi.Error{Code: http.StatusForbidden}, goseidon.ErrPermission),
+		Entry("status bad request", &googleapi.Error{Code: http.StatusBadRequest}, goseidon.ErrInvalidArgument),
+		Entry("status too many requests", &googleapi.Error{Code: http.StatusTooManyRequests}, goseidon.ErrTransient),
+		Entry("status bad gateway", &googleapi.Error{Code: http.StatusBadGateway}, goseidon.ErrTransient),
+		Entry("network timeout", &timeoutError{}, goseidon.ErrTransient),
+		Entry("unexpected eof", io.ErrUnexpectedEOF, goseidon.ErrTransient),
+	)
+
+	DescribeTable("google error is not recognized",
+		func(cause error) {
+			cl.EXPECT().
+				Delete(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(cause).
+				Times(1)
+
+			res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "mock-file-id"})
+
+			Expect(res).To(BeNil())
+			Expect(err).To(Equal(cause))
+		},
+		Entry("unknown status", &googleapi.Error{Code: http.StatusMethodNotAllowed}),
+		Entry("unknown error", fmt.Errorf("unknown")),
+	)
+})
+
+type timeoutError struct {
+}
+
+func (e *timeoutError) Error() string {
+	return "i/o timeout"
+}
+
+func (e *timeoutError) Timeout() bool {
+	return true
+}
+
+func (e *timeoutError) Temporary() bool {
+	return true
+}
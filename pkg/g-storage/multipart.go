@@ -0,0 +1,393 @@
+package g_storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	gstorage "cloud.google.com/go/storage"
+	goseidon "github.com/go-seidon/core"
+)
+
+const (
+	// multipartPrefix hold a temporary object per uploaded part,
+	// parts are composed into the file once the upload is completed
+	multipartPrefix   = goseidon.ReservedKeyPrefix + "multipart/"
+	manifestName      = "manifest"
+	partPrefix        = "part-"
+	maxComposeSources = 32
+)
+
+// uploadManifest is stored as json object when the upload is created
+type uploadManifest struct {
+	FileId             string            `json:"file_id"`
+	FileName           string            `json:"file_name,omitempty"`
+	ContentType        string            `json:"content_type,omitempty"`
+	ContentDisposition string            `json:"content_disposition,omitempty"`
+	CacheControl       string            `json:"cache_control,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+}
+
+func (s *GoogleStorage) CreateMultipartUpload(ctx context.Context, p goseidon.CreateMultipartParam) (*goseidon.CreateMultipartResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateKey(p.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadId, err := newUploadId()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(uploadManifest{
+		FileId:             p.FileId,
+		FileName:           p.FileName,
+		ContentType:        p.ContentType,
+		ContentDisposition: p.ContentDisposition,
+		CacheControl:       p.CacheControl,
+		Metadata:           p.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	attrs := gstorage.ObjectAttrs{
+		Name:        uploadPrefix(uploadId) + manifestName,
+		ContentType: "application/json",
+	}
+	wc := s.Client.NewWriter(wctx, s.Config.BucketName, attrs, gstorage.Conditions{DoesNotExist: true})
+	_, err = s.Client.Copy(wc, bytes.NewReader(data))
+	if err == nil {
+		err = wc.Close()
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.CreateMultipartResult{
+		UploadId:  uploadId,
+		FileId:    p.FileId,
+		CreatedAt: s.Clock.Now(),
+	}
+	return res, nil
+}
+
+// UploadPart store the part as its own object, the writer upload it
+// through a resumable session so a large part survive transient failure
+func (s *GoogleStorage) UploadPart(ctx context.Context, p goseidon.UploadPartParam) (*goseidon.UploadPartResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	if p.PartData == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid part data"))
+	}
+	err := goseidon.ValidatePartNumber(p.PartNumber)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.readManifest(ctx, p.FileId, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	attrs := gstorage.ObjectAttrs{
+		Name: partName(p.UploadId, p.PartNumber),
+	}
+	hash := md5.New()
+	wc := s.Client.NewWriter(wctx, s.Config.BucketName, attrs, gstorage.Conditions{})
+	size, err := s.Client.Copy(wc, io.TeeReader(p.PartData, hash))
+	if err == nil {
+		err = wc.Close()
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.UploadPartResult{
+		PartNumber: p.PartNumber,
+		ETag:       hex.EncodeToString(hash.Sum(nil)),
+		Size:       size,
+		UploadedAt: s.Clock.Now(),
+	}
+	return res, nil
+}
+
+// CompleteMultipartUpload compose the parts into the file,
+// more than 32 parts are composed in several rounds of intermediate objects
+func (s *GoogleStorage) CompleteMultipartUpload(ctx context.Context, p goseidon.CompleteMultipartParam) (*goseidon.UploadFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateCompletedParts(p.Parts)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := s.readManifest(ctx, p.FileId, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	uploaded, err := s.listParts(ctx, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+	etags := map[int]string{}
+	for _, part := range uploaded {
+		etags[part.PartNumber] = part.ETag
+	}
+
+	srcs := []string{}
+	for _, part := range p.Parts {
+		etag, ok := etags[part.PartNumber]
+		if !ok {
+			return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("part %d is not uploaded", part.PartNumber))
+		}
+		if etag != part.ETag {
+			return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("part %d etag mismatch", part.PartNumber))
+		}
+		srcs = append(srcs, partName(p.UploadId, part.PartNumber))
+	}
+
+	for round := 0; len(srcs) > maxComposeSources; round++ {
+		next := []string{}
+		for i := 0; i < len(srcs); i += maxComposeSources {
+			end := i + maxComposeSources
+			if end > len(srcs) {
+				end = len(srcs)
+			}
+			attrs := gstorage.ObjectAttrs{
+				Name: fmt.Sprintf("%scompose-%d-%05d", uploadPrefix(p.UploadId), round, i/maxComposeSources),
+			}
+			_, err = s.Client.Compose(ctx, s.Config.BucketName, attrs, gstorage.Conditions{}, srcs[i:end])
+			if err != nil {
+				return nil, mapError(err)
+			}
+			next = append(next, attrs.Name)
+		}
+		srcs = next
+	}
+
+	attrs := gstorage.ObjectAttrs{
+		Name:               p.FileId,
+		ContentType:        manifest.ContentType,
+		ContentDisposition: manifest.ContentDisposition,
+		CacheControl:       manifest.CacheControl,
+		Metadata:           buildMetadata(manifest.FileName, manifest.Metadata),
+	}
+	conds := gstorage.Conditions{}
+	if p.Overwrite != goseidon.OverwriteReplace {
+		conds.DoesNotExist = true
+	}
+
+	composed, err := s.Client.Compose(ctx, s.Config.BucketName, attrs, conds, srcs)
+	if isPreconditionFailed(err) {
+		if p.Overwrite == goseidon.OverwriteSkip {
+			s.removeUpload(ctx, p.UploadId)
+			res := &goseidon.UploadFileResult{
+				FileId:     p.FileId,
+				FileName:   manifest.FileName,
+				UploadedAt: s.Clock.Now(),
+				Skipped:    true,
+			}
+			return res, nil
+		}
+		return nil, goseidon.NewError(goseidon.ErrAlreadyExists, err)
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	// the file is already committed, leftover parts are only garbage
+	s.removeUpload(ctx, p.UploadId)
+
+	res := &goseidon.UploadFileResult{
+		FileId:     p.FileId,
+		FileName:   manifest.FileName,
+		UploadedAt: s.Clock.Now(),
+		VersionId:  formatGeneration(composed.Generation),
+	}
+	return res, nil
+}
+
+func (s *GoogleStorage) AbortMultipartUpload(ctx context.Context, p goseidon.AbortMultipartParam) (*goseidon.AbortMultipartResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	_, err := s.readManifest(ctx, p.FileId, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.removeUpload(ctx, p.UploadId)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.AbortMultipartResult{
+		UploadId:  p.UploadId,
+		AbortedAt: s.Clock.Now(),
+	}
+	return res, nil
+}
+
+func (s *GoogleStorage) ListParts(ctx context.Context, p goseidon.ListPartsParam) (*goseidon.ListPartsResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	_, err := s.readManifest(ctx, p.FileId, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := s.listParts(ctx, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+	res := &goseidon.ListPartsResult{
+		Parts: parts,
+	}
+	return res, nil
+}
+
+// readManifest validate the upload id and make sure it belong to fileId
+func (s *GoogleStorage) readManifest(ctx context.Context, fileId, uploadId string) (*uploadManifest, error) {
+	err := goseidon.ValidateKey(fileId)
+	if err != nil {
+		return nil, err
+	}
+	if !isUploadId(uploadId) {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid upload id"))
+	}
+
+	rc, err := s.Client.NewReader(ctx, s.Config.BucketName, uploadPrefix(uploadId)+manifestName, 0, 0, -1)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	manifest := &uploadManifest{}
+	err = json.Unmarshal(data, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upload manifest: %w", err)
+	}
+	if manifest.FileId != fileId {
+		return nil, goseidon.NewError(goseidon.ErrNotFound, fmt.Errorf("upload is not found"))
+	}
+	return manifest, nil
+}
+
+func (s *GoogleStorage) listUpload(ctx context.Context, uploadId string) ([]*gstorage.ObjectAttrs, error) {
+	q := &gstorage.Query{
+		Prefix: uploadPrefix(uploadId),
+	}
+	res := []*gstorage.ObjectAttrs{}
+	token := ""
+	for {
+		objects, nextToken, err := s.Client.ListObjects(ctx, s.Config.BucketName, q, defaultPageSize, token)
+		if err != nil {
+			return nil, mapError(err)
+		}
+		res = append(res, objects...)
+		if nextToken == "" {
+			return res, nil
+		}
+		token = nextToken
+	}
+}
+
+func (s *GoogleStorage) listParts(ctx context.Context, uploadId string) ([]goseidon.PartItem, error) {
+	objects, err := s.listUpload(ctx, uploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := []goseidon.PartItem{}
+	for _, obj := range objects {
+		name := strings.TrimPrefix(obj.Name, uploadPrefix(uploadId))
+		if !strings.HasPrefix(name, partPrefix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(name, partPrefix))
+		if err != nil {
+			continue
+		}
+		parts = append(parts, goseidon.PartItem{
+			PartNumber:   n,
+			ETag:         hex.EncodeToString(obj.MD5),
+			Size:         obj.Size,
+			LastModified: obj.Updated,
+		})
+	}
+	return parts, nil
+}
+
+// removeUpload delete every object of the upload,
+// the manifest goes last so an interrupted removal can be retried
+func (s *GoogleStorage) removeUpload(ctx context.Context, uploadId string) error {
+	objects, err := s.listUpload(ctx, uploadId)
+	if err != nil {
+		return err
+	}
+
+	manifest := uploadPrefix(uploadId) + manifestName
+	for _, obj := range objects {
+		if obj.Name == manifest {
+			continue
+		}
+		err = s.Client.Delete(ctx, s.Config.BucketName, obj.Name, 0)
+		if err != nil && !errors.Is(err, gstorage.ErrObjectNotExist) {
+			return err
+		}
+	}
+	err = s.Client.Delete(ctx, s.Config.BucketName, manifest, 0)
+	if err != nil && !errors.Is(err, gstorage.ErrObjectNotExist) {
+		return err
+	}
+	return nil
+}
+
+func uploadPrefix(uploadId string) string {
+	return multipartPrefix + uploadId + "/"
+}
+
+func partName(uploadId string, partNumber int) string {
+	return fmt.Sprintf("%s%s%05d", uploadPrefix(uploadId), partPrefix, partNumber)
+}
+
+func newUploadId() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func isUploadId(uploadId string) bool {
+	if len(uploadId) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(uploadId)
+	return err == nil
+}
@@ -0,0 +1,454 @@
+package g_storage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	g_cloud "github.com/go-seidon/core/internal/g-cloud"
+	g_storage "github.com/go-seidon/core/pkg/g-storage"
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/googleapi"
+)
+
+var _ = Describe("Multipart", func() {
+	const (
+		uploadId     = "0123456789abcdef0123456789abcdef"
+		uploadPrefix = ".goseidon-multipart/" + uploadId + "/"
+		manifestName = uploadPrefix + "manifest"
+	)
+
+	var (
+		ctx         context.Context
+		s           *g_storage.GoogleStorage
+		cfg         *g_storage.GoogleConfig
+		cl          *g_cloud.MockGoogleStorageClient
+		clo         *clock.MockClock
+		wc          *g_cloud.MockObjectWriter
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cfg = &g_storage.GoogleConfig{
+			BucketName:   "bucket-name",
+			GoogleClient: &storage.Client{},
+		}
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		cl = g_cloud.NewMockGoogleStorageClient(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		wc = g_cloud.NewMockObjectWriter(ctrl)
+		currentTime = time.Now()
+		s = &g_storage.GoogleStorage{
+			Config: cfg,
+			Client: cl,
+			Clock:  clo,
+		}
+	})
+
+	expectManifest := func(fileId string) {
+		manifest := `{"file_id":"` + fileId + `","file_name":"video.mp4","content_type":"video/mp4"}`
+		cl.EXPECT().
+			NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(manifestName), gomock.Eq(int64(0)), gomock.Eq(int64(0)), gomock.Eq(int64(-1))).
+			Return(io.NopCloser(strings.NewReader(manifest)), nil).
+			Times(1)
+	}
+
+	expectList := func(objects []*storage.ObjectAttrs) {
+		q := &storage.Query{Prefix: uploadPrefix}
+		cl.EXPECT().
+			ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(q), gomock.Eq(1000), gomock.Eq("")).
+			Return(objects, "", nil).
+			Times(1)
+	}
+
+	Context("CreateMultipartUpload method", func() {
+		var p goseidon.CreateMultipartParam
+
+		BeforeEach(func() {
+			p = goseidon.CreateMultipartParam{
+				FileId:   "mock-file-id",
+				FileName: "video.mp4",
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.CreateMultipartUpload(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("file id is invalid", func() {
+			It("should return error", func() {
+				p.FileId = "../video.mp4"
+				res, err := s.CreateMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("failed write manifest", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Any(), gomock.Eq(storage.Conditions{DoesNotExist: true})).
+					Return(wc).
+					Times(1)
+				cl.EXPECT().
+					Copy(gomock.Eq(wc), gomock.Any()).
+					Return(int64(0), nil).
+					Times(1)
+				wc.EXPECT().
+					Close().
+					Return(fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := s.CreateMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+
+		When("success create upload", func() {
+			It("should return result", func() {
+				var attrs storage.ObjectAttrs
+				cl.EXPECT().
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Any(), gomock.Eq(storage.Conditions{DoesNotExist: true})).
+					DoAndReturn(func(_ context.Context, _ string, a storage.ObjectAttrs, _ storage.Conditions) g_cloud.WriteCloser {
+						attrs = a
+						return wc
+					}).
+					Times(1)
+				cl.EXPECT().
+					Copy(gomock.Eq(wc), gomock.Any()).
+					Return(int64(0), nil).
+					Times(1)
+				wc.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CreateMultipartUpload(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.UploadId).To(HaveLen(32))
+				Expect(res.FileId).To(Equal(p.FileId))
+				Expect(res.CreatedAt).To(Equal(currentTime))
+				Expect(attrs.Name).To(Equal(".goseidon-multipart/" + res.UploadId + "/manifest"))
+			})
+		})
+	})
+
+	Context("UploadPart method", func() {
+		var p goseidon.UploadPartParam
+
+		BeforeEach(func() {
+			p = goseidon.UploadPartParam{
+				UploadId:   uploadId,
+				FileId:     "mock-file-id",
+				PartNumber: 2,
+				PartData:   strings.NewReader("hello"),
+			}
+		})
+
+		When("upload id is invalid", func() {
+			It("should return error", func() {
+				p.UploadId = "../upload"
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid upload id"))
+			})
+		})
+
+		When("upload belong to another file", func() {
+			It("should return error", func() {
+				expectManifest("other-file-id")
+
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrNotFound)).To(BeTrue())
+			})
+		})
+
+		When("upload is not found", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(manifestName), gomock.Eq(int64(0)), gomock.Eq(int64(0)), gomock.Eq(int64(-1))).
+					Return(nil, storage.ErrObjectNotExist).
+					Times(1)
+
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrNotFound)).To(BeTrue())
+			})
+		})
+
+		When("success upload part", func() {
+			It("should return md5 etag", func() {
+				expectManifest(p.FileId)
+				cl.EXPECT().
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq(storage.ObjectAttrs{Name: uploadPrefix + "part-00002"}), gomock.Eq(storage.Conditions{})).
+					Return(wc).
+					Times(1)
+				cl.EXPECT().
+					Copy(gomock.Eq(wc), gomock.Any()).
+					DoAndReturn(func(_ g_cloud.Writer, src g_cloud.Reader) (int64, error) {
+						return io.Copy(io.Discard, src)
+					}).
+					Times(1)
+				wc.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadPart(ctx, p)
+
+				eRes := &goseidon.UploadPartResult{
+					PartNumber: 2,
+					ETag:       "5d41402abc4b2a76b9719d911017c592",
+					Size:       5,
+					UploadedAt: currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListParts method", func() {
+		When("success list parts", func() {
+			It("should skip non part objects", func() {
+				p := goseidon.ListPartsParam{
+					UploadId: uploadId,
+					FileId:   "mock-file-id",
+				}
+				expectManifest(p.FileId)
+				expectList([]*storage.ObjectAttrs{
+					{Name: manifestName},
+					{Name: uploadPrefix + "part-00001", Size: 5, Updated: currentTime, MD5: []byte{0xab, 0xcd}},
+				})
+
+				res, err := s.ListParts(ctx, p)
+
+				eRes := &goseidon.ListPartsResult{
+					Parts: []goseidon.PartItem{
+						{PartNumber: 1, ETag: "abcd", Size: 5, LastModified: currentTime},
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("CompleteMultipartUpload method", func() {
+		var (
+			p       goseidon.CompleteMultipartParam
+			objects []*storage.ObjectAttrs
+		)
+
+		BeforeEach(func() {
+			p = goseidon.CompleteMultipartParam{
+				UploadId: uploadId,
+				FileId:   "mock-file-id",
+				Parts: []goseidon.CompletedPart{
+					{PartNumber: 1, ETag: "abcd"},
+				},
+			}
+			objects = []*storage.ObjectAttrs{
+				{Name: manifestName},
+				{Name: uploadPrefix + "part-00001", MD5: []byte{0xab, 0xcd}},
+			}
+		})
+
+		When("parts are invalid", func() {
+			It("should return error", func() {
+				p.Parts = nil
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("parts are empty"))
+			})
+		})
+
+		When("part is not uploaded", func() {
+			It("should return error", func() {
+				p.Parts = append(p.Parts, goseidon.CompletedPart{PartNumber: 2, ETag: "ef"})
+				expectManifest(p.FileId)
+				expectList(objects)
+
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("part 2 is not uploaded"))
+			})
+		})
+
+		When("part etag mismatch", func() {
+			It("should return error", func() {
+				p.Parts[0].ETag = "ef"
+				expectManifest(p.FileId)
+				expectList(objects)
+
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("part 1 etag mismatch"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("file already exists", func() {
+			It("should return error", func() {
+				expectManifest(p.FileId)
+				expectList(objects)
+				cl.EXPECT().
+					Compose(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Any(), gomock.Eq(storage.Conditions{DoesNotExist: true}), gomock.Any()).
+					Return(nil, &googleapi.Error{Code: http.StatusPreconditionFailed}).
+					Times(1)
+
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrAlreadyExists)).To(BeTrue())
+			})
+		})
+
+		When("success complete upload", func() {
+			It("should compose parts and remove upload", func() {
+				expectManifest(p.FileId)
+				expectList(objects)
+				attrs := storage.ObjectAttrs{
+					Name:        p.FileId,
+					ContentType: "video/mp4",
+					Metadata: map[string]string{
+						goseidon.FileNameMetadataKey: "video.mp4",
+					},
+				}
+				cl.EXPECT().
+					Compose(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(attrs), gomock.Eq(storage.Conditions{DoesNotExist: true}), gomock.Eq([]string{uploadPrefix + "part-00001"})).
+					Return(&storage.ObjectAttrs{}, nil).
+					Times(1)
+				expectList(objects)
+				gomock.InOrder(
+					cl.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(uploadPrefix+"part-00001"), gomock.Eq(int64(0))).Return(nil),
+					cl.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(manifestName), gomock.Eq(int64(0))).Return(nil),
+				)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				eRes := &goseidon.UploadFileResult{
+					FileId:     p.FileId,
+					FileName:   "video.mp4",
+					UploadedAt: currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("parts exceed compose limit", func() {
+			It("should compose in rounds", func() {
+				p.Overwrite = goseidon.OverwriteReplace
+				p.Parts = []goseidon.CompletedPart{}
+				objects = []*storage.ObjectAttrs{}
+				for i := 1; i <= 33; i++ {
+					p.Parts = append(p.Parts, goseidon.CompletedPart{PartNumber: i, ETag: "abcd"})
+					objects = append(objects, &storage.ObjectAttrs{Name: fmt.Sprintf("%spart-%05d", uploadPrefix, i), MD5: []byte{0xab, 0xcd}})
+				}
+				expectManifest(p.FileId)
+				expectList(objects)
+				first := uploadPrefix + "compose-0-00000"
+				second := uploadPrefix + "compose-0-00001"
+				gomock.InOrder(
+					cl.EXPECT().
+						Compose(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(storage.ObjectAttrs{Name: first}), gomock.Eq(storage.Conditions{}), gomock.Len(32)).
+						Return(&storage.ObjectAttrs{}, nil),
+					cl.EXPECT().
+						Compose(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(storage.ObjectAttrs{Name: second}), gomock.Eq(storage.Conditions{}), gomock.Len(1)).
+						Return(&storage.ObjectAttrs{}, nil),
+					cl.EXPECT().
+						Compose(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Any(), gomock.Eq(storage.Conditions{}), gomock.Eq([]string{first, second})).
+						Return(&storage.ObjectAttrs{}, nil),
+				)
+				expectList([]*storage.ObjectAttrs{})
+				cl.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(manifestName), gomock.Eq(int64(0))).Return(nil)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.FileId).To(Equal(p.FileId))
+			})
+		})
+	})
+
+	Context("AbortMultipartUpload method", func() {
+		var p goseidon.AbortMultipartParam
+
+		BeforeEach(func() {
+			p = goseidon.AbortMultipartParam{
+				UploadId: uploadId,
+				FileId:   "mock-file-id",
+			}
+		})
+
+		When("failed delete part", func() {
+			It("should return error", func() {
+				expectManifest(p.FileId)
+				expectList([]*storage.ObjectAttrs{
+					{Name: uploadPrefix + "part-00001"},
+				})
+				cl.EXPECT().
+					Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(uploadPrefix+"part-00001"), gomock.Eq(int64(0))).
+					Return(fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := s.AbortMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+
+		When("success abort upload", func() {
+			It("should return result", func() {
+				expectManifest(p.FileId)
+				expectList([]*storage.ObjectAttrs{
+					{Name: manifestName},
+					{Name: uploadPrefix + "part-00001"},
+				})
+				gomock.InOrder(
+					cl.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(uploadPrefix+"part-00001"), gomock.Eq(int64(0))).Return(storage.ErrObjectNotExist),
+					cl.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(manifestName), gomock.Eq(int64(0))).Return(nil),
+				)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.AbortMultipartUpload(ctx, p)
+
+				Expect(res).To(Equal(&goseidon.AbortMultipartResult{UploadId: uploadId, AbortedAt: currentTime}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,12 @@
+package g_storage
+
+import (
+	"context"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// DeletePrefix list the objects page by page, each page is deleted concurrently
+func (s *GoogleStorage) DeletePrefix(ctx context.Context, p goseidon.DeletePrefixParam) (*goseidon.DeletePrefixResult, error) {
+	return goseidon.DeleteByPrefix(ctx, s, p)
+}
@@ -0,0 +1,127 @@
+package g_storage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/storage"
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	g_cloud "github.com/go-seidon/core/internal/g-cloud"
+	g_storage "github.com/go-seidon/core/pkg/g-storage"
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Prefix", func() {
+	var (
+		ctx         context.Context
+		s           *g_storage.GoogleStorage
+		cfg         *g_storage.GoogleConfig
+		cl          *g_cloud.MockGoogleStorageClient
+		clo         *clock.MockClock
+		currentTime time.Time
+		q           *storage.Query
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cfg = &g_storage.GoogleConfig{
+			BucketName:   "bucket-name",
+			GoogleClient: &storage.Client{},
+		}
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		cl = g_cloud.NewMockGoogleStorageClient(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Now()
+		s = &g_storage.GoogleStorage{
+			Config: cfg,
+			Client: cl,
+			Clock:  clo,
+		}
+		q = &storage.Query{
+			Prefix: "tenant-1/",
+		}
+	})
+
+	Context("DeletePrefix method", func() {
+		When("dry run", func() {
+			It("should count every page without deleting", func() {
+				cl.EXPECT().
+					ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(q), gomock.Eq(1000), gomock.Eq("")).
+					Return([]*storage.ObjectAttrs{{Name: "tenant-1/a.jpg"}, {Name: "tenant-1/b.jpg"}}, "next-token", nil).
+					Times(1)
+				cl.EXPECT().
+					ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(q), gomock.Eq(1000), gomock.Eq("next-token")).
+					Return([]*storage.ObjectAttrs{{Name: "tenant-1/c.jpg"}}, "", nil).
+					Times(1)
+
+				res, err := s.DeletePrefix(ctx, goseidon.DeletePrefixParam{Prefix: "tenant-1/", DryRun: true})
+
+				eRes := &goseidon.DeletePrefixResult{
+					Prefix:  "tenant-1/",
+					Matched: 3,
+					Failed:  []goseidon.DeleteFileItem{},
+					DryRun:  true,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("an object fail to be deleted", func() {
+			It("should delete the rest and report the failure", func() {
+				cl.EXPECT().
+					ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(q), gomock.Eq(1000), gomock.Eq("")).
+					Return([]*storage.ObjectAttrs{{Name: "tenant-1/a.jpg"}, {Name: "tenant-1/b.jpg"}}, "", nil).
+					Times(1)
+				cl.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq("tenant-1/a.jpg"), gomock.Eq(int64(0))).
+					Return(nil).
+					Times(1)
+				cl.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq("tenant-1/b.jpg"), gomock.Eq(int64(0))).
+					Return(storage.ErrObjectNotExist).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime).AnyTimes()
+
+				res, err := s.DeletePrefix(ctx, goseidon.DeletePrefixParam{Prefix: "tenant-1/"})
+
+				Expect(err).To(BeNil())
+				Expect(res.Matched).To(Equal(2))
+				Expect(res.Deleted).To(Equal(1))
+				Expect(res.Failed).To(HaveLen(1))
+				Expect(res.Failed[0].Id).To(Equal("tenant-1/b.jpg"))
+				Expect(errors.Is(res.Failed[0].Error, goseidon.ErrNotFound)).To(BeTrue())
+			})
+		})
+
+		When("failed list the next page", func() {
+			It("should return the result so far with the error", func() {
+				cl.EXPECT().
+					ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(q), gomock.Eq(1000), gomock.Eq("")).
+					Return([]*storage.ObjectAttrs{{Name: "tenant-1/a.jpg"}}, "next-token", nil).
+					Times(1)
+				cl.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq("tenant-1/a.jpg"), gomock.Eq(int64(0))).
+					Return(nil).
+					Times(1)
+				cl.EXPECT().
+					ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(q), gomock.Eq(1000), gomock.Eq("next-token")).
+					Return(nil, "", fmt.Errorf("failed list objects")).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime).AnyTimes()
+
+				res, err := s.DeletePrefix(ctx, goseidon.DeletePrefixParam{Prefix: "tenant-1/"})
+
+				Expect(err.Error()).To(Equal("failed list objects"))
+				Expect(res.Matched).To(Equal(1))
+				Expect(res.Deleted).To(Equal(1))
+			})
+		})
+	})
+})
@@ -3,14 +3,20 @@ package g_storage
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
+	gstorage "cloud.google.com/go/storage"
 	goseidon "github.com/go-seidon/core"
 	"github.com/go-seidon/core/internal/clock"
 	g_cloud "github.com/go-seidon/core/internal/g-cloud"
 )
 
+const defaultPageSize = 1000
+
 type GoogleStorage struct {
 	Config *GoogleConfig
 	Client g_cloud.GoogleStorageClient
@@ -18,84 +24,360 @@ type GoogleStorage struct {
 }
 
 func (s *GoogleStorage) UploadFile(ctx context.Context, p goseidon.UploadFileParam) (*goseidon.UploadFileResult, error) {
+	return s.UploadStream(ctx, goseidon.UploadStreamParam{
+		FileData:  bytes.NewReader(p.FileData),
+		FileId:    p.FileId,
+		FileName:  p.FileName,
+		FileSize:  p.FileSize,
+		Overwrite: p.Overwrite,
+		Checksum:  p.Checksum,
+
+		ContentType:        p.ContentType,
+		ContentDisposition: p.ContentDisposition,
+		CacheControl:       p.CacheControl,
+		Metadata:           p.Metadata,
+	})
+}
+
+func (s *GoogleStorage) UploadStream(ctx context.Context, p goseidon.UploadStreamParam) (*goseidon.UploadFileResult, error) {
 	if ctx == nil {
-		return nil, fmt.Errorf("invalid context")
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
 	}
-
-	wc := s.Client.NewWriter(ctx, s.Config.BucketName, p.FileId)
-	buf := bytes.NewBuffer(p.FileData)
-	_, err := s.Client.Copy(wc, buf)
+	if p.FileData == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid file data"))
+	}
+	err := goseidon.ValidateKey(p.FileId)
 	if err != nil {
 		return nil, err
 	}
 
-	err = wc.Close()
+	// cancelling the writer context is the only way to discard
+	// a partially written object instead of committing it
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	attrs := gstorage.ObjectAttrs{
+		Name:               p.FileId,
+		ContentType:        p.ContentType,
+		ContentDisposition: p.ContentDisposition,
+		CacheControl:       p.CacheControl,
+		Metadata:           buildMetadata(p.FileName, p.Metadata),
+	}
+	conds := gstorage.Conditions{}
+	if p.Overwrite != goseidon.OverwriteReplace {
+		conds.DoesNotExist = true
+	}
+
+	// google storage verify the content against the digests sent along with it,
+	// only the digests given by the caller are known up front for unseekable data
+	var hashed *goseidon.ChecksumReader
+	data := p.FileData
+	checksum := p.Checksum
+	body, seekable := p.FileData.(io.ReadSeeker)
+	if seekable {
+		checksum, err = goseidon.ComputeChecksum(body)
+		if err != nil {
+			return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("failed read file data: %w", err))
+		}
+		err = goseidon.CompareChecksum(p.Checksum, checksum)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		hashed = goseidon.NewChecksumReader(p.FileData)
+		data = hashed
+	}
+	err = applyChecksum(&attrs, checksum)
 	if err != nil {
 		return nil, err
 	}
 
+	wc := s.Client.NewWriter(wctx, s.Config.BucketName, attrs, conds)
+	_, err = s.Client.Copy(wc, data)
+	if err == nil && hashed != nil {
+		checksum = hashed.Checksum()
+		// returning before close discard the object
+		err = goseidon.CompareChecksum(p.Checksum, checksum)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err == nil {
+		err = wc.Close()
+	}
+	if isPreconditionFailed(err) {
+		if p.Overwrite == goseidon.OverwriteSkip {
+			res := &goseidon.UploadFileResult{
+				FileId:     p.FileId,
+				FileName:   p.FileName,
+				UploadedAt: s.Clock.Now(),
+				Skipped:    true,
+			}
+			return res, nil
+		}
+		return nil, goseidon.NewError(goseidon.ErrAlreadyExists, err)
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+
 	uploadedAt := s.Clock.Now()
 	res := &goseidon.UploadFileResult{
 		FileId:     p.FileId,
 		FileName:   p.FileName,
 		UploadedAt: uploadedAt,
+		Checksum:   checksum,
+		VersionId:  formatGeneration(wc.Attrs().Generation),
 	}
 	return res, nil
 }
 
 func (s *GoogleStorage) RetrieveFile(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+	stream, err := s.RetrieveStream(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.File.Close()
+
+	fileData, err := io.ReadAll(stream.File)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.RetrieveFileResult{
+		File:        fileData,
+		RetrievedAt: stream.RetrievedAt,
+		Size:        stream.Size,
+		Offset:      stream.Offset,
+		Length:      stream.Length,
+		Checksum:    stream.Checksum,
+		VersionId:   stream.VersionId,
+
+		FileName:           stream.FileName,
+		ContentType:        stream.ContentType,
+		ContentDisposition: stream.ContentDisposition,
+		CacheControl:       stream.CacheControl,
+		Metadata:           stream.Metadata,
+	}
+	return res, nil
+}
+
+func (s *GoogleStorage) RetrieveStream(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveStreamResult, error) {
 	if ctx == nil {
-		return nil, fmt.Errorf("invalid context")
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateKey(p.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = goseidon.ValidateRange(p.Offset, p.Length)
+	if err != nil {
+		return nil, err
+	}
+	err = goseidon.ValidateVerify(p)
+	if err != nil {
+		return nil, err
 	}
 
-	rc, err := s.Client.NewReader(ctx, s.Config.BucketName, p.Id)
+	generation, err := parseGeneration(p.VersionId)
 	if err != nil {
 		return nil, err
 	}
-	defer rc.Close()
 
-	fileData, err := io.ReadAll(rc)
+	// reader attributes lack user metadata, so the object is read
+	// at the generation whose attributes were fetched
+	attrs, err := s.Client.Attrs(ctx, s.Config.BucketName, p.Id, generation)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	length, err := goseidon.ResolveRange(p.Offset, p.Length, attrs.Size)
 	if err != nil {
 		return nil, err
 	}
 
+	rc, err := s.Client.NewReader(ctx, s.Config.BucketName, p.Id, attrs.Generation, p.Offset, length)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	checksum := parseChecksum(attrs)
+	file := io.ReadCloser(rc)
+	if p.Verify {
+		file = goseidon.NewVerifyReader(rc, checksum)
+	}
+
+	fileName, metadata := parseMetadata(attrs.Metadata)
 	retrievedAt := s.Clock.Now()
-	res := &goseidon.RetrieveFileResult{
-		File:        fileData,
+	res := &goseidon.RetrieveStreamResult{
+		File:        file,
 		RetrievedAt: retrievedAt,
+		Size:        attrs.Size,
+		Offset:      p.Offset,
+		Length:      length,
+		Checksum:    checksum,
+		VersionId:   formatGeneration(attrs.Generation),
+
+		FileName:           fileName,
+		ContentType:        attrs.ContentType,
+		ContentDisposition: attrs.ContentDisposition,
+		CacheControl:       attrs.CacheControl,
+		Metadata:           metadata,
 	}
 	return res, nil
 }
 
 func (s *GoogleStorage) DeleteFile(ctx context.Context, p goseidon.DeleteFileParam) (*goseidon.DeleteFileResult, error) {
 	if ctx == nil {
-		return nil, fmt.Errorf("invalid context")
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateKey(p.Id)
+	if err != nil {
+		return nil, err
 	}
 
-	err := s.Client.Delete(ctx, s.Config.BucketName, p.Id)
+	generation, err := parseGeneration(p.VersionId)
 	if err != nil {
 		return nil, err
 	}
 
+	err = s.Client.Delete(ctx, s.Config.BucketName, p.Id, generation)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
 	deletedAt := s.Clock.Now()
 	res := &goseidon.DeleteFileResult{
 		Id:        p.Id,
 		DeletedAt: deletedAt,
+		VersionId: p.VersionId,
 	}
 	return res, nil
 }
 
-func NewGoogleStorage(opt GoogleStorageOption) (*GoogleStorage, error) {
-	if opt == nil {
-		return nil, fmt.Errorf("invalid google option")
+func (s *GoogleStorage) StatFile(ctx context.Context, p goseidon.StatFileParam) (*goseidon.StatFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateKey(p.Id)
+	if err != nil {
+		return nil, err
 	}
 
-	config := &GoogleConfig{}
-	err := opt.Apply(config)
+	attrs, err := s.Client.Attrs(ctx, s.Config.BucketName, p.Id, 0)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	fileName, metadata := parseMetadata(attrs.Metadata)
+	res := &goseidon.StatFileResult{
+		Id:           p.Id,
+		Size:         attrs.Size,
+		LastModified: attrs.Updated,
+		Checksum:     parseChecksum(attrs),
+
+		FileName:           fileName,
+		ContentType:        attrs.ContentType,
+		ContentDisposition: attrs.ContentDisposition,
+		CacheControl:       attrs.CacheControl,
+		Metadata:           metadata,
+	}
+	return res, nil
+}
+
+func (s *GoogleStorage) ListFiles(ctx context.Context, p goseidon.ListFileParam) (*goseidon.ListFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+
+	pageSize := p.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	q := &gstorage.Query{
+		Prefix:    p.Prefix,
+		Delimiter: p.Delimiter,
+	}
+	objects, nextToken, err := s.Client.ListObjects(ctx, s.Config.BucketName, q, pageSize, p.ContinuationToken)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	res := &goseidon.ListFileResult{
+		Files:                 []goseidon.ListFileItem{},
+		Prefixes:              []string{},
+		NextContinuationToken: nextToken,
+	}
+	for _, obj := range objects {
+		// pending multipart upload is never exposed as a stored file
+		if strings.HasPrefix(obj.Name, multipartPrefix) || strings.HasPrefix(obj.Prefix, multipartPrefix) {
+			continue
+		}
+		// synthetic entry representing a group of objects under delimiter
+		if obj.Prefix != "" {
+			res.Prefixes = append(res.Prefixes, obj.Prefix)
+			continue
+		}
+		res.Files = append(res.Files, goseidon.ListFileItem{
+			Id:           obj.Name,
+			Size:         obj.Size,
+			LastModified: obj.Updated,
+		})
+	}
+	return res, nil
+}
+
+func (s *GoogleStorage) SignURL(ctx context.Context, p goseidon.SignURLParam) (*goseidon.SignURLResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateSignURL(p)
 	if err != nil {
 		return nil, err
 	}
 
+	expiresAt := s.Clock.Now().Add(p.Expiry)
+	opts := &gstorage.SignedURLOptions{
+		Scheme:      gstorage.SigningSchemeV4,
+		Method:      p.Method,
+		Expires:     expiresAt,
+		ContentType: p.ContentType,
+	}
+	url, err := s.Client.SignedURL(s.Config.BucketName, p.Id, opts)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	headers := map[string]string{}
+	if p.ContentType != "" {
+		headers["Content-Type"] = p.ContentType
+	}
+	res := &goseidon.SignURLResult{
+		URL:       url,
+		Method:    p.Method,
+		Headers:   headers,
+		ExpiresAt: expiresAt,
+	}
+	return res, nil
+}
+
+func NewGoogleStorage(opts ...GoogleStorageOption) (*GoogleStorage, error) {
+	if len(opts) == 0 {
+		return nil, fmt.Errorf("invalid google option")
+	}
+
+	config := &GoogleConfig{}
+	for _, opt := range opts {
+		if opt == nil {
+			return nil, fmt.Errorf("invalid google option")
+		}
+		err := opt.Apply(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	client, _ := g_cloud.NewGoogleStorageClient(config.GoogleClient)
 	clock, _ := clock.NewClock()
 
@@ -106,3 +388,63 @@ func NewGoogleStorage(opt GoogleStorageOption) (*GoogleStorage, error) {
 	}
 	return s, nil
 }
+
+func parseChecksum(attrs *gstorage.ObjectAttrs) goseidon.Checksum {
+	checksum := goseidon.Checksum{
+		CRC32C: fmt.Sprintf("%08x", attrs.CRC32C),
+	}
+	// composite object doesn't have md5 digest
+	if len(attrs.MD5) > 0 {
+		checksum.MD5 = hex.EncodeToString(attrs.MD5)
+	}
+	return checksum
+}
+
+// applyChecksum set the known digests on attrs so the server verify them
+func applyChecksum(attrs *gstorage.ObjectAttrs, checksum goseidon.Checksum) error {
+	if checksum.MD5 != "" {
+		md5, err := hex.DecodeString(checksum.MD5)
+		if err != nil || len(md5) != 16 {
+			return goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid md5 checksum"))
+		}
+		attrs.MD5 = md5
+	}
+	if checksum.CRC32C != "" {
+		crc32c, err := strconv.ParseUint(checksum.CRC32C, 16, 32)
+		if err != nil {
+			return goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid crc32c checksum"))
+		}
+		attrs.CRC32C = uint32(crc32c)
+	}
+	return nil
+}
+
+// buildMetadata put the original file name next to user metadata,
+// nil is returned when there is nothing to store
+func buildMetadata(fileName string, m map[string]string) map[string]string {
+	if fileName == "" && len(m) == 0 {
+		return nil
+	}
+	res := map[string]string{}
+	for key, val := range m {
+		res[key] = val
+	}
+	if fileName != "" {
+		res[goseidon.FileNameMetadataKey] = fileName
+	}
+	return res
+}
+
+// parseMetadata split the original file name out of user metadata
+func parseMetadata(m map[string]string) (string, map[string]string) {
+	fileName := ""
+	res := map[string]string{}
+	for key, val := range m {
+		if key == goseidon.FileNameMetadataKey {
+			fileName = val
+			continue
+		}
+		res[key] = val
+	}
+	return fileName, res
+}
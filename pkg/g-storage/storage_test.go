@@ -3,8 +3,11 @@ package g_storage_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +17,7 @@ import (
 	g_cloud "github.com/go-seidon/core/internal/g-cloud"
 	g_storage "github.com/go-seidon/core/pkg/g-storage"
 	gomock "github.com/golang/mock/gomock"
+	"google.golang.org/api/googleapi"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -34,9 +38,18 @@ var _ = Describe("Storage", func() {
 	})
 
 	Context("NewGoogleStorage function", func() {
+		When("option is not given", func() {
+			It("should return error", func() {
+				s, err := g_storage.NewGoogleStorage()
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid google option")))
+			})
+		})
+
 		When("option is invalid", func() {
 			It("should return error", func() {
-				s, err := g_storage.NewGoogleStorage(nil)
+				s, err := g_storage.NewGoogleStorage(&withSuccessApply{}, nil)
 
 				Expect(s).To(BeNil())
 				Expect(err).To(Equal(fmt.Errorf("invalid google option")))
@@ -62,6 +75,20 @@ var _ = Describe("Storage", func() {
 				Expect(err).To(BeNil())
 			})
 		})
+
+		When("success apply options", func() {
+			It("should apply them in order", func() {
+				cl := &storage.Client{}
+
+				s, err := g_storage.NewGoogleStorage(
+					g_storage.WithGoogleClient("first", cl),
+					g_storage.WithGoogleClient("second", cl),
+				)
+
+				Expect(err).To(BeNil())
+				Expect(s.Config.BucketName).To(Equal("second"))
+			})
+		})
 	})
 
 	Context("UploadFile method", func() {
@@ -69,11 +96,12 @@ var _ = Describe("Storage", func() {
 			ctx         context.Context
 			s           *g_storage.GoogleStorage
 			cl          *g_cloud.MockGoogleStorageClient
-			wc          *g_cloud.MockWriteCloser
+			wc          *g_cloud.MockObjectWriter
 			cfg         *g_storage.GoogleConfig
 			p           goseidon.UploadFileParam
 			clo         *clock.MockClock
 			currentTime time.Time
+			attrs       storage.ObjectAttrs
 		)
 
 		BeforeEach(func() {
@@ -85,7 +113,7 @@ var _ = Describe("Storage", func() {
 			currentTime = time.Now()
 			ctrl := gomock.NewController(t)
 			cl = g_cloud.NewMockGoogleStorageClient(ctrl)
-			wc = g_cloud.NewMockWriteCloser(ctrl)
+			wc = g_cloud.NewMockObjectWriter(ctrl)
 			clo = clock.NewMockClock(ctrl)
 			s = &g_storage.GoogleStorage{
 				Client: cl,
@@ -93,10 +121,19 @@ var _ = Describe("Storage", func() {
 				Config: cfg,
 			}
 			p = goseidon.UploadFileParam{
+				FileId:   "file-id",
 				FileData: make([]byte, 1),
 				FileName: "file-name.jpg",
 				FileSize: 1,
 			}
+			attrs = storage.ObjectAttrs{
+				Name: "file-id",
+				Metadata: map[string]string{
+					"goseidon-file-name": "file-name.jpg",
+				},
+				MD5:    []byte{0x93, 0xb8, 0x85, 0xad, 0xfe, 0x0d, 0xa0, 0x89, 0xcd, 0xf6, 0x34, 0x90, 0x4f, 0xd5, 0x9f, 0x71},
+				CRC32C: 0x527d5351,
+			}
 		})
 
 		When("context is invalid", func() {
@@ -111,10 +148,10 @@ var _ = Describe("Storage", func() {
 		When("failed copy file", func() {
 			It("should return error", func() {
 				cl.EXPECT().
-					NewWriter(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.FileId)).
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq(attrs), gomock.Eq(storage.Conditions{DoesNotExist: true})).
 					Return(wc).
 					Times(1)
-				buf := bytes.NewBuffer(p.FileData)
+				buf := bytes.NewReader(p.FileData)
 				cl.EXPECT().
 					Copy(gomock.Eq(wc), gomock.Eq(buf)).
 					Return(int64(0), fmt.Errorf("failed copy file")).
@@ -134,10 +171,10 @@ var _ = Describe("Storage", func() {
 					Return(fmt.Errorf("failed close file")).
 					Times(1)
 				cl.EXPECT().
-					NewWriter(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.FileId)).
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq(attrs), gomock.Eq(storage.Conditions{DoesNotExist: true})).
 					Return(wc).
 					Times(1)
-				buf := bytes.NewBuffer(p.FileData)
+				buf := bytes.NewReader(p.FileData)
 				cl.EXPECT().
 					Copy(gomock.Eq(wc), gomock.Eq(buf)).
 					Return(int64(0), nil).
@@ -156,11 +193,15 @@ var _ = Describe("Storage", func() {
 					Close().
 					Return(nil).
 					Times(1)
+				wc.EXPECT().
+					Attrs().
+					Return(&storage.ObjectAttrs{Generation: 3}).
+					Times(1)
 				cl.EXPECT().
-					NewWriter(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.FileId)).
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq(attrs), gomock.Eq(storage.Conditions{DoesNotExist: true})).
 					Return(wc).
 					Times(1)
-				buf := bytes.NewBuffer(p.FileData)
+				buf := bytes.NewReader(p.FileData)
 				cl.EXPECT().
 					Copy(gomock.Eq(wc), gomock.Eq(buf)).
 					Return(int64(0), nil).
@@ -173,11 +214,327 @@ var _ = Describe("Storage", func() {
 					FileId:     p.FileId,
 					FileName:   p.FileName,
 					UploadedAt: currentTime,
+					Checksum: goseidon.Checksum{
+						MD5:    "93b885adfe0da089cdf634904fd59f71",
+						CRC32C: "527d5351",
+						SHA256: "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+					},
+					VersionId: "3",
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UploadStream method", func() {
+		var (
+			ctx         context.Context
+			s           *g_storage.GoogleStorage
+			cl          *g_cloud.MockGoogleStorageClient
+			wc          *g_cloud.MockObjectWriter
+			cfg         *g_storage.GoogleConfig
+			p           goseidon.UploadStreamParam
+			attrs       storage.ObjectAttrs
+			clo         *clock.MockClock
+			currentTime time.Time
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			cfg = &g_storage.GoogleConfig{
+				BucketName:   "bucket-name",
+				GoogleClient: &storage.Client{},
+			}
+			currentTime = time.Now()
+			ctrl := gomock.NewController(t)
+			cl = g_cloud.NewMockGoogleStorageClient(ctrl)
+			wc = g_cloud.NewMockObjectWriter(ctrl)
+			clo = clock.NewMockClock(ctrl)
+			s = &g_storage.GoogleStorage{
+				Client: cl,
+				Clock:  clo,
+				Config: cfg,
+			}
+			p = goseidon.UploadStreamParam{
+				FileId:   "file-id",
+				FileData: strings.NewReader("content"),
+				FileName: "file-name.jpg",
+
+				ContentType:        "image/jpeg",
+				ContentDisposition: "inline",
+				CacheControl:       "no-cache",
+				Metadata: map[string]string{
+					"owner": "tenant-1",
+				},
+			}
+			attrs = storage.ObjectAttrs{
+				Name:               "file-id",
+				ContentType:        "image/jpeg",
+				ContentDisposition: "inline",
+				CacheControl:       "no-cache",
+				Metadata: map[string]string{
+					"owner":              "tenant-1",
+					"goseidon-file-name": "file-name.jpg",
+				},
+				MD5:    []byte{0x9a, 0x03, 0x64, 0xb9, 0xe9, 0x9b, 0xb4, 0x80, 0xdd, 0x25, 0xe1, 0xf0, 0x28, 0x4c, 0x85, 0x55},
+				CRC32C: 0x61af7533,
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.UploadStream(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("file id is invalid", func() {
+			It("should return error", func() {
+				p.FileId = "tenant-1//image.jpg"
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("file id contains empty segment"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("file data is invalid", func() {
+			It("should return error", func() {
+				p.FileData = nil
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid file data"))
+			})
+		})
+
+		When("failed copy file", func() {
+			It("should cancel writer context and return error", func() {
+				var wctx context.Context
+				cl.EXPECT().
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq(attrs), gomock.Eq(storage.Conditions{DoesNotExist: true})).
+					DoAndReturn(func(c context.Context, bucketName string, attrs storage.ObjectAttrs, conds storage.Conditions) g_cloud.WriteCloser {
+						wctx = c
+						return wc
+					}).
+					Times(1)
+				cl.EXPECT().
+					Copy(gomock.Eq(wc), gomock.Eq(p.FileData)).
+					Return(int64(0), fmt.Errorf("failed copy file")).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed copy file"))
+				Expect(wctx.Err()).To(Equal(context.Canceled))
+			})
+		})
+
+		When("success upload file", func() {
+			It("should return result", func() {
+				wc.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+				wc.EXPECT().
+					Attrs().
+					Return(&storage.ObjectAttrs{Generation: 3}).
+					Times(1)
+				cl.EXPECT().
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq(attrs), gomock.Eq(storage.Conditions{DoesNotExist: true})).
+					Return(wc).
+					Times(1)
+				cl.EXPECT().
+					Copy(gomock.Eq(wc), gomock.Eq(p.FileData)).
+					Return(int64(7), nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				eRes := &goseidon.UploadFileResult{
+					FileId:     p.FileId,
+					FileName:   p.FileName,
+					UploadedAt: currentTime,
+					Checksum: goseidon.Checksum{
+						MD5:    "9a0364b9e99bb480dd25e1f0284c8555",
+						CRC32C: "61af7533",
+						SHA256: "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73",
+					},
+					VersionId: "3",
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("file already exists", func() {
+			It("should return error", func() {
+				wc.EXPECT().
+					Close().
+					Return(&googleapi.Error{Code: http.StatusPreconditionFailed}).
+					Times(1)
+				cl.EXPECT().
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq(attrs), gomock.Eq(storage.Conditions{DoesNotExist: true})).
+					Return(wc).
+					Times(1)
+				cl.EXPECT().
+					Copy(gomock.Eq(wc), gomock.Eq(p.FileData)).
+					Return(int64(7), nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrAlreadyExists)).To(BeTrue())
+			})
+		})
+
+		When("file already exists and overwrite mode is skip", func() {
+			It("should return skipped result", func() {
+				p.Overwrite = goseidon.OverwriteSkip
+				wc.EXPECT().
+					Close().
+					Return(&googleapi.Error{Code: http.StatusPreconditionFailed}).
+					Times(1)
+				cl.EXPECT().
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq(attrs), gomock.Eq(storage.Conditions{DoesNotExist: true})).
+					Return(wc).
+					Times(1)
+				cl.EXPECT().
+					Copy(gomock.Eq(wc), gomock.Eq(p.FileData)).
+					Return(int64(7), nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				eRes := &goseidon.UploadFileResult{
+					FileId:     p.FileId,
+					FileName:   p.FileName,
+					UploadedAt: currentTime,
+					Skipped:    true,
 				}
 				Expect(res).To(Equal(eRes))
 				Expect(err).To(BeNil())
 			})
 		})
+
+		When("overwrite mode is replace", func() {
+			It("should write without precondition", func() {
+				p.Overwrite = goseidon.OverwriteReplace
+				wc.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+				wc.EXPECT().
+					Attrs().
+					Return(&storage.ObjectAttrs{Generation: 3}).
+					Times(1)
+				cl.EXPECT().
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq(attrs), gomock.Eq(storage.Conditions{})).
+					Return(wc).
+					Times(1)
+				cl.EXPECT().
+					Copy(gomock.Eq(wc), gomock.Eq(p.FileData)).
+					Return(int64(7), nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Skipped).To(BeFalse())
+			})
+		})
+
+		When("checksum doesn't match file data", func() {
+			It("should return error", func() {
+				p.Checksum = goseidon.Checksum{SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrChecksumMismatch)).To(BeTrue())
+			})
+		})
+
+		When("given checksum is invalid", func() {
+			It("should return error", func() {
+				p.FileData = io.MultiReader(strings.NewReader("content"))
+				p.Checksum = goseidon.Checksum{CRC32C: "crc"}
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid crc32c checksum"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("file data is not seekable", func() {
+			It("should send the given checksum", func() {
+				p.FileData = io.MultiReader(strings.NewReader("content"))
+				p.Checksum = goseidon.Checksum{CRC32C: "61af7533"}
+				attrs.MD5 = nil
+				wc.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+				wc.EXPECT().
+					Attrs().
+					Return(&storage.ObjectAttrs{Generation: 3}).
+					Times(1)
+				cl.EXPECT().
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq(attrs), gomock.Eq(storage.Conditions{DoesNotExist: true})).
+					Return(wc).
+					Times(1)
+				cl.EXPECT().
+					Copy(gomock.Eq(wc), gomock.Any()).
+					DoAndReturn(func(dst io.Writer, src io.Reader) (int64, error) {
+						return io.Copy(io.Discard, src)
+					}).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Checksum.MD5).To(Equal("9a0364b9e99bb480dd25e1f0284c8555"))
+			})
+		})
+
+		When("file data is not seekable and checksum doesn't match", func() {
+			It("should discard the object and return error", func() {
+				p.FileData = io.MultiReader(strings.NewReader("content"))
+				p.Checksum = goseidon.Checksum{SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+				attrs.MD5 = nil
+				attrs.CRC32C = 0
+				var wctx context.Context
+				cl.EXPECT().
+					NewWriter(gomock.Any(), gomock.Eq(cfg.BucketName), gomock.Eq(attrs), gomock.Eq(storage.Conditions{DoesNotExist: true})).
+					DoAndReturn(func(c context.Context, bucketName string, attrs storage.ObjectAttrs, conds storage.Conditions) g_cloud.WriteCloser {
+						wctx = c
+						return wc
+					}).
+					Times(1)
+				cl.EXPECT().
+					Copy(gomock.Eq(wc), gomock.Any()).
+					DoAndReturn(func(dst io.Writer, src io.Reader) (int64, error) {
+						return io.Copy(io.Discard, src)
+					}).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrChecksumMismatch)).To(BeTrue())
+				Expect(wctx.Err()).To(Equal(context.Canceled))
+			})
+		})
 	})
 
 	Context("RetrieveFile method", func() {
@@ -222,10 +579,28 @@ var _ = Describe("Storage", func() {
 			})
 		})
 
+		When("failed get attributes", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(nil, storage.ErrObjectNotExist).
+					Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.NewError(goseidon.ErrNotFound, storage.ErrObjectNotExist)))
+			})
+		})
+
 		When("failed create reader", func() {
 			It("should return error", func() {
 				cl.EXPECT().
-					NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id)).
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(&storage.ObjectAttrs{Generation: 7}, nil).
+					Times(1)
+				cl.EXPECT().
+					NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(7)), gomock.Eq(int64(0)), gomock.Eq(int64(0))).
 					Return(nil, fmt.Errorf("failed create reader")).
 					Times(1)
 
@@ -246,9 +621,14 @@ var _ = Describe("Storage", func() {
 					Return(0, fmt.Errorf("failed read data")).
 					Times(1)
 				cl.EXPECT().
-					NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id)).
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(&storage.ObjectAttrs{Generation: 7}, nil).
+					Times(1)
+				cl.EXPECT().
+					NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(7)), gomock.Eq(int64(0)), gomock.Eq(int64(0))).
 					Return(rc, nil).
 					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
 
 				res, err := s.RetrieveFile(ctx, p)
 
@@ -267,7 +647,11 @@ var _ = Describe("Storage", func() {
 					Return(1, io.EOF).
 					Times(1)
 				cl.EXPECT().
-					NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id)).
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(&storage.ObjectAttrs{Generation: 7}, nil).
+					Times(1)
+				cl.EXPECT().
+					NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(7)), gomock.Eq(int64(0)), gomock.Eq(int64(0))).
 					Return(rc, nil).
 					Times(1)
 				clo.EXPECT().Now().Return(currentTime)
@@ -277,6 +661,9 @@ var _ = Describe("Storage", func() {
 				eRes := &goseidon.RetrieveFileResult{
 					File:        make([]byte, 1),
 					RetrievedAt: currentTime,
+					Checksum:    goseidon.Checksum{CRC32C: "00000000"},
+					VersionId:   "7",
+					Metadata:    map[string]string{},
 				}
 				Expect(res).To(Equal(eRes))
 				Expect(err).To(BeNil())
@@ -284,14 +671,15 @@ var _ = Describe("Storage", func() {
 		})
 	})
 
-	Context("DeleteFile method", func() {
+	Context("RetrieveStream method", func() {
 		var (
 			ctx         context.Context
-			s           goseidon.Storage
+			s           *g_storage.GoogleStorage
 			cfg         *g_storage.GoogleConfig
 			cl          *g_cloud.MockGoogleStorageClient
+			rc          *g_cloud.MockReadCloser
+			p           goseidon.RetrieveFileParam
 			clo         *clock.MockClock
-			p           goseidon.DeleteFileParam
 			currentTime time.Time
 		)
 
@@ -303,54 +691,612 @@ var _ = Describe("Storage", func() {
 			}
 			ctrl := gomock.NewController(t)
 			cl = g_cloud.NewMockGoogleStorageClient(ctrl)
-			currentTime = time.Now()
+			rc = g_cloud.NewMockReadCloser(ctrl)
 			clo = clock.NewMockClock(ctrl)
+			currentTime = time.Now()
 			s = &g_storage.GoogleStorage{
-				Config: cfg,
 				Client: cl,
+				Config: cfg,
 				Clock:  clo,
 			}
-			p = goseidon.DeleteFileParam{
+			p = goseidon.RetrieveFileParam{
 				Id: "mock-file-id",
 			}
 		})
 
 		When("context is invalid", func() {
 			It("should return error", func() {
-				res, err := s.DeleteFile(nil, p)
+				res, err := s.RetrieveStream(nil, p)
 
 				Expect(res).To(BeNil())
 				Expect(err.Error()).To(Equal("invalid context"))
 			})
 		})
 
-		When("failed delete file", func() {
+		When("file id is invalid", func() {
+			It("should return error", func() {
+				p.Id = "tenant-1//image.jpg"
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("file id contains empty segment"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("failed get attributes", func() {
 			It("should return error", func() {
 				cl.EXPECT().
-					Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id)).
-					Return(fmt.Errorf("failed delete file")).
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(nil, storage.ErrObjectNotExist).
 					Times(1)
 
-				res, err := s.DeleteFile(ctx, p)
+				res, err := s.RetrieveStream(ctx, p)
 
 				Expect(res).To(BeNil())
-				Expect(err.Error()).To(Equal("failed delete file"))
+				Expect(err).To(Equal(goseidon.NewError(goseidon.ErrNotFound, storage.ErrObjectNotExist)))
 			})
 		})
 
-		When("success delete file", func() {
-			It("should return result", func() {
+		When("failed create reader", func() {
+			It("should return error", func() {
 				cl.EXPECT().
-					Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id)).
-					Return(nil).
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(&storage.ObjectAttrs{Generation: 7}, nil).
+					Times(1)
+				cl.EXPECT().
+					NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(7)), gomock.Eq(int64(0)), gomock.Eq(int64(0))).
+					Return(nil, fmt.Errorf("failed create reader")).
 					Times(1)
-				clo.EXPECT().Now().Return(currentTime).Times(1)
 
-				res, err := s.DeleteFile(ctx, p)
+				res, err := s.RetrieveStream(ctx, p)
 
-				eRes := &goseidon.DeleteFileResult{
-					Id:        p.Id,
-					DeletedAt: currentTime,
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed create reader"))
+			})
+		})
+
+		When("success create reader", func() {
+			It("should return reader", func() {
+				cl.EXPECT().
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(&storage.ObjectAttrs{
+						Generation:         7,
+						Size:               120,
+						ContentType:        "image/png",
+						ContentDisposition: "inline",
+						CacheControl:       "max-age=60",
+						Metadata: map[string]string{
+							"owner":              "tenant-1",
+							"goseidon-file-name": "dolphin.png",
+						},
+						MD5:    []byte{0x9a, 0x03, 0x64, 0xb9, 0xe9, 0x9b, 0xb4, 0x80, 0xdd, 0x25, 0xe1, 0xf0, 0x28, 0x4c, 0x85, 0x55},
+						CRC32C: 0x61af7533,
+					}, nil).
+					Times(1)
+				cl.EXPECT().
+					NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(7)), gomock.Eq(int64(0)), gomock.Eq(int64(120))).
+					Return(rc, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				eRes := &goseidon.RetrieveStreamResult{
+					File:        rc,
+					RetrievedAt: currentTime,
+					Size:        120,
+					Length:      120,
+					Checksum: goseidon.Checksum{
+						MD5:    "9a0364b9e99bb480dd25e1f0284c8555",
+						CRC32C: "61af7533",
+					},
+					VersionId:          "7",
+					FileName:           "dolphin.png",
+					ContentType:        "image/png",
+					ContentDisposition: "inline",
+					CacheControl:       "max-age=60",
+					Metadata: map[string]string{
+						"owner": "tenant-1",
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("range is given", func() {
+			It("should read the range at the fetched generation", func() {
+				p.Offset = 100
+				p.Length = 50
+				cl.EXPECT().
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(&storage.ObjectAttrs{Generation: 7, Size: 120}, nil).
+					Times(1)
+				cl.EXPECT().
+					NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(7)), gomock.Eq(int64(100)), gomock.Eq(int64(20))).
+					Return(rc, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Size).To(Equal(int64(120)))
+				Expect(res.Offset).To(Equal(int64(100)))
+				Expect(res.Length).To(Equal(int64(20)))
+			})
+		})
+
+		When("range is not satisfiable", func() {
+			It("should return error", func() {
+				p.Offset = 120
+				cl.EXPECT().
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(&storage.ObjectAttrs{Generation: 7, Size: 120}, nil).
+					Times(1)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("range is not satisfiable"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("verify is requested on a range", func() {
+			It("should return error", func() {
+				p.Verify = true
+				p.Length = 50
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("checksum can't be verified on a range"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("verified content doesn't match", func() {
+			It("should return error once fully read", func() {
+				p.Verify = true
+				cl.EXPECT().
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(&storage.ObjectAttrs{Generation: 7, Size: 7, CRC32C: 0x61af7533}, nil).
+					Times(1)
+				cl.EXPECT().
+					NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(7)), gomock.Eq(int64(0)), gomock.Eq(int64(7))).
+					Return(io.NopCloser(strings.NewReader("corrupt")), nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveStream(ctx, p)
+				Expect(err).To(BeNil())
+
+				_, err = io.ReadAll(res.File)
+				Expect(errors.Is(err, goseidon.ErrChecksumMismatch)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("DeleteFile method", func() {
+		var (
+			ctx         context.Context
+			s           goseidon.Storage
+			cfg         *g_storage.GoogleConfig
+			cl          *g_cloud.MockGoogleStorageClient
+			clo         *clock.MockClock
+			p           goseidon.DeleteFileParam
+			currentTime time.Time
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			cfg = &g_storage.GoogleConfig{
+				BucketName:   "bucket-name",
+				GoogleClient: &storage.Client{},
+			}
+			ctrl := gomock.NewController(t)
+			cl = g_cloud.NewMockGoogleStorageClient(ctrl)
+			currentTime = time.Now()
+			clo = clock.NewMockClock(ctrl)
+			s = &g_storage.GoogleStorage{
+				Config: cfg,
+				Client: cl,
+				Clock:  clo,
+			}
+			p = goseidon.DeleteFileParam{
+				Id: "mock-file-id",
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.DeleteFile(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("failed delete file", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(fmt.Errorf("failed delete file")).
+					Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed delete file"))
+			})
+		})
+
+		When("success delete file", func() {
+			It("should return result", func() {
+				cl.EXPECT().
+					Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				eRes := &goseidon.DeleteFileResult{
+					Id:        p.Id,
+					DeletedAt: currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("StatFile method", func() {
+		var (
+			ctx         context.Context
+			s           *g_storage.GoogleStorage
+			cfg         *g_storage.GoogleConfig
+			cl          *g_cloud.MockGoogleStorageClient
+			p           goseidon.StatFileParam
+			currentTime time.Time
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			cfg = &g_storage.GoogleConfig{
+				BucketName:   "bucket-name",
+				GoogleClient: &storage.Client{},
+			}
+			ctrl := gomock.NewController(t)
+			cl = g_cloud.NewMockGoogleStorageClient(ctrl)
+			currentTime = time.Now()
+			s = &g_storage.GoogleStorage{
+				Config: cfg,
+				Client: cl,
+			}
+			p = goseidon.StatFileParam{
+				Id: "mock-file-id",
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.StatFile(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("failed get attributes", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(nil, fmt.Errorf("failed get attributes")).
+					Times(1)
+
+				res, err := s.StatFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed get attributes"))
+			})
+		})
+
+		When("success get attributes", func() {
+			It("should return result", func() {
+				attrs := &storage.ObjectAttrs{
+					Size:        120,
+					ContentType: "image/jpeg",
+					Updated:     currentTime,
+					MD5:         []byte{0xd4, 0x1d, 0x8c, 0xd9},
+					CRC32C:      0xab,
+					Metadata: map[string]string{
+						"owner":              "tenant-1",
+						"goseidon-file-name": "dolphin.jpg",
+					},
+				}
+				cl.EXPECT().
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(attrs, nil).
+					Times(1)
+
+				res, err := s.StatFile(ctx, p)
+
+				eRes := &goseidon.StatFileResult{
+					Id:           p.Id,
+					Size:         120,
+					ContentType:  "image/jpeg",
+					LastModified: currentTime,
+					FileName:     "dolphin.jpg",
+					Metadata: map[string]string{
+						"owner": "tenant-1",
+					},
+					Checksum: goseidon.Checksum{
+						MD5:    "d41d8cd9",
+						CRC32C: "000000ab",
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("object is a composite", func() {
+			It("should return result without md5", func() {
+				attrs := &storage.ObjectAttrs{
+					Size:   120,
+					CRC32C: 0xab,
+				}
+				cl.EXPECT().
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(int64(0))).
+					Return(attrs, nil).
+					Times(1)
+
+				res, err := s.StatFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Checksum).To(Equal(goseidon.Checksum{CRC32C: "000000ab"}))
+			})
+		})
+	})
+	Context("ListFiles method", func() {
+		var (
+			ctx         context.Context
+			s           *g_storage.GoogleStorage
+			cfg         *g_storage.GoogleConfig
+			cl          *g_cloud.MockGoogleStorageClient
+			p           goseidon.ListFileParam
+			currentTime time.Time
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			cfg = &g_storage.GoogleConfig{
+				BucketName:   "bucket-name",
+				GoogleClient: &storage.Client{},
+			}
+			ctrl := gomock.NewController(t)
+			cl = g_cloud.NewMockGoogleStorageClient(ctrl)
+			currentTime = time.Now()
+			s = &g_storage.GoogleStorage{
+				Config: cfg,
+				Client: cl,
+			}
+			p = goseidon.ListFileParam{
+				Prefix:            "tenant-1/",
+				Delimiter:         "/",
+				ContinuationToken: "mock-token",
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.ListFiles(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("failed list objects", func() {
+			It("should return error", func() {
+				q := &storage.Query{
+					Prefix:    p.Prefix,
+					Delimiter: p.Delimiter,
+				}
+				cl.EXPECT().
+					ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(q), gomock.Eq(1000), gomock.Eq(p.ContinuationToken)).
+					Return(nil, "", fmt.Errorf("failed list objects")).
+					Times(1)
+
+				res, err := s.ListFiles(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed list objects"))
+			})
+		})
+
+		When("success list objects", func() {
+			It("should return result", func() {
+				p.PageSize = 2
+				q := &storage.Query{
+					Prefix:    p.Prefix,
+					Delimiter: p.Delimiter,
+				}
+				objects := []*storage.ObjectAttrs{
+					{Name: "tenant-1/a.jpg", Size: 2, Updated: currentTime},
+					{Prefix: "tenant-1/sub/"},
+				}
+				cl.EXPECT().
+					ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(q), gomock.Eq(2), gomock.Eq(p.ContinuationToken)).
+					Return(objects, "next-token", nil).
+					Times(1)
+
+				res, err := s.ListFiles(ctx, p)
+
+				eRes := &goseidon.ListFileResult{
+					Files: []goseidon.ListFileItem{
+						{Id: "tenant-1/a.jpg", Size: 2, LastModified: currentTime},
+					},
+					Prefixes:              []string{"tenant-1/sub/"},
+					NextContinuationToken: "next-token",
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("pending multipart upload is listed", func() {
+			It("should hide it", func() {
+				p.Prefix = ""
+				p.Delimiter = "/"
+				q := &storage.Query{
+					Delimiter: "/",
+				}
+				objects := []*storage.ObjectAttrs{
+					{Prefix: ".goseidon-multipart/"},
+					{Name: "a.jpg", Size: 2, Updated: currentTime},
+				}
+				cl.EXPECT().
+					ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(q), gomock.Eq(1000), gomock.Eq(p.ContinuationToken)).
+					Return(objects, "", nil).
+					Times(1)
+
+				res, err := s.ListFiles(ctx, p)
+
+				eRes := &goseidon.ListFileResult{
+					Files: []goseidon.ListFileItem{
+						{Id: "a.jpg", Size: 2, LastModified: currentTime},
+					},
+					Prefixes: []string{},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("SignURL method", func() {
+		var (
+			ctx         context.Context
+			s           *g_storage.GoogleStorage
+			cfg         *g_storage.GoogleConfig
+			cl          *g_cloud.MockGoogleStorageClient
+			clo         *clock.MockClock
+			p           goseidon.SignURLParam
+			currentTime time.Time
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			cfg = &g_storage.GoogleConfig{
+				BucketName:   "bucket-name",
+				GoogleClient: &storage.Client{},
+			}
+			ctrl := gomock.NewController(t)
+			cl = g_cloud.NewMockGoogleStorageClient(ctrl)
+			clo = clock.NewMockClock(ctrl)
+			currentTime = time.Now()
+			s = &g_storage.GoogleStorage{
+				Config: cfg,
+				Client: cl,
+				Clock:  clo,
+			}
+			p = goseidon.SignURLParam{
+				Id:     "mock-file-id",
+				Method: http.MethodGet,
+				Expiry: 15 * time.Minute,
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.SignURL(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("param is invalid", func() {
+			It("should return error", func() {
+				p.Expiry = 0
+
+				res, err := s.SignURL(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("sign url expiry must be positive"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("failed sign url", func() {
+			It("should return error", func() {
+				clo.EXPECT().Now().Return(currentTime)
+				cl.EXPECT().
+					SignedURL(gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Any()).
+					Return("", fmt.Errorf("missing private key")).
+					Times(1)
+
+				res, err := s.SignURL(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("missing private key")))
+			})
+		})
+
+		When("success sign get url", func() {
+			It("should return result", func() {
+				opts := &storage.SignedURLOptions{
+					Scheme:  storage.SigningSchemeV4,
+					Method:  http.MethodGet,
+					Expires: currentTime.Add(p.Expiry),
+				}
+				clo.EXPECT().Now().Return(currentTime)
+				cl.EXPECT().
+					SignedURL(gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(opts)).
+					Return("https://storage.googleapis.com/bucket-name/mock-file-id?X-Goog-Signature=mock", nil).
+					Times(1)
+
+				res, err := s.SignURL(ctx, p)
+
+				eRes := &goseidon.SignURLResult{
+					URL:       "https://storage.googleapis.com/bucket-name/mock-file-id?X-Goog-Signature=mock",
+					Method:    http.MethodGet,
+					Headers:   map[string]string{},
+					ExpiresAt: currentTime.Add(p.Expiry),
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("success sign put url with content type", func() {
+			It("should return content type header", func() {
+				p.Method = http.MethodPut
+				p.ContentType = "image/png"
+				opts := &storage.SignedURLOptions{
+					Scheme:      storage.SigningSchemeV4,
+					Method:      http.MethodPut,
+					Expires:     currentTime.Add(p.Expiry),
+					ContentType: "image/png",
+				}
+				clo.EXPECT().Now().Return(currentTime)
+				cl.EXPECT().
+					SignedURL(gomock.Eq(cfg.BucketName), gomock.Eq(p.Id), gomock.Eq(opts)).
+					Return("https://storage.googleapis.com/bucket-name/mock-file-id?X-Goog-Signature=mock", nil).
+					Times(1)
+
+				res, err := s.SignURL(ctx, p)
+
+				eRes := &goseidon.SignURLResult{
+					URL:    "https://storage.googleapis.com/bucket-name/mock-file-id?X-Goog-Signature=mock",
+					Method: http.MethodPut,
+					Headers: map[string]string{
+						"Content-Type": "image/png",
+					},
+					ExpiresAt: currentTime.Add(p.Expiry),
 				}
 				Expect(res).To(Equal(eRes))
 				Expect(err).To(BeNil())
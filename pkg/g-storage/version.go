@@ -0,0 +1,103 @@
+package g_storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	gstorage "cloud.google.com/go/storage"
+	goseidon "github.com/go-seidon/core"
+)
+
+// ListVersions list the generations of a single object, newest first like the
+// other storages. google storage keep them oldest first, so every generation is
+// fetched before the page is cut, the continuation token is the last generation
+// returned. only the live generation is marked as latest
+func (s *GoogleStorage) ListVersions(ctx context.Context, p goseidon.ListVersionsParam) (*goseidon.ListVersionsResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateKey(p.Id)
+	if err != nil {
+		return nil, err
+	}
+	after, err := parseGeneration(p.ContinuationToken)
+	if err != nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid continuation token"))
+	}
+
+	pageSize := p.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	// the offsets narrow the listing to the object itself
+	q := &gstorage.Query{
+		Prefix:      p.Id,
+		StartOffset: p.Id,
+		EndOffset:   p.Id + "\x00",
+		Versions:    true,
+	}
+	objects := []*gstorage.ObjectAttrs{}
+	token := ""
+	for {
+		page, nextToken, err := s.Client.ListObjects(ctx, s.Config.BucketName, q, defaultPageSize, token)
+		if err != nil {
+			return nil, mapError(err)
+		}
+		for _, obj := range page {
+			if obj.Name == p.Id {
+				objects = append(objects, obj)
+			}
+		}
+		if nextToken == "" {
+			break
+		}
+		token = nextToken
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Generation > objects[j].Generation
+	})
+
+	res := &goseidon.ListVersionsResult{
+		Versions: []goseidon.FileVersion{},
+	}
+	for _, obj := range objects {
+		// a removed token doesn't stop the listing, generations only grow
+		if after > 0 && obj.Generation >= after {
+			continue
+		}
+		if len(res.Versions) == pageSize {
+			res.NextContinuationToken = res.Versions[pageSize-1].VersionId
+			break
+		}
+		res.Versions = append(res.Versions, goseidon.FileVersion{
+			VersionId:    formatGeneration(obj.Generation),
+			Size:         obj.Size,
+			LastModified: obj.Updated,
+			IsLatest:     obj.Deleted.IsZero(),
+		})
+	}
+	return res, nil
+}
+
+// parseGeneration turn a version id into an object generation,
+// empty version id is the zero generation which target the live object
+func parseGeneration(versionId string) (int64, error) {
+	if versionId == "" {
+		return 0, nil
+	}
+	generation, err := strconv.ParseInt(versionId, 10, 64)
+	if err != nil || generation <= 0 {
+		return 0, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid version id"))
+	}
+	return generation, nil
+}
+
+func formatGeneration(generation int64) string {
+	if generation <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(generation, 10)
+}
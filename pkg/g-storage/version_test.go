@@ -0,0 +1,210 @@
+package g_storage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	g_cloud "github.com/go-seidon/core/internal/g-cloud"
+	g_storage "github.com/go-seidon/core/pkg/g-storage"
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Version", func() {
+	var (
+		ctx         context.Context
+		s           *g_storage.GoogleStorage
+		cfg         *g_storage.GoogleConfig
+		cl          *g_cloud.MockGoogleStorageClient
+		clo         *clock.MockClock
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cfg = &g_storage.GoogleConfig{
+			BucketName:   "bucket-name",
+			GoogleClient: &storage.Client{},
+		}
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		cl = g_cloud.NewMockGoogleStorageClient(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Now()
+		s = &g_storage.GoogleStorage{
+			Config: cfg,
+			Client: cl,
+			Clock:  clo,
+		}
+	})
+
+	Context("RetrieveStream method", func() {
+		When("version id is invalid", func() {
+			It("should return error", func() {
+				res, err := s.RetrieveStream(ctx, goseidon.RetrieveFileParam{Id: "a.txt", VersionId: "v1"})
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid version id"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("version id is given", func() {
+			It("should read the given generation", func() {
+				cl.EXPECT().
+					Attrs(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq("a.txt"), gomock.Eq(int64(5))).
+					Return(&storage.ObjectAttrs{Generation: 5, Size: 3}, nil).
+					Times(1)
+				cl.EXPECT().
+					NewReader(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq("a.txt"), gomock.Eq(int64(5)), gomock.Eq(int64(0)), gomock.Eq(int64(3))).
+					Return(io.NopCloser(strings.NewReader("old")), nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveStream(ctx, goseidon.RetrieveFileParam{Id: "a.txt", VersionId: "5"})
+
+				Expect(err).To(BeNil())
+				Expect(res.VersionId).To(Equal("5"))
+			})
+		})
+	})
+
+	Context("DeleteFile method", func() {
+		When("version id is given", func() {
+			It("should delete the given generation", func() {
+				cl.EXPECT().
+					Delete(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq("a.txt"), gomock.Eq(int64(5))).
+					Return(nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt", VersionId: "5"})
+
+				eRes := &goseidon.DeleteFileResult{
+					Id:        "a.txt",
+					DeletedAt: currentTime,
+					VersionId: "5",
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListVersions method", func() {
+		var (
+			p goseidon.ListVersionsParam
+		)
+
+		BeforeEach(func() {
+			p = goseidon.ListVersionsParam{
+				Id:       "a.txt",
+				PageSize: 2,
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.ListVersions(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("continuation token is invalid", func() {
+			It("should return error", func() {
+				p.ContinuationToken = "token"
+
+				res, err := s.ListVersions(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid continuation token"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("failed list objects", func() {
+			It("should return error", func() {
+				cl.EXPECT().
+					ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Any(), gomock.Eq(1000), gomock.Eq("")).
+					Return(nil, "", fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := s.ListVersions(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("network error"))
+			})
+		})
+
+		When("object has generations", func() {
+			It("should return them newest first with the live one marked as latest", func() {
+				q := &storage.Query{
+					Prefix:      "a.txt",
+					StartOffset: "a.txt",
+					EndOffset:   "a.txt\x00",
+					Versions:    true,
+				}
+				gomock.InOrder(
+					cl.EXPECT().
+						ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(q), gomock.Eq(1000), gomock.Eq("")).
+						Return([]*storage.ObjectAttrs{
+							{Name: "a.txt", Generation: 1, Size: 3, Updated: currentTime.Add(-2 * time.Hour), Deleted: currentTime},
+							{Name: "a.txt", Generation: 2, Size: 4, Updated: currentTime.Add(-time.Hour), Deleted: currentTime},
+						}, "page-2", nil),
+					cl.EXPECT().
+						ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Eq(q), gomock.Eq(1000), gomock.Eq("page-2")).
+						Return([]*storage.ObjectAttrs{
+							{Name: "a.txt", Generation: 3, Size: 5, Updated: currentTime},
+						}, "", nil),
+				)
+
+				res, err := s.ListVersions(ctx, p)
+
+				eRes := &goseidon.ListVersionsResult{
+					Versions: []goseidon.FileVersion{
+						{VersionId: "3", Size: 5, LastModified: currentTime, IsLatest: true},
+						{VersionId: "2", Size: 4, LastModified: currentTime.Add(-time.Hour)},
+					},
+					NextContinuationToken: "2",
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("continuation token is given", func() {
+			It("should return the older generations", func() {
+				p.ContinuationToken = "2"
+				cl.EXPECT().
+					ListObjects(gomock.Eq(ctx), gomock.Eq(cfg.BucketName), gomock.Any(), gomock.Eq(1000), gomock.Eq("")).
+					Return([]*storage.ObjectAttrs{
+						{Name: "a.txt", Generation: 1, Size: 3, Deleted: currentTime},
+						{Name: "a.txt", Generation: 2, Size: 4, Deleted: currentTime},
+						{Name: "a.txt", Generation: 3, Size: 5},
+						{Name: "a.txt.bak", Generation: 9},
+					}, "", nil).
+					Times(1)
+
+				res, err := s.ListVersions(ctx, p)
+
+				eRes := &goseidon.ListVersionsResult{
+					Versions: []goseidon.FileVersion{
+						{VersionId: "1", Size: 3},
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,29 @@
+package limiter
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLimited is matched by every LimitError
+var ErrLimited = errors.New("storage call is limited")
+
+// LimitError is returned without calling the storage when a call is over the limits,
+// RetryAfter is the wait for the next token, it's zero when too many calls are in flight
+type LimitError struct {
+	Operation  Operation
+	InFlight   bool
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	if e.InFlight {
+		return fmt.Sprintf("too many calls in flight for %s", e.Operation)
+	}
+	return fmt.Sprintf("%s rate limit exceeded", e.Operation)
+}
+
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimited
+}
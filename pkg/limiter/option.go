@@ -0,0 +1,96 @@
+package limiter
+
+import (
+	"fmt"
+)
+
+type Operation string
+
+const (
+	OperationUpload   Operation = "upload"
+	OperationRetrieve Operation = "retrieve"
+	OperationDelete   Operation = "delete"
+)
+
+// Rate is a token bucket refilled with Limit tokens per second up to Burst tokens,
+// every call take one token
+type Rate struct {
+	Limit float64
+	Burst int
+}
+
+// LimiterConfig hold the limits, an operation without rate and zero MaxInFlight
+// are not limited. FailFast reject a call instead of waiting for its turn
+type LimiterConfig struct {
+	Rates       map[Operation]Rate
+	MaxInFlight int
+	FailFast    bool
+}
+
+type LimiterStorageOption interface {
+	Apply(c *LimiterConfig) error
+}
+
+type withRate struct {
+	operation Operation
+	rate      Rate
+}
+
+func (o *withRate) Apply(c *LimiterConfig) error {
+	switch o.operation {
+	case OperationUpload, OperationRetrieve, OperationDelete:
+	default:
+		return fmt.Errorf("invalid operation")
+	}
+	if o.rate.Limit <= 0 || o.rate.Burst < 1 {
+		return fmt.Errorf("invalid rate")
+	}
+	if c.Rates == nil {
+		c.Rates = map[Operation]Rate{}
+	}
+	c.Rates[o.operation] = o.rate
+	return nil
+}
+
+// WithRate allow limit calls per second of the operation, with bursts up to burst calls
+func WithRate(operation Operation, limit float64, burst int) LimiterStorageOption {
+	return &withRate{
+		operation: operation,
+		rate: Rate{
+			Limit: limit,
+			Burst: burst,
+		},
+	}
+}
+
+type withMaxInFlight struct {
+	maxInFlight int
+}
+
+func (o *withMaxInFlight) Apply(c *LimiterConfig) error {
+	if o.maxInFlight < 1 {
+		return fmt.Errorf("invalid max in flight")
+	}
+	c.MaxInFlight = o.maxInFlight
+	return nil
+}
+
+// WithMaxInFlight limit the number of calls running at once, whatever their operation
+func WithMaxInFlight(maxInFlight int) LimiterStorageOption {
+	return &withMaxInFlight{
+		maxInFlight: maxInFlight,
+	}
+}
+
+type withFailFast struct {
+}
+
+func (o *withFailFast) Apply(c *LimiterConfig) error {
+	c.FailFast = true
+	return nil
+}
+
+// WithFailFast reject a call over the limits with LimitError instead of waiting
+func WithFailFast() LimiterStorageOption {
+	return &withFailFast{}
+}
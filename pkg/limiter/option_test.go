@@ -0,0 +1,43 @@
+package limiter_test
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/core/pkg/limiter"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Limiter Option", func() {
+	var (
+		cfg *limiter.LimiterConfig
+	)
+
+	BeforeEach(func() {
+		cfg = &limiter.LimiterConfig{}
+	})
+
+	DescribeTable("invalid option",
+		func(opt limiter.LimiterStorageOption, eErr error) {
+			err := opt.Apply(cfg)
+
+			Expect(err).To(Equal(eErr))
+		},
+		Entry("unknown operation", limiter.WithRate("list", 1, 1), fmt.Errorf("invalid operation")),
+		Entry("limit", limiter.WithRate(limiter.OperationUpload, 0, 1), fmt.Errorf("invalid rate")),
+		Entry("burst", limiter.WithRate(limiter.OperationUpload, 1, 0), fmt.Errorf("invalid rate")),
+		Entry("max in flight", limiter.WithMaxInFlight(-1), fmt.Errorf("invalid max in flight")),
+	)
+
+	When("rate is given per operation", func() {
+		It("should keep every rate", func() {
+			Expect(limiter.WithRate(limiter.OperationUpload, 1, 2).Apply(cfg)).To(BeNil())
+			Expect(limiter.WithRate(limiter.OperationDelete, 3, 4).Apply(cfg)).To(BeNil())
+
+			Expect(cfg.Rates).To(Equal(map[limiter.Operation]limiter.Rate{
+				limiter.OperationUpload: {Limit: 1, Burst: 2},
+				limiter.OperationDelete: {Limit: 3, Burst: 4},
+			}))
+		})
+	})
+})
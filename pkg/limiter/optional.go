@@ -0,0 +1,205 @@
+package limiter
+
+import (
+	"context"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// the optional calls have no bucket of their own, each one draw from the bucket
+// of the operation it's closest to: writes from upload, reads and listings from
+// retrieve, removals from delete. a call the storage can't serve take no token
+
+func (s *LimiterStorage) UploadStream(ctx context.Context, p goseidon.UploadStreamParam) (*goseidon.UploadFileResult, error) {
+	storage, ok := s.Storage.(goseidon.StreamUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("stream upload")
+	}
+	release, err := s.acquire(ctx, OperationUpload)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.UploadStream(ctx, p)
+}
+
+// RetrieveStream hold its in flight slot while the stream is opened, reading it isn't limited
+func (s *LimiterStorage) RetrieveStream(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveStreamResult, error) {
+	storage, ok := s.Storage.(goseidon.StreamRetriever)
+	if !ok {
+		return nil, goseidon.Unsupported("stream retrieve")
+	}
+	release, err := s.acquire(ctx, OperationRetrieve)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.RetrieveStream(ctx, p)
+}
+
+func (s *LimiterStorage) StatFile(ctx context.Context, p goseidon.StatFileParam) (*goseidon.StatFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Stater)
+	if !ok {
+		return nil, goseidon.Unsupported("stat")
+	}
+	release, err := s.acquire(ctx, OperationRetrieve)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.StatFile(ctx, p)
+}
+
+func (s *LimiterStorage) ListFiles(ctx context.Context, p goseidon.ListFileParam) (*goseidon.ListFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Lister)
+	if !ok {
+		return nil, goseidon.Unsupported("list")
+	}
+	release, err := s.acquire(ctx, OperationRetrieve)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.ListFiles(ctx, p)
+}
+
+// SignURL isn't limited, signing is done without calling the provider
+func (s *LimiterStorage) SignURL(ctx context.Context, p goseidon.SignURLParam) (*goseidon.SignURLResult, error) {
+	storage, ok := s.Storage.(goseidon.URLSigner)
+	if !ok {
+		return nil, goseidon.Unsupported("sign url")
+	}
+	return storage.SignURL(ctx, p)
+}
+
+func (s *LimiterStorage) CreateMultipartUpload(ctx context.Context, p goseidon.CreateMultipartParam) (*goseidon.CreateMultipartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	release, err := s.acquire(ctx, OperationUpload)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.CreateMultipartUpload(ctx, p)
+}
+
+func (s *LimiterStorage) UploadPart(ctx context.Context, p goseidon.UploadPartParam) (*goseidon.UploadPartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	release, err := s.acquire(ctx, OperationUpload)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.UploadPart(ctx, p)
+}
+
+func (s *LimiterStorage) CompleteMultipartUpload(ctx context.Context, p goseidon.CompleteMultipartParam) (*goseidon.UploadFileResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	release, err := s.acquire(ctx, OperationUpload)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.CompleteMultipartUpload(ctx, p)
+}
+
+func (s *LimiterStorage) AbortMultipartUpload(ctx context.Context, p goseidon.AbortMultipartParam) (*goseidon.AbortMultipartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	release, err := s.acquire(ctx, OperationDelete)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.AbortMultipartUpload(ctx, p)
+}
+
+func (s *LimiterStorage) ListParts(ctx context.Context, p goseidon.ListPartsParam) (*goseidon.ListPartsResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	release, err := s.acquire(ctx, OperationRetrieve)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.ListParts(ctx, p)
+}
+
+func (s *LimiterStorage) CopyFile(ctx context.Context, p goseidon.CopyFileParam) (*goseidon.CopyFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Copier)
+	if !ok {
+		return nil, goseidon.Unsupported("copy")
+	}
+	release, err := s.acquire(ctx, OperationUpload)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.CopyFile(ctx, p)
+}
+
+func (s *LimiterStorage) MoveFile(ctx context.Context, p goseidon.MoveFileParam) (*goseidon.MoveFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Mover)
+	if !ok {
+		return nil, goseidon.Unsupported("move")
+	}
+	release, err := s.acquire(ctx, OperationUpload)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.MoveFile(ctx, p)
+}
+
+// DeleteFiles take a single delete token for the whole batch
+func (s *LimiterStorage) DeleteFiles(ctx context.Context, p goseidon.DeleteFilesParam) (*goseidon.DeleteFilesResult, error) {
+	storage, ok := s.Storage.(goseidon.BatchDeleter)
+	if !ok {
+		return nil, goseidon.Unsupported("batch delete")
+	}
+	release, err := s.acquire(ctx, OperationDelete)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.DeleteFiles(ctx, p)
+}
+
+// DeletePrefix take a single delete token however many files match
+func (s *LimiterStorage) DeletePrefix(ctx context.Context, p goseidon.DeletePrefixParam) (*goseidon.DeletePrefixResult, error) {
+	storage, ok := s.Storage.(goseidon.PrefixDeleter)
+	if !ok {
+		return nil, goseidon.Unsupported("prefix delete")
+	}
+	release, err := s.acquire(ctx, OperationDelete)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.DeletePrefix(ctx, p)
+}
+
+func (s *LimiterStorage) ListVersions(ctx context.Context, p goseidon.ListVersionsParam) (*goseidon.ListVersionsResult, error) {
+	storage, ok := s.Storage.(goseidon.VersionLister)
+	if !ok {
+		return nil, goseidon.Unsupported("list versions")
+	}
+	release, err := s.acquire(ctx, OperationRetrieve)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return storage.ListVersions(ctx, p)
+}
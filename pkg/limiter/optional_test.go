@@ -0,0 +1,181 @@
+package limiter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/pkg/limiter"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Optional interfaces", func() {
+	var (
+		ctx context.Context
+		s   *limiter.LimiterStorage
+		cfg *limiter.LimiterConfig
+		st  *goseidon.MockFullStorage
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		st = goseidon.NewMockFullStorage(ctrl)
+		clo := clock.NewMockClock(ctrl)
+		now = time.Now()
+		clo.EXPECT().Now().DoAndReturn(func() time.Time { return now }).AnyTimes()
+		cfg = &limiter.LimiterConfig{
+			Rates: map[limiter.Operation]limiter.Rate{
+				limiter.OperationRetrieve: {Limit: 1, Burst: 2},
+				limiter.OperationDelete:   {Limit: 1, Burst: 1},
+			},
+			FailFast: true,
+		}
+		s = &limiter.LimiterStorage{
+			Config:  cfg,
+			Storage: st,
+			Clock:   clo,
+		}
+	})
+
+	When("wrapped storage doesn't implement the interface", func() {
+		It("should return error without taking a token", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			inner := goseidon.NewMockStorage(ctrl)
+			s.Storage = inner
+			p := goseidon.DeleteFileParam{Id: "a.txt"}
+			inner.EXPECT().
+				DeleteFile(gomock.Eq(ctx), gomock.Eq(p)).
+				Return(&goseidon.DeleteFileResult{Id: "a.txt"}, nil).
+				Times(1)
+
+			_, err := s.DeleteFiles(ctx, goseidon.DeleteFilesParam{Ids: []string{"a.txt"}})
+			Expect(errors.Is(err, goseidon.ErrUnsupported)).To(BeTrue())
+			_, err = s.DeletePrefix(ctx, goseidon.DeletePrefixParam{Prefix: "a/"})
+			Expect(errors.Is(err, goseidon.ErrUnsupported)).To(BeTrue())
+
+			r, err := s.DeleteFile(ctx, p)
+
+			Expect(err).To(BeNil())
+			Expect(r).To(Equal(&goseidon.DeleteFileResult{Id: "a.txt"}))
+		})
+	})
+
+	When("reads exceed the retrieve rate", func() {
+		It("should reject the optional call", func() {
+			st.MockStorage.EXPECT().
+				RetrieveFile(gomock.Eq(ctx), gomock.Any()).
+				Return(&goseidon.RetrieveFileResult{}, nil).
+				Times(1)
+			st.MockStater.EXPECT().
+				StatFile(gomock.Eq(ctx), gomock.Any()).
+				Return(&goseidon.StatFileResult{}, nil).
+				Times(1)
+
+			s.RetrieveFile(ctx, goseidon.RetrieveFileParam{Id: "a.txt"})
+			s.StatFile(ctx, goseidon.StatFileParam{Id: "a.txt"})
+			r, err := s.ListFiles(ctx, goseidon.ListFileParam{Prefix: "a/"})
+
+			Expect(r).To(BeNil())
+			Expect(err.Error()).To(Equal("retrieve rate limit exceeded"))
+		})
+	})
+
+	When("removals exceed the delete rate", func() {
+		It("should reject the optional call", func() {
+			p := goseidon.DeleteFilesParam{Ids: []string{"a.txt", "b.txt"}}
+			res := &goseidon.DeleteFilesResult{}
+			st.MockBatchDeleter.EXPECT().
+				DeleteFiles(gomock.Eq(ctx), gomock.Eq(p)).
+				Return(res, nil).
+				Times(1)
+
+			r, err := s.DeleteFiles(ctx, p)
+			Expect(r).To(Equal(res))
+			Expect(err).To(BeNil())
+
+			_, err = s.DeletePrefix(ctx, goseidon.DeletePrefixParam{Prefix: "a/"})
+			Expect(err.Error()).To(Equal("delete rate limit exceeded"))
+		})
+	})
+
+	Context("SignURL method", func() {
+		When("retrieve rate is exhausted", func() {
+			It("should not be limited", func() {
+				cfg.Rates[limiter.OperationRetrieve] = limiter.Rate{Limit: 1, Burst: 1}
+				st.MockStorage.EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Any()).
+					Return(&goseidon.RetrieveFileResult{}, nil).
+					Times(1)
+				st.MockURLSigner.EXPECT().
+					SignURL(gomock.Eq(ctx), gomock.Any()).
+					Return(&goseidon.SignURLResult{URL: "http://a"}, nil).
+					Times(2)
+
+				s.RetrieveFile(ctx, goseidon.RetrieveFileParam{Id: "a.txt"})
+				_, err := s.SignURL(ctx, goseidon.SignURLParam{Id: "a.txt"})
+				Expect(err).To(BeNil())
+				_, err = s.SignURL(ctx, goseidon.SignURLParam{Id: "a.txt"})
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("RetrieveStream method", func() {
+		When("stream is open", func() {
+			It("should release its in flight slot", func() {
+				cfg.Rates = nil
+				cfg.MaxInFlight = 1
+				p := goseidon.RetrieveFileParam{Id: "a.txt"}
+				res := &goseidon.RetrieveStreamResult{File: io.NopCloser(strings.NewReader("a"))}
+				st.MockStreamRetriever.EXPECT().
+					RetrieveStream(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(res, nil).
+					Times(1)
+				st.MockStorage.EXPECT().
+					DeleteFile(gomock.Eq(ctx), gomock.Any()).
+					Return(&goseidon.DeleteFileResult{}, nil).
+					Times(1)
+
+				r, err := s.RetrieveStream(ctx, p)
+				Expect(r).To(Equal(res))
+				Expect(err).To(BeNil())
+
+				_, err = s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt"})
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UploadStream method", func() {
+		When("call is over the in flight limit", func() {
+			It("should return limit error", func() {
+				cfg.Rates = nil
+				cfg.MaxInFlight = 1
+				p := goseidon.UploadStreamParam{FileId: "a.txt", FileData: strings.NewReader("a")}
+				var inner error
+				st.MockStreamUploader.EXPECT().
+					UploadStream(gomock.Eq(ctx), gomock.Eq(p)).
+					DoAndReturn(func(context.Context, goseidon.UploadStreamParam) (*goseidon.UploadFileResult, error) {
+						_, inner = s.MoveFile(ctx, goseidon.MoveFileParam{SourceId: "a.txt", DestinationId: "b.txt"})
+						return &goseidon.UploadFileResult{}, nil
+					}).
+					Times(1)
+
+				_, err := s.UploadStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				var lerr *limiter.LimitError
+				Expect(errors.As(inner, &lerr)).To(BeTrue())
+				Expect(lerr.Operation).To(Equal(limiter.OperationUpload))
+			})
+		})
+	})
+})
@@ -0,0 +1,209 @@
+package limiter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+)
+
+// LimiterStorage throttle the calls made to the wrapped storage,
+// each operation draw from its own token bucket and every call share the in flight limit
+type LimiterStorage struct {
+	Config  *LimiterConfig
+	Storage goseidon.Storage
+	Clock   clock.Clock
+
+	once    sync.Once
+	mu      sync.Mutex
+	buckets map[Operation]*bucket
+	slots   chan struct{}
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (s *LimiterStorage) UploadFile(ctx context.Context, p goseidon.UploadFileParam) (*goseidon.UploadFileResult, error) {
+	release, err := s.acquire(ctx, OperationUpload)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return s.Storage.UploadFile(ctx, p)
+}
+
+func (s *LimiterStorage) RetrieveFile(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+	release, err := s.acquire(ctx, OperationRetrieve)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return s.Storage.RetrieveFile(ctx, p)
+}
+
+func (s *LimiterStorage) DeleteFile(ctx context.Context, p goseidon.DeleteFileParam) (*goseidon.DeleteFileResult, error) {
+	release, err := s.acquire(ctx, OperationDelete)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return s.Storage.DeleteFile(ctx, p)
+}
+
+// acquire take a token of the operation then an in flight slot,
+// release give the slot back once the call is done
+func (s *LimiterStorage) acquire(ctx context.Context, op Operation) (release func(), err error) {
+	release = func() {}
+	// the wrapped storage reports the invalid context
+	if ctx == nil {
+		return release, nil
+	}
+	s.once.Do(s.init)
+
+	err = s.wait(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.slots == nil {
+		return release, nil
+	}
+	release = func() {
+		<-s.slots
+	}
+	if s.Config.FailFast {
+		select {
+		case s.slots <- struct{}{}:
+			return release, nil
+		default:
+			s.refund(op)
+			return nil, &LimitError{
+				Operation: op,
+				InFlight:  true,
+			}
+		}
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		s.refund(op)
+		return nil, ctx.Err()
+	}
+}
+
+// wait until a token of the operation is available,
+// a reserved token is given back when ctx is done first
+func (s *LimiterStorage) wait(ctx context.Context, op Operation) error {
+	rate, ok := s.Config.Rates[op]
+	if !ok {
+		return nil
+	}
+
+	now := s.Clock.Now()
+	delay := s.reserve(op, rate, now)
+	if delay == 0 {
+		return nil
+	}
+
+	limitErr := &LimitError{
+		Operation:  op,
+		RetryAfter: delay,
+	}
+	deadline, ok := ctx.Deadline()
+	if s.Config.FailFast || (ok && now.Add(delay).After(deadline)) {
+		s.cancel(op, rate)
+		return limitErr
+	}
+
+	select {
+	case <-s.Clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		s.cancel(op, rate)
+		return ctx.Err()
+	}
+}
+
+// reserve take a token, the bucket goes into debt when it's empty
+// and the returned delay is the time needed to pay it back
+func (s *LimiterStorage) reserve(op Operation, rate Rate, now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[op]
+	if !ok {
+		b = &bucket{
+			tokens: float64(rate.Burst),
+			last:   now,
+		}
+		s.buckets[op] = b
+	}
+	if now.After(b.last) {
+		b.tokens = math.Min(float64(rate.Burst), b.tokens+now.Sub(b.last).Seconds()*rate.Limit)
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(-b.tokens / rate.Limit * float64(time.Second)))
+}
+
+func (s *LimiterStorage) cancel(op Operation, rate Rate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.buckets[op]
+	b.tokens = math.Min(float64(rate.Burst), b.tokens+1)
+}
+
+// refund give back the token taken by a call which didn't get its in flight slot
+func (s *LimiterStorage) refund(op Operation) {
+	rate, ok := s.Config.Rates[op]
+	if !ok {
+		return
+	}
+	s.cancel(op, rate)
+}
+
+func (s *LimiterStorage) init() {
+	s.buckets = map[Operation]*bucket{}
+	if s.Config.MaxInFlight > 0 {
+		s.slots = make(chan struct{}, s.Config.MaxInFlight)
+	}
+}
+
+func NewLimiterStorage(storage goseidon.Storage, opts ...LimiterStorageOption) (*LimiterStorage, error) {
+	if storage == nil {
+		return nil, fmt.Errorf("invalid storage")
+	}
+
+	cfg := &LimiterConfig{
+		Rates: map[Operation]Rate{},
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			return nil, fmt.Errorf("invalid limiter option")
+		}
+		err := opt.Apply(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clock, _ := clock.NewClock()
+	s := &LimiterStorage{
+		Config:  cfg,
+		Storage: storage,
+		Clock:   clock,
+	}
+	return s, nil
+}
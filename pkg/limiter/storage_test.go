@@ -0,0 +1,382 @@
+package limiter_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/pkg/limiter"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLimiter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Limiter Package")
+}
+
+var _ = Describe("Storage", func() {
+	var (
+		ctx context.Context
+		s   *limiter.LimiterStorage
+		cfg *limiter.LimiterConfig
+		st  *goseidon.MockStorage
+		clo *clock.MockClock
+		now time.Time
+		p   goseidon.RetrieveFileParam
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		st = goseidon.NewMockStorage(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		now = time.Now()
+		clo.EXPECT().Now().DoAndReturn(func() time.Time { return now }).AnyTimes()
+		p = goseidon.RetrieveFileParam{Id: "a.txt"}
+		cfg = &limiter.LimiterConfig{
+			Rates: map[limiter.Operation]limiter.Rate{
+				limiter.OperationRetrieve: {Limit: 2, Burst: 2},
+			},
+		}
+		s = &limiter.LimiterStorage{
+			Config:  cfg,
+			Storage: st,
+			Clock:   clo,
+		}
+	})
+
+	expectWait := func(delay time.Duration) {
+		clo.EXPECT().
+			After(gomock.Eq(delay)).
+			DoAndReturn(func(d time.Duration) <-chan time.Time {
+				now = now.Add(d)
+				ch := make(chan time.Time, 1)
+				ch <- now
+				return ch
+			}).
+			Times(1)
+	}
+
+	Context("NewLimiterStorage function", func() {
+		When("storage is invalid", func() {
+			It("should return error", func() {
+				s, err := limiter.NewLimiterStorage(nil)
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid storage")))
+			})
+		})
+
+		When("option is invalid", func() {
+			It("should return error", func() {
+				s, err := limiter.NewLimiterStorage(st, nil)
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid limiter option")))
+			})
+		})
+
+		When("failed apply option", func() {
+			It("should return error", func() {
+				s, err := limiter.NewLimiterStorage(st, limiter.WithMaxInFlight(0))
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid max in flight")))
+			})
+		})
+
+		When("success create storage", func() {
+			It("should apply every option", func() {
+				s, err := limiter.NewLimiterStorage(st,
+					limiter.WithRate(limiter.OperationUpload, 10, 5),
+					limiter.WithMaxInFlight(4),
+					limiter.WithFailFast(),
+				)
+
+				Expect(err).To(BeNil())
+				Expect(s.Config.Rates).To(Equal(map[limiter.Operation]limiter.Rate{
+					limiter.OperationUpload: {Limit: 10, Burst: 5},
+				}))
+				Expect(s.Config.MaxInFlight).To(Equal(4))
+				Expect(s.Config.FailFast).To(BeTrue())
+			})
+		})
+	})
+
+	Context("rate limit", func() {
+		When("burst is available", func() {
+			It("should not wait", func() {
+				st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(&goseidon.RetrieveFileResult{}, nil).Times(2)
+
+				_, err := s.RetrieveFile(ctx, p)
+				Expect(err).To(BeNil())
+				_, err = s.RetrieveFile(ctx, p)
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("bucket is empty", func() {
+			It("should wait for the next token", func() {
+				st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(&goseidon.RetrieveFileResult{}, nil).Times(4)
+				expectWait(500 * time.Millisecond)
+				expectWait(500 * time.Millisecond)
+
+				for i := 0; i < 4; i++ {
+					_, err := s.RetrieveFile(ctx, p)
+					Expect(err).To(BeNil())
+				}
+			})
+		})
+
+		When("bucket is refilled over time", func() {
+			It("should not wait", func() {
+				st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(&goseidon.RetrieveFileResult{}, nil).Times(3)
+
+				s.RetrieveFile(ctx, p)
+				s.RetrieveFile(ctx, p)
+				now = now.Add(time.Second)
+				_, err := s.RetrieveFile(ctx, p)
+
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("operation has no rate", func() {
+			It("should not be limited", func() {
+				st.EXPECT().DeleteFile(gomock.Eq(ctx), gomock.Any()).Return(&goseidon.DeleteFileResult{}, nil).Times(3)
+				st.EXPECT().UploadFile(gomock.Eq(ctx), gomock.Any()).Return(&goseidon.UploadFileResult{}, nil).Times(1)
+
+				for i := 0; i < 3; i++ {
+					_, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt"})
+					Expect(err).To(BeNil())
+				}
+				_, err := s.UploadFile(ctx, goseidon.UploadFileParam{FileId: "a.txt"})
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("fail fast is configured", func() {
+			It("should return limit error", func() {
+				cfg.FailFast = true
+				st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(&goseidon.RetrieveFileResult{}, nil).Times(3)
+
+				s.RetrieveFile(ctx, p)
+				s.RetrieveFile(ctx, p)
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, limiter.ErrLimited)).To(BeTrue())
+				Expect(err.Error()).To(Equal("retrieve rate limit exceeded"))
+				var lerr *limiter.LimitError
+				Expect(errors.As(err, &lerr)).To(BeTrue())
+				Expect(lerr.RetryAfter).To(Equal(500 * time.Millisecond))
+
+				// the rejected call didn't take the token
+				now = now.Add(500 * time.Millisecond)
+				_, err = s.RetrieveFile(ctx, p)
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("the wait would outlast the deadline", func() {
+			It("should return limit error", func() {
+				dctx, cancel := context.WithDeadline(ctx, now.Add(100*time.Millisecond))
+				defer cancel()
+				st.EXPECT().RetrieveFile(gomock.Eq(dctx), gomock.Eq(p)).Return(&goseidon.RetrieveFileResult{}, nil).Times(2)
+
+				s.RetrieveFile(dctx, p)
+				s.RetrieveFile(dctx, p)
+				_, err := s.RetrieveFile(dctx, p)
+
+				Expect(errors.Is(err, limiter.ErrLimited)).To(BeTrue())
+			})
+		})
+
+		When("context is done while waiting", func() {
+			It("should give the token back", func() {
+				cctx, cancel := context.WithCancel(ctx)
+				st.EXPECT().RetrieveFile(gomock.Any(), gomock.Eq(p)).Return(&goseidon.RetrieveFileResult{}, nil).Times(3)
+				clo.EXPECT().
+					After(gomock.Any()).
+					DoAndReturn(func(time.Duration) <-chan time.Time {
+						cancel()
+						return make(chan time.Time)
+					}).
+					Times(1)
+
+				s.RetrieveFile(ctx, p)
+				s.RetrieveFile(ctx, p)
+				res, err := s.RetrieveFile(cctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(context.Canceled))
+
+				now = now.Add(500 * time.Millisecond)
+				_, err = s.RetrieveFile(ctx, p)
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("in flight limit", func() {
+		BeforeEach(func() {
+			cfg.Rates = nil
+			cfg.MaxInFlight = 1
+		})
+
+		When("fail fast is configured", func() {
+			It("should reject a call over the limit", func() {
+				cfg.FailFast = true
+				var inner error
+				st.EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					DoAndReturn(func(context.Context, goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+						_, inner = s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt"})
+						return &goseidon.RetrieveFileResult{}, nil
+					}).
+					Times(1)
+
+				_, err := s.RetrieveFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(inner.Error()).To(Equal("too many calls in flight for delete"))
+				var lerr *limiter.LimitError
+				Expect(errors.As(inner, &lerr)).To(BeTrue())
+				Expect(lerr.InFlight).To(BeTrue())
+			})
+		})
+
+		When("call with a rate is rejected", func() {
+			It("should give the token back", func() {
+				cfg.FailFast = true
+				cfg.Rates = map[limiter.Operation]limiter.Rate{
+					limiter.OperationDelete: {Limit: 1, Burst: 1},
+				}
+				var inner error
+				st.EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					DoAndReturn(func(context.Context, goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+						_, inner = s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt"})
+						return &goseidon.RetrieveFileResult{}, nil
+					}).
+					Times(1)
+				st.EXPECT().
+					DeleteFile(gomock.Eq(ctx), gomock.Any()).
+					Return(&goseidon.DeleteFileResult{}, nil).
+					Times(1)
+
+				s.RetrieveFile(ctx, p)
+				_, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt"})
+
+				var lerr *limiter.LimitError
+				Expect(errors.As(inner, &lerr)).To(BeTrue())
+				Expect(lerr.InFlight).To(BeTrue())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("context is done while waiting", func() {
+			It("should return context error", func() {
+				cctx, cancel := context.WithCancel(ctx)
+				var inner error
+				st.EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					DoAndReturn(func(context.Context, goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+						cancel()
+						_, inner = s.RetrieveFile(cctx, p)
+						return &goseidon.RetrieveFileResult{}, nil
+					}).
+					Times(1)
+
+				_, err := s.RetrieveFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(inner).To(Equal(context.Canceled))
+			})
+		})
+
+		When("call with a rate is cancelled while waiting", func() {
+			It("should give the token back", func() {
+				cfg.Rates = map[limiter.Operation]limiter.Rate{
+					limiter.OperationDelete: {Limit: 1, Burst: 1},
+				}
+				cctx, cancel := context.WithCancel(ctx)
+				var inner error
+				st.EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					DoAndReturn(func(context.Context, goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+						cancel()
+						_, inner = s.DeleteFile(cctx, goseidon.DeleteFileParam{Id: "a.txt"})
+						return &goseidon.RetrieveFileResult{}, nil
+					}).
+					Times(1)
+				st.EXPECT().
+					DeleteFile(gomock.Eq(ctx), gomock.Any()).
+					Return(&goseidon.DeleteFileResult{}, nil).
+					Times(1)
+
+				s.RetrieveFile(ctx, p)
+				// a leaked token would make this call wait on the clock
+				_, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt"})
+
+				Expect(inner).To(Equal(context.Canceled))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("slot is released", func() {
+			It("should let the waiting call through", func() {
+				started := make(chan struct{})
+				proceed := make(chan struct{})
+				st.EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					DoAndReturn(func(context.Context, goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+						close(started)
+						<-proceed
+						return &goseidon.RetrieveFileResult{}, nil
+					}).
+					Times(1)
+				st.EXPECT().
+					DeleteFile(gomock.Eq(ctx), gomock.Any()).
+					Return(&goseidon.DeleteFileResult{}, nil).
+					Times(1)
+
+				done := make(chan error)
+				go func() {
+					_, err := s.RetrieveFile(ctx, p)
+					done <- err
+				}()
+				<-started
+				deleted := make(chan error)
+				go func() {
+					_, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt"})
+					deleted <- err
+				}()
+				Consistently(deleted, 50*time.Millisecond).ShouldNot(Receive())
+
+				close(proceed)
+				Expect(<-done).To(BeNil())
+				Eventually(deleted).Should(Receive(BeNil()))
+			})
+		})
+	})
+
+	When("context is invalid", func() {
+		It("should leave it to the storage", func() {
+			st.EXPECT().
+				RetrieveFile(nil, gomock.Eq(p)).
+				Return(nil, goseidon.ErrInvalidArgument).
+				Times(1)
+
+			_, err := s.RetrieveFile(nil, p)
+
+			Expect(err).To(Equal(goseidon.ErrInvalidArgument))
+		})
+	})
+})
@@ -0,0 +1,92 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// tmpPrefix mark a file being written, it's renamed into place once complete
+const tmpPrefix = goseidon.ReservedKeyPrefix + "tmp-"
+
+// writeTemp store src into a new temp file inside dir and flush it to disk,
+// the temp file is removed on failure, including once ctx is done
+func (s *LocalStorage) writeTemp(ctx context.Context, dir string, src io.Reader) (string, error) {
+	file, err := s.Client.CreateTemp(dir, tmpPrefix+"*", s.fileMode())
+	if err != nil {
+		return "", err
+	}
+	tmpPath := file.Name()
+
+	err = s.chown(tmpPath)
+	if err == nil {
+		_, err = s.Client.CopyContext(ctx, file, src)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if err != nil {
+		file.Close()
+		s.Client.RemoveFile(tmpPath)
+		return "", err
+	}
+
+	err = file.Close()
+	if err != nil {
+		s.Client.RemoveFile(tmpPath)
+		return "", err
+	}
+	return tmpPath, nil
+}
+
+// commit move the temp file into path, without replace the file is linked
+// instead so an existing file is never clobbered (fs.ErrExist is returned)
+func (s *LocalStorage) commit(tmpPath, path string, replace bool) error {
+	if replace {
+		err := s.Client.Rename(tmpPath, path)
+		if err != nil {
+			s.Client.RemoveFile(tmpPath)
+		}
+		return err
+	}
+
+	err := s.Client.Link(tmpPath, path)
+	s.Client.RemoveFile(tmpPath)
+	return err
+}
+
+// SweepTempFiles remove temp files older than maxAge,
+// such file is an orphan left behind by an interrupted write
+func (s *LocalStorage) SweepTempFiles(maxAge time.Duration) (int, error) {
+	entries, err := s.Client.WalkFiles(s.Config.StorageDir)
+	if err != nil {
+		return 0, wrapError(err, err)
+	}
+
+	threshold := s.Clock.Now().Add(-maxAge)
+	removed := 0
+	for _, entry := range entries {
+		if !strings.HasPrefix(path.Base(entry.Path), tmpPrefix) {
+			continue
+		}
+		if entry.ModTime.After(threshold) {
+			continue
+		}
+
+		err := s.Client.RemoveFile(s.Config.StorageDir + "/" + entry.Path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return removed, wrapError(err, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,13 @@
+package local
+
+import (
+	"context"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// DeleteFiles remove the ids one by one, removals share parent directories
+// and pruning them concurrently would race with each other
+func (s *LocalStorage) DeleteFiles(ctx context.Context, p goseidon.DeleteFilesParam) (*goseidon.DeleteFilesResult, error) {
+	return goseidon.DeleteEach(ctx, s, p, 1)
+}
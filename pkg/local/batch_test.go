@@ -0,0 +1,113 @@
+package local_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/internal/io"
+	"github.com/go-seidon/core/pkg/local"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batch", func() {
+	var (
+		ctx         context.Context
+		s           *local.LocalStorage
+		cfg         *local.LocalConfig
+		clo         *clock.MockClock
+		fm          *io.MockFileManager
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cfg = &local.LocalConfig{
+			StorageDir: "storage",
+		}
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		fm = io.NewMockFileManager(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Now()
+		s = &local.LocalStorage{
+			Config: cfg,
+			Client: fm,
+			Clock:  clo,
+		}
+	})
+
+	Context("DeleteFiles method", func() {
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.DeleteFiles(nil, goseidon.DeleteFilesParam{Ids: []string{"a.jpg"}})
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("ids are empty", func() {
+			It("should return error", func() {
+				res, err := s.DeleteFiles(ctx, goseidon.DeleteFilesParam{})
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("ids are empty"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("ids have different outcomes", func() {
+			It("should report every id in order", func() {
+				fm.EXPECT().Stat(gomock.Eq("storage/tenant-1/a.jpg")).Return(&fileInfo{size: 1}, nil).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq("storage/tenant-1/a.jpg")).Return(nil).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq("storage/tenant-1/.goseidon-meta-a.jpg.json")).Return(fs.ErrNotExist).Times(1)
+				fm.EXPECT().RemoveDir(gomock.Eq("storage/tenant-1")).Return(fs.ErrExist).Times(1)
+
+				fm.EXPECT().Stat(gomock.Eq("storage/missing.jpg")).Return(nil, fs.ErrNotExist).Times(1)
+
+				fm.EXPECT().Stat(gomock.Eq("storage/locked.jpg")).Return(&fileInfo{size: 1}, nil).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq("storage/locked.jpg")).Return(fs.ErrPermission).Times(1)
+
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.DeleteFiles(ctx, goseidon.DeleteFilesParam{
+					Ids: []string{"tenant-1/a.jpg", "missing.jpg", "../secret.txt", "locked.jpg"},
+				})
+
+				Expect(err).To(BeNil())
+				Expect(res.Items).To(HaveLen(4))
+				Expect(res.Items[0]).To(Equal(goseidon.DeleteFileItem{Id: "tenant-1/a.jpg", DeletedAt: currentTime}))
+				Expect(res.Items[1].Id).To(Equal("missing.jpg"))
+				Expect(res.Items[1].Error).To(Equal(goseidon.ErrNotFound))
+				Expect(res.Items[2].Id).To(Equal("../secret.txt"))
+				Expect(errors.Is(res.Items[2].Error, goseidon.ErrInvalidArgument)).To(BeTrue())
+				Expect(res.Items[3].Id).To(Equal("locked.jpg"))
+				Expect(res.Items[3].Error.Error()).To(Equal("failed delete file"))
+				Expect(errors.Is(res.Items[3].Error, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("context is cancelled", func() {
+			It("should report every id with the context error", func() {
+				cctx, cancel := context.WithCancel(ctx)
+				cancel()
+
+				res, err := s.DeleteFiles(cctx, goseidon.DeleteFilesParam{
+					Ids: []string{"a.jpg", "b.jpg"},
+				})
+
+				Expect(err).To(BeNil())
+				Expect(res.Items).To(Equal([]goseidon.DeleteFileItem{
+					{Id: "a.jpg", Error: context.Canceled},
+					{Id: "b.jpg", Error: context.Canceled},
+				}))
+			})
+		})
+	})
+})
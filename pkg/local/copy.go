@@ -0,0 +1,198 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// CopyFile duplicate the file content and its metadata sidecar,
+// the copy goes through a temp file like any other upload
+func (s *LocalStorage) CopyFile(ctx context.Context, p goseidon.CopyFileParam) (*goseidon.CopyFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateCopy(p.SourceId, p.DestinationId)
+	if err != nil {
+		return nil, err
+	}
+
+	srcPath, _ := s.filePath(p.SourceId)
+	if !s.Client.IsExists(srcPath) {
+		return nil, goseidon.ErrNotFound
+	}
+
+	meta, err := s.readMeta(srcPath)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed read file metadata"))
+	}
+
+	file, err := s.Client.Open(srcPath)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed open file"))
+	}
+	defer file.Close()
+
+	upload, err := s.UploadStream(ctx, goseidon.UploadStreamParam{
+		FileData:  file,
+		FileId:    p.DestinationId,
+		FileName:  meta.FileName,
+		Overwrite: p.Overwrite,
+
+		ContentType:        meta.ContentType,
+		ContentDisposition: meta.ContentDisposition,
+		CacheControl:       meta.CacheControl,
+		Metadata:           meta.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &goseidon.CopyFileResult{
+		SourceId:      p.SourceId,
+		DestinationId: p.DestinationId,
+		CopiedAt:      upload.UploadedAt,
+		Skipped:       upload.Skipped,
+	}
+	return res, nil
+}
+
+// MoveFile rename the file into its destination, without replace the file
+// is linked then unlinked instead so an existing file is never clobbered.
+// the destination sidecar is written before the move, a move which can't
+// commit it is rolled back so the source is left as it was
+func (s *LocalStorage) MoveFile(ctx context.Context, p goseidon.MoveFileParam) (*goseidon.MoveFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateCopy(p.SourceId, p.DestinationId)
+	if err != nil {
+		return nil, err
+	}
+
+	srcPath, _ := s.filePath(p.SourceId)
+	dstPath, _ := s.filePath(p.DestinationId)
+	found, err := s.isFile(srcPath)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed stat file"))
+	}
+	if !found {
+		return nil, goseidon.ErrNotFound
+	}
+
+	meta, err := s.readMeta(srcPath)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed read file metadata"))
+	}
+
+	dir := filepath.Dir(dstPath)
+	err = s.createDir(dir)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed create storage dir: %s", dir))
+	}
+
+	replace := p.Overwrite == goseidon.OverwriteReplace
+	if !replace && s.Client.IsExists(dstPath) {
+		return s.moveConflict(p)
+	}
+
+	metaTmpPath, err := s.writeMetaTemp(dstPath, *meta)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed storing file metadata"))
+	}
+
+	// the replaced destination is archived before it's clobbered
+	if replace && s.Config.Versioning && s.Client.IsExists(dstPath) {
+		_, err = s.archive(p.DestinationId, dstPath)
+		if err != nil {
+			s.Client.RemoveFile(metaTmpPath)
+			return nil, wrapError(err, fmt.Errorf("failed archive file"))
+		}
+	}
+
+	// the source key is left like on delete, its current version stay available
+	if s.Config.Versioning {
+		_, err = s.archive(p.SourceId, srcPath)
+		if err != nil {
+			s.Client.RemoveFile(metaTmpPath)
+			return nil, wrapError(err, fmt.Errorf("failed archive file"))
+		}
+	}
+
+	// the replaced sidecar is dropped first, like on upload
+	if replace {
+		err = s.Client.RemoveFile(metaPath(dstPath))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			s.Client.RemoveFile(metaTmpPath)
+			return nil, wrapError(err, fmt.Errorf("failed storing file metadata"))
+		}
+	}
+
+	if replace {
+		err = s.Client.Rename(srcPath, dstPath)
+	} else {
+		err = s.Client.Link(srcPath, dstPath)
+		if err == nil {
+			err = s.Client.RemoveFile(srcPath)
+			if err != nil {
+				s.Client.RemoveFile(dstPath)
+			}
+		}
+	}
+	if err != nil {
+		s.Client.RemoveFile(metaTmpPath)
+	}
+	if errors.Is(err, fs.ErrExist) {
+		return s.moveConflict(p)
+	}
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed move file"))
+	}
+
+	err = s.commit(metaTmpPath, metaPath(dstPath), true)
+	if err != nil {
+		s.Client.Rename(dstPath, srcPath)
+		return nil, wrapError(err, fmt.Errorf("failed storing file metadata"))
+	}
+	err = s.Client.RemoveFile(metaPath(srcPath))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, wrapError(err, fmt.Errorf("failed delete file metadata"))
+	}
+
+	s.pruneDirs(p.SourceId)
+
+	if s.Config.SyncDir {
+		err = s.Client.SyncDir(dir)
+		if err != nil {
+			return nil, wrapError(err, fmt.Errorf("failed sync storage dir"))
+		}
+	}
+
+	movedAt := s.Clock.Now()
+	res := &goseidon.MoveFileResult{
+		SourceId:      p.SourceId,
+		DestinationId: p.DestinationId,
+		MovedAt:       movedAt,
+	}
+	return res, nil
+}
+
+// moveConflict resolve a move to an already taken path, the source is kept
+func (s *LocalStorage) moveConflict(p goseidon.MoveFileParam) (*goseidon.MoveFileResult, error) {
+	if p.Overwrite != goseidon.OverwriteSkip {
+		return nil, goseidon.ErrAlreadyExists
+	}
+
+	movedAt := s.Clock.Now()
+	res := &goseidon.MoveFileResult{
+		SourceId:      p.SourceId,
+		DestinationId: p.DestinationId,
+		MovedAt:       movedAt,
+		Skipped:       true,
+	}
+	return res, nil
+}
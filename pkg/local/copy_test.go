@@ -0,0 +1,485 @@
+package local_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	goio "io"
+	"io/fs"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/internal/io"
+	"github.com/go-seidon/core/pkg/local"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Copy", func() {
+	const (
+		srcPath     = "storage/image.jpg"
+		srcMetaPath = "storage/.goseidon-meta-image.jpg.json"
+		dstDir      = "storage/archive"
+		dstPath     = dstDir + "/image.jpg"
+		dstMetaPath = dstDir + "/.goseidon-meta-image.jpg.json"
+		meta        = `{"file_name":"image.jpg","content_type":"image/jpeg"}`
+	)
+
+	var (
+		ctx         context.Context
+		s           *local.LocalStorage
+		cfg         *local.LocalConfig
+		clo         *clock.MockClock
+		fm          *io.MockFileManager
+		file        *io.MockFile
+		metaFile    *io.MockFile
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cfg = &local.LocalConfig{
+			StorageDir: "storage",
+		}
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		fm = io.NewMockFileManager(ctrl)
+		file = io.NewMockFile(ctrl)
+		metaFile = io.NewMockFile(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Now()
+		s = &local.LocalStorage{
+			Config: cfg,
+			Client: fm,
+			Clock:  clo,
+		}
+	})
+
+	expectReadMeta := func() {
+		fm.EXPECT().
+			Open(gomock.Eq(srcMetaPath)).
+			Return(metaFile, nil).
+			Times(1)
+		fm.EXPECT().
+			ReadFile(gomock.Eq(metaFile)).
+			Return([]byte(meta), nil).
+			Times(1)
+		metaFile.EXPECT().Close().Return(nil).Times(1)
+	}
+
+	Context("CopyFile method", func() {
+		var p goseidon.CopyFileParam
+
+		BeforeEach(func() {
+			p = goseidon.CopyFileParam{
+				SourceId:      "image.jpg",
+				DestinationId: "archive/image.jpg",
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.CopyFile(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("source and destination are the same file", func() {
+			It("should return error", func() {
+				p.DestinationId = p.SourceId
+				res, err := s.CopyFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("source is not found", func() {
+			It("should return error", func() {
+				fm.EXPECT().IsExists(gomock.Eq(srcPath)).Return(false).Times(1)
+
+				res, err := s.CopyFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+
+		When("failed open source", func() {
+			It("should return error", func() {
+				fm.EXPECT().IsExists(gomock.Eq(srcPath)).Return(true).Times(1)
+				expectReadMeta()
+				fm.EXPECT().
+					Open(gomock.Eq(srcPath)).
+					Return(nil, fs.ErrPermission).
+					Times(1)
+
+				res, err := s.CopyFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("destination already exists", func() {
+			It("should return error", func() {
+				fm.EXPECT().IsExists(gomock.Eq(srcPath)).Return(true).Times(1)
+				expectReadMeta()
+				fm.EXPECT().Open(gomock.Eq(srcPath)).Return(file, nil).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(dstDir)).Return(true).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(dstPath)).Return(true).Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+
+				res, err := s.CopyFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrAlreadyExists))
+			})
+		})
+
+		When("destination already exists and overwrite mode is skip", func() {
+			It("should return skipped result", func() {
+				p.Overwrite = goseidon.OverwriteSkip
+				fm.EXPECT().IsExists(gomock.Eq(srcPath)).Return(true).Times(1)
+				expectReadMeta()
+				fm.EXPECT().Open(gomock.Eq(srcPath)).Return(file, nil).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(dstDir)).Return(true).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(dstPath)).Return(true).Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CopyFile(ctx, p)
+
+				eRes := &goseidon.CopyFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					CopiedAt:      currentTime,
+					Skipped:       true,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("success copy file", func() {
+			It("should store the content and metadata", func() {
+				dstFile := io.NewMockFile(gomock.NewController(GinkgoT()))
+				dstMetaFile := io.NewMockFile(gomock.NewController(GinkgoT()))
+				fm.EXPECT().IsExists(gomock.Eq(srcPath)).Return(true).Times(1)
+				expectReadMeta()
+				fm.EXPECT().Open(gomock.Eq(srcPath)).Return(file, nil).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(dstDir)).Return(true).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(dstPath)).Return(false).Times(1)
+
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(dstDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(dstFile, nil).
+					Times(1)
+				dstFile.EXPECT().Name().Return(dstDir + "/.goseidon-tmp-1").Times(1)
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(dstFile), gomock.Any()).
+					Return(int64(5), nil).
+					Times(1)
+				dstFile.EXPECT().Sync().Return(nil).Times(1)
+				dstFile.EXPECT().Close().Return(nil).Times(1)
+				fm.EXPECT().
+					Link(gomock.Eq(dstDir+"/.goseidon-tmp-1"), gomock.Eq(dstPath)).
+					Return(nil).
+					Times(1)
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(dstDir + "/.goseidon-tmp-1")).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(dstDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(dstMetaFile, nil).
+					Times(1)
+				dstMetaFile.EXPECT().Name().Return(dstDir + "/.goseidon-tmp-2").Times(1)
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(dstMetaFile), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ goio.Writer, src goio.Reader) (int64, error) {
+						data, _ := goio.ReadAll(src)
+						Expect(string(data)).To(HavePrefix(meta[:len(meta)-1] + `,"md5":`))
+						return int64(len(data)), nil
+					}).
+					Times(1)
+				dstMetaFile.EXPECT().Sync().Return(nil).Times(1)
+				dstMetaFile.EXPECT().Close().Return(nil).Times(1)
+				fm.EXPECT().
+					Rename(gomock.Eq(dstDir+"/.goseidon-tmp-2"), gomock.Eq(dstMetaPath)).
+					Return(nil).
+					Times(1)
+
+				file.EXPECT().Close().Return(nil).Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CopyFile(ctx, p)
+
+				eRes := &goseidon.CopyFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					CopiedAt:      currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("MoveFile method", func() {
+		var p goseidon.MoveFileParam
+
+		BeforeEach(func() {
+			p = goseidon.MoveFileParam{
+				SourceId:      "image.jpg",
+				DestinationId: "archive/image.jpg",
+			}
+		})
+
+		// expectWriteMeta expect the destination sidecar in a temp file, committed when commit is set
+		expectWriteMeta := func(commit bool) {
+			fm.EXPECT().
+				CreateTemp(gomock.Eq(dstDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+				Return(file, nil).
+				Times(1)
+			file.EXPECT().Name().Return(dstDir + "/.goseidon-tmp-1").Times(1)
+			fm.EXPECT().
+				CopyContext(gomock.Any(), gomock.Eq(file), gomock.Eq(bytes.NewReader([]byte(meta)))).
+				Return(int64(len(meta)), nil).
+				Times(1)
+			file.EXPECT().Sync().Return(nil).Times(1)
+			file.EXPECT().Close().Return(nil).Times(1)
+			if commit {
+				fm.EXPECT().
+					Rename(gomock.Eq(dstDir+"/.goseidon-tmp-1"), gomock.Eq(dstMetaPath)).
+					Return(nil).
+					Times(1)
+				return
+			}
+			fm.EXPECT().RemoveFile(gomock.Eq(dstDir + "/.goseidon-tmp-1")).Return(nil).Times(1)
+		}
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.MoveFile(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("destination escapes storage dir", func() {
+			It("should return error", func() {
+				p.DestinationId = "../image.jpg"
+				res, err := s.MoveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("source is not found", func() {
+			It("should return error", func() {
+				fm.EXPECT().Stat(gomock.Eq(srcPath)).Return(nil, fs.ErrNotExist).Times(1)
+
+				res, err := s.MoveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+
+		When("source is a directory", func() {
+			It("should return error", func() {
+				fm.EXPECT().Stat(gomock.Eq(srcPath)).Return(&fileInfo{dir: true}, nil).Times(1)
+
+				res, err := s.MoveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+
+		When("destination already exists", func() {
+			It("should return error", func() {
+				fm.EXPECT().Stat(gomock.Eq(srcPath)).Return(&fileInfo{size: 5}, nil).Times(1)
+				expectReadMeta()
+				fm.EXPECT().IsExists(gomock.Eq(dstDir)).Return(true).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(dstPath)).Return(true).Times(1)
+
+				res, err := s.MoveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrAlreadyExists))
+			})
+		})
+
+		When("destination is taken while linking and overwrite mode is skip", func() {
+			It("should keep the source", func() {
+				p.Overwrite = goseidon.OverwriteSkip
+				fm.EXPECT().Stat(gomock.Eq(srcPath)).Return(&fileInfo{size: 5}, nil).Times(1)
+				expectReadMeta()
+				fm.EXPECT().IsExists(gomock.Eq(dstDir)).Return(true).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(dstPath)).Return(false).Times(1)
+				expectWriteMeta(false)
+				fm.EXPECT().
+					Link(gomock.Eq(srcPath), gomock.Eq(dstPath)).
+					Return(fs.ErrExist).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.MoveFile(ctx, p)
+
+				eRes := &goseidon.MoveFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					MovedAt:       currentTime,
+					Skipped:       true,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed rename file", func() {
+			It("should return error", func() {
+				p.Overwrite = goseidon.OverwriteReplace
+				fm.EXPECT().Stat(gomock.Eq(srcPath)).Return(&fileInfo{size: 5}, nil).Times(1)
+				expectReadMeta()
+				fm.EXPECT().IsExists(gomock.Eq(dstDir)).Return(true).Times(1)
+				expectWriteMeta(false)
+				fm.EXPECT().RemoveFile(gomock.Eq(dstMetaPath)).Return(fs.ErrNotExist).Times(1)
+				fm.EXPECT().
+					Rename(gomock.Eq(srcPath), gomock.Eq(dstPath)).
+					Return(fmt.Errorf("disk error")).
+					Times(1)
+
+				res, err := s.MoveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed move file")))
+			})
+		})
+
+		When("failed commit metadata", func() {
+			It("should move the file back", func() {
+				p.Overwrite = goseidon.OverwriteReplace
+				fm.EXPECT().Stat(gomock.Eq(srcPath)).Return(&fileInfo{size: 5}, nil).Times(1)
+				expectReadMeta()
+				fm.EXPECT().IsExists(gomock.Eq(dstDir)).Return(true).Times(1)
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(dstDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Name().Return(dstDir + "/.goseidon-tmp-1").Times(1)
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(file), gomock.Eq(bytes.NewReader([]byte(meta)))).
+					Return(int64(len(meta)), nil).
+					Times(1)
+				file.EXPECT().Sync().Return(nil).Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq(dstMetaPath)).Return(nil).Times(1)
+				gomock.InOrder(
+					fm.EXPECT().
+						Rename(gomock.Eq(srcPath), gomock.Eq(dstPath)).
+						Return(nil).
+						Times(1),
+					fm.EXPECT().
+						Rename(gomock.Eq(dstDir+"/.goseidon-tmp-1"), gomock.Eq(dstMetaPath)).
+						Return(fs.ErrPermission).
+						Times(1),
+					fm.EXPECT().RemoveFile(gomock.Eq(dstDir+"/.goseidon-tmp-1")).Return(nil).Times(1),
+					fm.EXPECT().
+						Rename(gomock.Eq(dstPath), gomock.Eq(srcPath)).
+						Return(nil).
+						Times(1),
+				)
+
+				res, err := s.MoveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed storing file metadata"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("success move file", func() {
+			It("should link then unlink the source", func() {
+				fm.EXPECT().Stat(gomock.Eq(srcPath)).Return(&fileInfo{size: 5}, nil).Times(1)
+				expectReadMeta()
+				fm.EXPECT().IsExists(gomock.Eq(dstDir)).Return(true).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(dstPath)).Return(false).Times(1)
+				fm.EXPECT().
+					Link(gomock.Eq(srcPath), gomock.Eq(dstPath)).
+					Return(nil).
+					Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq(srcPath)).Return(nil).Times(1)
+				expectWriteMeta(true)
+				fm.EXPECT().RemoveFile(gomock.Eq(srcMetaPath)).Return(nil).Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.MoveFile(ctx, p)
+
+				eRes := &goseidon.MoveFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					MovedAt:       currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("success move nested file with replace", func() {
+			It("should rename the file and prune empty dirs", func() {
+				p.SourceId = "tmp/image.jpg"
+				p.Overwrite = goseidon.OverwriteReplace
+				cfg.SyncDir = true
+				fm.EXPECT().Stat(gomock.Eq("storage/tmp/image.jpg")).Return(&fileInfo{size: 5}, nil).Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq("storage/tmp/.goseidon-meta-image.jpg.json")).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(dstDir)).Return(true).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq(dstMetaPath)).Return(nil).Times(1)
+				fm.EXPECT().
+					Rename(gomock.Eq("storage/tmp/image.jpg"), gomock.Eq(dstPath)).
+					Return(nil).
+					Times(1)
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(dstDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Name().Return(dstDir + "/.goseidon-tmp-1").Times(1)
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(file), gomock.Eq(bytes.NewReader([]byte(`{}`)))).
+					Return(int64(2), nil).
+					Times(1)
+				file.EXPECT().Sync().Return(nil).Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+				fm.EXPECT().
+					Rename(gomock.Eq(dstDir+"/.goseidon-tmp-1"), gomock.Eq(dstMetaPath)).
+					Return(nil).
+					Times(1)
+				fm.EXPECT().
+					RemoveFile(gomock.Eq("storage/tmp/.goseidon-meta-image.jpg.json")).
+					Return(fs.ErrNotExist).
+					Times(1)
+				fm.EXPECT().RemoveDir(gomock.Eq("storage/tmp")).Return(nil).Times(1)
+				fm.EXPECT().SyncDir(gomock.Eq(dstDir)).Return(nil).Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.MoveFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Skipped).To(BeFalse())
+			})
+		})
+	})
+})
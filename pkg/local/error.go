@@ -0,0 +1,41 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// wrapError classify err using the os error which causing it,
+// err is returned as it is when the cause is not recognized
+func wrapError(cause, err error) error {
+	switch {
+	case errors.Is(cause, context.Canceled), errors.Is(cause, context.DeadlineExceeded):
+		// the context error is kept so a cancellation can be told apart
+		if errors.Is(err, cause) {
+			return err
+		}
+		return fmt.Errorf("%v: %w", err, cause)
+	case errors.Is(cause, fs.ErrNotExist):
+		return goseidon.NewError(goseidon.ErrNotFound, err)
+	case errors.Is(cause, fs.ErrExist):
+		return goseidon.NewError(goseidon.ErrAlreadyExists, err)
+	case errors.Is(cause, fs.ErrPermission):
+		return goseidon.NewError(goseidon.ErrPermission, err)
+	case errors.Is(cause, fs.ErrInvalid),
+		errors.Is(cause, syscall.ENAMETOOLONG),
+		errors.Is(cause, syscall.EISDIR),
+		errors.Is(cause, syscall.ENOTDIR):
+		return goseidon.NewError(goseidon.ErrInvalidArgument, err)
+	case errors.Is(cause, syscall.EAGAIN),
+		errors.Is(cause, syscall.EINTR),
+		errors.Is(cause, syscall.EBUSY),
+		errors.Is(cause, syscall.ETIMEDOUT):
+		return goseidon.NewError(goseidon.ErrTransient, err)
+	}
+	return err
+}
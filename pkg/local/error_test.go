@@ -0,0 +1,76 @@
+package local_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/internal/io"
+	"github.com/go-seidon/core/pkg/local"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Error", func() {
+	var (
+		ctx context.Context
+		s   *local.LocalStorage
+		fm  *io.MockFileManager
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		fm = io.NewMockFileManager(ctrl)
+		clo := clock.NewMockClock(ctrl)
+		clo.EXPECT().Now().Return(time.Now()).AnyTimes()
+		s = &local.LocalStorage{
+			Config: &local.LocalConfig{
+				StorageDir: "storage",
+			},
+			Client: fm,
+			Clock:  clo,
+		}
+	})
+
+	DescribeTable("os error is classified",
+		func(cause error, kind error) {
+			fm.EXPECT().Stat(gomock.Any()).Return(&fileInfo{size: 1}, nil).Times(1)
+			fm.EXPECT().RemoveFile(gomock.Any()).Return(cause).Times(1)
+
+			res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "file.jpg"})
+
+			Expect(res).To(BeNil())
+			Expect(err.Error()).To(Equal("failed delete file"))
+			Expect(errors.Is(err, kind)).To(BeTrue())
+		},
+		Entry("not exist", &os.PathError{Op: "remove", Err: syscall.ENOENT}, goseidon.ErrNotFound),
+		Entry("already exist", &os.PathError{Op: "remove", Err: syscall.EEXIST}, goseidon.ErrAlreadyExists),
+		Entry("permission denied", &os.PathError{Op: "remove", Err: syscall.EACCES}, goseidon.ErrPermission),
+		Entry("invalid file", fs.ErrInvalid, goseidon.ErrInvalidArgument),
+		Entry("name too long", &os.PathError{Op: "remove", Err: syscall.ENAMETOOLONG}, goseidon.ErrInvalidArgument),
+		Entry("is directory", &os.PathError{Op: "remove", Err: syscall.EISDIR}, goseidon.ErrInvalidArgument),
+		Entry("resource busy", &os.PathError{Op: "remove", Err: syscall.EBUSY}, goseidon.ErrTransient),
+		Entry("interrupted", &os.PathError{Op: "remove", Err: syscall.EINTR}, goseidon.ErrTransient),
+	)
+
+	When("os error is not recognized", func() {
+		It("should return unclassified error", func() {
+			fm.EXPECT().Stat(gomock.Any()).Return(&fileInfo{size: 1}, nil).Times(1)
+			fm.EXPECT().RemoveFile(gomock.Any()).Return(fmt.Errorf("unknown")).Times(1)
+
+			res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "file.jpg"})
+
+			Expect(res).To(BeNil())
+			Expect(err).To(Equal(fmt.Errorf("failed delete file")))
+		})
+	})
+})
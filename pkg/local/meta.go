@@ -0,0 +1,106 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// fileMeta is persisted as json sidecar next to the stored file
+type fileMeta struct {
+	FileName           string            `json:"file_name,omitempty"`
+	ContentType        string            `json:"content_type,omitempty"`
+	ContentDisposition string            `json:"content_disposition,omitempty"`
+	CacheControl       string            `json:"cache_control,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+
+	MD5    string `json:"md5,omitempty"`
+	CRC32C string `json:"crc32c,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+
+	VersionId string `json:"version_id,omitempty"`
+}
+
+func metaPath(path string) string {
+	dir, name := filepath.Split(path)
+	return dir + goseidon.ReservedKeyPrefix + "meta-" + name + ".json"
+}
+
+// isReserved report whether path is managed internally by local storage,
+// such file is never exposed as a stored file
+func isReserved(path string) bool {
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, goseidon.ReservedKeyPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *LocalStorage) writeMeta(path string, meta fileMeta) error {
+	tmpPath, err := s.writeMetaTemp(path, meta)
+	if err != nil {
+		return err
+	}
+	return s.commit(tmpPath, metaPath(path), true)
+}
+
+// writeMetaTemp store the sidecar of path into a temp file, it's committed by the caller
+func (s *LocalStorage) writeMetaTemp(path string, meta fileMeta) (string, error) {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return "", err
+	}
+
+	// the sidecar is small, it's never abandoned halfway on cancellation
+	return s.writeTemp(context.Background(), filepath.Dir(path), bytes.NewReader(data))
+}
+
+// readMeta return empty meta when the sidecar is absent,
+// e.g: file stored before sidecar was introduced
+func (s *LocalStorage) readMeta(path string) (*fileMeta, error) {
+	meta := &fileMeta{}
+
+	file, err := s.Client.Open(metaPath(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return meta, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := s.Client.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, meta)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file metadata: %w", err)
+	}
+	return meta, nil
+}
+
+func (m *fileMeta) checksum() goseidon.Checksum {
+	return goseidon.Checksum{
+		MD5:    m.MD5,
+		CRC32C: m.CRC32C,
+		SHA256: m.SHA256,
+	}
+}
+
+func (m *fileMeta) contentType(id string) string {
+	if m.ContentType != "" {
+		return m.ContentType
+	}
+	return mime.TypeByExtension(filepath.Ext(id))
+}
@@ -0,0 +1,422 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	goseidon "github.com/go-seidon/core"
+	goseidon_io "github.com/go-seidon/core/internal/io"
+)
+
+const (
+	// multipartDir hold a directory per pending upload with its manifest and parts,
+	// parts are concatenated into the file once the upload is completed
+	multipartDir = goseidon.ReservedKeyPrefix + "multipart"
+	manifestName = "manifest.json"
+	partPrefix   = "part-"
+)
+
+// uploadManifest is written when the upload is created
+type uploadManifest struct {
+	FileId string `json:"file_id"`
+	fileMeta
+}
+
+func (s *LocalStorage) CreateMultipartUpload(ctx context.Context, p goseidon.CreateMultipartParam) (*goseidon.CreateMultipartResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateKey(p.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadId, err := newUploadId()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(uploadManifest{
+		FileId: p.FileId,
+		fileMeta: fileMeta{
+			FileName:           p.FileName,
+			ContentType:        p.ContentType,
+			ContentDisposition: p.ContentDisposition,
+			CacheControl:       p.CacheControl,
+			Metadata:           p.Metadata,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dir := s.uploadDir(uploadId)
+	err = s.createDir(dir)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed create upload dir: %s", dir))
+	}
+	tmpPath, err := s.writeTemp(ctx, dir, bytes.NewReader(data))
+	if err == nil {
+		err = s.commit(tmpPath, dir+"/"+manifestName, true)
+	}
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed storing upload manifest"))
+	}
+
+	res := &goseidon.CreateMultipartResult{
+		UploadId:  uploadId,
+		FileId:    p.FileId,
+		CreatedAt: s.Clock.Now(),
+	}
+	return res, nil
+}
+
+// UploadPart store the part as "part-<number>-<md5>" so its etag is known without reading it,
+// an older copy of the same part number is removed once the new one is in place
+func (s *LocalStorage) UploadPart(ctx context.Context, p goseidon.UploadPartParam) (*goseidon.UploadPartResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	if p.PartData == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid part data"))
+	}
+	err := goseidon.ValidatePartNumber(p.PartNumber)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.readManifest(p.FileId, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := s.uploadDir(p.UploadId)
+	hash := md5.New()
+	var size byteCounter
+	tmpPath, err := s.writeTemp(ctx, dir, io.TeeReader(p.PartData, io.MultiWriter(hash, &size)))
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed storing part"))
+	}
+
+	etag := hex.EncodeToString(hash.Sum(nil))
+	name := partName(p.PartNumber, etag)
+	err = s.commit(tmpPath, dir+"/"+name, true)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed storing part"))
+	}
+
+	entries, err := s.Client.WalkFiles(dir)
+	if err == nil {
+		prefix := partName(p.PartNumber, "")
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.Path, prefix) && entry.Path != name {
+				s.Client.RemoveFile(dir + "/" + entry.Path)
+			}
+		}
+	}
+
+	res := &goseidon.UploadPartResult{
+		PartNumber: p.PartNumber,
+		ETag:       etag,
+		Size:       int64(size),
+		UploadedAt: s.Clock.Now(),
+	}
+	return res, nil
+}
+
+// CompleteMultipartUpload stream the parts one after another through UploadStream,
+// so the file is committed atomically the same way as a single upload
+func (s *LocalStorage) CompleteMultipartUpload(ctx context.Context, p goseidon.CompleteMultipartParam) (*goseidon.UploadFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateCompletedParts(p.Parts)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := s.readManifest(p.FileId, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	uploaded, err := s.listParts(p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+	etags := map[int]string{}
+	for _, part := range uploaded {
+		etags[part.PartNumber] = part.ETag
+	}
+
+	dir := s.uploadDir(p.UploadId)
+	paths := []string{}
+	for _, part := range p.Parts {
+		etag, ok := etags[part.PartNumber]
+		if !ok {
+			return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("part %d is not uploaded", part.PartNumber))
+		}
+		if etag != part.ETag {
+			return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("part %d etag mismatch", part.PartNumber))
+		}
+		paths = append(paths, dir+"/"+partName(part.PartNumber, etag))
+	}
+
+	data := &partReader{
+		client: s.Client,
+		paths:  paths,
+	}
+	defer data.Close()
+
+	res, err := s.UploadStream(ctx, goseidon.UploadStreamParam{
+		FileData:  data,
+		FileId:    p.FileId,
+		FileName:  manifest.FileName,
+		Overwrite: p.Overwrite,
+
+		ContentType:        manifest.ContentType,
+		ContentDisposition: manifest.ContentDisposition,
+		CacheControl:       manifest.CacheControl,
+		Metadata:           manifest.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the file is already committed, leftover parts are only garbage
+	data.Close()
+	s.removeUpload(p.UploadId)
+	return res, nil
+}
+
+func (s *LocalStorage) AbortMultipartUpload(ctx context.Context, p goseidon.AbortMultipartParam) (*goseidon.AbortMultipartResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	_, err := s.readManifest(p.FileId, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.removeUpload(p.UploadId)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed remove upload"))
+	}
+
+	res := &goseidon.AbortMultipartResult{
+		UploadId:  p.UploadId,
+		AbortedAt: s.Clock.Now(),
+	}
+	return res, nil
+}
+
+func (s *LocalStorage) ListParts(ctx context.Context, p goseidon.ListPartsParam) (*goseidon.ListPartsResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	_, err := s.readManifest(p.FileId, p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := s.listParts(p.UploadId)
+	if err != nil {
+		return nil, err
+	}
+	res := &goseidon.ListPartsResult{
+		Parts: parts,
+	}
+	return res, nil
+}
+
+func (s *LocalStorage) uploadDir(uploadId string) string {
+	return s.Config.StorageDir + "/" + multipartDir + "/" + uploadId
+}
+
+// readManifest validate the upload id and make sure it belong to fileId
+func (s *LocalStorage) readManifest(fileId, uploadId string) (*uploadManifest, error) {
+	err := goseidon.ValidateKey(fileId)
+	if err != nil {
+		return nil, err
+	}
+	if !isUploadId(uploadId) {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid upload id"))
+	}
+
+	file, err := s.Client.Open(s.uploadDir(uploadId) + "/" + manifestName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, goseidon.NewError(goseidon.ErrNotFound, fmt.Errorf("upload is not found"))
+	}
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed read upload manifest"))
+	}
+	defer file.Close()
+
+	data, err := s.Client.ReadFile(file)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed read upload manifest"))
+	}
+
+	manifest := &uploadManifest{}
+	err = json.Unmarshal(data, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upload manifest: %w", err)
+	}
+	if manifest.FileId != fileId {
+		return nil, goseidon.NewError(goseidon.ErrNotFound, fmt.Errorf("upload is not found"))
+	}
+	return manifest, nil
+}
+
+// listParts return the latest copy of every part number, ordered by part number
+func (s *LocalStorage) listParts(uploadId string) ([]goseidon.PartItem, error) {
+	entries, err := s.Client.WalkFiles(s.uploadDir(uploadId))
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed list parts"))
+	}
+
+	parts := []goseidon.PartItem{}
+	for _, entry := range entries {
+		n, etag, ok := parsePartName(entry.Path)
+		if !ok {
+			continue
+		}
+		part := goseidon.PartItem{
+			PartNumber:   n,
+			ETag:         etag,
+			Size:         entry.Size,
+			LastModified: entry.ModTime,
+		}
+
+		last := len(parts) - 1
+		if last >= 0 && parts[last].PartNumber == n {
+			if part.LastModified.After(parts[last].LastModified) {
+				parts[last] = part
+			}
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// removeUpload delete the parts and then the manifest,
+// so an interrupted removal can be retried
+func (s *LocalStorage) removeUpload(uploadId string) error {
+	dir := s.uploadDir(uploadId)
+	entries, err := s.Client.WalkFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Path == manifestName {
+			continue
+		}
+		err = s.Client.RemoveFile(dir + "/" + entry.Path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	err = s.Client.RemoveFile(dir + "/" + manifestName)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	s.Client.RemoveDir(dir)
+	return nil
+}
+
+func partName(partNumber int, etag string) string {
+	return fmt.Sprintf("%s%05d-%s", partPrefix, partNumber, etag)
+}
+
+func parsePartName(name string) (int, string, bool) {
+	if !strings.HasPrefix(name, partPrefix) {
+		return 0, "", false
+	}
+	rest := strings.TrimPrefix(name, partPrefix)
+	idx := strings.Index(rest, "-")
+	if idx < 0 {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(rest[:idx])
+	if err != nil {
+		return 0, "", false
+	}
+	return n, rest[idx+1:], true
+}
+
+func newUploadId() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func isUploadId(uploadId string) bool {
+	if len(uploadId) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(uploadId)
+	return err == nil
+}
+
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// partReader read the part files one after another,
+// only a single part is open at a time
+type partReader struct {
+	client goseidon_io.FileManager
+	paths  []string
+	file   goseidon_io.File
+}
+
+func (r *partReader) Read(p []byte) (int, error) {
+	for {
+		if r.file == nil {
+			if len(r.paths) == 0 {
+				return 0, io.EOF
+			}
+			file, err := r.client.Open(r.paths[0])
+			if err != nil {
+				return 0, err
+			}
+			r.file = file
+			r.paths = r.paths[1:]
+		}
+
+		n, err := r.file.Read(p)
+		if err == io.EOF {
+			r.file.Close()
+			r.file = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (r *partReader) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,586 @@
+package local_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	goio "io"
+	"io/fs"
+	"strings"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/internal/io"
+	"github.com/go-seidon/core/pkg/local"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Multipart", func() {
+	const (
+		uploadId  = "0123456789abcdef0123456789abcdef"
+		uploadDir = "storage/.goseidon-multipart/" + uploadId
+		manifest  = `{"file_id":"video.mp4","file_name":"video.mp4","content_type":"video/mp4"}`
+	)
+
+	var (
+		ctx          context.Context
+		s            *local.LocalStorage
+		cfg          *local.LocalConfig
+		clo          *clock.MockClock
+		fm           *io.MockFileManager
+		file         *io.MockFile
+		manifestFile *io.MockFile
+		currentTime  time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cfg = &local.LocalConfig{
+			StorageDir: "storage",
+		}
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		fm = io.NewMockFileManager(ctrl)
+		file = io.NewMockFile(ctrl)
+		manifestFile = io.NewMockFile(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Now()
+		s = &local.LocalStorage{
+			Config: cfg,
+			Client: fm,
+			Clock:  clo,
+		}
+	})
+
+	expectManifest := func(data string) {
+		fm.EXPECT().
+			Open(gomock.Eq(uploadDir+"/manifest.json")).
+			Return(manifestFile, nil).
+			Times(1)
+		fm.EXPECT().
+			ReadFile(gomock.Eq(manifestFile)).
+			Return([]byte(data), nil).
+			Times(1)
+		manifestFile.EXPECT().Close().Return(nil).Times(1)
+	}
+
+	Context("CreateMultipartUpload method", func() {
+		var p goseidon.CreateMultipartParam
+
+		BeforeEach(func() {
+			p = goseidon.CreateMultipartParam{
+				FileId:      "video.mp4",
+				FileName:    "video.mp4",
+				ContentType: "video/mp4",
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.CreateMultipartUpload(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("file id is invalid", func() {
+			It("should return error", func() {
+				p.FileId = "../video.mp4"
+				res, err := s.CreateMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("failed create upload dir", func() {
+			It("should return error", func() {
+				fm.EXPECT().IsExists(gomock.Any()).Return(false).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq("storage/.goseidon-multipart")).Return(true).Times(1)
+				fm.EXPECT().
+					CreateDir(gomock.Any(), gomock.Eq(fs.FileMode(0755))).
+					Return(fs.ErrPermission).
+					Times(1)
+
+				res, err := s.CreateMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(HavePrefix("failed create upload dir: storage/.goseidon-multipart/"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("success create upload", func() {
+			It("should store manifest", func() {
+				var dir string
+				fm.EXPECT().IsExists(gomock.Any()).DoAndReturn(func(path string) bool {
+					dir = path
+					return false
+				}).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq("storage/.goseidon-multipart")).Return(true).Times(1)
+				fm.EXPECT().CreateDir(gomock.Any(), gomock.Eq(fs.FileMode(0755))).Return(nil).Times(1)
+				fm.EXPECT().Chmod(gomock.Any(), gomock.Eq(fs.FileMode(0755))).Return(nil).Times(1)
+				fm.EXPECT().
+					CreateTemp(gomock.Any(), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Name().Return("tmp").Times(1)
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(file), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ goio.Writer, src goio.Reader) (int64, error) {
+						data, _ := goio.ReadAll(src)
+						Expect(string(data)).To(Equal(manifest))
+						return int64(len(data)), nil
+					}).
+					Times(1)
+				file.EXPECT().Sync().Return(nil).Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+				fm.EXPECT().Rename(gomock.Eq("tmp"), gomock.Any()).Return(nil).Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.CreateMultipartUpload(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.UploadId).To(HaveLen(32))
+				Expect(res.FileId).To(Equal(p.FileId))
+				Expect(res.CreatedAt).To(Equal(currentTime))
+				Expect(dir).To(Equal("storage/.goseidon-multipart/" + res.UploadId))
+			})
+		})
+	})
+
+	Context("UploadPart method", func() {
+		var p goseidon.UploadPartParam
+
+		BeforeEach(func() {
+			p = goseidon.UploadPartParam{
+				UploadId:   uploadId,
+				FileId:     "video.mp4",
+				PartNumber: 1,
+				PartData:   strings.NewReader("hello"),
+			}
+		})
+
+		When("part data is invalid", func() {
+			It("should return error", func() {
+				p.PartData = nil
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid part data"))
+			})
+		})
+
+		When("upload id is invalid", func() {
+			It("should return error", func() {
+				p.UploadId = "../../etc"
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid upload id"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("upload is not found", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					Open(gomock.Eq(uploadDir+"/manifest.json")).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("upload is not found"))
+				Expect(errors.Is(err, goseidon.ErrNotFound)).To(BeTrue())
+			})
+		})
+
+		When("upload belong to another file", func() {
+			It("should return error", func() {
+				p.FileId = "other.mp4"
+				expectManifest(manifest)
+
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrNotFound)).To(BeTrue())
+			})
+		})
+
+		When("success upload part", func() {
+			It("should replace older copy of the part", func() {
+				name := "part-00001-5d41402abc4b2a76b9719d911017c592"
+				expectManifest(manifest)
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(uploadDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Name().Return(uploadDir + "/.goseidon-tmp-1").Times(1)
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(file), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ goio.Writer, src goio.Reader) (int64, error) {
+						return goio.Copy(goio.Discard, src)
+					}).
+					Times(1)
+				file.EXPECT().Sync().Return(nil).Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+				fm.EXPECT().
+					Rename(gomock.Eq(uploadDir+"/.goseidon-tmp-1"), gomock.Eq(uploadDir+"/"+name)).
+					Return(nil).
+					Times(1)
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(uploadDir)).
+					Return([]io.FileEntry{
+						{Path: "manifest.json"},
+						{Path: "part-00001-old"},
+						{Path: name},
+						{Path: "part-00002-other"},
+					}, nil).
+					Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq(uploadDir + "/part-00001-old")).Return(nil).Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadPart(ctx, p)
+
+				eRes := &goseidon.UploadPartResult{
+					PartNumber: 1,
+					ETag:       "5d41402abc4b2a76b9719d911017c592",
+					Size:       5,
+					UploadedAt: currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("ListParts method", func() {
+		When("success list parts", func() {
+			It("should return latest copy of each part", func() {
+				p := goseidon.ListPartsParam{
+					UploadId: uploadId,
+					FileId:   "video.mp4",
+				}
+				expectManifest(manifest)
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(uploadDir)).
+					Return([]io.FileEntry{
+						{Path: ".goseidon-tmp-1"},
+						{Path: "manifest.json"},
+						{Path: "part-00001-aa", Size: 5, ModTime: currentTime},
+						{Path: "part-00001-bb", Size: 6, ModTime: currentTime.Add(time.Second)},
+						{Path: "part-00002-cc", Size: 2, ModTime: currentTime},
+					}, nil).
+					Times(1)
+
+				res, err := s.ListParts(ctx, p)
+
+				eRes := &goseidon.ListPartsResult{
+					Parts: []goseidon.PartItem{
+						{PartNumber: 1, ETag: "bb", Size: 6, LastModified: currentTime.Add(time.Second)},
+						{PartNumber: 2, ETag: "cc", Size: 2, LastModified: currentTime},
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("CompleteMultipartUpload method", func() {
+		var p goseidon.CompleteMultipartParam
+
+		BeforeEach(func() {
+			p = goseidon.CompleteMultipartParam{
+				UploadId: uploadId,
+				FileId:   "video.mp4",
+				Parts: []goseidon.CompletedPart{
+					{PartNumber: 1, ETag: "aa"},
+				},
+			}
+		})
+
+		When("parts are invalid", func() {
+			It("should return error", func() {
+				p.Parts = []goseidon.CompletedPart{{PartNumber: 1}}
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("part 1 has empty etag"))
+			})
+		})
+
+		When("part etag mismatch", func() {
+			It("should return error", func() {
+				expectManifest(manifest)
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(uploadDir)).
+					Return([]io.FileEntry{{Path: "part-00001-bb"}}, nil).
+					Times(1)
+
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("part 1 etag mismatch"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("file already exists", func() {
+			It("should keep the upload", func() {
+				expectManifest(manifest)
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(uploadDir)).
+					Return([]io.FileEntry{{Path: "part-00001-aa"}}, nil).
+					Times(1)
+				fm.EXPECT().IsExists(gomock.Eq("storage")).Return(true).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq("storage/video.mp4")).Return(true).Times(1)
+
+				res, err := s.CompleteMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrAlreadyExists))
+			})
+		})
+
+		Context("parts are joined", func() {
+			var (
+				parts    map[string]*partFile
+				entries  []io.FileEntry
+				written  *bytes.Buffer
+				metaFile *io.MockFile
+			)
+
+			BeforeEach(func() {
+				p.Parts = []goseidon.CompletedPart{
+					{PartNumber: 1, ETag: "aa"},
+					{PartNumber: 2, ETag: "bb"},
+					{PartNumber: 3, ETag: "cc"},
+				}
+				parts = map[string]*partFile{
+					"part-00001-aa": {Reader: strings.NewReader("hello ")},
+					"part-00002-bb": {Reader: strings.NewReader("")},
+					"part-00003-cc": {Reader: strings.NewReader("world")},
+				}
+				// part 4 is uploaded but left out of the completed parts
+				entries = []io.FileEntry{
+					{Path: "manifest.json"},
+					{Path: "part-00001-aa", Size: 6},
+					{Path: "part-00002-bb", Size: 0},
+					{Path: "part-00003-cc", Size: 5},
+					{Path: "part-00004-dd", Size: 3},
+				}
+				written = &bytes.Buffer{}
+				metaFile = io.NewMockFile(gomock.NewController(GinkgoT()))
+
+				expectManifest(manifest)
+				fm.EXPECT().IsExists(gomock.Eq("storage")).Return(true).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq("storage/video.mp4")).Return(false).Times(1)
+				fm.EXPECT().
+					CreateTemp(gomock.Eq("storage"), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Name().Return("storage/.goseidon-tmp-1").Times(1)
+			})
+
+			expectOpenParts := func(names ...string) {
+				for _, name := range names {
+					fm.EXPECT().
+						Open(gomock.Eq(uploadDir+"/"+name)).
+						Return(parts[name], nil).
+						Times(1)
+				}
+			}
+
+			When("every part is read", func() {
+				It("should store the parts in order", func() {
+					fm.EXPECT().
+						WalkFiles(gomock.Eq(uploadDir)).
+						Return(entries, nil).
+						Times(2)
+					expectOpenParts("part-00001-aa", "part-00002-bb", "part-00003-cc")
+					fm.EXPECT().
+						CopyContext(gomock.Eq(ctx), gomock.Eq(file), gomock.Any()).
+						DoAndReturn(func(_ context.Context, _ goio.Writer, src goio.Reader) (int64, error) {
+							return goio.Copy(written, src)
+						}).
+						Times(1)
+					file.EXPECT().Sync().Return(nil).Times(1)
+					file.EXPECT().Close().Return(nil).Times(1)
+
+					fm.EXPECT().
+						CreateTemp(gomock.Eq("storage"), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+						Return(metaFile, nil).
+						Times(1)
+					metaFile.EXPECT().Name().Return("storage/.goseidon-tmp-2").Times(1)
+					fm.EXPECT().
+						CopyContext(gomock.Any(), gomock.Eq(metaFile), gomock.Any()).
+						DoAndReturn(copyAll).
+						Times(1)
+					metaFile.EXPECT().Sync().Return(nil).Times(1)
+					metaFile.EXPECT().Close().Return(nil).Times(1)
+
+					fm.EXPECT().Link(gomock.Eq("storage/.goseidon-tmp-1"), gomock.Eq("storage/video.mp4")).Return(nil).Times(1)
+					fm.EXPECT().RemoveFile(gomock.Eq("storage/.goseidon-tmp-1")).Return(nil).Times(1)
+					fm.EXPECT().Rename(gomock.Eq("storage/.goseidon-tmp-2"), gomock.Eq("storage/.goseidon-meta-video.mp4.json")).Return(nil).Times(1)
+					clo.EXPECT().Now().Return(currentTime)
+
+					for _, entry := range entries {
+						fm.EXPECT().RemoveFile(gomock.Eq(uploadDir + "/" + entry.Path)).Return(nil).Times(1)
+					}
+					fm.EXPECT().RemoveDir(gomock.Eq(uploadDir)).Return(nil).Times(1)
+
+					res, err := s.CompleteMultipartUpload(ctx, p)
+
+					Expect(err).To(BeNil())
+					Expect(res.FileId).To(Equal("video.mp4"))
+					Expect(res.Checksum.MD5).To(Equal("5eb63bbbe01eeed093cb22bb8f5acdc3"))
+					Expect(written.String()).To(Equal("hello world"))
+					for name, part := range parts {
+						Expect(part.closed).To(BeTrue(), name)
+					}
+				})
+			})
+
+			When("failed open a part", func() {
+				It("should close the read parts and keep the upload", func() {
+					fm.EXPECT().
+						WalkFiles(gomock.Eq(uploadDir)).
+						Return(entries, nil).
+						Times(1)
+					expectOpenParts("part-00001-aa")
+					fm.EXPECT().
+						Open(gomock.Eq(uploadDir+"/part-00002-bb")).
+						Return(nil, fs.ErrPermission).
+						Times(1)
+					fm.EXPECT().
+						CopyContext(gomock.Eq(ctx), gomock.Eq(file), gomock.Any()).
+						DoAndReturn(func(_ context.Context, _ goio.Writer, src goio.Reader) (int64, error) {
+							return goio.Copy(written, src)
+						}).
+						Times(1)
+					file.EXPECT().Close().Return(nil).Times(1)
+					fm.EXPECT().RemoveFile(gomock.Eq("storage/.goseidon-tmp-1")).Return(nil).Times(1)
+
+					res, err := s.CompleteMultipartUpload(ctx, p)
+
+					Expect(res).To(BeNil())
+					Expect(err.Error()).To(Equal("failed storing file"))
+					Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+					Expect(written.String()).To(Equal("hello "))
+					Expect(parts["part-00001-aa"].closed).To(BeTrue())
+				})
+			})
+
+			When("copy stop in the middle of a part", func() {
+				It("should close the open part", func() {
+					fm.EXPECT().
+						WalkFiles(gomock.Eq(uploadDir)).
+						Return(entries, nil).
+						Times(1)
+					expectOpenParts("part-00001-aa")
+					fm.EXPECT().
+						CopyContext(gomock.Eq(ctx), gomock.Eq(file), gomock.Any()).
+						DoAndReturn(func(_ context.Context, _ goio.Writer, src goio.Reader) (int64, error) {
+							n, _ := src.Read(make([]byte, 2))
+							return int64(n), context.Canceled
+						}).
+						Times(1)
+					file.EXPECT().Close().Return(nil).Times(1)
+					fm.EXPECT().RemoveFile(gomock.Eq("storage/.goseidon-tmp-1")).Return(nil).Times(1)
+
+					res, err := s.CompleteMultipartUpload(ctx, p)
+
+					Expect(res).To(BeNil())
+					Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+					Expect(parts["part-00001-aa"].closed).To(BeTrue())
+				})
+			})
+		})
+	})
+
+	Context("AbortMultipartUpload method", func() {
+		var p goseidon.AbortMultipartParam
+
+		BeforeEach(func() {
+			p = goseidon.AbortMultipartParam{
+				UploadId: uploadId,
+				FileId:   "video.mp4",
+			}
+		})
+
+		When("failed remove part", func() {
+			It("should return error", func() {
+				expectManifest(manifest)
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(uploadDir)).
+					Return([]io.FileEntry{{Path: "part-00001-aa"}}, nil).
+					Times(1)
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(uploadDir + "/part-00001-aa")).
+					Return(fs.ErrPermission).
+					Times(1)
+
+				res, err := s.AbortMultipartUpload(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.NewError(goseidon.ErrPermission, fmt.Errorf("failed remove upload"))))
+			})
+		})
+
+		When("success abort upload", func() {
+			It("should remove parts before manifest", func() {
+				expectManifest(manifest)
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(uploadDir)).
+					Return([]io.FileEntry{{Path: "manifest.json"}, {Path: "part-00001-aa"}}, nil).
+					Times(1)
+				gomock.InOrder(
+					fm.EXPECT().RemoveFile(gomock.Eq(uploadDir+"/part-00001-aa")).Return(nil),
+					fm.EXPECT().RemoveFile(gomock.Eq(uploadDir+"/manifest.json")).Return(nil),
+					fm.EXPECT().RemoveDir(gomock.Eq(uploadDir)).Return(nil),
+				)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.AbortMultipartUpload(ctx, p)
+
+				Expect(res).To(Equal(&goseidon.AbortMultipartResult{UploadId: uploadId, AbortedAt: currentTime}))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
+
+// partFile is an uploaded part read from memory
+type partFile struct {
+	*strings.Reader
+	closed bool
+}
+
+func (f *partFile) Write(p []byte) (int, error) {
+	return 0, fs.ErrPermission
+}
+
+func (f *partFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *partFile) Sync() error {
+	return nil
+}
+
+func (f *partFile) Name() string {
+	return "part"
+}
+
+func (f *partFile) Stat() (fs.FileInfo, error) {
+	return nil, fs.ErrInvalid
+}
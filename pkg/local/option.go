@@ -2,11 +2,38 @@ package local
 
 import (
 	"fmt"
+	"io/fs"
+	"net/url"
 	"strings"
+	"time"
 )
 
+const (
+	defaultDirMode  = fs.FileMode(0755)
+	defaultFileMode = fs.FileMode(0644)
+)
+
+// LocalConfig hold the local storage setting,
+// zero DirMode and FileMode fallback to 0755 and 0644
 type LocalConfig struct {
 	StorageDir string
+	SyncDir    bool
+	Versioning bool
+	TempMaxAge time.Duration
+	DirMode    fs.FileMode
+	FileMode   fs.FileMode
+	Umask      fs.FileMode
+	Owner      *FileOwner
+
+	SignBaseURL string
+	SignSecret  []byte
+}
+
+// FileOwner is the owner given to stored file and created directory,
+// -1 keep the id of the current process
+type FileOwner struct {
+	Uid int
+	Gid int
 }
 
 type LocalStorageOption interface {
@@ -15,13 +42,17 @@ type LocalStorageOption interface {
 
 type withNormalStorageDir struct {
 	storageDir string
+	lowerCase  bool
 }
 
 func (o *withNormalStorageDir) Apply(c *LocalConfig) error {
 	if o.storageDir == "" {
 		return fmt.Errorf("invalid storage directory")
 	}
-	sDir := strings.ToLower(o.storageDir)
+	sDir := o.storageDir
+	if o.lowerCase {
+		sDir = strings.ToLower(sDir)
+	}
 	sDir = strings.TrimSuffix(sDir, "/")
 	c.StorageDir = sDir
 	return nil
@@ -32,3 +63,170 @@ func WithNormalStorageDir(storageDir string) LocalStorageOption {
 		storageDir: storageDir,
 	}
 }
+
+// WithLowerCaseStorageDir lowercase the storage directory,
+// it's only safe on case insensitive file system
+func WithLowerCaseStorageDir(storageDir string) LocalStorageOption {
+	return &withNormalStorageDir{
+		storageDir: storageDir,
+		lowerCase:  true,
+	}
+}
+
+type withSyncDir struct {
+}
+
+func (o *withSyncDir) Apply(c *LocalConfig) error {
+	c.SyncDir = true
+	return nil
+}
+
+// WithSyncDir fsync the storage directory after every write,
+// so a committed file survive a power loss
+func WithSyncDir() LocalStorageOption {
+	return &withSyncDir{}
+}
+
+type withVersioning struct {
+}
+
+func (o *withVersioning) Apply(c *LocalConfig) error {
+	c.Versioning = true
+	return nil
+}
+
+// WithVersioning keep the replaced and deleted files as noncurrent versions,
+// they're stored under a reserved directory which is never listed as file
+func WithVersioning() LocalStorageOption {
+	return &withVersioning{}
+}
+
+type withTempSweeper struct {
+	maxAge time.Duration
+}
+
+func (o *withTempSweeper) Apply(c *LocalConfig) error {
+	if o.maxAge <= 0 {
+		return fmt.Errorf("invalid temp max age")
+	}
+	c.TempMaxAge = o.maxAge
+	return nil
+}
+
+// WithTempSweeper remove temp files older than maxAge when the storage is created,
+// such file is left behind by a write interrupted by a crash
+func WithTempSweeper(maxAge time.Duration) LocalStorageOption {
+	return &withTempSweeper{
+		maxAge: maxAge,
+	}
+}
+
+type withDirMode struct {
+	mode fs.FileMode
+}
+
+func (o *withDirMode) Apply(c *LocalConfig) error {
+	if o.mode == 0 || o.mode&^fs.ModePerm != 0 {
+		return fmt.Errorf("invalid directory mode")
+	}
+	c.DirMode = o.mode
+	return nil
+}
+
+// WithDirMode set the permission of created directories, default is 0755
+func WithDirMode(mode fs.FileMode) LocalStorageOption {
+	return &withDirMode{
+		mode: mode,
+	}
+}
+
+type withFileMode struct {
+	mode fs.FileMode
+}
+
+func (o *withFileMode) Apply(c *LocalConfig) error {
+	if o.mode == 0 || o.mode&^fs.ModePerm != 0 {
+		return fmt.Errorf("invalid file mode")
+	}
+	c.FileMode = o.mode
+	return nil
+}
+
+// WithFileMode set the permission of stored files, default is 0644
+func WithFileMode(mode fs.FileMode) LocalStorageOption {
+	return &withFileMode{
+		mode: mode,
+	}
+}
+
+type withUmask struct {
+	mask fs.FileMode
+}
+
+func (o *withUmask) Apply(c *LocalConfig) error {
+	if o.mask&^fs.ModePerm != 0 {
+		return fmt.Errorf("invalid umask")
+	}
+	c.Umask = o.mask
+	return nil
+}
+
+// WithUmask clear the mask bits from directory and file mode,
+// modes are set explicitly so the process umask is never applied
+func WithUmask(mask fs.FileMode) LocalStorageOption {
+	return &withUmask{
+		mask: mask,
+	}
+}
+
+type withOwner struct {
+	uid int
+	gid int
+}
+
+func (o *withOwner) Apply(c *LocalConfig) error {
+	if o.uid < -1 || o.gid < -1 {
+		return fmt.Errorf("invalid owner")
+	}
+	c.Owner = &FileOwner{
+		Uid: o.uid,
+		Gid: o.gid,
+	}
+	return nil
+}
+
+// WithOwner change the owner of stored files and created directories,
+// -1 keep the id unchanged, the process needs the privilege to chown
+func WithOwner(uid, gid int) LocalStorageOption {
+	return &withOwner{
+		uid: uid,
+		gid: gid,
+	}
+}
+
+type withURLSigner struct {
+	baseURL string
+	secret  []byte
+}
+
+func (o *withURLSigner) Apply(c *LocalConfig) error {
+	u, err := url.Parse(o.baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.RawQuery != "" {
+		return fmt.Errorf("invalid sign base url")
+	}
+	if len(o.secret) < minSignSecretLength {
+		return fmt.Errorf("invalid sign secret")
+	}
+	c.SignBaseURL = strings.TrimSuffix(o.baseURL, "/")
+	c.SignSecret = o.secret
+	return nil
+}
+
+// WithURLSigner enable signed url, baseURL is where SignedURLHandler is mounted
+// and secret is the hmac key which must be kept private and at least 32 bytes
+func WithURLSigner(baseURL string, secret []byte) LocalStorageOption {
+	return &withURLSigner{
+		baseURL: baseURL,
+		secret:  secret,
+	}
+}
@@ -2,6 +2,9 @@ package local_test
 
 import (
 	"fmt"
+	"io/fs"
+	"strings"
+	"time"
 
 	"github.com/go-seidon/core/pkg/local"
 	. "github.com/onsi/ginkgo/v2"
@@ -31,13 +34,13 @@ var _ = Describe("Storage Option", func() {
 		})
 
 		When("storage directory contain capital word", func() {
-			It("should be lowercased", func() {
+			It("should be kept", func() {
 				cfg := &local.LocalConfig{}
-				opt := local.WithNormalStorageDir("STORAGE/Sub-Dir")
+				opt := local.WithNormalStorageDir("/mnt/Data/Sub-Dir")
 				err := opt.Apply(cfg)
 
 				Expect(err).To(BeNil())
-				Expect(cfg.StorageDir).To(Equal("storage/sub-dir"))
+				Expect(cfg.StorageDir).To(Equal("/mnt/Data/Sub-Dir"))
 			})
 		})
 
@@ -47,9 +50,213 @@ var _ = Describe("Storage Option", func() {
 				opt := local.WithNormalStorageDir("STORAGE/Sub-Dir/")
 				err := opt.Apply(cfg)
 
+				Expect(err).To(BeNil())
+				Expect(cfg.StorageDir).To(Equal("STORAGE/Sub-Dir"))
+			})
+		})
+	})
+
+	Context("With lower case storage dir option", func() {
+		When("storage directory is invalid", func() {
+			It("should return error", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithLowerCaseStorageDir("")
+				err := opt.Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid storage directory")))
+			})
+		})
+
+		When("storage directory contain capital word", func() {
+			It("should be lowercased", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithLowerCaseStorageDir("STORAGE/Sub-Dir/")
+				err := opt.Apply(cfg)
+
 				Expect(err).To(BeNil())
 				Expect(cfg.StorageDir).To(Equal("storage/sub-dir"))
 			})
 		})
 	})
+
+	Context("With sync dir option", func() {
+		When("option is applied", func() {
+			It("should enable directory sync", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithSyncDir()
+				err := opt.Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.SyncDir).To(BeTrue())
+			})
+		})
+	})
+
+	Context("With versioning option", func() {
+		When("option is applied", func() {
+			It("should enable versioning", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithVersioning()
+				err := opt.Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.Versioning).To(BeTrue())
+			})
+		})
+	})
+
+	Context("With temp sweeper option", func() {
+		When("max age is invalid", func() {
+			It("should return error", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithTempSweeper(0)
+				err := opt.Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid temp max age")))
+			})
+		})
+
+		When("max age is valid", func() {
+			It("should set max age", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithTempSweeper(time.Hour)
+				err := opt.Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.TempMaxAge).To(Equal(time.Hour))
+			})
+		})
+	})
+
+	Context("With dir mode option", func() {
+		When("mode is invalid", func() {
+			It("should return error", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithDirMode(fs.ModeDir | 0755)
+				err := opt.Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid directory mode")))
+			})
+		})
+
+		When("mode is valid", func() {
+			It("should set dir mode", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithDirMode(0700)
+				err := opt.Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.DirMode).To(Equal(fs.FileMode(0700)))
+			})
+		})
+	})
+
+	Context("With file mode option", func() {
+		When("mode is invalid", func() {
+			It("should return error", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithFileMode(0)
+				err := opt.Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid file mode")))
+			})
+		})
+
+		When("mode is valid", func() {
+			It("should set file mode", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithFileMode(0600)
+				err := opt.Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.FileMode).To(Equal(fs.FileMode(0600)))
+			})
+		})
+	})
+
+	Context("With umask option", func() {
+		When("mask is invalid", func() {
+			It("should return error", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithUmask(fs.ModeSetuid)
+				err := opt.Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid umask")))
+			})
+		})
+
+		When("mask is valid", func() {
+			It("should set umask", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithUmask(0027)
+				err := opt.Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.Umask).To(Equal(fs.FileMode(0027)))
+			})
+		})
+	})
+
+	Context("With owner option", func() {
+		When("owner is invalid", func() {
+			It("should return error", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithOwner(-2, 1000)
+				err := opt.Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid owner")))
+			})
+		})
+
+		When("owner is valid", func() {
+			It("should set owner", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithOwner(1000, -1)
+				err := opt.Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.Owner).To(Equal(&local.FileOwner{Uid: 1000, Gid: -1}))
+			})
+		})
+	})
+
+	Context("With url signer option", func() {
+		var secret []byte
+
+		BeforeEach(func() {
+			secret = []byte(strings.Repeat("s", 32))
+		})
+
+		When("base url is invalid", func() {
+			It("should return error", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithURLSigner("/files", secret)
+				err := opt.Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid sign base url")))
+			})
+		})
+
+		When("secret is too short", func() {
+			It("should return error", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithURLSigner("https://cdn.example.com/files", []byte("secret"))
+				err := opt.Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid sign secret")))
+			})
+		})
+
+		When("option is valid", func() {
+			It("should set url signer", func() {
+				cfg := &local.LocalConfig{}
+				opt := local.WithURLSigner("https://cdn.example.com/files/", secret)
+				err := opt.Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.SignBaseURL).To(Equal("https://cdn.example.com/files"))
+				Expect(cfg.SignSecret).To(Equal(secret))
+			})
+		})
+	})
 })
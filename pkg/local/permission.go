@@ -0,0 +1,58 @@
+package local
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+func (s *LocalStorage) dirMode() fs.FileMode {
+	mode := s.Config.DirMode
+	if mode == 0 {
+		mode = defaultDirMode
+	}
+	return mode &^ s.Config.Umask
+}
+
+func (s *LocalStorage) fileMode() fs.FileMode {
+	mode := s.Config.FileMode
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+	return mode &^ s.Config.Umask
+}
+
+func (s *LocalStorage) chown(path string) error {
+	if s.Config.Owner == nil {
+		return nil
+	}
+	return s.Client.Chown(path, s.Config.Owner.Uid, s.Config.Owner.Gid)
+}
+
+// createDir create dir and its missing parents one by one,
+// each created directory is chmod-ed so the process umask doesn't interfere
+func (s *LocalStorage) createDir(dir string) error {
+	missing := []string{}
+	for d := dir; !s.Client.IsExists(d); d = filepath.Dir(d) {
+		missing = append(missing, d)
+		if d == filepath.Dir(d) {
+			break
+		}
+	}
+
+	mode := s.dirMode()
+	for i := len(missing) - 1; i >= 0; i-- {
+		err := s.Client.CreateDir(missing[i], mode)
+		if err != nil {
+			return err
+		}
+		err = s.Client.Chmod(missing[i], mode)
+		if err != nil {
+			return err
+		}
+		err = s.chown(missing[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
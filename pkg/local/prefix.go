@@ -0,0 +1,13 @@
+package local
+
+import (
+	"context"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// DeletePrefix walk the storage directory once per page,
+// parent directories emptied by the removal are pruned along the way
+func (s *LocalStorage) DeletePrefix(ctx context.Context, p goseidon.DeletePrefixParam) (*goseidon.DeletePrefixResult, error) {
+	return goseidon.DeleteByPrefix(ctx, s, p)
+}
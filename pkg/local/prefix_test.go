@@ -0,0 +1,125 @@
+package local_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/internal/io"
+	"github.com/go-seidon/core/pkg/local"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Prefix", func() {
+	var (
+		ctx         context.Context
+		s           *local.LocalStorage
+		cfg         *local.LocalConfig
+		clo         *clock.MockClock
+		fm          *io.MockFileManager
+		currentTime time.Time
+		entries     []io.FileEntry
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cfg = &local.LocalConfig{
+			StorageDir: "storage",
+		}
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		fm = io.NewMockFileManager(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Now()
+		s = &local.LocalStorage{
+			Config: cfg,
+			Client: fm,
+			Clock:  clo,
+		}
+		entries = []io.FileEntry{
+			{Path: "tenant-1/.goseidon-meta-a.jpg.json"},
+			{Path: "tenant-1/a.jpg"},
+			{Path: "tenant-1/b.jpg"},
+			{Path: "tenant-2/c.jpg"},
+		}
+	})
+
+	Context("DeletePrefix method", func() {
+		When("prefix is empty", func() {
+			It("should return error", func() {
+				res, err := s.DeletePrefix(ctx, goseidon.DeletePrefixParam{})
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("failed walk storage dir", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(cfg.StorageDir)).
+					Return(nil, fs.ErrPermission).
+					Times(1)
+
+				res, err := s.DeletePrefix(ctx, goseidon.DeletePrefixParam{Prefix: "tenant-1/"})
+
+				Expect(res.Matched).To(Equal(0))
+				Expect(err.Error()).To(Equal("failed list files"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("dry run", func() {
+			It("should count the files without deleting them", func() {
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(cfg.StorageDir)).
+					Return(entries, nil).
+					Times(1)
+
+				res, err := s.DeletePrefix(ctx, goseidon.DeletePrefixParam{Prefix: "tenant-1/", DryRun: true})
+
+				eRes := &goseidon.DeletePrefixResult{
+					Prefix:  "tenant-1/",
+					Matched: 2,
+					Failed:  []goseidon.DeleteFileItem{},
+					DryRun:  true,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("a file fail to be deleted", func() {
+			It("should delete the rest and report the failure", func() {
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(cfg.StorageDir)).
+					Return(entries, nil).
+					Times(1)
+
+				fm.EXPECT().Stat(gomock.Eq("storage/tenant-1/a.jpg")).Return(&fileInfo{size: 1}, nil).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq("storage/tenant-1/a.jpg")).Return(nil).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq("storage/tenant-1/.goseidon-meta-a.jpg.json")).Return(nil).Times(1)
+				fm.EXPECT().RemoveDir(gomock.Eq("storage/tenant-1")).Return(fs.ErrExist).Times(1)
+
+				fm.EXPECT().Stat(gomock.Eq("storage/tenant-1/b.jpg")).Return(&fileInfo{size: 1}, nil).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq("storage/tenant-1/b.jpg")).Return(fs.ErrPermission).Times(1)
+
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.DeletePrefix(ctx, goseidon.DeletePrefixParam{Prefix: "tenant-1/"})
+
+				Expect(err).To(BeNil())
+				Expect(res.Matched).To(Equal(2))
+				Expect(res.Deleted).To(Equal(1))
+				Expect(res.Failed).To(HaveLen(1))
+				Expect(res.Failed[0].Id).To(Equal("tenant-1/b.jpg"))
+				Expect(errors.Is(res.Failed[0].Error, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+	})
+})
@@ -0,0 +1,59 @@
+package local
+
+import (
+	"fmt"
+	"io"
+
+	goseidon "github.com/go-seidon/core"
+	goseidon_io "github.com/go-seidon/core/internal/io"
+)
+
+type rangeFile struct {
+	file   io.ReadCloser
+	size   int64
+	length int64
+}
+
+// openRange seek the opened file to offset and limit it to the range length,
+// the file is returned as it is when the whole file is read
+func openRange(file goseidon_io.File, offset, length int64) (*rangeFile, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed stat file"))
+	}
+	// a directory isn't a file of the storage
+	if info.IsDir() {
+		return nil, goseidon.ErrNotFound
+	}
+	size := info.Size()
+
+	length, err = goseidon.ResolveRange(offset, length, size)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset > 0 {
+		_, err = file.Seek(offset, io.SeekStart)
+		if err != nil {
+			return nil, wrapError(err, fmt.Errorf("failed seek file"))
+		}
+	}
+
+	res := &rangeFile{
+		file:   file,
+		size:   size,
+		length: length,
+	}
+	if length < size-offset {
+		res.file = &limitedFile{
+			Reader: io.LimitReader(file, length),
+			Closer: file,
+		}
+	}
+	return res, nil
+}
+
+type limitedFile struct {
+	io.Reader
+	io.Closer
+}
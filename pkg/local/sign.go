@@ -0,0 +1,239 @@
+package local
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+const (
+	minSignSecretLength = 32
+
+	signExpiresQuery     = "expires"
+	signContentTypeQuery = "content_type"
+	signSignatureQuery   = "signature"
+)
+
+func (s *LocalStorage) SignURL(ctx context.Context, p goseidon.SignURLParam) (*goseidon.SignURLResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := goseidon.ValidateSignURL(p)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Config.SignSecret) == 0 {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("url signer is not configured"))
+	}
+
+	expiresAt := s.Clock.Now().Add(p.Expiry).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	q := url.Values{}
+	q.Set(signExpiresQuery, expires)
+	if p.ContentType != "" {
+		q.Set(signContentTypeQuery, p.ContentType)
+	}
+	q.Set(signSignatureQuery, s.signature(p.Method, p.Id, expires, p.ContentType))
+
+	headers := map[string]string{}
+	if p.ContentType != "" {
+		headers["Content-Type"] = p.ContentType
+	}
+	res := &goseidon.SignURLResult{
+		URL:       s.Config.SignBaseURL + "/" + escapeKey(p.Id) + "?" + q.Encode(),
+		Method:    p.Method,
+		Headers:   headers,
+		ExpiresAt: expiresAt,
+	}
+	return res, nil
+}
+
+// signature is the hex encoded hmac-sha256 of every signed field,
+// fields are newline separated since none of them may contain one
+func (s *LocalStorage) signature(method, id, expires, contentType string) string {
+	mac := hmac.New(sha256.New, s.Config.SignSecret)
+	mac.Write([]byte(method + "\n" + id + "\n" + expires + "\n" + contentType))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func escapeKey(id string) string {
+	segments := strings.Split(id, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// SignedURLHandler serve url created by SignURL, it must be mounted
+// at the path of the sign base url with that path left in the request url
+func (s *LocalStorage) SignedURLHandler() http.Handler {
+	prefix := "/"
+	u, err := url.Parse(s.Config.SignBaseURL)
+	if err == nil {
+		prefix = strings.TrimSuffix(u.Path, "/") + "/"
+	}
+	return &signedURLHandler{
+		storage: s,
+		prefix:  prefix,
+	}
+}
+
+type signedURLHandler struct {
+	storage *LocalStorage
+	prefix  string
+}
+
+func (h *signedURLHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut {
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, h.prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, h.prefix)
+
+	q := r.URL.Query()
+	expires := q.Get(signExpiresQuery)
+	contentType := q.Get(signContentTypeQuery)
+	signature, err := hex.DecodeString(q.Get(signSignatureQuery))
+	if err != nil || len(h.storage.Config.SignSecret) == 0 {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+	expected, _ := hex.DecodeString(h.storage.signature(r.Method, id, expires, contentType))
+	if !hmac.Equal(signature, expected) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || h.storage.Clock.Now().Unix() > expiresAt {
+		http.Error(w, "url is expired", http.StatusForbidden)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		h.retrieve(w, r, id)
+		return
+	}
+	if contentType != "" && r.Header.Get("Content-Type") != contentType {
+		http.Error(w, "content type mismatch", http.StatusForbidden)
+		return
+	}
+	h.upload(w, r, id)
+}
+
+// retrieve serve the file, a single "bytes=start-end" or "bytes=start-" range
+// is answered with partial content while any other range is ignored
+func (h *signedURLHandler) retrieve(w http.ResponseWriter, r *http.Request, id string) {
+	offset, length, ranged := parseRange(r.Header.Get("Range"))
+	res, err := h.storage.RetrieveStream(r.Context(), goseidon.RetrieveFileParam{
+		Id:     id,
+		Offset: offset,
+		Length: length,
+	})
+	if ranged && errors.Is(err, goseidon.ErrInvalidArgument) {
+		http.Error(w, "range is not satisfiable", http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer res.File.Close()
+
+	if ranged && res.Length == 0 {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", res.Size))
+		http.Error(w, "range is not satisfiable", http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+
+	if res.ContentType != "" {
+		w.Header().Set("Content-Type", res.ContentType)
+	}
+	if res.ContentDisposition != "" {
+		w.Header().Set("Content-Disposition", res.ContentDisposition)
+	}
+	if res.CacheControl != "" {
+		w.Header().Set("Cache-Control", res.CacheControl)
+	}
+	w.Header().Set("Accept-Ranges", "bytes")
+	w.Header().Set("Content-Length", strconv.FormatInt(res.Length, 10))
+	if ranged {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", res.Offset, res.Offset+res.Length-1, res.Size))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	io.Copy(w, res.File)
+}
+
+// parseRange parse a single byte range into offset and length,
+// ranged is false for an absent, suffix, multiple or malformed range
+func parseRange(header string) (offset, length int64, ranged bool) {
+	spec := strings.TrimPrefix(header, "bytes=")
+	if spec == header || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	idx := strings.Index(spec, "-")
+	if idx <= 0 {
+		return 0, 0, false
+	}
+
+	start, err := strconv.ParseInt(spec[:idx], 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false
+	}
+	if spec[idx+1:] == "" {
+		return start, 0, true
+	}
+	end, err := strconv.ParseInt(spec[idx+1:], 10, 64)
+	if err != nil || end < start {
+		return 0, 0, false
+	}
+	return start, end - start + 1, true
+}
+
+// upload replace the existing file, the same as a presigned put in s3
+func (h *signedURLHandler) upload(w http.ResponseWriter, r *http.Request, id string) {
+	_, err := h.storage.UploadStream(r.Context(), goseidon.UploadStreamParam{
+		FileData:    r.Body,
+		FileId:      id,
+		FileSize:    r.ContentLength,
+		Overwrite:   goseidon.OverwriteReplace,
+		ContentType: r.Header.Get("Content-Type"),
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, goseidon.ErrNotFound):
+		http.Error(w, "file is not found", http.StatusNotFound)
+	case errors.Is(err, goseidon.ErrInvalidArgument):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, goseidon.ErrPermission):
+		http.Error(w, "permission denied", http.StatusForbidden)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
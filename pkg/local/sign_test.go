@@ -0,0 +1,315 @@
+package local_test
+
+import (
+	"context"
+	"errors"
+	goio "io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/internal/io"
+	"github.com/go-seidon/core/pkg/local"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sign", func() {
+	var (
+		ctx         context.Context
+		s           *local.LocalStorage
+		cfg         *local.LocalConfig
+		clo         *clock.MockClock
+		fm          *io.MockFileManager
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cfg = &local.LocalConfig{
+			StorageDir:  "storage",
+			SignBaseURL: "https://cdn.example.com/files",
+			SignSecret:  []byte(strings.Repeat("s", 32)),
+		}
+		t := GinkgoT()
+		ctrl := gomock.NewController(t)
+		fm = io.NewMockFileManager(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Unix(1650000000, 0)
+		s = &local.LocalStorage{
+			Config: cfg,
+			Client: fm,
+			Clock:  clo,
+		}
+	})
+
+	Context("SignURL method", func() {
+		var p goseidon.SignURLParam
+
+		BeforeEach(func() {
+			p = goseidon.SignURLParam{
+				Id:     "tenant-1/ikan paus.jpg",
+				Method: http.MethodGet,
+				Expiry: time.Minute,
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.SignURL(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("param is invalid", func() {
+			It("should return error", func() {
+				p.ContentType = "image/jpeg"
+
+				res, err := s.SignURL(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("content type is only allowed for PUT"))
+			})
+		})
+
+		When("url signer is not configured", func() {
+			It("should return error", func() {
+				cfg.SignSecret = nil
+
+				res, err := s.SignURL(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("url signer is not configured"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("success sign url", func() {
+			It("should return result", func() {
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.SignURL(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.URL).To(HavePrefix("https://cdn.example.com/files/tenant-1/ikan%20paus.jpg?expires=1650000060&signature="))
+				Expect(res.Method).To(Equal(http.MethodGet))
+				Expect(res.Headers).To(BeEmpty())
+				Expect(res.ExpiresAt).To(Equal(currentTime.Add(time.Minute)))
+			})
+		})
+
+		When("content type is given", func() {
+			It("should bind content type", func() {
+				p.Method = http.MethodPut
+				p.ContentType = "image/jpeg"
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.SignURL(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.URL).To(ContainSubstring("content_type=image%2Fjpeg"))
+				Expect(res.Headers).To(Equal(map[string]string{"Content-Type": "image/jpeg"}))
+			})
+		})
+	})
+
+	Context("SignedURLHandler method", func() {
+		var (
+			h   http.Handler
+			rec *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			h = s.SignedURLHandler()
+			rec = httptest.NewRecorder()
+		})
+
+		sign := func(method, contentType string) string {
+			clo.EXPECT().Now().Return(currentTime)
+			res, err := s.SignURL(ctx, goseidon.SignURLParam{
+				Id:          "tenant-1/image.jpg",
+				Method:      method,
+				Expiry:      time.Minute,
+				ContentType: contentType,
+			})
+			Expect(err).To(BeNil())
+			return res.URL
+		}
+
+		When("method is not allowed", func() {
+			It("should return 405", func() {
+				req := httptest.NewRequest(http.MethodDelete, "https://cdn.example.com/files/tenant-1/image.jpg", nil)
+				h.ServeHTTP(rec, req)
+
+				Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+			})
+		})
+
+		When("signature is missing", func() {
+			It("should return 403", func() {
+				req := httptest.NewRequest(http.MethodGet, "https://cdn.example.com/files/tenant-1/image.jpg", nil)
+				h.ServeHTTP(rec, req)
+
+				Expect(rec.Code).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		When("file id is tampered", func() {
+			It("should return 403", func() {
+				url := strings.Replace(sign(http.MethodGet, ""), "image.jpg", "other.jpg", 1)
+				req := httptest.NewRequest(http.MethodGet, url, nil)
+				h.ServeHTTP(rec, req)
+
+				Expect(rec.Code).To(Equal(http.StatusForbidden))
+				Expect(rec.Body.String()).To(Equal("invalid signature\n"))
+			})
+		})
+
+		When("method differ from the signed one", func() {
+			It("should return 403", func() {
+				req := httptest.NewRequest(http.MethodPut, sign(http.MethodGet, ""), strings.NewReader("data"))
+				h.ServeHTTP(rec, req)
+
+				Expect(rec.Code).To(Equal(http.StatusForbidden))
+				Expect(rec.Body.String()).To(Equal("invalid signature\n"))
+			})
+		})
+
+		When("url is expired", func() {
+			It("should return 403", func() {
+				url := sign(http.MethodGet, "")
+				clo.EXPECT().Now().Return(currentTime.Add(time.Minute + time.Second))
+				req := httptest.NewRequest(http.MethodGet, url, nil)
+				h.ServeHTTP(rec, req)
+
+				Expect(rec.Code).To(Equal(http.StatusForbidden))
+				Expect(rec.Body.String()).To(Equal("url is expired\n"))
+			})
+		})
+
+		When("content type mismatch", func() {
+			It("should return 403", func() {
+				url := sign(http.MethodPut, "image/jpeg")
+				clo.EXPECT().Now().Return(currentTime)
+				req := httptest.NewRequest(http.MethodPut, url, strings.NewReader("data"))
+				req.Header.Set("Content-Type", "text/html")
+				h.ServeHTTP(rec, req)
+
+				Expect(rec.Code).To(Equal(http.StatusForbidden))
+				Expect(rec.Body.String()).To(Equal("content type mismatch\n"))
+			})
+		})
+
+		When("file is not available", func() {
+			It("should return 404", func() {
+				url := sign(http.MethodGet, "")
+				clo.EXPECT().Now().Return(currentTime)
+				fm.EXPECT().
+					IsExists(gomock.Eq("storage/tenant-1/image.jpg")).
+					Return(false).
+					Times(1)
+				req := httptest.NewRequest(http.MethodGet, url, nil)
+				h.ServeHTTP(rec, req)
+
+				Expect(rec.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		When("success serve file", func() {
+			It("should write file content", func() {
+				url := sign(http.MethodGet, "")
+				file := io.NewMockFile(gomock.NewController(GinkgoT()))
+				clo.EXPECT().Now().Return(currentTime).Times(2)
+				fm.EXPECT().
+					IsExists(gomock.Eq("storage/tenant-1/image.jpg")).
+					Return(true).
+					Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq("storage/tenant-1/.goseidon-meta-image.jpg.json")).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq("storage/tenant-1/image.jpg")).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Stat().Return(&fileInfo{size: 5}, nil).Times(1)
+				file.EXPECT().
+					Read(gomock.Any()).
+					DoAndReturn(func(b []byte) (int, error) {
+						return copy(b, "image"), goio.EOF
+					}).
+					Times(1)
+				file.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+				req := httptest.NewRequest(http.MethodGet, url, nil)
+				h.ServeHTTP(rec, req)
+
+				Expect(rec.Code).To(Equal(http.StatusOK))
+				Expect(rec.Header().Get("Content-Type")).To(Equal("image/jpeg"))
+				Expect(rec.Header().Get("Content-Length")).To(Equal("5"))
+				Expect(rec.Body.String()).To(Equal("image"))
+			})
+		})
+
+		When("range is requested", func() {
+			It("should write partial content", func() {
+				url := sign(http.MethodGet, "")
+				file := io.NewMockFile(gomock.NewController(GinkgoT()))
+				clo.EXPECT().Now().Return(currentTime).Times(2)
+				fm.EXPECT().IsExists(gomock.Eq("storage/tenant-1/image.jpg")).Return(true).Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq("storage/tenant-1/.goseidon-meta-image.jpg.json")).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+				fm.EXPECT().Open(gomock.Eq("storage/tenant-1/image.jpg")).Return(file, nil).Times(1)
+				file.EXPECT().Stat().Return(&fileInfo{size: 10}, nil).Times(1)
+				file.EXPECT().Seek(gomock.Eq(int64(2)), gomock.Eq(0)).Return(int64(2), nil).Times(1)
+				file.EXPECT().
+					Read(gomock.Any()).
+					DoAndReturn(func(b []byte) (int, error) {
+						return copy(b, "age"), goio.EOF
+					}).
+					Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+				req := httptest.NewRequest(http.MethodGet, url, nil)
+				req.Header.Set("Range", "bytes=2-4")
+				h.ServeHTTP(rec, req)
+
+				Expect(rec.Code).To(Equal(http.StatusPartialContent))
+				Expect(rec.Header().Get("Content-Range")).To(Equal("bytes 2-4/10"))
+				Expect(rec.Header().Get("Content-Length")).To(Equal("3"))
+				Expect(rec.Body.String()).To(Equal("age"))
+			})
+		})
+
+		When("range is not satisfiable", func() {
+			It("should return 416", func() {
+				url := sign(http.MethodGet, "")
+				file := io.NewMockFile(gomock.NewController(GinkgoT()))
+				clo.EXPECT().Now().Return(currentTime)
+				fm.EXPECT().IsExists(gomock.Eq("storage/tenant-1/image.jpg")).Return(true).Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq("storage/tenant-1/.goseidon-meta-image.jpg.json")).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+				fm.EXPECT().Open(gomock.Eq("storage/tenant-1/image.jpg")).Return(file, nil).Times(1)
+				file.EXPECT().Stat().Return(&fileInfo{size: 10}, nil).Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+				req := httptest.NewRequest(http.MethodGet, url, nil)
+				req.Header.Set("Range", "bytes=20-")
+				h.ServeHTTP(rec, req)
+
+				Expect(rec.Code).To(Equal(http.StatusRequestedRangeNotSatisfiable))
+			})
+		})
+	})
+})
@@ -1,15 +1,21 @@
 package local
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
+	"path/filepath"
+	"strings"
 
 	goseidon "github.com/go-seidon/core"
 	"github.com/go-seidon/core/internal/clock"
 	"github.com/go-seidon/core/internal/io"
 )
 
+const defaultPageSize = 1000
+
 type LocalStorage struct {
 	Config *LocalConfig
 	Client io.FileManager
@@ -17,27 +23,191 @@ type LocalStorage struct {
 }
 
 func (s *LocalStorage) UploadFile(ctx context.Context, p goseidon.UploadFileParam) (*goseidon.UploadFileResult, error) {
+	return s.UploadStream(ctx, goseidon.UploadStreamParam{
+		FileData:  bytes.NewReader(p.FileData),
+		FileId:    p.FileId,
+		FileName:  p.FileName,
+		FileSize:  p.FileSize,
+		Overwrite: p.Overwrite,
+		Checksum:  p.Checksum,
+
+		ContentType:        p.ContentType,
+		ContentDisposition: p.ContentDisposition,
+		CacheControl:       p.CacheControl,
+		Metadata:           p.Metadata,
+	})
+}
+
+func (s *LocalStorage) UploadStream(ctx context.Context, p goseidon.UploadStreamParam) (*goseidon.UploadFileResult, error) {
 	if ctx == nil {
-		return nil, fmt.Errorf("invalid context")
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	if p.FileData == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid file data"))
+	}
+
+	path, err := s.filePath(p.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	// nested file id is stored under its own parent directories
+	dir := filepath.Dir(path)
+	err = s.createDir(dir)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed create storage dir: %s", dir))
+	}
+
+	replace := p.Overwrite == goseidon.OverwriteReplace
+	if !replace && s.Client.IsExists(path) {
+		return s.uploadConflict(p)
 	}
 
-	rwPermission := fs.FileMode(0644)
+	// data is written aside and moved into place once complete,
+	// so a crash never leaves a truncated file behind the path
+	hashed := goseidon.NewChecksumReader(p.FileData)
+	tmpPath, err := s.writeTemp(ctx, dir, hashed)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed storing file"))
+	}
+	// nothing is visible yet, a cancelled upload is dropped as a whole
+	err = ctx.Err()
+	if err != nil {
+		s.Client.RemoveFile(tmpPath)
+		return nil, wrapError(err, fmt.Errorf("failed storing file"))
+	}
+
+	checksum := hashed.Checksum()
+	err = goseidon.CompareChecksum(p.Checksum, checksum)
+	if err != nil {
+		s.Client.RemoveFile(tmpPath)
+		return nil, err
+	}
 
-	if !s.Client.IsExists(s.Config.StorageDir) {
-		err := s.Client.CreateDir(s.Config.StorageDir, rwPermission)
+	versionId := ""
+	if s.Config.Versioning {
+		versionId, err = newVersionId(s.Clock.Now())
 		if err != nil {
-			return nil, fmt.Errorf("failed create storage dir: %s", s.Config.StorageDir)
+			s.Client.RemoveFile(tmpPath)
+			return nil, wrapError(err, fmt.Errorf("failed archive file"))
 		}
 	}
 
-	path := fmt.Sprintf("%s/%s", s.Config.StorageDir, p.FileId)
-	if s.Client.IsExists(path) {
-		return nil, fmt.Errorf("file already exists")
+	// the sidecar is written aside as well, nothing is touched until both are complete
+	meta := fileMeta{
+		FileName:           p.FileName,
+		ContentType:        p.ContentType,
+		ContentDisposition: p.ContentDisposition,
+		CacheControl:       p.CacheControl,
+		Metadata:           p.Metadata,
+
+		MD5:    checksum.MD5,
+		CRC32C: checksum.CRC32C,
+		SHA256: checksum.SHA256,
+
+		VersionId: versionId,
+	}
+	metaTmpPath, err := s.writeMetaTemp(path, meta)
+	if err != nil {
+		s.Client.RemoveFile(tmpPath)
+		return nil, wrapError(err, fmt.Errorf("failed storing file metadata"))
+	}
+
+	// the replaced file is archived before it's clobbered
+	if s.Config.Versioning && replace && s.Client.IsExists(path) {
+		_, err = s.archive(p.FileId, path)
+		if err != nil {
+			s.Client.RemoveFile(tmpPath)
+			s.Client.RemoveFile(metaTmpPath)
+			return nil, wrapError(err, fmt.Errorf("failed archive file"))
+		}
+	}
+
+	// the replaced sidecar is dropped first, an interrupted replace leaves
+	// the file without metadata rather than paired with the one of another content
+	if replace {
+		err = s.Client.RemoveFile(metaPath(path))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			s.Client.RemoveFile(tmpPath)
+			s.Client.RemoveFile(metaTmpPath)
+			return nil, wrapError(err, fmt.Errorf("failed storing file metadata"))
+		}
 	}
 
-	err := s.Client.WriteFile(path, p.FileData, rwPermission)
+	err = s.commit(tmpPath, path, replace)
 	if err != nil {
-		return nil, fmt.Errorf("failed storing file")
+		s.Client.RemoveFile(metaTmpPath)
+	}
+	if errors.Is(err, fs.ErrExist) {
+		return s.uploadConflict(p)
+	}
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed storing file"))
+	}
+
+	err = s.commit(metaTmpPath, metaPath(path), true)
+	if err != nil {
+		// a new file is withdrawn, a replacing one is kept since the previous content is gone
+		if !replace {
+			s.Client.RemoveFile(path)
+		}
+		return nil, wrapError(err, fmt.Errorf("failed storing file metadata"))
+	}
+
+	if s.Config.SyncDir {
+		err = s.Client.SyncDir(dir)
+		if err != nil {
+			return nil, wrapError(err, fmt.Errorf("failed sync storage dir"))
+		}
+	}
+
+	uploadedAt := s.Clock.Now()
+	res := &goseidon.UploadFileResult{
+		FileId:     p.FileId,
+		FileName:   p.FileName,
+		UploadedAt: uploadedAt,
+		Checksum:   checksum,
+		VersionId:  versionId,
+	}
+	return res, nil
+}
+
+// filePath resolve a validated file id into a path inside the storage directory
+func (s *LocalStorage) filePath(id string) (string, error) {
+	err := goseidon.ValidateKey(id)
+	if err != nil {
+		return "", err
+	}
+	return s.Config.StorageDir + "/" + id, nil
+}
+
+// isFile report whether a file is at path, a directory isn't a file of the storage
+func (s *LocalStorage) isFile(path string) (bool, error) {
+	info, err := s.Client.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// pruneDirs remove the parent directories of a nested file id once they're empty,
+// it stops at the first non empty one and never remove the storage directory
+func (s *LocalStorage) pruneDirs(id string) {
+	for dir := filepath.Dir(id); dir != "."; dir = filepath.Dir(dir) {
+		err := s.Client.RemoveDir(s.Config.StorageDir + "/" + dir)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// uploadConflict resolve an upload to an already taken path
+func (s *LocalStorage) uploadConflict(p goseidon.UploadStreamParam) (*goseidon.UploadFileResult, error) {
+	if p.Overwrite != goseidon.OverwriteSkip {
+		return nil, goseidon.ErrAlreadyExists
 	}
 
 	uploadedAt := s.Clock.Now()
@@ -45,72 +215,286 @@ func (s *LocalStorage) UploadFile(ctx context.Context, p goseidon.UploadFilePara
 		FileId:     p.FileId,
 		FileName:   p.FileName,
 		UploadedAt: uploadedAt,
+		Skipped:    true,
 	}
 	return res, nil
 }
 
 func (s *LocalStorage) RetrieveFile(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+	stream, err := s.RetrieveStream(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.File.Close()
+
+	binFile, err := s.Client.ReadFileContext(ctx, stream.File)
+	if err != nil {
+		return nil, wrapError(err, err)
+	}
+
+	res := &goseidon.RetrieveFileResult{
+		File:        binFile,
+		RetrievedAt: stream.RetrievedAt,
+		Size:        stream.Size,
+		Offset:      stream.Offset,
+		Length:      stream.Length,
+		Checksum:    stream.Checksum,
+		VersionId:   stream.VersionId,
+
+		FileName:           stream.FileName,
+		ContentType:        stream.ContentType,
+		ContentDisposition: stream.ContentDisposition,
+		CacheControl:       stream.CacheControl,
+		Metadata:           stream.Metadata,
+	}
+	return res, nil
+}
+
+func (s *LocalStorage) RetrieveStream(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveStreamResult, error) {
 	if ctx == nil {
-		return nil, fmt.Errorf("invalid context")
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
 	}
 
-	path := fmt.Sprintf("%s/%s", s.Config.StorageDir, p.Id)
+	path, err := s.filePath(p.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = goseidon.ValidateRange(p.Offset, p.Length)
+	if err != nil {
+		return nil, err
+	}
+	err = goseidon.ValidateVerify(p)
+	if err != nil {
+		return nil, err
+	}
+	if p.VersionId != "" {
+		path, err = s.versionFile(p.Id, path, p.VersionId)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if !s.Client.IsExists(path) {
-		return nil, fmt.Errorf("file is not found")
+		return nil, goseidon.ErrNotFound
 	}
 
-	file, err := s.Client.Open(path)
+	meta, err := s.readMeta(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed open file")
+		return nil, wrapError(err, fmt.Errorf("failed read file metadata"))
 	}
-	defer file.Close()
 
-	binFile, err := s.Client.ReadFile(file)
+	file, err := s.Client.Open(path)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed open file"))
+	}
+	rf, err := openRange(file, p.Offset, p.Length)
 	if err != nil {
+		file.Close()
 		return nil, err
 	}
+	// the stream stop once ctx is done, a context which is never done is left out
+	if ctx.Done() != nil {
+		rf.file = io.NewContextReadCloser(ctx, rf.file)
+	}
+	if p.Verify {
+		rf.file = goseidon.NewVerifyReader(rf.file, meta.checksum())
+	}
 
 	retrievedAt := s.Clock.Now()
-	res := &goseidon.RetrieveFileResult{
-		File:        binFile,
+	res := &goseidon.RetrieveStreamResult{
+		File:        rf.file,
 		RetrievedAt: retrievedAt,
+		Size:        rf.size,
+		Offset:      p.Offset,
+		Length:      rf.length,
+		Checksum:    meta.checksum(),
+		VersionId:   meta.VersionId,
+
+		FileName:           meta.FileName,
+		ContentType:        meta.contentType(p.Id),
+		ContentDisposition: meta.ContentDisposition,
+		CacheControl:       meta.CacheControl,
+		Metadata:           meta.Metadata,
 	}
 	return res, nil
 }
 
 func (s *LocalStorage) DeleteFile(ctx context.Context, p goseidon.DeleteFileParam) (*goseidon.DeleteFileResult, error) {
 	if ctx == nil {
-		return nil, fmt.Errorf("invalid context")
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
 	}
 
-	path := fmt.Sprintf("%s/%s", s.Config.StorageDir, p.Id)
-	if !s.Client.IsExists(path) {
-		return nil, fmt.Errorf("file is not found")
+	path, err := s.filePath(p.Id)
+	if err != nil {
+		return nil, err
+	}
+	if p.VersionId != "" {
+		err = s.deleteVersion(p.Id, path, p.VersionId)
+		if err != nil {
+			return nil, err
+		}
+		res := &goseidon.DeleteFileResult{
+			Id:        p.Id,
+			DeletedAt: s.Clock.Now(),
+			VersionId: p.VersionId,
+		}
+		return res, nil
+	}
+	found, err := s.isFile(path)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed stat file"))
+	}
+	if !found {
+		return nil, goseidon.ErrNotFound
+	}
+
+	// the deleted file stay available as a noncurrent version
+	versionId := ""
+	if s.Config.Versioning {
+		versionId, err = s.archive(p.Id, path)
+		if err != nil {
+			return nil, wrapError(err, fmt.Errorf("failed archive file"))
+		}
 	}
 
-	err := s.Client.RemoveFile(path)
+	err = s.Client.RemoveFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed delete file")
+		return nil, wrapError(err, fmt.Errorf("failed delete file"))
 	}
 
+	err = s.Client.RemoveFile(metaPath(path))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, wrapError(err, fmt.Errorf("failed delete file metadata"))
+	}
+
+	s.pruneDirs(p.Id)
+
 	deletedAt := s.Clock.Now()
 	res := &goseidon.DeleteFileResult{
 		Id:        p.Id,
 		DeletedAt: deletedAt,
+		VersionId: versionId,
 	}
 	return res, nil
 }
 
-func NewLocalStorage(opt LocalStorageOption) (*LocalStorage, error) {
-	if opt == nil {
-		return nil, fmt.Errorf("invalid storage option")
+func (s *LocalStorage) StatFile(ctx context.Context, p goseidon.StatFileParam) (*goseidon.StatFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
 	}
 
-	cfg := &LocalConfig{}
-	err := opt.Apply(cfg)
+	path, err := s.filePath(p.Id)
 	if err != nil {
 		return nil, err
 	}
+	info, err := s.Client.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, goseidon.ErrNotFound
+	}
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed stat file"))
+	}
+	if info.IsDir() {
+		return nil, goseidon.ErrNotFound
+	}
+
+	meta, err := s.readMeta(path)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed read file metadata"))
+	}
+
+	res := &goseidon.StatFileResult{
+		Id:           p.Id,
+		Size:         info.Size(),
+		Checksum:     meta.checksum(),
+		LastModified: info.ModTime(),
+
+		FileName:           meta.FileName,
+		ContentType:        meta.contentType(p.Id),
+		ContentDisposition: meta.ContentDisposition,
+		CacheControl:       meta.CacheControl,
+		Metadata:           meta.Metadata,
+	}
+	return res, nil
+}
+
+// ListFiles walk the whole storage directory on every call,
+// the continuation token is the last id (or prefix) of the previous page
+func (s *LocalStorage) ListFiles(ctx context.Context, p goseidon.ListFileParam) (*goseidon.ListFileResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+
+	pageSize := p.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	entries, err := s.Client.WalkFiles(s.Config.StorageDir)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed list files"))
+	}
+
+	res := &goseidon.ListFileResult{
+		Files:    []goseidon.ListFileItem{},
+		Prefixes: []string{},
+	}
+	lastKey := p.ContinuationToken
+	count := 0
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Path, p.Prefix) || isReserved(entry.Path) {
+			continue
+		}
+
+		// keys are sorted, so grouped prefix is never smaller than the previous key
+		key := entry.Path
+		isPrefix := false
+		if p.Delimiter != "" {
+			rest := strings.TrimPrefix(entry.Path, p.Prefix)
+			idx := strings.Index(rest, p.Delimiter)
+			if idx >= 0 {
+				key = p.Prefix + rest[:idx+len(p.Delimiter)]
+				isPrefix = true
+			}
+		}
+		if key <= lastKey {
+			continue
+		}
+
+		if count == pageSize {
+			res.NextContinuationToken = lastKey
+			break
+		}
+
+		if isPrefix {
+			res.Prefixes = append(res.Prefixes, key)
+		} else {
+			res.Files = append(res.Files, goseidon.ListFileItem{
+				Id:           entry.Path,
+				Size:         entry.Size,
+				LastModified: entry.ModTime,
+			})
+		}
+		lastKey = key
+		count++
+	}
+	return res, nil
+}
+
+func NewLocalStorage(opts ...LocalStorageOption) (*LocalStorage, error) {
+	if len(opts) == 0 {
+		return nil, fmt.Errorf("invalid storage option")
+	}
+
+	cfg := &LocalConfig{}
+	for _, opt := range opts {
+		if opt == nil {
+			return nil, fmt.Errorf("invalid storage option")
+		}
+		err := opt.Apply(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	client, _ := io.NewFileManager()
 	clock, _ := clock.NewClock()
@@ -119,5 +503,12 @@ func NewLocalStorage(opt LocalStorageOption) (*LocalStorage, error) {
 		Client: client,
 		Clock:  clock,
 	}
+
+	if cfg.TempMaxAge > 0 {
+		_, err := s.SweepTempFiles(cfg.TempMaxAge)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return s, nil
 }
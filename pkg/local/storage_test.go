@@ -1,10 +1,14 @@
 package local_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	goio "io"
 	"io/fs"
-	"os"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -17,6 +21,11 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+// copyAll drain src like the real Copy so a wrapping reader sees the whole content
+func copyAll(_ context.Context, _ goio.Writer, src goio.Reader) (int64, error) {
+	return goio.Copy(goio.Discard, src)
+}
+
 func TestLocal(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Local Package")
@@ -42,21 +51,1701 @@ var _ = Describe("Storage", func() {
 			})
 		})
 
-		When("success create storage", func() {
-			It("should return local storage", func() {
-				s, err := local.NewLocalStorage(&withSuccessOption{})
+		When("option is not specified", func() {
+			It("should return error", func() {
+				s, err := local.NewLocalStorage()
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid storage option")))
+			})
+		})
+
+		When("success create storage", func() {
+			It("should return local storage", func() {
+				s, err := local.NewLocalStorage(&withSuccessOption{})
+
+				Expect(s).ToNot(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("multiple options are specified", func() {
+			It("should apply all of them", func() {
+				s, err := local.NewLocalStorage(
+					local.WithNormalStorageDir("storage"),
+					local.WithSyncDir(),
+				)
+
+				Expect(err).To(BeNil())
+				Expect(s.Config.StorageDir).To(Equal("storage"))
+				Expect(s.Config.SyncDir).To(BeTrue())
+			})
+		})
+	})
+
+	Context("SweepTempFiles method", func() {
+		var (
+			s           *local.LocalStorage
+			cfg         *local.LocalConfig
+			fm          *io.MockFileManager
+			clo         *clock.MockClock
+			currentTime time.Time
+			entries     []io.FileEntry
+		)
+
+		BeforeEach(func() {
+			cfg = &local.LocalConfig{
+				StorageDir: "storage",
+			}
+			t := GinkgoT()
+			ctrl := gomock.NewController(t)
+			fm = io.NewMockFileManager(ctrl)
+			clo = clock.NewMockClock(ctrl)
+			currentTime = time.Now()
+			s = &local.LocalStorage{
+				Config: cfg,
+				Client: fm,
+				Clock:  clo,
+			}
+			entries = []io.FileEntry{
+				{Path: ".goseidon-tmp-1", ModTime: currentTime.Add(-2 * time.Hour)},
+				{Path: ".goseidon-tmp-2", ModTime: currentTime.Add(-time.Minute)},
+				{Path: "image.jpg", ModTime: currentTime.Add(-2 * time.Hour)},
+				{Path: "tenant-1/.goseidon-tmp-3", ModTime: currentTime.Add(-3 * time.Hour)},
+			}
+		})
+
+		When("failed walk storage dir", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(cfg.StorageDir)).
+					Return(nil, fs.ErrPermission).
+					Times(1)
+
+				removed, err := s.SweepTempFiles(time.Hour)
+
+				Expect(removed).To(Equal(0))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("failed remove temp file", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(cfg.StorageDir)).
+					Return(entries, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/.goseidon-tmp-1")).
+					Return(fs.ErrPermission).
+					Times(1)
+
+				removed, err := s.SweepTempFiles(time.Hour)
+
+				Expect(removed).To(Equal(0))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("success sweep temp files", func() {
+			It("should remove only stale temp files", func() {
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(cfg.StorageDir)).
+					Return(entries, nil).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/.goseidon-tmp-1")).
+					Return(nil).
+					Times(1)
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/tenant-1/.goseidon-tmp-3")).
+					Return(fs.ErrNotExist).
+					Times(1)
+
+				removed, err := s.SweepTempFiles(time.Hour)
+
+				Expect(removed).To(Equal(1))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UploadFile method", func() {
+		var (
+			ctx         context.Context
+			s           *local.LocalStorage
+			p           goseidon.UploadFileParam
+			cfg         *local.LocalConfig
+			fm          *io.MockFileManager
+			file        *io.MockFile
+			metaFile    *io.MockFile
+			clo         *clock.MockClock
+			currentTime time.Time
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			p = goseidon.UploadFileParam{
+				FileId:   "image.jpg",
+				FileName: "image.jpg",
+				FileData: make([]byte, 1),
+			}
+			cfg = &local.LocalConfig{
+				StorageDir: "storage",
+			}
+			t := GinkgoT()
+			ctrl := gomock.NewController(t)
+			fm = io.NewMockFileManager(ctrl)
+			file = io.NewMockFile(ctrl)
+			metaFile = io.NewMockFile(ctrl)
+			currentTime = time.Now()
+			clo = clock.NewMockClock(ctrl)
+			s = &local.LocalStorage{
+				Config: cfg,
+				Client: fm,
+				Clock:  clo,
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.UploadFile(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("file already exists", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.FileId)).
+					Return(true).
+					Times(1)
+
+				res, err := s.UploadFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrAlreadyExists))
+			})
+		})
+
+		When("success upload file", func() {
+			It("should return result", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.FileId)).
+					Return(false).
+					Times(1)
+
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(cfg.StorageDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Name().Return(cfg.StorageDir + "/.goseidon-tmp-1").Times(1)
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(file), gomock.Any()).
+					DoAndReturn(copyAll).
+					Times(1)
+				file.EXPECT().Sync().Return(nil).Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+				fm.EXPECT().
+					Link(gomock.Eq(cfg.StorageDir+"/.goseidon-tmp-1"), gomock.Eq(cfg.StorageDir+"/"+p.FileId)).
+					Return(nil).
+					Times(1)
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/.goseidon-tmp-1")).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(cfg.StorageDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(metaFile, nil).
+					Times(1)
+				metaFile.EXPECT().Name().Return(cfg.StorageDir + "/.goseidon-tmp-2").Times(1)
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(metaFile), gomock.Eq(bytes.NewReader([]byte(`{"file_name":"image.jpg",`+
+						`"md5":"93b885adfe0da089cdf634904fd59f71","crc32c":"527d5351",`+
+						`"sha256":"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d"}`)))).
+					DoAndReturn(copyAll).
+					Times(1)
+				metaFile.EXPECT().Sync().Return(nil).Times(1)
+				metaFile.EXPECT().Close().Return(nil).Times(1)
+				fm.EXPECT().
+					Rename(gomock.Eq(cfg.StorageDir+"/.goseidon-tmp-2"), gomock.Eq(cfg.StorageDir+"/.goseidon-meta-image.jpg.json")).
+					Return(nil).
+					Times(1)
+
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadFile(ctx, p)
+
+				eRes := &goseidon.UploadFileResult{
+					FileId:     p.FileId,
+					FileName:   p.FileName,
+					UploadedAt: currentTime,
+					Checksum: goseidon.Checksum{
+						MD5:    "93b885adfe0da089cdf634904fd59f71",
+						CRC32C: "527d5351",
+						SHA256: "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("UploadStream method", func() {
+		var (
+			ctx         context.Context
+			s           *local.LocalStorage
+			p           goseidon.UploadStreamParam
+			cfg         *local.LocalConfig
+			fm          *io.MockFileManager
+			file        *io.MockFile
+			metaFile    *io.MockFile
+			clo         *clock.MockClock
+			currentTime time.Time
+			path        string
+			tmpPath     string
+			metaTmpPath string
+			metaData    []byte
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			p = goseidon.UploadStreamParam{
+				FileId:      "image.jpg",
+				FileName:    "dolphin.jpg",
+				FileData:    strings.NewReader("content"),
+				ContentType: "image/jpeg",
+				Metadata: map[string]string{
+					"owner": "tenant-1",
+				},
+			}
+			cfg = &local.LocalConfig{
+				StorageDir: "storage",
+			}
+			path = cfg.StorageDir + "/" + p.FileId
+			tmpPath = cfg.StorageDir + "/.goseidon-tmp-1"
+			metaTmpPath = cfg.StorageDir + "/.goseidon-tmp-2"
+			metaData = []byte(`{"file_name":"dolphin.jpg","content_type":"image/jpeg","metadata":{"owner":"tenant-1"},` +
+				`"md5":"9a0364b9e99bb480dd25e1f0284c8555","crc32c":"61af7533",` +
+				`"sha256":"ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73"}`)
+			t := GinkgoT()
+			ctrl := gomock.NewController(t)
+			fm = io.NewMockFileManager(ctrl)
+			file = io.NewMockFile(ctrl)
+			metaFile = io.NewMockFile(ctrl)
+			currentTime = time.Now()
+			clo = clock.NewMockClock(ctrl)
+			s = &local.LocalStorage{
+				Config: cfg,
+				Client: fm,
+				Clock:  clo,
+			}
+		})
+
+		expectWriteTemp := func(dir string) {
+			fm.EXPECT().
+				CreateTemp(gomock.Eq(dir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644)&^cfg.Umask)).
+				Return(file, nil).
+				Times(1)
+			file.EXPECT().Name().Return(tmpPath).Times(1)
+			if cfg.Owner != nil {
+				fm.EXPECT().
+					Chown(gomock.Eq(tmpPath), gomock.Eq(cfg.Owner.Uid), gomock.Eq(cfg.Owner.Gid)).
+					Return(nil).
+					Times(1)
+			}
+			fm.EXPECT().
+				CopyContext(gomock.Any(), gomock.Eq(file), gomock.Any()).
+				DoAndReturn(copyAll).
+				Times(1)
+			file.EXPECT().Sync().Return(nil).Times(1)
+			file.EXPECT().Close().Return(nil).Times(1)
+		}
+
+		expectWriteMetaTemp := func(dir string) {
+			fm.EXPECT().
+				CreateTemp(gomock.Eq(dir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644)&^cfg.Umask)).
+				Return(metaFile, nil).
+				Times(1)
+			metaFile.EXPECT().Name().Return(metaTmpPath).Times(1)
+			if cfg.Owner != nil {
+				fm.EXPECT().
+					Chown(gomock.Eq(metaTmpPath), gomock.Eq(cfg.Owner.Uid), gomock.Eq(cfg.Owner.Gid)).
+					Return(nil).
+					Times(1)
+			}
+			fm.EXPECT().
+				CopyContext(gomock.Any(), gomock.Eq(metaFile), gomock.Eq(bytes.NewReader(metaData))).
+				Return(int64(len(metaData)), nil).
+				Times(1)
+			metaFile.EXPECT().Sync().Return(nil).Times(1)
+			metaFile.EXPECT().Close().Return(nil).Times(1)
+		}
+
+		expectWriteMeta := func(dir string, renameErr error) {
+			expectWriteMetaTemp(dir)
+			fm.EXPECT().
+				Rename(gomock.Eq(metaTmpPath), gomock.Eq(dir+"/.goseidon-meta-image.jpg.json")).
+				Return(renameErr).
+				Times(1)
+		}
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.UploadStream(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("file id escapes storage dir", func() {
+			It("should return error", func() {
+				p.FileId = "../../etc/passwd"
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal(`file id contains ".." segment`))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("file data is invalid", func() {
+			It("should return error", func() {
+				p.FileData = nil
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid file data"))
+			})
+		})
+
+		When("failed create storage dir", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(false).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(".")).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					CreateDir(gomock.Eq(cfg.StorageDir), gomock.Eq(fs.FileMode(0755))).
+					Return(fmt.Errorf("invalid storage dir")).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed create storage dir: %s", cfg.StorageDir)))
+			})
+		})
+
+		When("file already exists", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(true).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrAlreadyExists))
+			})
+		})
+
+		When("file is created while writing", func() {
+			It("should keep existing file and return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(false).
+					Times(1)
+
+				expectWriteTemp(cfg.StorageDir)
+				expectWriteMetaTemp(cfg.StorageDir)
+
+				fm.EXPECT().
+					Link(gomock.Eq(tmpPath), gomock.Eq(path)).
+					Return(&fs.PathError{Op: "link", Path: path, Err: fs.ErrExist}).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(metaTmpPath)).
+					Return(nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrAlreadyExists))
+			})
+		})
+
+		When("file already exists and overwrite mode is skip", func() {
+			It("should return skipped result", func() {
+				p.Overwrite = goseidon.OverwriteSkip
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(true).
+					Times(1)
+
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadStream(ctx, p)
+
+				eRes := &goseidon.UploadFileResult{
+					FileId:     p.FileId,
+					FileName:   p.FileName,
+					UploadedAt: currentTime,
+					Skipped:    true,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("overwrite mode is replace", func() {
+			It("should rename temp file over existing file", func() {
+				p.Overwrite = goseidon.OverwriteReplace
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				expectWriteTemp(cfg.StorageDir)
+				expectWriteMeta(cfg.StorageDir, nil)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/.goseidon-meta-image.jpg.json")).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					Rename(gomock.Eq(tmpPath), gomock.Eq(path)).
+					Return(nil).
+					Times(1)
+
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Skipped).To(BeFalse())
+			})
+		})
+
+		When("failed write metadata while replacing", func() {
+			It("should keep existing file and return error", func() {
+				p.Overwrite = goseidon.OverwriteReplace
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				expectWriteTemp(cfg.StorageDir)
+
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(cfg.StorageDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(nil, fs.ErrPermission).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed storing file metadata"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("failed remove replaced metadata", func() {
+			It("should keep existing file and return error", func() {
+				p.Overwrite = goseidon.OverwriteReplace
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				expectWriteTemp(cfg.StorageDir)
+				expectWriteMetaTemp(cfg.StorageDir)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/.goseidon-meta-image.jpg.json")).
+					Return(fs.ErrPermission).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(metaTmpPath)).
+					Return(nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed storing file metadata"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("failed commit metadata while replacing", func() {
+			It("should keep the new file and return error", func() {
+				p.Overwrite = goseidon.OverwriteReplace
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				expectWriteTemp(cfg.StorageDir)
+				expectWriteMeta(cfg.StorageDir, fs.ErrPermission)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/.goseidon-meta-image.jpg.json")).
+					Return(fs.ErrNotExist).
+					Times(1)
+
+				fm.EXPECT().
+					Rename(gomock.Eq(tmpPath), gomock.Eq(path)).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(metaTmpPath)).
+					Return(nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed storing file metadata"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("failed create temp file", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(false).
+					Times(1)
+
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(cfg.StorageDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(nil, fmt.Errorf("access denied")).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed storing file")))
+			})
+		})
+
+		When("failed copy file", func() {
+			It("should remove temp file and return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(false).
+					Times(1)
+
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(cfg.StorageDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(file, nil).
+					Times(1)
+
+				file.EXPECT().Name().Return(tmpPath).Times(1)
+
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(file), gomock.Any()).
+					Return(int64(0), fmt.Errorf("disk is full")).
+					Times(1)
+
+				file.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed storing file")))
+			})
+		})
+
+		When("context is cancelled while copying", func() {
+			It("should remove temp file and return context error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(false).
+					Times(1)
+
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(cfg.StorageDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(file, nil).
+					Times(1)
+
+				file.EXPECT().Name().Return(tmpPath).Times(1)
+
+				fm.EXPECT().
+					CopyContext(gomock.Eq(ctx), gomock.Eq(file), gomock.Any()).
+					Return(int64(3), context.Canceled).
+					Times(1)
+
+				file.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed storing file: context canceled"))
+				Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			})
+		})
+
+		When("context is cancelled once the file is written", func() {
+			It("should remove temp file and return context error", func() {
+				cctx, cancel := context.WithTimeout(ctx, time.Minute)
+				defer cancel()
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(false).
+					Times(1)
+
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(cfg.StorageDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Name().Return(tmpPath).Times(1)
+				fm.EXPECT().
+					CopyContext(gomock.Eq(cctx), gomock.Eq(file), gomock.Any()).
+					DoAndReturn(func(c context.Context, dst goio.Writer, src goio.Reader) (int64, error) {
+						defer cancel()
+						return copyAll(c, dst, src)
+					}).
+					Times(1)
+				file.EXPECT().Sync().Return(nil).Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				res, err := s.UploadStream(cctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			})
+		})
+
+		When("content doesn't match given checksum", func() {
+			It("should remove temp file and return error", func() {
+				p.Checksum = goseidon.Checksum{
+					SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+				}
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(false).
+					Times(1)
+
+				expectWriteTemp(cfg.StorageDir)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				eErr := &goseidon.ChecksumMismatchError{
+					Algorithm: "sha256",
+					Expected:  "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+					Actual:    "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73",
+				}
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(eErr))
+			})
+		})
+
+		When("failed sync file", func() {
+			It("should remove temp file and return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(false).
+					Times(1)
+
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(cfg.StorageDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(file, nil).
+					Times(1)
+
+				file.EXPECT().Name().Return(tmpPath).Times(1)
+
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(file), gomock.Any()).
+					DoAndReturn(copyAll).
+					Times(1)
+
+				file.EXPECT().
+					Sync().
+					Return(fmt.Errorf("i/o error")).
+					Times(1)
+
+				file.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed storing file")))
+			})
+		})
+
+		When("failed close file", func() {
+			It("should remove temp file and return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(false).
+					Times(1)
+
+				fm.EXPECT().
+					CreateTemp(gomock.Eq(cfg.StorageDir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(file, nil).
+					Times(1)
+
+				file.EXPECT().Name().Return(tmpPath).Times(1)
+
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(file), gomock.Any()).
+					DoAndReturn(copyAll).
+					Times(1)
+
+				file.EXPECT().
+					Sync().
+					Return(nil).
+					Times(1)
+
+				file.EXPECT().
+					Close().
+					Return(fmt.Errorf("i/o error")).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed storing file")))
+			})
+		})
+
+		When("failed write metadata", func() {
+			It("should remove stored file and return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(false).
+					Times(1)
+
+				expectWriteTemp(cfg.StorageDir)
+
+				fm.EXPECT().
+					Link(gomock.Eq(tmpPath), gomock.Eq(path)).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				expectWriteMeta(cfg.StorageDir, fs.ErrPermission)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(metaTmpPath)).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(path)).
+					Return(nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed storing file metadata"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("failed sync storage dir", func() {
+			It("should return error", func() {
+				cfg.SyncDir = true
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(false).
+					Times(1)
+
+				expectWriteTemp(cfg.StorageDir)
+
+				fm.EXPECT().
+					Link(gomock.Eq(tmpPath), gomock.Eq(path)).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				expectWriteMeta(cfg.StorageDir, nil)
+
+				fm.EXPECT().
+					SyncDir(gomock.Eq(cfg.StorageDir)).
+					Return(fmt.Errorf("i/o error")).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed sync storage dir")))
+			})
+		})
+
+		When("file id is nested", func() {
+			It("should create parent directories", func() {
+				p.FileId = "tenant-1/2022/image.jpg"
+				dir := cfg.StorageDir + "/tenant-1/2022"
+
+				cfg.Umask = 0027
+				cfg.Owner = &local.FileOwner{Uid: 1000, Gid: -1}
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(dir)).
+					Return(false).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/tenant-1")).
+					Return(false).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				for _, d := range []string{cfg.StorageDir + "/tenant-1", dir} {
+					fm.EXPECT().
+						CreateDir(gomock.Eq(d), gomock.Eq(fs.FileMode(0750))).
+						Return(nil).
+						Times(1)
+
+					fm.EXPECT().
+						Chmod(gomock.Eq(d), gomock.Eq(fs.FileMode(0750))).
+						Return(nil).
+						Times(1)
+
+					fm.EXPECT().
+						Chown(gomock.Eq(d), gomock.Eq(1000), gomock.Eq(-1)).
+						Return(nil).
+						Times(1)
+				}
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(dir + "/image.jpg")).
+					Return(false).
+					Times(1)
+
+				expectWriteTemp(dir)
+
+				fm.EXPECT().
+					Link(gomock.Eq(tmpPath), gomock.Eq(dir+"/image.jpg")).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				expectWriteMeta(dir, nil)
+
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.FileId).To(Equal("tenant-1/2022/image.jpg"))
+			})
+		})
+
+		When("success upload file", func() {
+			It("should return result", func() {
+				cfg.SyncDir = true
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					IsExists(gomock.Eq(path)).
+					Return(false).
+					Times(1)
+
+				expectWriteTemp(cfg.StorageDir)
+
+				fm.EXPECT().
+					Link(gomock.Eq(tmpPath), gomock.Eq(path)).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(tmpPath)).
+					Return(nil).
+					Times(1)
+
+				expectWriteMeta(cfg.StorageDir, nil)
+
+				fm.EXPECT().
+					SyncDir(gomock.Eq(cfg.StorageDir)).
+					Return(nil).
+					Times(1)
+
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.UploadStream(ctx, p)
+
+				eRes := &goseidon.UploadFileResult{
+					FileId:     p.FileId,
+					FileName:   p.FileName,
+					UploadedAt: currentTime,
+					Checksum: goseidon.Checksum{
+						MD5:    "9a0364b9e99bb480dd25e1f0284c8555",
+						CRC32C: "61af7533",
+						SHA256: "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73",
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("RetrieveFile method", func() {
+		var (
+			ctx         context.Context
+			s           *local.LocalStorage
+			p           goseidon.RetrieveFileParam
+			cfg         *local.LocalConfig
+			clo         *clock.MockClock
+			fm          *io.MockFileManager
+			file        *io.MockFile
+			metaFile    *io.MockFile
+			currentTime time.Time
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			p = goseidon.RetrieveFileParam{
+				Id: "image.jpg",
+			}
+			cfg = &local.LocalConfig{
+				StorageDir: "storage",
+			}
+			t := GinkgoT()
+			ctrl := gomock.NewController(t)
+			fm = io.NewMockFileManager(ctrl)
+			file = io.NewMockFile(ctrl)
+			metaFile = io.NewMockFile(ctrl)
+			clo = clock.NewMockClock(ctrl)
+			currentTime = time.Now()
+			s = &local.LocalStorage{
+				Config: cfg,
+				Client: fm,
+				Clock:  clo,
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.RetrieveFile(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("file is not available", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(false).
+					Times(1)
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+
+		When("failed open file", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/.goseidon-meta-image.jpg.json")).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(nil, fmt.Errorf("access denied")).
+					Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed open file")))
+			})
+		})
+
+		When("failed read file", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/.goseidon-meta-image.jpg.json")).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Stat().Return(&fileInfo{size: 1}, nil).Times(1)
+
+				clo.EXPECT().Now().Return(currentTime)
+
+				fm.EXPECT().
+					ReadFileContext(gomock.Eq(ctx), gomock.Eq(file)).
+					Return(nil, fmt.Errorf("failed read file")).
+					Times(1)
+
+				file.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed read file")))
+			})
+		})
+
+		When("checksum is verified on a range", func() {
+			It("should return error", func() {
+				p.Verify = true
+				p.Length = 1
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("checksum can't be verified on a range"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("content doesn't match stored checksum", func() {
+			It("should return checksum mismatch error", func() {
+				p.Verify = true
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/.goseidon-meta-image.jpg.json")).
+					Return(metaFile, nil).
+					Times(1)
+				fm.EXPECT().
+					ReadFile(gomock.Eq(metaFile)).
+					Return([]byte(`{"md5":"9a0364b9e99bb480dd25e1f0284c8555"}`), nil).
+					Times(1)
+				metaFile.EXPECT().Close().Return(nil).Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Stat().Return(&fileInfo{size: 7}, nil).Times(1)
+				file.EXPECT().
+					Read(gomock.Any()).
+					DoAndReturn(strings.NewReader("corrupt").Read).
+					AnyTimes()
+				fm.EXPECT().
+					ReadFileContext(gomock.Eq(ctx), gomock.Any()).
+					DoAndReturn(func(_ context.Context, r goio.Reader) ([]byte, error) {
+						return goio.ReadAll(r)
+					}).
+					Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrChecksumMismatch)).To(BeTrue())
+				var mismatch *goseidon.ChecksumMismatchError
+				Expect(errors.As(err, &mismatch)).To(BeTrue())
+				Expect(mismatch.Algorithm).To(Equal("md5"))
+				Expect(mismatch.Expected).To(Equal("9a0364b9e99bb480dd25e1f0284c8555"))
+			})
+		})
+
+		When("success retrieve file", func() {
+			It("should return result", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/.goseidon-meta-image.jpg.json")).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Stat().Return(&fileInfo{size: 1}, nil).Times(1)
+
+				binFile := make([]byte, 1)
+				fm.EXPECT().
+					ReadFileContext(gomock.Eq(ctx), gomock.Eq(file)).
+					Return(binFile, nil).
+					Times(1)
+
+				file.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				eRes := &goseidon.RetrieveFileResult{
+					File:        binFile,
+					RetrievedAt: currentTime,
+					Size:        1,
+					Length:      1,
+					ContentType: "image/jpeg",
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Context("RetrieveStream method", func() {
+		var (
+			ctx         context.Context
+			s           *local.LocalStorage
+			p           goseidon.RetrieveFileParam
+			cfg         *local.LocalConfig
+			clo         *clock.MockClock
+			fm          *io.MockFileManager
+			file        *io.MockFile
+			metaFile    *io.MockFile
+			currentTime time.Time
+			metaPath    string
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			p = goseidon.RetrieveFileParam{
+				Id: "image.jpg",
+			}
+			cfg = &local.LocalConfig{
+				StorageDir: "storage",
+			}
+			t := GinkgoT()
+			ctrl := gomock.NewController(t)
+			fm = io.NewMockFileManager(ctrl)
+			file = io.NewMockFile(ctrl)
+			metaFile = io.NewMockFile(ctrl)
+			metaPath = cfg.StorageDir + "/.goseidon-meta-image.jpg.json"
+			clo = clock.NewMockClock(ctrl)
+			currentTime = time.Now()
+			s = &local.LocalStorage{
+				Config: cfg,
+				Client: fm,
+				Clock:  clo,
+			}
+		})
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.RetrieveStream(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("file id escapes storage dir", func() {
+			It("should return error", func() {
+				p.Id = "../../etc/passwd"
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal(`file id contains ".." segment`))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("file is not available", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(false).
+					Times(1)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+
+		When("failed open metadata", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(metaPath)).
+					Return(nil, fs.ErrPermission).
+					Times(1)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed read file metadata"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("failed read metadata", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(metaPath)).
+					Return(metaFile, nil).
+					Times(1)
+
+				fm.EXPECT().
+					ReadFile(gomock.Eq(metaFile)).
+					Return(nil, fmt.Errorf("i/o error")).
+					Times(1)
+
+				metaFile.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed read file metadata")))
+			})
+		})
+
+		When("metadata is malformed", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(metaPath)).
+					Return(metaFile, nil).
+					Times(1)
+
+				fm.EXPECT().
+					ReadFile(gomock.Eq(metaFile)).
+					Return([]byte("{"), nil).
+					Times(1)
+
+				metaFile.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed read file metadata")))
+			})
+		})
+
+		When("failed open file", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(metaPath)).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(nil, fmt.Errorf("access denied")).
+					Times(1)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("failed open file")))
+			})
+		})
+
+		When("success retrieve stream", func() {
+			It("should return opened file", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(metaPath)).
+					Return(metaFile, nil).
+					Times(1)
+
+				meta := `{"file_name":"dolphin.jpg","content_type":"image/png","content_disposition":"inline","cache_control":"no-cache","metadata":{"owner":"tenant-1"}}`
+				fm.EXPECT().
+					ReadFile(gomock.Eq(metaFile)).
+					Return([]byte(meta), nil).
+					Times(1)
+
+				metaFile.EXPECT().
+					Close().
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Stat().Return(&fileInfo{size: 1}, nil).Times(1)
+
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				eRes := &goseidon.RetrieveStreamResult{
+					File:               file,
+					RetrievedAt:        currentTime,
+					Size:               1,
+					Length:             1,
+					FileName:           "dolphin.jpg",
+					ContentType:        "image/png",
+					ContentDisposition: "inline",
+					CacheControl:       "no-cache",
+					Metadata: map[string]string{
+						"owner": "tenant-1",
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("range is invalid", func() {
+			It("should return error", func() {
+				p.Length = -1
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("range length must not be negative"))
+			})
+		})
+
+		When("file id is a directory", func() {
+			It("should close it and return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq(metaPath)).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Stat().Return(&fileInfo{dir: true}, nil).Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+
+		When("range is not satisfiable", func() {
+			It("should close the file and return error", func() {
+				p.Offset = 120
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq(metaPath)).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Stat().Return(&fileInfo{size: 120}, nil).Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("range is not satisfiable"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("context is cancelled while reading", func() {
+			It("should stop the stream", func() {
+				cctx, cancel := context.WithCancel(ctx)
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq(metaPath)).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Stat().Return(&fileInfo{size: 10}, nil).Times(1)
+				file.EXPECT().
+					Read(gomock.Any()).
+					DoAndReturn(func(b []byte) (int, error) {
+						return copy(b, "aaaaa"), nil
+					}).
+					Times(1)
+				file.EXPECT().Close().Return(nil).Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveStream(cctx, p)
+				Expect(err).To(BeNil())
+
+				n, err := res.File.Read(make([]byte, 32))
+				Expect(n).To(Equal(5))
+				Expect(err).To(BeNil())
+
+				cancel()
+				n, err = res.File.Read(make([]byte, 32))
+				Expect(n).To(Equal(0))
+				Expect(err).To(Equal(context.Canceled))
+				Expect(res.File.Close()).To(BeNil())
+			})
+		})
+
+		When("range is given", func() {
+			It("should seek and limit the file", func() {
+				p.Offset = 100
+				p.Length = 10
+				fm.EXPECT().
+					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(true).
+					Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq(metaPath)).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(file, nil).
+					Times(1)
+				file.EXPECT().Stat().Return(&fileInfo{size: 120}, nil).Times(1)
+				file.EXPECT().Seek(gomock.Eq(int64(100)), gomock.Eq(0)).Return(int64(100), nil).Times(1)
+				file.EXPECT().
+					Read(gomock.Any()).
+					DoAndReturn(func(b []byte) (int, error) {
+						Expect(b).To(HaveLen(10))
+						return copy(b, strings.Repeat("a", 10)), nil
+					}).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime)
+
+				res, err := s.RetrieveStream(ctx, p)
 
-				Expect(s).ToNot(BeNil())
 				Expect(err).To(BeNil())
+				Expect(res.Size).To(Equal(int64(120)))
+				Expect(res.Offset).To(Equal(int64(100)))
+				Expect(res.Length).To(Equal(int64(10)))
+				n, _ := res.File.Read(make([]byte, 32))
+				Expect(n).To(Equal(10))
 			})
 		})
 	})
 
-	Context("UploadFile method", func() {
+	Context("DeleteFile method", func() {
 		var (
 			ctx         context.Context
 			s           *local.LocalStorage
-			p           goseidon.UploadFileParam
+			p           goseidon.DeleteFileParam
 			cfg         *local.LocalConfig
 			fm          *io.MockFileManager
 			clo         *clock.MockClock
@@ -65,9 +1754,8 @@ var _ = Describe("Storage", func() {
 
 		BeforeEach(func() {
 			ctx = context.Background()
-			p = goseidon.UploadFileParam{
-				FileName: "image.jpg",
-				FileData: make([]byte, 1),
+			p = goseidon.DeleteFileParam{
+				Id: "image.jpg",
 			}
 			cfg = &local.LocalConfig{
 				StorageDir: "storage",
@@ -75,8 +1763,8 @@ var _ = Describe("Storage", func() {
 			t := GinkgoT()
 			ctrl := gomock.NewController(t)
 			fm = io.NewMockFileManager(ctrl)
-			currentTime = time.Now()
 			clo = clock.NewMockClock(ctrl)
+			currentTime = time.Now()
 			s = &local.LocalStorage{
 				Config: cfg,
 				Client: fm,
@@ -86,128 +1774,219 @@ var _ = Describe("Storage", func() {
 
 		When("context is invalid", func() {
 			It("should return error", func() {
-				res, err := s.UploadFile(nil, p)
+				res, err := s.DeleteFile(nil, p)
 
 				Expect(res).To(BeNil())
 				Expect(err.Error()).To(Equal("invalid context"))
 			})
 		})
 
-		When("failed create storage dir", func() {
+		When("file id escapes storage dir", func() {
+			It("should return error", func() {
+				p.Id = "../../etc/passwd"
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal(`file id contains ".." segment`))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("file is not found", func() {
 			It("should return error", func() {
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir)).
-					Return(false).
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+
+		When("file id is a directory", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(&fileInfo{dir: true}, nil).
 					Times(1)
 
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+
+		When("failed stat file", func() {
+			It("should return error", func() {
 				fm.EXPECT().
-					CreateDir(gomock.Eq(cfg.StorageDir), gomock.Eq(fs.FileMode(0644))).
-					Return(fmt.Errorf("invalid storage dir")).
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(nil, fs.ErrPermission).
 					Times(1)
 
-				res, err := s.UploadFile(ctx, p)
+				res, err := s.DeleteFile(ctx, p)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("failed create storage dir: %s", cfg.StorageDir)))
+				Expect(err.Error()).To(Equal("failed stat file"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
 			})
 		})
 
-		When("file already exists", func() {
+		When("failed remove file", func() {
 			It("should return error", func() {
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir)).
-					Return(true).
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(&fileInfo{size: 1}, nil).
 					Times(1)
 
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.FileId)).
-					Return(true).
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(fmt.Errorf("invalid permission")).
 					Times(1)
 
-				res, err := s.UploadFile(ctx, p)
+				res, err := s.DeleteFile(ctx, p)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("file already exists")))
+				Expect(err).To(Equal(fmt.Errorf("failed delete file")))
 			})
 		})
 
-		When("failed upload file", func() {
+		When("failed remove metadata", func() {
 			It("should return error", func() {
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir)).
-					Return(true).
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(&fileInfo{size: 1}, nil).
 					Times(1)
 
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.FileId)).
-					Return(false).
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(nil).
 					Times(1)
 
 				fm.EXPECT().
-					WriteFile(
-						gomock.Eq(cfg.StorageDir+"/"+p.FileId),
-						gomock.Eq(make([]byte, 1)),
-						gomock.Eq(fs.FileMode(0644)),
-					).
-					Return(fmt.Errorf("access denied")).
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/.goseidon-meta-image.jpg.json")).
+					Return(fmt.Errorf("invalid permission")).
 					Times(1)
-				res, err := s.UploadFile(ctx, p)
+
+				res, err := s.DeleteFile(ctx, p)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("failed storing file")))
+				Expect(err).To(Equal(fmt.Errorf("failed delete file metadata")))
 			})
 		})
 
-		When("success upload file", func() {
+		When("metadata is absent", func() {
 			It("should return result", func() {
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir)).
-					Return(true).
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(&fileInfo{size: 1}, nil).
 					Times(1)
 
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.FileId)).
-					Return(false).
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/.goseidon-meta-image.jpg.json")).
+					Return(fs.ErrNotExist).
+					Times(1)
+
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Id).To(Equal(p.Id))
+			})
+		})
+
+		When("file id is nested", func() {
+			It("should prune empty parent directories", func() {
+				p.Id = "tenant-1/2022/image.jpg"
+
+				fm.EXPECT().
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(&fileInfo{size: 1}, nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(nil).
 					Times(1)
 
 				fm.EXPECT().
-					WriteFile(
-						gomock.Eq(cfg.StorageDir+"/"+p.FileId),
-						gomock.Eq(make([]byte, 1)),
-						gomock.Eq(fs.FileMode(0644)),
-					).
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/tenant-1/2022/.goseidon-meta-image.jpg.json")).
 					Return(nil).
 					Times(1)
-				clo.EXPECT().Now().Return(currentTime)
 
-				res, err := s.UploadFile(ctx, p)
+				fm.EXPECT().
+					RemoveDir(gomock.Eq(cfg.StorageDir + "/tenant-1/2022")).
+					Return(nil).
+					Times(1)
 
-				eRes := &goseidon.UploadFileResult{
-					FileId:     p.FileId,
-					FileName:   p.FileName,
-					UploadedAt: currentTime,
+				fm.EXPECT().
+					RemoveDir(gomock.Eq(cfg.StorageDir + "/tenant-1")).
+					Return(&fs.PathError{Op: "rmdir", Path: cfg.StorageDir + "/tenant-1", Err: syscall.ENOTEMPTY}).
+					Times(1)
+
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Id).To(Equal(p.Id))
+			})
+		})
+
+		When("success remove file", func() {
+			It("should return result", func() {
+				fm.EXPECT().
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(&fileInfo{size: 1}, nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
+					Return(nil).
+					Times(1)
+
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(cfg.StorageDir + "/.goseidon-meta-image.jpg.json")).
+					Return(nil).
+					Times(1)
+
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				eRes := &goseidon.DeleteFileResult{
+					Id:        p.Id,
+					DeletedAt: currentTime,
 				}
 				Expect(res).To(Equal(eRes))
 				Expect(err).To(BeNil())
 			})
 		})
-	})
 
-	Context("RetrieveFile method", func() {
+	})
+	Context("StatFile method", func() {
 		var (
 			ctx         context.Context
 			s           *local.LocalStorage
-			p           goseidon.RetrieveFileParam
+			p           goseidon.StatFileParam
 			cfg         *local.LocalConfig
-			clo         *clock.MockClock
 			fm          *io.MockFileManager
 			currentTime time.Time
 		)
 
 		BeforeEach(func() {
 			ctx = context.Background()
-			p = goseidon.RetrieveFileParam{
-				Id: "unique-access-id",
+			p = goseidon.StatFileParam{
+				Id: "image.jpg",
 			}
 			cfg = &local.LocalConfig{
 				StorageDir: "storage",
@@ -215,215 +1994,285 @@ var _ = Describe("Storage", func() {
 			t := GinkgoT()
 			ctrl := gomock.NewController(t)
 			fm = io.NewMockFileManager(ctrl)
-			clo = clock.NewMockClock(ctrl)
 			currentTime = time.Now()
 			s = &local.LocalStorage{
 				Config: cfg,
 				Client: fm,
-				Clock:  clo,
 			}
 		})
 
 		When("context is invalid", func() {
 			It("should return error", func() {
-				res, err := s.RetrieveFile(nil, p)
+				res, err := s.StatFile(nil, p)
 
 				Expect(res).To(BeNil())
 				Expect(err.Error()).To(Equal("invalid context"))
 			})
 		})
 
-		When("file is not available", func() {
+		When("file id escapes storage dir", func() {
 			It("should return error", func() {
-				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
-					Return(false).
-					Times(1)
-				res, err := s.RetrieveFile(ctx, p)
+				p.Id = "../../etc/passwd"
+				res, err := s.StatFile(ctx, p)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("file is not found")))
+				Expect(err.Error()).To(Equal(`file id contains ".." segment`))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
 			})
 		})
 
-		When("failed open file", func() {
+		When("file is not found", func() {
 			It("should return error", func() {
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
-					Return(true).
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(nil, fs.ErrNotExist).
 					Times(1)
 
+				res, err := s.StatFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+
+		When("failed stat file", func() {
+			It("should return error", func() {
 				fm.EXPECT().
-					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
-					Return(nil, fmt.Errorf("access denied")).
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(nil, fs.ErrPermission).
 					Times(1)
 
-				res, err := s.RetrieveFile(ctx, p)
+				res, err := s.StatFile(ctx, p)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("failed open file")))
+				Expect(err.Error()).To(Equal("failed stat file"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
 			})
 		})
 
-		When("failed read file", func() {
+		When("path is a directory", func() {
 			It("should return error", func() {
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
-					Return(true).
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(&fileInfo{dir: true}, nil).
 					Times(1)
 
-				file := &os.File{}
+				res, err := s.StatFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+
+		When("failed read metadata", func() {
+			It("should return error", func() {
 				fm.EXPECT().
-					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
-					Return(file, nil).
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(&fileInfo{size: 120, modTime: currentTime}, nil).
 					Times(1)
 
 				fm.EXPECT().
-					ReadFile(gomock.Eq(file)).
-					Return(nil, fmt.Errorf("failed read file")).
+					Open(gomock.Eq(cfg.StorageDir+"/.goseidon-meta-image.jpg.json")).
+					Return(nil, fmt.Errorf("i/o error")).
 					Times(1)
 
-				res, err := s.RetrieveFile(ctx, p)
+				res, err := s.StatFile(ctx, p)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("failed read file")))
+				Expect(err).To(Equal(fmt.Errorf("failed read file metadata")))
 			})
 		})
 
-		When("success retrieve file", func() {
+		When("success stat file", func() {
 			It("should return result", func() {
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
-					Return(true).
-					Times(1)
-
-				file := &os.File{}
-				fm.EXPECT().
-					Open(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
-					Return(file, nil).
+					Stat(gomock.Eq(cfg.StorageDir+"/"+p.Id)).
+					Return(&fileInfo{size: 120, modTime: currentTime}, nil).
 					Times(1)
 
-				binFile := make([]byte, 1)
 				fm.EXPECT().
-					ReadFile(gomock.Eq(file)).
-					Return(binFile, nil).
+					Open(gomock.Eq(cfg.StorageDir+"/.goseidon-meta-image.jpg.json")).
+					Return(nil, fs.ErrNotExist).
 					Times(1)
 
-				clo.EXPECT().Now().Return(currentTime)
-
-				res, err := s.RetrieveFile(ctx, p)
+				res, err := s.StatFile(ctx, p)
 
-				eRes := &goseidon.RetrieveFileResult{
-					File:        binFile,
-					RetrievedAt: currentTime,
+				eRes := &goseidon.StatFileResult{
+					Id:           p.Id,
+					Size:         120,
+					ContentType:  "image/jpeg",
+					LastModified: currentTime,
 				}
 				Expect(res).To(Equal(eRes))
 				Expect(err).To(BeNil())
 			})
 		})
 	})
-
-	Context("DeleteFile method", func() {
+	Context("ListFiles method", func() {
 		var (
 			ctx         context.Context
 			s           *local.LocalStorage
-			p           goseidon.DeleteFileParam
+			p           goseidon.ListFileParam
 			cfg         *local.LocalConfig
 			fm          *io.MockFileManager
-			clo         *clock.MockClock
 			currentTime time.Time
+			entries     []io.FileEntry
 		)
 
 		BeforeEach(func() {
 			ctx = context.Background()
-			p = goseidon.DeleteFileParam{
-				Id: "unique-access-id",
-			}
+			p = goseidon.ListFileParam{}
 			cfg = &local.LocalConfig{
 				StorageDir: "storage",
 			}
 			t := GinkgoT()
 			ctrl := gomock.NewController(t)
 			fm = io.NewMockFileManager(ctrl)
-			clo = clock.NewMockClock(ctrl)
 			currentTime = time.Now()
 			s = &local.LocalStorage{
 				Config: cfg,
 				Client: fm,
-				Clock:  clo,
+			}
+			entries = []io.FileEntry{
+				{Path: ".goseidon-meta-a.jpg.json", Size: 1, ModTime: currentTime},
+				{Path: "a.jpg", Size: 1, ModTime: currentTime},
+				{Path: "tenant-1/.goseidon-meta-a.jpg.json", Size: 1, ModTime: currentTime},
+				{Path: "tenant-1/a.jpg", Size: 2, ModTime: currentTime},
+				{Path: "tenant-1/b.jpg", Size: 3, ModTime: currentTime},
+				{Path: "tenant-1/sub/c.jpg", Size: 4, ModTime: currentTime},
+				{Path: "tenant-2/a.jpg", Size: 5, ModTime: currentTime},
 			}
 		})
 
 		When("context is invalid", func() {
 			It("should return error", func() {
-				res, err := s.DeleteFile(nil, p)
+				res, err := s.ListFiles(nil, p)
 
 				Expect(res).To(BeNil())
 				Expect(err.Error()).To(Equal("invalid context"))
 			})
 		})
 
-		When("file is not found", func() {
+		When("failed walk storage dir", func() {
 			It("should return error", func() {
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
-					Return(false).
+					WalkFiles(gomock.Eq(cfg.StorageDir)).
+					Return(nil, fmt.Errorf("access denied")).
 					Times(1)
 
-				res, err := s.DeleteFile(ctx, p)
+				res, err := s.ListFiles(ctx, p)
 
 				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("file is not found")))
+				Expect(err).To(Equal(fmt.Errorf("failed list files")))
 			})
 		})
 
-		When("failed remove file", func() {
-			It("should return error", func() {
+		When("prefix is specified", func() {
+			It("should return matching files", func() {
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
-					Return(true).
+					WalkFiles(gomock.Eq(cfg.StorageDir)).
+					Return(entries, nil).
 					Times(1)
 
+				p.Prefix = "tenant-1/"
+				res, err := s.ListFiles(ctx, p)
+
+				eRes := &goseidon.ListFileResult{
+					Files: []goseidon.ListFileItem{
+						{Id: "tenant-1/a.jpg", Size: 2, LastModified: currentTime},
+						{Id: "tenant-1/b.jpg", Size: 3, LastModified: currentTime},
+						{Id: "tenant-1/sub/c.jpg", Size: 4, LastModified: currentTime},
+					},
+					Prefixes: []string{},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("delimiter is specified", func() {
+			It("should group files into prefixes", func() {
 				fm.EXPECT().
-					RemoveFile(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
-					Return(fmt.Errorf("invalid permission")).
+					WalkFiles(gomock.Eq(cfg.StorageDir)).
+					Return(entries, nil).
 					Times(1)
 
-				res, err := s.DeleteFile(ctx, p)
+				p.Delimiter = "/"
+				res, err := s.ListFiles(ctx, p)
 
-				Expect(res).To(BeNil())
-				Expect(err).To(Equal(fmt.Errorf("failed delete file")))
+				eRes := &goseidon.ListFileResult{
+					Files: []goseidon.ListFileItem{
+						{Id: "a.jpg", Size: 1, LastModified: currentTime},
+					},
+					Prefixes: []string{"tenant-1/", "tenant-2/"},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
 			})
 		})
 
-		When("success remove file", func() {
-			It("should return result", func() {
+		When("result exceed page size", func() {
+			It("should return continuation token", func() {
 				fm.EXPECT().
-					IsExists(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
-					Return(true).
+					WalkFiles(gomock.Eq(cfg.StorageDir)).
+					Return(entries, nil).
 					Times(1)
 
+				p.Prefix = "tenant-1/"
+				p.Delimiter = "/"
+				p.PageSize = 2
+				res, err := s.ListFiles(ctx, p)
+
+				eRes := &goseidon.ListFileResult{
+					Files: []goseidon.ListFileItem{
+						{Id: "tenant-1/a.jpg", Size: 2, LastModified: currentTime},
+						{Id: "tenant-1/b.jpg", Size: 3, LastModified: currentTime},
+					},
+					Prefixes:              []string{},
+					NextContinuationToken: "tenant-1/b.jpg",
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("continuation token is specified", func() {
+			It("should return the next page", func() {
 				fm.EXPECT().
-					RemoveFile(gomock.Eq(cfg.StorageDir + "/" + p.Id)).
-					Return(nil).
+					WalkFiles(gomock.Eq(cfg.StorageDir)).
+					Return(entries, nil).
 					Times(1)
 
-				clo.EXPECT().Now().Return(currentTime).Times(1)
-
-				res, err := s.DeleteFile(ctx, p)
+				p.Delimiter = "/"
+				p.PageSize = 1
+				p.ContinuationToken = "tenant-1/"
+				res, err := s.ListFiles(ctx, p)
 
-				eRes := &goseidon.DeleteFileResult{
-					Id:        p.Id,
-					DeletedAt: currentTime,
+				eRes := &goseidon.ListFileResult{
+					Files:    []goseidon.ListFileItem{},
+					Prefixes: []string{"tenant-2/"},
 				}
 				Expect(res).To(Equal(eRes))
 				Expect(err).To(BeNil())
 			})
 		})
-
 	})
 })
 
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) Mode() fs.FileMode  { return 0644 }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.dir }
+func (fi *fileInfo) Sys() interface{}   { return nil }
+
 type withFailedOption struct {
 }
 
@@ -0,0 +1,285 @@
+package local
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// versionDir keep the noncurrent versions of every file, a version is stored
+// as versionDir/<file id>/<version id> next to its own metadata sidecar
+const versionDir = goseidon.ReservedKeyPrefix + "versions"
+
+// ListVersions list the current file followed by its noncurrent versions, newest first,
+// the continuation token is the last version id of the previous page
+func (s *LocalStorage) ListVersions(ctx context.Context, p goseidon.ListVersionsParam) (*goseidon.ListVersionsResult, error) {
+	if ctx == nil {
+		return nil, goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+
+	path, err := s.filePath(p.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := p.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	versions := []goseidon.FileVersion{}
+	current := ""
+	info, err := s.Client.Stat(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, wrapError(err, fmt.Errorf("failed stat file"))
+	}
+	if err == nil && !info.IsDir() {
+		meta, err := s.readMeta(path)
+		if err != nil {
+			return nil, wrapError(err, fmt.Errorf("failed read file metadata"))
+		}
+		current = meta.VersionId
+		if current == "" {
+			current = implicitVersionId(info.ModTime())
+		}
+		versions = append(versions, goseidon.FileVersion{
+			VersionId:    current,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+			IsLatest:     true,
+		})
+	}
+
+	archived, err := s.listArchived(p.Id)
+	if err != nil {
+		return nil, wrapError(err, fmt.Errorf("failed list versions"))
+	}
+	for _, version := range archived {
+		// left behind by an upload interrupted after archiving the current file
+		if version.VersionId == current {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	start := 0
+	if p.ContinuationToken != "" {
+		start = len(versions)
+		for i, version := range versions {
+			if version.IsLatest {
+				if version.VersionId == p.ContinuationToken {
+					start = i + 1
+					break
+				}
+				continue
+			}
+			// version ids sort chronologically, so a removed token doesn't stop the listing
+			if version.VersionId < p.ContinuationToken {
+				start = i
+				break
+			}
+		}
+	}
+	end := start + pageSize
+	if end > len(versions) {
+		end = len(versions)
+	}
+
+	res := &goseidon.ListVersionsResult{
+		Versions: append([]goseidon.FileVersion{}, versions[start:end]...),
+	}
+	if end < len(versions) {
+		res.NextContinuationToken = versions[end-1].VersionId
+	}
+	return res, nil
+}
+
+func (s *LocalStorage) versionPath(id, versionId string) string {
+	return s.Config.StorageDir + "/" + versionDir + "/" + id + "/" + versionId
+}
+
+// listArchived return the noncurrent versions of id, newest first
+func (s *LocalStorage) listArchived(id string) ([]goseidon.FileVersion, error) {
+	entries, err := s.Client.WalkFiles(s.Config.StorageDir + "/" + versionDir + "/" + id)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []goseidon.FileVersion{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		// sidecars and versions of nested file ids are skipped
+		if !isVersionId(entries[i].Path) {
+			continue
+		}
+		versions = append(versions, goseidon.FileVersion{
+			VersionId:    entries[i].Path,
+			Size:         entries[i].Size,
+			LastModified: entries[i].ModTime,
+		})
+	}
+	return versions, nil
+}
+
+// archive keep the current file as a noncurrent version, the file is linked
+// so it stays in place until it's replaced or removed
+func (s *LocalStorage) archive(id, path string) (string, error) {
+	meta, err := s.readMeta(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := s.Client.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if meta.VersionId == "" {
+		meta.VersionId = implicitVersionId(info.ModTime())
+	}
+
+	dst := s.versionPath(id, meta.VersionId)
+	err = s.createDir(filepath.Dir(dst))
+	if err != nil {
+		return "", err
+	}
+	err = s.Client.Link(path, dst)
+	if err != nil && !errors.Is(err, fs.ErrExist) {
+		return "", err
+	}
+
+	err = s.writeMeta(dst, *meta)
+	if err != nil {
+		return "", err
+	}
+	return meta.VersionId, nil
+}
+
+// versionFile resolve the path holding the given version,
+// either its archived copy or the current file
+func (s *LocalStorage) versionFile(id, path, versionId string) (string, error) {
+	if !isVersionId(versionId) {
+		return "", goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid version id"))
+	}
+
+	archived := s.versionPath(id, versionId)
+	if s.Client.IsExists(archived) {
+		return archived, nil
+	}
+
+	info, err := s.Client.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", goseidon.ErrNotFound
+	}
+	if err != nil {
+		return "", wrapError(err, fmt.Errorf("failed stat file"))
+	}
+	meta, err := s.readMeta(path)
+	if err != nil {
+		return "", wrapError(err, fmt.Errorf("failed read file metadata"))
+	}
+	current := meta.VersionId
+	if current == "" {
+		current = implicitVersionId(info.ModTime())
+	}
+	if current != versionId {
+		return "", goseidon.ErrNotFound
+	}
+	return path, nil
+}
+
+// deleteVersion remove a single version for good,
+// the latest noncurrent version take the place of a removed current file
+func (s *LocalStorage) deleteVersion(id, path, versionId string) error {
+	file, err := s.versionFile(id, path, versionId)
+	if err != nil {
+		return err
+	}
+
+	err = s.Client.RemoveFile(file)
+	if err != nil {
+		return wrapError(err, fmt.Errorf("failed delete file"))
+	}
+	err = s.Client.RemoveFile(metaPath(file))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return wrapError(err, fmt.Errorf("failed delete file metadata"))
+	}
+
+	if file == path {
+		restored, err := s.restoreLatest(id, path)
+		if err != nil {
+			return wrapError(err, fmt.Errorf("failed restore previous version"))
+		}
+		if !restored {
+			s.pruneDirs(id)
+		}
+	}
+	s.pruneVersionDirs(id)
+	return nil
+}
+
+// restoreLatest move the latest noncurrent version back into path
+func (s *LocalStorage) restoreLatest(id, path string) (bool, error) {
+	archived, err := s.listArchived(id)
+	if err != nil || len(archived) == 0 {
+		return false, err
+	}
+
+	src := s.versionPath(id, archived[0].VersionId)
+	meta, err := s.readMeta(src)
+	if err != nil {
+		return false, err
+	}
+	err = s.Client.Rename(src, path)
+	if err != nil {
+		return false, err
+	}
+	meta.VersionId = archived[0].VersionId
+	err = s.writeMeta(path, *meta)
+	if err != nil {
+		return true, err
+	}
+	err = s.Client.RemoveFile(metaPath(src))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return true, err
+	}
+	return true, nil
+}
+
+// pruneVersionDirs remove the version directories of id once they're empty
+func (s *LocalStorage) pruneVersionDirs(id string) {
+	for dir := id; dir != "."; dir = filepath.Dir(dir) {
+		err := s.Client.RemoveDir(s.Config.StorageDir + "/" + versionDir + "/" + dir)
+		if err != nil {
+			return
+		}
+	}
+	s.Client.RemoveDir(s.Config.StorageDir + "/" + versionDir)
+}
+
+// newVersionId start with the creation time so version ids sort chronologically
+func newVersionId(t time.Time) (string, error) {
+	b := make([]byte, 4)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%016x%s", t.UnixNano(), hex.EncodeToString(b)), nil
+}
+
+// implicitVersionId is the version id of a file stored before versioning was enabled
+func implicitVersionId(modTime time.Time) string {
+	return fmt.Sprintf("%016x%08x", modTime.UnixNano(), 0)
+}
+
+func isVersionId(versionId string) bool {
+	if len(versionId) != 24 {
+		return false
+	}
+	_, err := hex.DecodeString(versionId)
+	return err == nil
+}
@@ -0,0 +1,605 @@
+package local_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/internal/io"
+	"github.com/go-seidon/core/pkg/local"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Version", func() {
+	const (
+		path       = "storage/a.txt"
+		metaPath   = "storage/.goseidon-meta-a.txt.json"
+		versionDir = "storage/.goseidon-versions/a.txt"
+		v1         = "000000000000000100000000"
+		v2         = "000000000000000200000000"
+		v3         = "000000000000000300000000"
+	)
+
+	var (
+		ctx         context.Context
+		ctrl        *gomock.Controller
+		s           *local.LocalStorage
+		cfg         *local.LocalConfig
+		clo         *clock.MockClock
+		fm          *io.MockFileManager
+		metaFile    *io.MockFile
+		currentTime time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cfg = &local.LocalConfig{
+			StorageDir: "storage",
+			Versioning: true,
+		}
+		t := GinkgoT()
+		ctrl = gomock.NewController(t)
+		fm = io.NewMockFileManager(ctrl)
+		metaFile = io.NewMockFile(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Now()
+		s = &local.LocalStorage{
+			Config: cfg,
+			Client: fm,
+			Clock:  clo,
+		}
+	})
+
+	expectReadMeta := func(metaPath, data string) {
+		fm.EXPECT().
+			Open(gomock.Eq(metaPath)).
+			Return(metaFile, nil).
+			Times(1)
+		fm.EXPECT().
+			ReadFile(gomock.Eq(metaFile)).
+			Return([]byte(data), nil).
+			Times(1)
+		metaFile.EXPECT().Close().Return(nil).Times(1)
+	}
+
+	// expectWriteMeta expect a sidecar written into a temp file then renamed into target,
+	// an empty data match any content
+	expectWriteMeta := func(dir, tmpPath, target, data string) {
+		tmpFile := io.NewMockFile(ctrl)
+		fm.EXPECT().
+			CreateTemp(gomock.Eq(dir), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+			Return(tmpFile, nil).
+			Times(1)
+		tmpFile.EXPECT().Name().Return(tmpPath).Times(1)
+		content := gomock.Any()
+		if data != "" {
+			content = gomock.Eq(bytes.NewReader([]byte(data)))
+		}
+		fm.EXPECT().
+			CopyContext(gomock.Any(), gomock.Eq(tmpFile), content).
+			Return(int64(len(data)), nil).
+			Times(1)
+		tmpFile.EXPECT().Sync().Return(nil).Times(1)
+		tmpFile.EXPECT().Close().Return(nil).Times(1)
+		fm.EXPECT().
+			Rename(gomock.Eq(tmpPath), gomock.Eq(target)).
+			Return(nil).
+			Times(1)
+	}
+
+	// expectArchive expect the current file to be linked as version v3
+	expectArchive := func() {
+		expectReadMeta(metaPath, `{"version_id":"`+v3+`"}`)
+		fm.EXPECT().
+			Stat(gomock.Eq(path)).
+			Return(&fileInfo{size: 3, modTime: currentTime}, nil).
+			Times(1)
+		fm.EXPECT().IsExists(gomock.Eq(versionDir)).Return(true).Times(1)
+		fm.EXPECT().
+			Link(gomock.Eq(path), gomock.Eq(versionDir+"/"+v3)).
+			Return(nil).
+			Times(1)
+		expectWriteMeta(versionDir, versionDir+"/.goseidon-tmp-3", versionDir+"/.goseidon-meta-"+v3+".json", `{"version_id":"`+v3+`"}`)
+	}
+
+	Context("ListVersions method", func() {
+		var p goseidon.ListVersionsParam
+
+		BeforeEach(func() {
+			p = goseidon.ListVersionsParam{
+				Id:       "a.txt",
+				PageSize: 2,
+			}
+		})
+
+		expectVersions := func() {
+			fm.EXPECT().
+				Stat(gomock.Eq(path)).
+				Return(&fileInfo{size: 3, modTime: currentTime}, nil).
+				Times(1)
+			fm.EXPECT().
+				Open(gomock.Eq(metaPath)).
+				Return(metaFile, nil).
+				Times(1)
+			fm.EXPECT().
+				ReadFile(gomock.Eq(metaFile)).
+				Return([]byte(`{"version_id":"`+v3+`"}`), nil).
+				Times(1)
+			metaFile.EXPECT().Close().Return(nil).Times(1)
+			fm.EXPECT().
+				WalkFiles(gomock.Eq(versionDir)).
+				Return([]io.FileEntry{
+					{Path: ".goseidon-meta-" + v1 + ".json", Size: 2},
+					{Path: ".goseidon-meta-" + v2 + ".json", Size: 2},
+					{Path: v1, Size: 1, ModTime: currentTime.Add(-2 * time.Hour)},
+					{Path: v2, Size: 2, ModTime: currentTime.Add(-time.Hour)},
+				}, nil).
+				Times(1)
+		}
+
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := s.ListVersions(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("failed stat file", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					Stat(gomock.Eq(path)).
+					Return(nil, fs.ErrPermission).
+					Times(1)
+
+				res, err := s.ListVersions(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("file has noncurrent versions", func() {
+			It("should return the first page", func() {
+				expectVersions()
+
+				res, err := s.ListVersions(ctx, p)
+
+				eRes := &goseidon.ListVersionsResult{
+					Versions: []goseidon.FileVersion{
+						{VersionId: v3, Size: 3, LastModified: currentTime, IsLatest: true},
+						{VersionId: v2, Size: 2, LastModified: currentTime.Add(-time.Hour)},
+					},
+					NextContinuationToken: v2,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("continuation token is given", func() {
+			It("should return the versions older than the token", func() {
+				p.ContinuationToken = v2
+				expectVersions()
+
+				res, err := s.ListVersions(ctx, p)
+
+				eRes := &goseidon.ListVersionsResult{
+					Versions: []goseidon.FileVersion{
+						{VersionId: v1, Size: 1, LastModified: currentTime.Add(-2 * time.Hour)},
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("file is deleted", func() {
+			It("should return the noncurrent versions only", func() {
+				fm.EXPECT().
+					Stat(gomock.Eq(path)).
+					Return(nil, fs.ErrNotExist).
+					Times(1)
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(versionDir)).
+					Return([]io.FileEntry{{Path: v1, Size: 1}}, nil).
+					Times(1)
+
+				res, err := s.ListVersions(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.Versions).To(Equal([]goseidon.FileVersion{{VersionId: v1, Size: 1}}))
+			})
+		})
+	})
+
+	Context("UploadStream method", func() {
+		var p goseidon.UploadStreamParam
+
+		BeforeEach(func() {
+			p = goseidon.UploadStreamParam{
+				FileData:  strings.NewReader("abc"),
+				FileId:    "a.txt",
+				Overwrite: goseidon.OverwriteReplace,
+			}
+		})
+
+		expectWriteTemp := func() {
+			dataFile := io.NewMockFile(ctrl)
+			fm.EXPECT().IsExists(gomock.Eq("storage")).Return(true).Times(1)
+			fm.EXPECT().
+				CreateTemp(gomock.Eq("storage"), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+				Return(dataFile, nil).
+				Times(1)
+			dataFile.EXPECT().Name().Return("storage/.goseidon-tmp-1").Times(1)
+			fm.EXPECT().
+				CopyContext(gomock.Any(), gomock.Eq(dataFile), gomock.Any()).
+				DoAndReturn(copyAll).
+				Times(1)
+			dataFile.EXPECT().Sync().Return(nil).Times(1)
+			dataFile.EXPECT().Close().Return(nil).Times(1)
+		}
+
+		When("file is replaced", func() {
+			It("should archive the previous version", func() {
+				expectWriteTemp()
+				clo.EXPECT().Now().Return(currentTime).Times(2)
+				metaTmpFile := io.NewMockFile(ctrl)
+				fm.EXPECT().
+					CreateTemp(gomock.Eq("storage"), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(metaTmpFile, nil).
+					Times(1)
+				metaTmpFile.EXPECT().Name().Return("storage/.goseidon-tmp-2").Times(1)
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(metaTmpFile), gomock.Any()).
+					Return(int64(0), nil).
+					Times(1)
+				metaTmpFile.EXPECT().Sync().Return(nil).Times(1)
+				metaTmpFile.EXPECT().Close().Return(nil).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(path)).Return(true).Times(1)
+				expectArchive()
+				fm.EXPECT().RemoveFile(gomock.Eq(metaPath)).Return(nil).Times(1)
+				fm.EXPECT().
+					Rename(gomock.Eq("storage/.goseidon-tmp-1"), gomock.Eq(path)).
+					Return(nil).
+					Times(1)
+				fm.EXPECT().
+					Rename(gomock.Eq("storage/.goseidon-tmp-2"), gomock.Eq(metaPath)).
+					Return(nil).
+					Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(res.VersionId).To(HaveLen(24))
+				Expect(res.VersionId).To(HavePrefix(fmt.Sprintf("%016x", currentTime.UnixNano())))
+				Expect(res.VersionId > v3).To(BeTrue())
+			})
+		})
+
+		When("failed archive the previous version", func() {
+			It("should drop the upload", func() {
+				expectWriteTemp()
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+				metaTmpFile := io.NewMockFile(ctrl)
+				fm.EXPECT().
+					CreateTemp(gomock.Eq("storage"), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(metaTmpFile, nil).
+					Times(1)
+				metaTmpFile.EXPECT().Name().Return("storage/.goseidon-tmp-2").Times(1)
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(metaTmpFile), gomock.Any()).
+					Return(int64(0), nil).
+					Times(1)
+				metaTmpFile.EXPECT().Sync().Return(nil).Times(1)
+				metaTmpFile.EXPECT().Close().Return(nil).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(path)).Return(true).Times(1)
+				expectReadMeta(metaPath, `{"version_id":"`+v3+`"}`)
+				fm.EXPECT().
+					Stat(gomock.Eq(path)).
+					Return(&fileInfo{size: 3, modTime: currentTime}, nil).
+					Times(1)
+				fm.EXPECT().IsExists(gomock.Eq(versionDir)).Return(true).Times(1)
+				fm.EXPECT().
+					Link(gomock.Eq(path), gomock.Eq(versionDir+"/"+v3)).
+					Return(fs.ErrPermission).
+					Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq("storage/.goseidon-tmp-1")).Return(nil).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq("storage/.goseidon-tmp-2")).Return(nil).Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed archive file"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("RetrieveStream method", func() {
+		When("version id is invalid", func() {
+			It("should return error", func() {
+				res, err := s.RetrieveStream(ctx, goseidon.RetrieveFileParam{Id: "a.txt", VersionId: "v1"})
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid version id"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("version is not found", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(versionDir + "/" + v1)).
+					Return(false).
+					Times(1)
+				fm.EXPECT().
+					Stat(gomock.Eq(path)).
+					Return(&fileInfo{size: 3, modTime: currentTime}, nil).
+					Times(1)
+				fm.EXPECT().
+					Open(gomock.Eq(metaPath)).
+					Return(metaFile, nil).
+					Times(1)
+				fm.EXPECT().
+					ReadFile(gomock.Eq(metaFile)).
+					Return([]byte(`{"version_id":"`+v3+`"}`), nil).
+					Times(1)
+				metaFile.EXPECT().Close().Return(nil).Times(1)
+
+				res, err := s.RetrieveStream(ctx, goseidon.RetrieveFileParam{Id: "a.txt", VersionId: v1})
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+	})
+
+	Context("DeleteFile method", func() {
+		When("noncurrent version is given", func() {
+			It("should remove the version for good", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(versionDir + "/" + v1)).
+					Return(true).
+					Times(1)
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(versionDir + "/" + v1)).
+					Return(nil).
+					Times(1)
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(versionDir + "/.goseidon-meta-" + v1 + ".json")).
+					Return(nil).
+					Times(1)
+				fm.EXPECT().
+					RemoveDir(gomock.Eq(versionDir)).
+					Return(fmt.Errorf("directory not empty")).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt", VersionId: v1})
+
+				eRes := &goseidon.DeleteFileResult{
+					Id:        "a.txt",
+					DeletedAt: currentTime,
+					VersionId: v1,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed remove version", func() {
+			It("should return error", func() {
+				fm.EXPECT().
+					IsExists(gomock.Eq(versionDir + "/" + v1)).
+					Return(true).
+					Times(1)
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(versionDir + "/" + v1)).
+					Return(fs.ErrPermission).
+					Times(1)
+
+				res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt", VersionId: v1})
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed delete file"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("current file is deleted", func() {
+			It("should archive it", func() {
+				fm.EXPECT().Stat(gomock.Eq(path)).Return(&fileInfo{size: 3}, nil).Times(1)
+				expectArchive()
+				fm.EXPECT().RemoveFile(gomock.Eq(path)).Return(nil).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq(metaPath)).Return(nil).Times(1)
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt"})
+
+				eRes := &goseidon.DeleteFileResult{
+					Id:        "a.txt",
+					DeletedAt: currentTime,
+					VersionId: v3,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("current version is removed", func() {
+			It("should restore the latest noncurrent version", func() {
+				fm.EXPECT().IsExists(gomock.Eq(versionDir + "/" + v3)).Return(false).Times(1)
+				fm.EXPECT().
+					Stat(gomock.Eq(path)).
+					Return(&fileInfo{size: 3, modTime: currentTime}, nil).
+					Times(1)
+				expectReadMeta(metaPath, `{"version_id":"`+v3+`"}`)
+				fm.EXPECT().RemoveFile(gomock.Eq(path)).Return(nil).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq(metaPath)).Return(nil).Times(1)
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(versionDir)).
+					Return([]io.FileEntry{
+						{Path: ".goseidon-meta-" + v1 + ".json"},
+						{Path: ".goseidon-meta-" + v2 + ".json"},
+						{Path: v1},
+						{Path: v2},
+					}, nil).
+					Times(1)
+				expectReadMeta(versionDir+"/.goseidon-meta-"+v2+".json", `{"file_name":"a.txt"}`)
+				fm.EXPECT().
+					Rename(gomock.Eq(versionDir+"/"+v2), gomock.Eq(path)).
+					Return(nil).
+					Times(1)
+				expectWriteMeta("storage", "storage/.goseidon-tmp-1", metaPath, `{"file_name":"a.txt","version_id":"`+v2+`"}`)
+				fm.EXPECT().
+					RemoveFile(gomock.Eq(versionDir + "/.goseidon-meta-" + v2 + ".json")).
+					Return(nil).
+					Times(1)
+				fm.EXPECT().
+					RemoveDir(gomock.Eq(versionDir)).
+					Return(fmt.Errorf("directory not empty")).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt", VersionId: v3})
+
+				Expect(err).To(BeNil())
+				Expect(res.VersionId).To(Equal(v3))
+			})
+		})
+
+		When("failed list noncurrent versions to restore", func() {
+			It("should return error", func() {
+				fm.EXPECT().IsExists(gomock.Eq(versionDir + "/" + v3)).Return(false).Times(1)
+				fm.EXPECT().
+					Stat(gomock.Eq(path)).
+					Return(&fileInfo{size: 3, modTime: currentTime}, nil).
+					Times(1)
+				expectReadMeta(metaPath, `{"version_id":"`+v3+`"}`)
+				fm.EXPECT().RemoveFile(gomock.Eq(path)).Return(nil).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq(metaPath)).Return(nil).Times(1)
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(versionDir)).
+					Return(nil, fs.ErrPermission).
+					Times(1)
+
+				res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt", VersionId: v3})
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed restore previous version"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("failed move the noncurrent version back", func() {
+			It("should return error", func() {
+				fm.EXPECT().IsExists(gomock.Eq(versionDir + "/" + v3)).Return(false).Times(1)
+				fm.EXPECT().
+					Stat(gomock.Eq(path)).
+					Return(&fileInfo{size: 3, modTime: currentTime}, nil).
+					Times(1)
+				expectReadMeta(metaPath, `{"version_id":"`+v3+`"}`)
+				fm.EXPECT().RemoveFile(gomock.Eq(path)).Return(nil).Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq(metaPath)).Return(nil).Times(1)
+				fm.EXPECT().
+					WalkFiles(gomock.Eq(versionDir)).
+					Return([]io.FileEntry{{Path: v2}}, nil).
+					Times(1)
+				expectReadMeta(versionDir+"/.goseidon-meta-"+v2+".json", `{}`)
+				fm.EXPECT().
+					Rename(gomock.Eq(versionDir+"/"+v2), gomock.Eq(path)).
+					Return(fs.ErrPermission).
+					Times(1)
+
+				res, err := s.DeleteFile(ctx, goseidon.DeleteFileParam{Id: "a.txt", VersionId: v3})
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed restore previous version"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("MoveFile method", func() {
+		var p goseidon.MoveFileParam
+
+		BeforeEach(func() {
+			p = goseidon.MoveFileParam{
+				SourceId:      "a.txt",
+				DestinationId: "b.txt",
+			}
+		})
+
+		When("file is moved", func() {
+			It("should archive the source", func() {
+				fm.EXPECT().Stat(gomock.Eq(path)).Return(&fileInfo{size: 3}, nil).Times(1)
+				expectReadMeta(metaPath, `{"version_id":"`+v3+`"}`)
+				fm.EXPECT().IsExists(gomock.Eq("storage")).Return(true).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq("storage/b.txt")).Return(false).Times(1)
+				expectArchive()
+				fm.EXPECT().
+					Link(gomock.Eq(path), gomock.Eq("storage/b.txt")).
+					Return(nil).
+					Times(1)
+				fm.EXPECT().RemoveFile(gomock.Eq(path)).Return(nil).Times(1)
+				expectWriteMeta("storage", "storage/.goseidon-tmp-1", "storage/.goseidon-meta-b.txt.json", `{"version_id":"`+v3+`"}`)
+				fm.EXPECT().RemoveFile(gomock.Eq(metaPath)).Return(nil).Times(1)
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				res, err := s.MoveFile(ctx, p)
+
+				eRes := &goseidon.MoveFileResult{
+					SourceId:      p.SourceId,
+					DestinationId: p.DestinationId,
+					MovedAt:       currentTime,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("failed archive the source", func() {
+			It("should keep the source in place", func() {
+				fm.EXPECT().Stat(gomock.Eq(path)).Return(&fileInfo{size: 3}, nil).Times(1)
+				expectReadMeta(metaPath, `{"version_id":"`+v3+`"}`)
+				fm.EXPECT().IsExists(gomock.Eq("storage")).Return(true).Times(1)
+				fm.EXPECT().IsExists(gomock.Eq("storage/b.txt")).Return(false).Times(1)
+				tmpFile := io.NewMockFile(ctrl)
+				fm.EXPECT().
+					CreateTemp(gomock.Eq("storage"), gomock.Eq(".goseidon-tmp-*"), gomock.Eq(fs.FileMode(0644))).
+					Return(tmpFile, nil).
+					Times(1)
+				tmpFile.EXPECT().Name().Return("storage/.goseidon-tmp-1").Times(1)
+				fm.EXPECT().
+					CopyContext(gomock.Any(), gomock.Eq(tmpFile), gomock.Any()).
+					Return(int64(0), nil).
+					Times(1)
+				tmpFile.EXPECT().Sync().Return(nil).Times(1)
+				tmpFile.EXPECT().Close().Return(nil).Times(1)
+				expectReadMeta(metaPath, `{"version_id":"`+v3+`"}`)
+				fm.EXPECT().
+					Stat(gomock.Eq(path)).
+					Return(nil, fs.ErrPermission).
+					Times(1)
+				// the destination sidecar written ahead is dropped
+				fm.EXPECT().RemoveFile(gomock.Eq("storage/.goseidon-tmp-1")).Return(nil).Times(1)
+
+				res, err := s.MoveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("failed archive file"))
+				Expect(errors.Is(err, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+	})
+})
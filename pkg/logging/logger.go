@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Event describe a finished storage call, ErrorClass and Error are empty on success.
+// Bytes is the size of the uploaded or retrieved content, 0 when it's unknown or
+// nothing was stored. with redaction the ids within the text of Error are redacted
+type Event struct {
+	Operation  string
+	Backend    string
+	FileId     string
+	Bytes      int64
+	Duration   time.Duration
+	ErrorClass string
+	Error      error
+}
+
+// Logger receive an event after every storage call
+type Logger interface {
+	Log(ctx context.Context, e Event)
+}
+
+// SlogLogger is the subset of *slog.Logger used by the slog adapter,
+// any logger with the same methods can be given
+type SlogLogger interface {
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+type slogLogger struct {
+	logger SlogLogger
+}
+
+func (l *slogLogger) Log(ctx context.Context, e Event) {
+	args := []interface{}{
+		"op", e.Operation,
+		"backend", e.Backend,
+		"id", e.FileId,
+		"bytes", e.Bytes,
+		"duration", e.Duration,
+	}
+	if e.Error == nil {
+		l.logger.InfoContext(ctx, eventMessage, args...)
+		return
+	}
+	args = append(args, "error_class", e.ErrorClass, "error", e.Error.Error())
+	l.logger.ErrorContext(ctx, eventMessage, args...)
+}
+
+// NewSlogLogger log events as key value pairs, failed calls are logged at error level
+func NewSlogLogger(logger SlogLogger) Logger {
+	return &slogLogger{
+		logger: logger,
+	}
+}
+
+type stdLogger struct {
+	logger *log.Logger
+}
+
+func (l *stdLogger) Log(ctx context.Context, e Event) {
+	b := &strings.Builder{}
+	b.WriteString(eventMessage)
+	fmt.Fprintf(b, " op=%s backend=%q id=%q bytes=%d duration=%s", e.Operation, e.Backend, e.FileId, e.Bytes, e.Duration)
+	if e.Error != nil {
+		fmt.Fprintf(b, " error_class=%s error=%q", e.ErrorClass, e.Error.Error())
+	}
+	l.logger.Print(b.String())
+}
+
+// NewStdLogger log events as a single logfmt line, nil logger use the standard logger
+func NewStdLogger(logger *log.Logger) Logger {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &stdLogger{
+		logger: logger,
+	}
+}
+
+const eventMessage = "storage call"
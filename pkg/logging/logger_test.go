@@ -0,0 +1,128 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-seidon/core/pkg/logging"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type slogCall struct {
+	level string
+	msg   string
+	args  []interface{}
+}
+
+type fakeSlog struct {
+	calls []slogCall
+}
+
+func (l *fakeSlog) InfoContext(ctx context.Context, msg string, args ...interface{}) {
+	l.calls = append(l.calls, slogCall{level: "info", msg: msg, args: args})
+}
+
+func (l *fakeSlog) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	l.calls = append(l.calls, slogCall{level: "error", msg: msg, args: args})
+}
+
+var _ = Describe("Logger", func() {
+	var (
+		ctx context.Context
+		e   logging.Event
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		e = logging.Event{
+			Operation: logging.OperationUpload,
+			Backend:   "local",
+			FileId:    "a.txt",
+			Bytes:     5,
+			Duration:  2 * time.Millisecond,
+		}
+	})
+
+	Context("NewStdLogger function", func() {
+		var (
+			buf *bytes.Buffer
+			lo  logging.Logger
+		)
+
+		BeforeEach(func() {
+			buf = &bytes.Buffer{}
+			lo = logging.NewStdLogger(log.New(buf, "", 0))
+		})
+
+		When("call is success", func() {
+			It("should write a single line", func() {
+				lo.Log(ctx, e)
+
+				Expect(buf.String()).To(Equal(`storage call op=upload backend="local" id="a.txt" bytes=5 duration=2ms` + "\n"))
+			})
+		})
+
+		When("call is failed", func() {
+			It("should write the error", func() {
+				e.ErrorClass = "transient"
+				e.Error = fmt.Errorf("slow down")
+
+				lo.Log(ctx, e)
+
+				Expect(buf.String()).To(Equal(`storage call op=upload backend="local" id="a.txt" bytes=5 duration=2ms error_class=transient error="slow down"` + "\n"))
+			})
+		})
+	})
+
+	Context("NewSlogLogger function", func() {
+		var (
+			sl *fakeSlog
+			lo logging.Logger
+		)
+
+		BeforeEach(func() {
+			sl = &fakeSlog{}
+			lo = logging.NewSlogLogger(sl)
+		})
+
+		When("call is success", func() {
+			It("should log at info level", func() {
+				lo.Log(ctx, e)
+
+				Expect(sl.calls).To(Equal([]slogCall{
+					{
+						level: "info",
+						msg:   "storage call",
+						args: []interface{}{
+							"op", "upload",
+							"backend", "local",
+							"id", "a.txt",
+							"bytes", int64(5),
+							"duration", 2 * time.Millisecond,
+						},
+					},
+				}))
+			})
+		})
+
+		When("call is failed", func() {
+			It("should log at error level", func() {
+				e.ErrorClass = "transient"
+				e.Error = fmt.Errorf("slow down")
+
+				lo.Log(ctx, e)
+
+				Expect(sl.calls).To(HaveLen(1))
+				Expect(sl.calls[0].level).To(Equal("error"))
+				Expect(sl.calls[0].args[10:]).To(Equal([]interface{}{
+					"error_class", "transient",
+					"error", "slow down",
+				}))
+			})
+		})
+	})
+})
@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// LoggingConfig hold the logger and how file ids are shown,
+// nil Redact log the file ids as they are
+type LoggingConfig struct {
+	Logger  Logger
+	Backend string
+	Redact  func(id string) string
+}
+
+type LoggingStorageOption interface {
+	Apply(c *LoggingConfig) error
+}
+
+type withLogger struct {
+	logger Logger
+}
+
+func (o *withLogger) Apply(c *LoggingConfig) error {
+	if o.logger == nil {
+		return fmt.Errorf("invalid logger")
+	}
+	c.Logger = o.logger
+	return nil
+}
+
+// WithLogger send the events to logger instead of the standard logger
+func WithLogger(logger Logger) LoggingStorageOption {
+	return &withLogger{
+		logger: logger,
+	}
+}
+
+type withBackend struct {
+	backend string
+}
+
+func (o *withBackend) Apply(c *LoggingConfig) error {
+	if o.backend == "" {
+		return fmt.Errorf("invalid backend")
+	}
+	c.Backend = o.backend
+	return nil
+}
+
+// WithBackend name the wrapped storage in every event, e.g. "s3" or "local"
+func WithBackend(backend string) LoggingStorageOption {
+	return &withBackend{
+		backend: backend,
+	}
+}
+
+type withRedaction struct {
+	redact func(id string) string
+}
+
+func (o *withRedaction) Apply(c *LoggingConfig) error {
+	if o.redact == nil {
+		return fmt.Errorf("invalid redaction")
+	}
+	c.Redact = o.redact
+	return nil
+}
+
+// WithRedaction replace every file id by redact(id) before it's logged,
+// in the event id as well as within the error text. see RedactHash and RedactAll
+func WithRedaction(redact func(id string) string) LoggingStorageOption {
+	return &withRedaction{
+		redact: redact,
+	}
+}
+
+// RedactHash replace the id by the start of its sha256 digest,
+// events of the same file can still be correlated
+func RedactHash(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return "sha256:" + hex.EncodeToString(sum[:6])
+}
+
+// RedactAll hide the id entirely
+func RedactAll(id string) string {
+	return "[redacted]"
+}
@@ -0,0 +1,54 @@
+package logging_test
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/core/pkg/logging"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logging Option", func() {
+	var (
+		cfg *logging.LoggingConfig
+	)
+
+	BeforeEach(func() {
+		cfg = &logging.LoggingConfig{}
+	})
+
+	DescribeTable("invalid option",
+		func(opt logging.LoggingStorageOption, eErr error) {
+			err := opt.Apply(cfg)
+
+			Expect(err).To(Equal(eErr))
+		},
+		Entry("logger", logging.WithLogger(nil), fmt.Errorf("invalid logger")),
+		Entry("backend", logging.WithBackend(""), fmt.Errorf("invalid backend")),
+		Entry("redaction", logging.WithRedaction(nil), fmt.Errorf("invalid redaction")),
+	)
+
+	When("redaction is given", func() {
+		It("should redact the id", func() {
+			err := logging.WithRedaction(logging.RedactHash).Apply(cfg)
+
+			Expect(err).To(BeNil())
+			Expect(cfg.Redact("a.txt")).To(Equal(logging.RedactHash("a.txt")))
+		})
+	})
+
+	Context("RedactHash function", func() {
+		It("should hide the id", func() {
+			id := logging.RedactHash("secret/a.txt")
+
+			Expect(id).To(HavePrefix("sha256:"))
+			Expect(id).To(HaveLen(len("sha256:") + 12))
+			Expect(id).ToNot(ContainSubstring("secret"))
+		})
+
+		It("should be stable", func() {
+			Expect(logging.RedactHash("a.txt")).To(Equal(logging.RedactHash("a.txt")))
+			Expect(logging.RedactHash("a.txt")).ToNot(Equal(logging.RedactHash("b.txt")))
+		})
+	})
+})
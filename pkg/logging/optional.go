@@ -0,0 +1,203 @@
+package logging
+
+import (
+	"context"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// an optional call is logged under its own Operation with the id it's about,
+// "src -> dst" for a copy or move and the prefix for a listing. only the calls
+// moving data log a size, a call the storage can't serve isn't logged
+
+// UploadStream log the given FileSize of a stored file, an unknown size is logged as 0
+func (s *LoggingStorage) UploadStream(ctx context.Context, p goseidon.UploadStreamParam) (*goseidon.UploadFileResult, error) {
+	storage, ok := s.Storage.(goseidon.StreamUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("stream upload")
+	}
+	start := s.Clock.Now()
+	res, err := storage.UploadStream(ctx, p)
+	size := int64(0)
+	if err == nil && res != nil && !res.Skipped && p.FileSize > 0 {
+		size = p.FileSize
+	}
+	s.log(ctx, OperationUploadStream, []string{p.FileId}, size, start, err)
+	return res, err
+}
+
+// RetrieveStream log once the stream is opened, with the length it's going to return
+func (s *LoggingStorage) RetrieveStream(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveStreamResult, error) {
+	storage, ok := s.Storage.(goseidon.StreamRetriever)
+	if !ok {
+		return nil, goseidon.Unsupported("stream retrieve")
+	}
+	start := s.Clock.Now()
+	res, err := storage.RetrieveStream(ctx, p)
+	size := int64(0)
+	if res != nil {
+		size = res.Length
+	}
+	s.log(ctx, OperationRetrieveStream, []string{p.Id}, size, start, err)
+	return res, err
+}
+
+func (s *LoggingStorage) StatFile(ctx context.Context, p goseidon.StatFileParam) (*goseidon.StatFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Stater)
+	if !ok {
+		return nil, goseidon.Unsupported("stat")
+	}
+	start := s.Clock.Now()
+	res, err := storage.StatFile(ctx, p)
+	s.log(ctx, OperationStat, []string{p.Id}, 0, start, err)
+	return res, err
+}
+
+// ListFiles log the listed prefix as the file id
+func (s *LoggingStorage) ListFiles(ctx context.Context, p goseidon.ListFileParam) (*goseidon.ListFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Lister)
+	if !ok {
+		return nil, goseidon.Unsupported("list")
+	}
+	start := s.Clock.Now()
+	res, err := storage.ListFiles(ctx, p)
+	s.log(ctx, OperationList, []string{p.Prefix}, 0, start, err)
+	return res, err
+}
+
+func (s *LoggingStorage) SignURL(ctx context.Context, p goseidon.SignURLParam) (*goseidon.SignURLResult, error) {
+	storage, ok := s.Storage.(goseidon.URLSigner)
+	if !ok {
+		return nil, goseidon.Unsupported("sign url")
+	}
+	start := s.Clock.Now()
+	res, err := storage.SignURL(ctx, p)
+	s.log(ctx, OperationSignURL, []string{p.Id}, 0, start, err)
+	return res, err
+}
+
+func (s *LoggingStorage) CreateMultipartUpload(ctx context.Context, p goseidon.CreateMultipartParam) (*goseidon.CreateMultipartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	start := s.Clock.Now()
+	res, err := storage.CreateMultipartUpload(ctx, p)
+	s.log(ctx, OperationCreateMultipart, []string{p.FileId}, 0, start, err)
+	return res, err
+}
+
+// UploadPart log the given PartSize, an unknown size is logged as 0
+func (s *LoggingStorage) UploadPart(ctx context.Context, p goseidon.UploadPartParam) (*goseidon.UploadPartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	start := s.Clock.Now()
+	res, err := storage.UploadPart(ctx, p)
+	size := p.PartSize
+	if size < 0 {
+		size = 0
+	}
+	s.log(ctx, OperationUploadPart, []string{p.FileId}, size, start, err)
+	return res, err
+}
+
+func (s *LoggingStorage) CompleteMultipartUpload(ctx context.Context, p goseidon.CompleteMultipartParam) (*goseidon.UploadFileResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	start := s.Clock.Now()
+	res, err := storage.CompleteMultipartUpload(ctx, p)
+	s.log(ctx, OperationCompleteMultipart, []string{p.FileId}, 0, start, err)
+	return res, err
+}
+
+func (s *LoggingStorage) AbortMultipartUpload(ctx context.Context, p goseidon.AbortMultipartParam) (*goseidon.AbortMultipartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	start := s.Clock.Now()
+	res, err := storage.AbortMultipartUpload(ctx, p)
+	s.log(ctx, OperationAbortMultipart, []string{p.FileId}, 0, start, err)
+	return res, err
+}
+
+func (s *LoggingStorage) ListParts(ctx context.Context, p goseidon.ListPartsParam) (*goseidon.ListPartsResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	start := s.Clock.Now()
+	res, err := storage.ListParts(ctx, p)
+	s.log(ctx, OperationListParts, []string{p.FileId}, 0, start, err)
+	return res, err
+}
+
+// CopyFile log both ids as "source -> destination"
+func (s *LoggingStorage) CopyFile(ctx context.Context, p goseidon.CopyFileParam) (*goseidon.CopyFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Copier)
+	if !ok {
+		return nil, goseidon.Unsupported("copy")
+	}
+	start := s.Clock.Now()
+	res, err := storage.CopyFile(ctx, p)
+	s.log(ctx, OperationCopy, []string{p.SourceId, p.DestinationId}, 0, start, err)
+	return res, err
+}
+
+// MoveFile log both ids as "source -> destination"
+func (s *LoggingStorage) MoveFile(ctx context.Context, p goseidon.MoveFileParam) (*goseidon.MoveFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Mover)
+	if !ok {
+		return nil, goseidon.Unsupported("move")
+	}
+	start := s.Clock.Now()
+	res, err := storage.MoveFile(ctx, p)
+	s.log(ctx, OperationMove, []string{p.SourceId, p.DestinationId}, 0, start, err)
+	return res, err
+}
+
+// DeleteFiles log an event per id carrying its own error, timed from the start of
+// the batch. a batch failed as a whole log a single event without id
+func (s *LoggingStorage) DeleteFiles(ctx context.Context, p goseidon.DeleteFilesParam) (*goseidon.DeleteFilesResult, error) {
+	storage, ok := s.Storage.(goseidon.BatchDeleter)
+	if !ok {
+		return nil, goseidon.Unsupported("batch delete")
+	}
+	start := s.Clock.Now()
+	res, err := storage.DeleteFiles(ctx, p)
+	if err != nil || res == nil {
+		s.log(ctx, OperationDeleteFiles, nil, 0, start, s.redactError(err, p.Ids))
+		return res, err
+	}
+	for _, item := range res.Items {
+		s.log(ctx, OperationDeleteFiles, []string{item.Id}, 0, start, item.Error)
+	}
+	return res, err
+}
+
+// DeletePrefix log the prefix as the file id, along with the error of an interrupted delete
+func (s *LoggingStorage) DeletePrefix(ctx context.Context, p goseidon.DeletePrefixParam) (*goseidon.DeletePrefixResult, error) {
+	storage, ok := s.Storage.(goseidon.PrefixDeleter)
+	if !ok {
+		return nil, goseidon.Unsupported("prefix delete")
+	}
+	start := s.Clock.Now()
+	res, err := storage.DeletePrefix(ctx, p)
+	s.log(ctx, OperationDeletePrefix, []string{p.Prefix}, 0, start, err)
+	return res, err
+}
+
+func (s *LoggingStorage) ListVersions(ctx context.Context, p goseidon.ListVersionsParam) (*goseidon.ListVersionsResult, error) {
+	storage, ok := s.Storage.(goseidon.VersionLister)
+	if !ok {
+		return nil, goseidon.Unsupported("list versions")
+	}
+	start := s.Clock.Now()
+	res, err := storage.ListVersions(ctx, p)
+	s.log(ctx, OperationListVersions, []string{p.Id}, 0, start, err)
+	return res, err
+}
@@ -0,0 +1,229 @@
+package logging_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/pkg/logging"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Optional interfaces", func() {
+	var (
+		ctx context.Context
+		s   *logging.LoggingStorage
+		st  *goseidon.MockFullStorage
+		lo  *fakeLogger
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		st = goseidon.NewMockFullStorage(ctrl)
+		clo := clock.NewMockClock(ctrl)
+		lo = &fakeLogger{}
+		now = time.Now()
+		clo.EXPECT().Now().DoAndReturn(func() time.Time {
+			t := now
+			now = now.Add(time.Second)
+			return t
+		}).AnyTimes()
+		s = &logging.LoggingStorage{
+			Config: &logging.LoggingConfig{
+				Logger:  lo,
+				Backend: "local",
+			},
+			Storage: st,
+			Clock:   clo,
+		}
+	})
+
+	When("wrapped storage doesn't implement the interface", func() {
+		It("should return error without logging", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			s.Storage = goseidon.NewMockStorage(ctrl)
+
+			r, err := s.CopyFile(ctx, goseidon.CopyFileParam{SourceId: "a.txt", DestinationId: "b.txt"})
+
+			Expect(r).To(BeNil())
+			Expect(errors.Is(err, goseidon.ErrUnsupported)).To(BeTrue())
+			Expect(lo.events).To(BeEmpty())
+		})
+	})
+
+	Context("UploadStream method", func() {
+		When("size is unknown", func() {
+			It("should log zero bytes", func() {
+				p := goseidon.UploadStreamParam{FileId: "a.txt", FileData: strings.NewReader("hello"), FileSize: -1}
+				eRes := &goseidon.UploadFileResult{FileId: "a.txt"}
+				st.MockStreamUploader.EXPECT().UploadStream(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(lo.events).To(Equal([]logging.Event{
+					{
+						Operation: logging.OperationUploadStream,
+						Backend:   "local",
+						FileId:    "a.txt",
+						Duration:  time.Second,
+					},
+				}))
+			})
+		})
+
+		When("upload is skipped", func() {
+			It("should log zero bytes", func() {
+				p := goseidon.UploadStreamParam{FileId: "a.txt", FileData: strings.NewReader("hello"), FileSize: 5}
+				eRes := &goseidon.UploadFileResult{FileId: "a.txt", Skipped: true}
+				st.MockStreamUploader.EXPECT().UploadStream(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(lo.events).To(HaveLen(1))
+				Expect(lo.events[0].Bytes).To(Equal(int64(0)))
+			})
+		})
+	})
+
+	Context("RetrieveStream method", func() {
+		When("stream is opened", func() {
+			It("should log its length", func() {
+				p := goseidon.RetrieveFileParam{Id: "a.txt"}
+				eRes := &goseidon.RetrieveStreamResult{File: io.NopCloser(strings.NewReader("hi")), Size: 10, Length: 2}
+				st.MockStreamRetriever.EXPECT().RetrieveStream(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(lo.events).To(HaveLen(1))
+				Expect(lo.events[0].Operation).To(Equal(logging.OperationRetrieveStream))
+				Expect(lo.events[0].Bytes).To(Equal(int64(2)))
+			})
+		})
+	})
+
+	Context("UploadPart method", func() {
+		When("failed upload part", func() {
+			It("should log the part size and error", func() {
+				p := goseidon.UploadPartParam{UploadId: "u1", FileId: "a.txt", PartNumber: 1, PartData: strings.NewReader("hi"), PartSize: 2}
+				eErr := goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("slow down"))
+				st.MockMultipartUploader.EXPECT().UploadPart(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, eErr).Times(1)
+
+				res, err := s.UploadPart(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(eErr))
+				Expect(lo.events).To(HaveLen(1))
+				Expect(lo.events[0].Operation).To(Equal(logging.OperationUploadPart))
+				Expect(lo.events[0].Bytes).To(Equal(int64(2)))
+				Expect(lo.events[0].ErrorClass).To(Equal("transient"))
+			})
+		})
+	})
+
+	Context("CopyFile method", func() {
+		When("redaction is configured", func() {
+			It("should log both redacted ids", func() {
+				s.Config.Redact = logging.RedactAll
+				p := goseidon.CopyFileParam{SourceId: "a.txt", DestinationId: "b.txt"}
+				eRes := &goseidon.CopyFileResult{SourceId: "a.txt", DestinationId: "b.txt"}
+				st.MockCopier.EXPECT().CopyFile(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.CopyFile(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(lo.events).To(HaveLen(1))
+				Expect(lo.events[0].Operation).To(Equal(logging.OperationCopy))
+				Expect(lo.events[0].FileId).To(Equal("[redacted] -> [redacted]"))
+			})
+		})
+	})
+
+	Context("DeleteFiles method", func() {
+		When("an id fails", func() {
+			It("should log an event per id", func() {
+				p := goseidon.DeleteFilesParam{Ids: []string{"a.txt", "b.txt"}}
+				eRes := &goseidon.DeleteFilesResult{Items: []goseidon.DeleteFileItem{
+					{Id: "a.txt"},
+					{Id: "b.txt", Error: goseidon.ErrNotFound},
+				}}
+				st.MockBatchDeleter.EXPECT().DeleteFiles(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.DeleteFiles(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(lo.events).To(HaveLen(2))
+				Expect(lo.events[0].FileId).To(Equal("a.txt"))
+				Expect(lo.events[0].Error).To(BeNil())
+				Expect(lo.events[1].FileId).To(Equal("b.txt"))
+				Expect(lo.events[1].ErrorClass).To(Equal("not_found"))
+			})
+		})
+
+		When("batch fails as a whole", func() {
+			It("should log a single event", func() {
+				p := goseidon.DeleteFilesParam{Ids: []string{"a.txt", "b.txt"}}
+				st.MockBatchDeleter.EXPECT().DeleteFiles(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, context.Canceled).Times(1)
+
+				res, err := s.DeleteFiles(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(context.Canceled))
+				Expect(lo.events).To(HaveLen(1))
+				Expect(lo.events[0].FileId).To(Equal(""))
+				Expect(lo.events[0].ErrorClass).To(Equal("canceled"))
+			})
+		})
+
+		When("batch fails as a whole with redaction", func() {
+			It("should redact every id within the logged error", func() {
+				s.Config.Redact = logging.RedactAll
+				p := goseidon.DeleteFilesParam{Ids: []string{"a.txt", "b.txt"}}
+				eErr := goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("failed delete a.txt, b.txt"))
+				st.MockBatchDeleter.EXPECT().DeleteFiles(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, eErr).Times(1)
+
+				res, err := s.DeleteFiles(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(eErr))
+				Expect(lo.events).To(HaveLen(1))
+				Expect(lo.events[0].Error.Error()).To(Equal("failed delete [redacted], [redacted]"))
+				Expect(lo.events[0].ErrorClass).To(Equal("transient"))
+			})
+		})
+	})
+
+	Context("DeletePrefix method", func() {
+		When("delete is interrupted", func() {
+			It("should return the partial result and log the error", func() {
+				p := goseidon.DeletePrefixParam{Prefix: "a/"}
+				eRes := &goseidon.DeletePrefixResult{Prefix: "a/", Matched: 2, Deleted: 1}
+				st.MockPrefixDeleter.EXPECT().DeletePrefix(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, context.Canceled).Times(1)
+
+				res, err := s.DeletePrefix(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(Equal(context.Canceled))
+				Expect(lo.events).To(HaveLen(1))
+				Expect(lo.events[0].Operation).To(Equal(logging.OperationDeletePrefix))
+				Expect(lo.events[0].FileId).To(Equal("a/"))
+			})
+		})
+	})
+})
@@ -0,0 +1,167 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+)
+
+const (
+	OperationUpload   = "upload"
+	OperationRetrieve = "retrieve"
+	OperationDelete   = "delete"
+
+	OperationUploadStream      = "upload_stream"
+	OperationRetrieveStream    = "retrieve_stream"
+	OperationStat              = "stat"
+	OperationList              = "list"
+	OperationSignURL           = "sign_url"
+	OperationCreateMultipart   = "create_multipart"
+	OperationUploadPart        = "upload_part"
+	OperationCompleteMultipart = "complete_multipart"
+	OperationAbortMultipart    = "abort_multipart"
+	OperationListParts         = "list_parts"
+	OperationCopy              = "copy"
+	OperationMove              = "move"
+	OperationDeleteFiles       = "delete_files"
+	OperationDeletePrefix      = "delete_prefix"
+	OperationListVersions      = "list_versions"
+)
+
+// LoggingStorage log an event after every call made to the wrapped storage,
+// results and errors are returned untouched
+type LoggingStorage struct {
+	Config  *LoggingConfig
+	Storage goseidon.Storage
+	Clock   clock.Clock
+}
+
+func (s *LoggingStorage) UploadFile(ctx context.Context, p goseidon.UploadFileParam) (*goseidon.UploadFileResult, error) {
+	start := s.Clock.Now()
+	res, err := s.Storage.UploadFile(ctx, p)
+	size := int64(0)
+	if err == nil && res != nil && !res.Skipped {
+		size = int64(len(p.FileData))
+	}
+	s.log(ctx, OperationUpload, []string{p.FileId}, size, start, err)
+	return res, err
+}
+
+func (s *LoggingStorage) RetrieveFile(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+	start := s.Clock.Now()
+	res, err := s.Storage.RetrieveFile(ctx, p)
+	size := int64(0)
+	if res != nil {
+		size = int64(len(res.File))
+	}
+	s.log(ctx, OperationRetrieve, []string{p.Id}, size, start, err)
+	return res, err
+}
+
+func (s *LoggingStorage) DeleteFile(ctx context.Context, p goseidon.DeleteFileParam) (*goseidon.DeleteFileResult, error) {
+	start := s.Clock.Now()
+	res, err := s.Storage.DeleteFile(ctx, p)
+	s.log(ctx, OperationDelete, []string{p.Id}, 0, start, err)
+	return res, err
+}
+
+// log send the event of a finished call about the given ids, "src -> dst" for two ids.
+// with redaction the ids are redacted in FileId and within the text of err
+func (s *LoggingStorage) log(ctx context.Context, op string, ids []string, size int64, start time.Time, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	redacted := make([]string, len(ids))
+	for i, id := range ids {
+		redacted[i] = s.redact(id)
+	}
+	s.Config.Logger.Log(ctx, Event{
+		Operation:  op,
+		Backend:    s.Config.Backend,
+		FileId:     strings.Join(redacted, " -> "),
+		Bytes:      size,
+		Duration:   s.Clock.Now().Sub(start),
+		ErrorClass: goseidon.ErrorClass(err),
+		Error:      s.redactError(err, ids),
+	})
+}
+
+func (s *LoggingStorage) redact(id string) string {
+	if s.Config.Redact == nil {
+		return id
+	}
+	return s.Config.Redact(id)
+}
+
+// redactError replace the ids found within the text of err by their redacted form,
+// the longest id goes first so an id containing another is redacted as a whole
+func (s *LoggingStorage) redactError(err error, ids []string) error {
+	if err == nil || s.Config.Redact == nil || len(ids) == 0 {
+		return err
+	}
+	sorted := append([]string{}, ids...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+	pairs := []string{}
+	for _, id := range sorted {
+		if id != "" {
+			pairs = append(pairs, id, s.Config.Redact(id))
+		}
+	}
+	if len(pairs) == 0 {
+		return err
+	}
+	return &redactedError{
+		err: err,
+		msg: strings.NewReplacer(pairs...).Replace(err.Error()),
+	}
+}
+
+// redactedError is a logged error whose text has the ids redacted,
+// errors.Is and errors.As still reach the error returned by the storage
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+func NewLoggingStorage(storage goseidon.Storage, opts ...LoggingStorageOption) (*LoggingStorage, error) {
+	if storage == nil {
+		return nil, fmt.Errorf("invalid storage")
+	}
+
+	cfg := &LoggingConfig{}
+	for _, opt := range opts {
+		if opt == nil {
+			return nil, fmt.Errorf("invalid logging option")
+		}
+		err := opt.Apply(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = NewStdLogger(nil)
+	}
+
+	clock, _ := clock.NewClock()
+	s := &LoggingStorage{
+		Config:  cfg,
+		Storage: storage,
+		Clock:   clock,
+	}
+	return s, nil
+}
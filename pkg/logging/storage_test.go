@@ -0,0 +1,288 @@
+package logging_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/pkg/logging"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLogging(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Logging Package")
+}
+
+type fakeLogger struct {
+	events []logging.Event
+}
+
+func (l *fakeLogger) Log(ctx context.Context, e logging.Event) {
+	l.events = append(l.events, e)
+}
+
+var _ = Describe("Storage", func() {
+	var (
+		ctx context.Context
+		s   *logging.LoggingStorage
+		st  *goseidon.MockStorage
+		clo *clock.MockClock
+		lo  *fakeLogger
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		st = goseidon.NewMockStorage(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		lo = &fakeLogger{}
+		now = time.Now()
+		// every call to Now move the clock forward, so each call last one second
+		clo.EXPECT().Now().DoAndReturn(func() time.Time {
+			t := now
+			now = now.Add(time.Second)
+			return t
+		}).AnyTimes()
+		s = &logging.LoggingStorage{
+			Config: &logging.LoggingConfig{
+				Logger:  lo,
+				Backend: "local",
+			},
+			Storage: st,
+			Clock:   clo,
+		}
+	})
+
+	Context("NewLoggingStorage function", func() {
+		When("storage is invalid", func() {
+			It("should return error", func() {
+				s, err := logging.NewLoggingStorage(nil)
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid storage")))
+			})
+		})
+
+		When("option is invalid", func() {
+			It("should return error", func() {
+				s, err := logging.NewLoggingStorage(st, nil)
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid logging option")))
+			})
+		})
+
+		When("option is failed to apply", func() {
+			It("should return error", func() {
+				s, err := logging.NewLoggingStorage(st, logging.WithLogger(nil))
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid logger")))
+			})
+		})
+
+		When("no logger is given", func() {
+			It("should use the standard logger", func() {
+				s, err := logging.NewLoggingStorage(st)
+
+				Expect(err).To(BeNil())
+				Expect(s.Config.Logger).To(Equal(logging.NewStdLogger(nil)))
+				Expect(s.Config.Redact).To(BeNil())
+			})
+		})
+
+		When("options are given", func() {
+			It("should apply them", func() {
+				s, err := logging.NewLoggingStorage(st,
+					logging.WithLogger(lo),
+					logging.WithBackend("s3"),
+				)
+
+				Expect(err).To(BeNil())
+				Expect(s.Config.Logger).To(Equal(lo))
+				Expect(s.Config.Backend).To(Equal("s3"))
+				Expect(s.Clock).ToNot(BeNil())
+			})
+		})
+	})
+
+	Context("UploadFile function", func() {
+		var (
+			p goseidon.UploadFileParam
+		)
+
+		BeforeEach(func() {
+			p = goseidon.UploadFileParam{
+				FileId:   "a.txt",
+				FileData: []byte("hello"),
+			}
+		})
+
+		When("success upload file", func() {
+			It("should log the call", func() {
+				eRes := &goseidon.UploadFileResult{FileId: "a.txt"}
+				st.EXPECT().UploadFile(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.UploadFile(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(lo.events).To(Equal([]logging.Event{
+					{
+						Operation: logging.OperationUpload,
+						Backend:   "local",
+						FileId:    "a.txt",
+						Bytes:     5,
+						Duration:  time.Second,
+					},
+				}))
+			})
+		})
+
+		When("failed upload file", func() {
+			It("should log the error", func() {
+				eErr := goseidon.NewError(goseidon.ErrAlreadyExists, fmt.Errorf("file exists"))
+				st.EXPECT().UploadFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, eErr).Times(1)
+
+				res, err := s.UploadFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(eErr))
+				Expect(lo.events).To(HaveLen(1))
+				Expect(lo.events[0].Bytes).To(Equal(int64(0)))
+				Expect(lo.events[0].ErrorClass).To(Equal("already_exists"))
+				Expect(lo.events[0].Error).To(Equal(eErr))
+			})
+		})
+
+		When("upload is skipped", func() {
+			It("should log zero bytes", func() {
+				eRes := &goseidon.UploadFileResult{FileId: "a.txt", Skipped: true}
+				st.EXPECT().UploadFile(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.UploadFile(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(lo.events).To(HaveLen(1))
+				Expect(lo.events[0].Bytes).To(Equal(int64(0)))
+			})
+		})
+	})
+
+	Context("RetrieveFile function", func() {
+		var (
+			p goseidon.RetrieveFileParam
+		)
+
+		BeforeEach(func() {
+			p = goseidon.RetrieveFileParam{Id: "a.txt"}
+		})
+
+		When("success retrieve file", func() {
+			It("should log the retrieved size", func() {
+				eRes := &goseidon.RetrieveFileResult{File: []byte("hi")}
+				st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(lo.events).To(Equal([]logging.Event{
+					{
+						Operation: logging.OperationRetrieve,
+						Backend:   "local",
+						FileId:    "a.txt",
+						Bytes:     2,
+						Duration:  time.Second,
+					},
+				}))
+			})
+		})
+
+		When("context is canceled", func() {
+			It("should log the cancellation", func() {
+				st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, context.Canceled).Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(context.Canceled))
+				Expect(lo.events).To(HaveLen(1))
+				Expect(lo.events[0].Bytes).To(Equal(int64(0)))
+				Expect(lo.events[0].ErrorClass).To(Equal("canceled"))
+			})
+		})
+	})
+
+	Context("DeleteFile function", func() {
+		var (
+			p goseidon.DeleteFileParam
+		)
+
+		BeforeEach(func() {
+			p = goseidon.DeleteFileParam{Id: "secret/a.txt"}
+		})
+
+		When("redaction is configured", func() {
+			It("should log the redacted id", func() {
+				s.Config.Redact = logging.RedactAll
+				eRes := &goseidon.DeleteFileResult{}
+				st.EXPECT().DeleteFile(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(lo.events).To(Equal([]logging.Event{
+					{
+						Operation: logging.OperationDelete,
+						Backend:   "local",
+						FileId:    "[redacted]",
+						Duration:  time.Second,
+					},
+				}))
+			})
+		})
+
+		When("failed delete file with redaction", func() {
+			It("should redact the id within the logged error", func() {
+				s.Config.Redact = logging.RedactAll
+				eErr := goseidon.NewError(goseidon.ErrPermission, fmt.Errorf("failed remove /data/secret/a.txt: permission denied"))
+				st.EXPECT().DeleteFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, eErr).Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(eErr))
+				Expect(lo.events).To(HaveLen(1))
+				Expect(lo.events[0].FileId).To(Equal("[redacted]"))
+				Expect(lo.events[0].ErrorClass).To(Equal("permission"))
+				Expect(lo.events[0].Error.Error()).To(Equal("failed remove /data/[redacted]: permission denied"))
+				Expect(errors.Is(lo.events[0].Error, goseidon.ErrPermission)).To(BeTrue())
+			})
+		})
+
+		When("context is invalid", func() {
+			It("should pass it to the storage", func() {
+				eErr := goseidon.NewError(goseidon.ErrInvalidArgument, fmt.Errorf("invalid context"))
+				st.EXPECT().DeleteFile(gomock.Nil(), gomock.Eq(p)).Return(nil, eErr).Times(1)
+
+				res, err := s.DeleteFile(nil, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(eErr))
+				Expect(lo.events).To(HaveLen(1))
+				Expect(lo.events[0].ErrorClass).To(Equal("invalid_argument"))
+			})
+		})
+	})
+})
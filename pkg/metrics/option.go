@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"fmt"
+)
+
+// MetricsConfig hold where observations are sent and the backend label
+type MetricsConfig struct {
+	Recorder Recorder
+	Backend  string
+}
+
+type MetricsStorageOption interface {
+	Apply(c *MetricsConfig) error
+}
+
+type withRecorder struct {
+	recorder Recorder
+}
+
+func (o *withRecorder) Apply(c *MetricsConfig) error {
+	if o.recorder == nil {
+		return fmt.Errorf("invalid recorder")
+	}
+	c.Recorder = o.recorder
+	return nil
+}
+
+// WithRecorder send the observations to recorder, e.g. a shared *Registry
+func WithRecorder(recorder Recorder) MetricsStorageOption {
+	return &withRecorder{
+		recorder: recorder,
+	}
+}
+
+type withBackend struct {
+	backend string
+}
+
+func (o *withBackend) Apply(c *MetricsConfig) error {
+	if o.backend == "" {
+		return fmt.Errorf("invalid backend")
+	}
+	c.Backend = o.backend
+	return nil
+}
+
+// WithBackend set the backend label, e.g. "s3" or "local"
+func WithBackend(backend string) MetricsStorageOption {
+	return &withBackend{
+		backend: backend,
+	}
+}
@@ -0,0 +1,41 @@
+package metrics_test
+
+import (
+	"fmt"
+
+	"github.com/go-seidon/core/pkg/metrics"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics Option", func() {
+	var (
+		cfg *metrics.MetricsConfig
+	)
+
+	BeforeEach(func() {
+		cfg = &metrics.MetricsConfig{}
+	})
+
+	DescribeTable("invalid option",
+		func(opt metrics.MetricsStorageOption, eErr error) {
+			err := opt.Apply(cfg)
+
+			Expect(err).To(Equal(eErr))
+		},
+		Entry("recorder", metrics.WithRecorder(nil), fmt.Errorf("invalid recorder")),
+		Entry("backend", metrics.WithBackend(""), fmt.Errorf("invalid backend")),
+	)
+
+	When("options are valid", func() {
+		It("should set the config", func() {
+			r, _ := metrics.NewRegistry()
+
+			Expect(metrics.WithRecorder(r).Apply(cfg)).To(BeNil())
+			Expect(metrics.WithBackend("s3").Apply(cfg)).To(BeNil())
+
+			Expect(cfg.Recorder).To(Equal(r))
+			Expect(cfg.Backend).To(Equal("s3"))
+		})
+	})
+})
@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"context"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// an optional call is observed under its own Operation, Bytes only count data
+// actually transferred by a successful call so the byte counters can be summed
+// across operations. a call refused with goseidon.Unsupported isn't observed
+
+// UploadStream record the given FileSize, an unknown size is recorded as 0
+func (s *MetricsStorage) UploadStream(ctx context.Context, p goseidon.UploadStreamParam) (*goseidon.UploadFileResult, error) {
+	storage, ok := s.Storage.(goseidon.StreamUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("stream upload")
+	}
+	start := s.Clock.Now()
+	res, err := storage.UploadStream(ctx, p)
+	size := int64(0)
+	if err == nil && res != nil && !res.Skipped && p.FileSize > 0 {
+		size = p.FileSize
+	}
+	s.observe(OperationUploadStream, size, start, err)
+	return res, err
+}
+
+// RetrieveStream record once the stream is opened, with the length it's going to return
+func (s *MetricsStorage) RetrieveStream(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveStreamResult, error) {
+	storage, ok := s.Storage.(goseidon.StreamRetriever)
+	if !ok {
+		return nil, goseidon.Unsupported("stream retrieve")
+	}
+	start := s.Clock.Now()
+	res, err := storage.RetrieveStream(ctx, p)
+	size := int64(0)
+	if res != nil {
+		size = res.Length
+	}
+	s.observe(OperationRetrieveStream, size, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) StatFile(ctx context.Context, p goseidon.StatFileParam) (*goseidon.StatFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Stater)
+	if !ok {
+		return nil, goseidon.Unsupported("stat")
+	}
+	start := s.Clock.Now()
+	res, err := storage.StatFile(ctx, p)
+	s.observe(OperationStat, 0, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) ListFiles(ctx context.Context, p goseidon.ListFileParam) (*goseidon.ListFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Lister)
+	if !ok {
+		return nil, goseidon.Unsupported("list")
+	}
+	start := s.Clock.Now()
+	res, err := storage.ListFiles(ctx, p)
+	s.observe(OperationList, 0, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) SignURL(ctx context.Context, p goseidon.SignURLParam) (*goseidon.SignURLResult, error) {
+	storage, ok := s.Storage.(goseidon.URLSigner)
+	if !ok {
+		return nil, goseidon.Unsupported("sign url")
+	}
+	start := s.Clock.Now()
+	res, err := storage.SignURL(ctx, p)
+	s.observe(OperationSignURL, 0, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) CreateMultipartUpload(ctx context.Context, p goseidon.CreateMultipartParam) (*goseidon.CreateMultipartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	start := s.Clock.Now()
+	res, err := storage.CreateMultipartUpload(ctx, p)
+	s.observe(OperationCreateMultipart, 0, start, err)
+	return res, err
+}
+
+// UploadPart record the given PartSize, the completing call doesn't count the bytes again
+func (s *MetricsStorage) UploadPart(ctx context.Context, p goseidon.UploadPartParam) (*goseidon.UploadPartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	start := s.Clock.Now()
+	res, err := storage.UploadPart(ctx, p)
+	size := int64(0)
+	if err == nil && p.PartSize > 0 {
+		size = p.PartSize
+	}
+	s.observe(OperationUploadPart, size, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) CompleteMultipartUpload(ctx context.Context, p goseidon.CompleteMultipartParam) (*goseidon.UploadFileResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	start := s.Clock.Now()
+	res, err := storage.CompleteMultipartUpload(ctx, p)
+	s.observe(OperationCompleteMultipart, 0, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) AbortMultipartUpload(ctx context.Context, p goseidon.AbortMultipartParam) (*goseidon.AbortMultipartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	start := s.Clock.Now()
+	res, err := storage.AbortMultipartUpload(ctx, p)
+	s.observe(OperationAbortMultipart, 0, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) ListParts(ctx context.Context, p goseidon.ListPartsParam) (*goseidon.ListPartsResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	start := s.Clock.Now()
+	res, err := storage.ListParts(ctx, p)
+	s.observe(OperationListParts, 0, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) CopyFile(ctx context.Context, p goseidon.CopyFileParam) (*goseidon.CopyFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Copier)
+	if !ok {
+		return nil, goseidon.Unsupported("copy")
+	}
+	start := s.Clock.Now()
+	res, err := storage.CopyFile(ctx, p)
+	s.observe(OperationCopy, 0, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) MoveFile(ctx context.Context, p goseidon.MoveFileParam) (*goseidon.MoveFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Mover)
+	if !ok {
+		return nil, goseidon.Unsupported("move")
+	}
+	start := s.Clock.Now()
+	res, err := storage.MoveFile(ctx, p)
+	s.observe(OperationMove, 0, start, err)
+	return res, err
+}
+
+// DeleteFiles record the batch as a single call, an id failing within it isn't an error
+func (s *MetricsStorage) DeleteFiles(ctx context.Context, p goseidon.DeleteFilesParam) (*goseidon.DeleteFilesResult, error) {
+	storage, ok := s.Storage.(goseidon.BatchDeleter)
+	if !ok {
+		return nil, goseidon.Unsupported("batch delete")
+	}
+	start := s.Clock.Now()
+	res, err := storage.DeleteFiles(ctx, p)
+	s.observe(OperationDeleteFiles, 0, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) DeletePrefix(ctx context.Context, p goseidon.DeletePrefixParam) (*goseidon.DeletePrefixResult, error) {
+	storage, ok := s.Storage.(goseidon.PrefixDeleter)
+	if !ok {
+		return nil, goseidon.Unsupported("prefix delete")
+	}
+	start := s.Clock.Now()
+	res, err := storage.DeletePrefix(ctx, p)
+	s.observe(OperationDeletePrefix, 0, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) ListVersions(ctx context.Context, p goseidon.ListVersionsParam) (*goseidon.ListVersionsResult, error) {
+	storage, ok := s.Storage.(goseidon.VersionLister)
+	if !ok {
+		return nil, goseidon.Unsupported("list versions")
+	}
+	start := s.Clock.Now()
+	res, err := storage.ListVersions(ctx, p)
+	s.observe(OperationListVersions, 0, start, err)
+	return res, err
+}
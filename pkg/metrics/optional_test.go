@@ -0,0 +1,203 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/pkg/metrics"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Optional interfaces", func() {
+	var (
+		ctx context.Context
+		s   *metrics.MetricsStorage
+		st  *goseidon.MockFullStorage
+		rec *fakeRecorder
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		st = goseidon.NewMockFullStorage(ctrl)
+		clo := clock.NewMockClock(ctrl)
+		rec = &fakeRecorder{}
+		now = time.Now()
+		clo.EXPECT().Now().DoAndReturn(func() time.Time {
+			t := now
+			now = now.Add(time.Second)
+			return t
+		}).AnyTimes()
+		s = &metrics.MetricsStorage{
+			Config: &metrics.MetricsConfig{
+				Recorder: rec,
+				Backend:  "local",
+			},
+			Storage: st,
+			Clock:   clo,
+		}
+	})
+
+	When("wrapped storage doesn't implement the interface", func() {
+		It("should return error without recording", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			s.Storage = goseidon.NewMockStorage(ctrl)
+
+			r, err := s.UploadStream(ctx, goseidon.UploadStreamParam{FileId: "a.txt", FileData: strings.NewReader("a")})
+
+			Expect(r).To(BeNil())
+			Expect(errors.Is(err, goseidon.ErrUnsupported)).To(BeTrue())
+			Expect(rec.observations).To(BeEmpty())
+		})
+	})
+
+	Context("UploadStream method", func() {
+		var (
+			p goseidon.UploadStreamParam
+		)
+
+		BeforeEach(func() {
+			p = goseidon.UploadStreamParam{FileId: "a.txt", FileData: strings.NewReader("hello"), FileSize: 5}
+		})
+
+		When("success upload stream", func() {
+			It("should record the given size", func() {
+				eRes := &goseidon.UploadFileResult{FileId: "a.txt"}
+				st.MockStreamUploader.EXPECT().UploadStream(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(rec.observations).To(Equal([]metrics.Observation{
+					{
+						Backend:   "local",
+						Operation: metrics.OperationUploadStream,
+						Bytes:     5,
+						Duration:  time.Second,
+					},
+				}))
+			})
+		})
+
+		When("upload is skipped", func() {
+			It("should record the call without bytes", func() {
+				p.Overwrite = goseidon.OverwriteSkip
+				eRes := &goseidon.UploadFileResult{FileId: "a.txt", Skipped: true}
+				st.MockStreamUploader.EXPECT().UploadStream(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				_, err := s.UploadStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(rec.observations).To(HaveLen(1))
+				Expect(rec.observations[0].Bytes).To(Equal(int64(0)))
+			})
+		})
+
+		When("size is unknown", func() {
+			It("should record the call without bytes", func() {
+				p.FileSize = -1
+				eRes := &goseidon.UploadFileResult{FileId: "a.txt"}
+				st.MockStreamUploader.EXPECT().UploadStream(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				_, err := s.UploadStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(rec.observations).To(HaveLen(1))
+				Expect(rec.observations[0].Bytes).To(Equal(int64(0)))
+			})
+		})
+
+		When("failed upload stream", func() {
+			It("should record the error class without bytes", func() {
+				eErr := goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("slow down"))
+				st.MockStreamUploader.EXPECT().UploadStream(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, eErr).Times(1)
+
+				res, err := s.UploadStream(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(eErr))
+				Expect(rec.observations).To(Equal([]metrics.Observation{
+					{
+						Backend:    "local",
+						Operation:  metrics.OperationUploadStream,
+						Duration:   time.Second,
+						ErrorClass: "transient",
+					},
+				}))
+			})
+		})
+	})
+
+	Context("RetrieveStream method", func() {
+		When("stream is opened", func() {
+			It("should record its length", func() {
+				p := goseidon.RetrieveFileParam{Id: "a.txt"}
+				eRes := &goseidon.RetrieveStreamResult{File: io.NopCloser(strings.NewReader("hi")), Size: 10, Length: 2}
+				st.MockStreamRetriever.EXPECT().RetrieveStream(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.RetrieveStream(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(rec.observations).To(Equal([]metrics.Observation{
+					{
+						Backend:   "local",
+						Operation: metrics.OperationRetrieveStream,
+						Bytes:     2,
+						Duration:  time.Second,
+					},
+				}))
+			})
+		})
+	})
+
+	Context("UploadPart method", func() {
+		When("success upload part", func() {
+			It("should record the part size", func() {
+				p := goseidon.UploadPartParam{UploadId: "u1", FileId: "a.txt", PartNumber: 1, PartData: strings.NewReader("hi"), PartSize: 2}
+				eRes := &goseidon.UploadPartResult{PartNumber: 1, Size: 2}
+				st.MockMultipartUploader.EXPECT().UploadPart(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				_, err := s.UploadPart(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(rec.observations).To(HaveLen(1))
+				Expect(rec.observations[0].Operation).To(Equal(metrics.OperationUploadPart))
+				Expect(rec.observations[0].Bytes).To(Equal(int64(2)))
+			})
+		})
+	})
+
+	Context("DeletePrefix method", func() {
+		When("delete is interrupted", func() {
+			It("should return the partial result and record the error", func() {
+				p := goseidon.DeletePrefixParam{Prefix: "a/"}
+				eRes := &goseidon.DeletePrefixResult{Prefix: "a/", Matched: 2, Deleted: 1}
+				st.MockPrefixDeleter.EXPECT().DeletePrefix(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, context.Canceled).Times(1)
+
+				res, err := s.DeletePrefix(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(Equal(context.Canceled))
+				Expect(rec.observations).To(Equal([]metrics.Observation{
+					{
+						Backend:    "local",
+						Operation:  metrics.OperationDeletePrefix,
+						Duration:   time.Second,
+						ErrorClass: "canceled",
+					},
+				}))
+			})
+		})
+	})
+})
@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"time"
+)
+
+// Observation describe a finished storage call, ErrorClass is empty on success
+type Observation struct {
+	Backend    string
+	Operation  string
+	Bytes      int64
+	Duration   time.Duration
+	ErrorClass string
+}
+
+// Recorder receive an observation after every storage call,
+// it's called concurrently and must not block
+type Recorder interface {
+	Observe(o Observation)
+}
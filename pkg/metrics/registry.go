@@ -0,0 +1,213 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the latency histogram
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type seriesKey struct {
+	backend   string
+	operation string
+}
+
+type errorKey struct {
+	seriesKey
+	class string
+}
+
+type series struct {
+	requests uint64
+	bytes    int64
+	// counts[i] is the number of calls which fall in bucket i, the last one is +Inf
+	counts []uint64
+	sum    float64
+}
+
+// Registry is an in memory Recorder which expose what it recorded
+// in the prometheus text format, it can be mounted as a http handler
+type Registry struct {
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[seriesKey]*series
+	errors map[errorKey]uint64
+}
+
+func (r *Registry) Observe(o Observation) {
+	k := seriesKey{backend: o.Backend, operation: o.Operation}
+	d := o.Duration.Seconds()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.series[k]
+	if !ok {
+		s = &series{counts: make([]uint64, len(r.buckets)+1)}
+		r.series[k] = s
+	}
+	s.requests++
+	s.bytes += o.Bytes
+	s.sum += d
+	s.counts[sort.SearchFloat64s(r.buckets, d)]++
+
+	if o.ErrorClass != "" {
+		r.errors[errorKey{seriesKey: k, class: o.ErrorClass}]++
+	}
+}
+
+// WriteTo write every metric in the prometheus text format,
+// series are sorted so the output is stable
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	keys := make([]seriesKey, 0, len(r.series))
+	snapshot := make(map[seriesKey]series, len(r.series))
+	for k, s := range r.series {
+		keys = append(keys, k)
+		c := *s
+		c.counts = append([]uint64(nil), s.counts...)
+		snapshot[k] = c
+	}
+	ekeys := make([]errorKey, 0, len(r.errors))
+	errors := make(map[errorKey]uint64, len(r.errors))
+	for k, n := range r.errors {
+		ekeys = append(ekeys, k)
+		errors[k] = n
+	}
+	r.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].less(keys[j])
+	})
+	sort.Slice(ekeys, func(i, j int) bool {
+		if ekeys[i].seriesKey != ekeys[j].seriesKey {
+			return ekeys[i].seriesKey.less(ekeys[j].seriesKey)
+		}
+		return ekeys[i].class < ekeys[j].class
+	})
+
+	cw := &countWriter{w: bufio.NewWriter(w)}
+
+	writeHeader(cw, "goseidon_storage_requests_total", "counter", "Total number of storage calls.")
+	for _, k := range keys {
+		fmt.Fprintf(cw, "goseidon_storage_requests_total{%s} %d\n", k.labels(), snapshot[k].requests)
+	}
+
+	writeHeader(cw, "goseidon_storage_errors_total", "counter", "Total number of failed storage calls by error class.")
+	for _, k := range ekeys {
+		fmt.Fprintf(cw, "goseidon_storage_errors_total{%s,error_class=%s} %d\n", k.labels(), quote(k.class), errors[k])
+	}
+
+	writeHeader(cw, "goseidon_storage_bytes_total", "counter", "Total number of bytes uploaded or retrieved.")
+	for _, k := range keys {
+		fmt.Fprintf(cw, "goseidon_storage_bytes_total{%s} %d\n", k.labels(), snapshot[k].bytes)
+	}
+
+	writeHeader(cw, "goseidon_storage_duration_seconds", "histogram", "Latency of storage calls in seconds.")
+	for _, k := range keys {
+		s := snapshot[k]
+		cumulative := uint64(0)
+		for i, n := range s.counts {
+			cumulative += n
+			le := "+Inf"
+			if i < len(r.buckets) {
+				le = formatFloat(r.buckets[i])
+			}
+			fmt.Fprintf(cw, "goseidon_storage_duration_seconds_bucket{%s,le=%s} %d\n", k.labels(), quote(le), cumulative)
+		}
+		fmt.Fprintf(cw, "goseidon_storage_duration_seconds_sum{%s} %s\n", k.labels(), formatFloat(s.sum))
+		fmt.Fprintf(cw, "goseidon_storage_duration_seconds_count{%s} %d\n", k.labels(), s.requests)
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	err := cw.w.Flush()
+	return cw.n, err
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	if req.Method == http.MethodHead {
+		return
+	}
+	r.WriteTo(w)
+}
+
+// NewRegistry create an empty registry, buckets are the latency upper bounds in seconds
+// and default to DefaultBuckets
+func NewRegistry(buckets ...float64) (*Registry, error) {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	for i, b := range buckets {
+		if math.IsNaN(b) || math.IsInf(b, 0) || (i > 0 && b <= buckets[i-1]) {
+			return nil, fmt.Errorf("invalid buckets")
+		}
+	}
+
+	r := &Registry{
+		buckets: append([]float64(nil), buckets...),
+		series:  map[seriesKey]*series{},
+		errors:  map[errorKey]uint64{},
+	}
+	return r, nil
+}
+
+func (k seriesKey) less(o seriesKey) bool {
+	if k.backend != o.backend {
+		return k.backend < o.backend
+	}
+	return k.operation < o.operation
+}
+
+func (k seriesKey) labels() string {
+	return "backend=" + quote(k.backend) + ",operation=" + quote(k.operation)
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quote(v string) string {
+	return `"` + labelReplacer.Replace(v) + `"`
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// countWriter keep the first write error, so the remaining writes are skipped
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	w.err = err
+	return n, err
+}
@@ -0,0 +1,173 @@
+package metrics_test
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-seidon/core/pkg/metrics"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type failWriter struct {
+}
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	return 0, fmt.Errorf("disk full")
+}
+
+var _ = Describe("Registry", func() {
+	var (
+		r *metrics.Registry
+	)
+
+	BeforeEach(func() {
+		r, _ = metrics.NewRegistry(0.1, 1)
+	})
+
+	Context("NewRegistry function", func() {
+		DescribeTable("invalid buckets",
+			func(buckets []float64) {
+				r, err := metrics.NewRegistry(buckets...)
+
+				Expect(r).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid buckets")))
+			},
+			Entry("unsorted", []float64{1, 0.5}),
+			Entry("duplicated", []float64{1, 1}),
+			Entry("nan", []float64{math.NaN()}),
+			Entry("infinite", []float64{1, math.Inf(1)}),
+		)
+
+		When("buckets are not given", func() {
+			It("should use the default buckets", func() {
+				r, err := metrics.NewRegistry()
+
+				Expect(err).To(BeNil())
+				Expect(r).ToNot(BeNil())
+			})
+		})
+	})
+
+	Context("WriteTo function", func() {
+		When("nothing is recorded", func() {
+			It("should write the metric headers only", func() {
+				buf := &bytes.Buffer{}
+
+				n, err := r.WriteTo(buf)
+
+				Expect(err).To(BeNil())
+				Expect(n).To(Equal(int64(buf.Len())))
+				Expect(buf.String()).To(Equal(`# HELP goseidon_storage_requests_total Total number of storage calls.
+# TYPE goseidon_storage_requests_total counter
+# HELP goseidon_storage_errors_total Total number of failed storage calls by error class.
+# TYPE goseidon_storage_errors_total counter
+# HELP goseidon_storage_bytes_total Total number of bytes uploaded or retrieved.
+# TYPE goseidon_storage_bytes_total counter
+# HELP goseidon_storage_duration_seconds Latency of storage calls in seconds.
+# TYPE goseidon_storage_duration_seconds histogram
+`))
+			})
+		})
+
+		When("calls are recorded", func() {
+			It("should write sorted series", func() {
+				r.Observe(metrics.Observation{Backend: "s3", Operation: "upload", Bytes: 10, Duration: 50 * time.Millisecond})
+				r.Observe(metrics.Observation{Backend: "s3", Operation: "upload", Bytes: 5, Duration: 500 * time.Millisecond})
+				r.Observe(metrics.Observation{Backend: "s3", Operation: "upload", Duration: 2 * time.Second, ErrorClass: "transient"})
+				r.Observe(metrics.Observation{Backend: "local", Operation: "delete", Duration: 100 * time.Millisecond, ErrorClass: "not_found"})
+				buf := &bytes.Buffer{}
+
+				_, err := r.WriteTo(buf)
+
+				Expect(err).To(BeNil())
+				Expect(buf.String()).To(Equal(`# HELP goseidon_storage_requests_total Total number of storage calls.
+# TYPE goseidon_storage_requests_total counter
+goseidon_storage_requests_total{backend="local",operation="delete"} 1
+goseidon_storage_requests_total{backend="s3",operation="upload"} 3
+# HELP goseidon_storage_errors_total Total number of failed storage calls by error class.
+# TYPE goseidon_storage_errors_total counter
+goseidon_storage_errors_total{backend="local",operation="delete",error_class="not_found"} 1
+goseidon_storage_errors_total{backend="s3",operation="upload",error_class="transient"} 1
+# HELP goseidon_storage_bytes_total Total number of bytes uploaded or retrieved.
+# TYPE goseidon_storage_bytes_total counter
+goseidon_storage_bytes_total{backend="local",operation="delete"} 0
+goseidon_storage_bytes_total{backend="s3",operation="upload"} 15
+# HELP goseidon_storage_duration_seconds Latency of storage calls in seconds.
+# TYPE goseidon_storage_duration_seconds histogram
+goseidon_storage_duration_seconds_bucket{backend="local",operation="delete",le="0.1"} 1
+goseidon_storage_duration_seconds_bucket{backend="local",operation="delete",le="1"} 1
+goseidon_storage_duration_seconds_bucket{backend="local",operation="delete",le="+Inf"} 1
+goseidon_storage_duration_seconds_sum{backend="local",operation="delete"} 0.1
+goseidon_storage_duration_seconds_count{backend="local",operation="delete"} 1
+goseidon_storage_duration_seconds_bucket{backend="s3",operation="upload",le="0.1"} 1
+goseidon_storage_duration_seconds_bucket{backend="s3",operation="upload",le="1"} 2
+goseidon_storage_duration_seconds_bucket{backend="s3",operation="upload",le="+Inf"} 3
+goseidon_storage_duration_seconds_sum{backend="s3",operation="upload"} 2.55
+goseidon_storage_duration_seconds_count{backend="s3",operation="upload"} 3
+`))
+			})
+		})
+
+		When("label value contain special characters", func() {
+			It("should escape the value", func() {
+				r.Observe(metrics.Observation{Backend: "a\"b\\c\nd", Operation: "upload"})
+				buf := &bytes.Buffer{}
+
+				_, err := r.WriteTo(buf)
+
+				Expect(err).To(BeNil())
+				Expect(buf.String()).To(ContainSubstring(`goseidon_storage_requests_total{backend="a\"b\\c\nd",operation="upload"} 1`))
+			})
+		})
+
+		When("writer is failed", func() {
+			It("should return error", func() {
+				_, err := r.WriteTo(&failWriter{})
+
+				Expect(err).To(Equal(fmt.Errorf("disk full")))
+			})
+		})
+	})
+
+	Context("ServeHTTP function", func() {
+		When("method is get", func() {
+			It("should write the metrics", func() {
+				r.Observe(metrics.Observation{Backend: "s3", Operation: "retrieve", Bytes: 3})
+				w := httptest.NewRecorder()
+
+				r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Header().Get("Content-Type")).To(Equal("text/plain; version=0.0.4; charset=utf-8"))
+				Expect(w.Body.String()).To(ContainSubstring(`goseidon_storage_bytes_total{backend="s3",operation="retrieve"} 3`))
+			})
+		})
+
+		When("method is head", func() {
+			It("should not write a body", func() {
+				w := httptest.NewRecorder()
+
+				r.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/metrics", nil))
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Body.Len()).To(Equal(0))
+			})
+		})
+
+		When("method is not allowed", func() {
+			It("should return error", func() {
+				w := httptest.NewRecorder()
+
+				r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+
+				Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+				Expect(w.Header().Get("Allow")).To(Equal("GET, HEAD"))
+			})
+		})
+	})
+})
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+)
+
+const (
+	OperationUpload   = "upload"
+	OperationRetrieve = "retrieve"
+	OperationDelete   = "delete"
+
+	OperationUploadStream      = "upload_stream"
+	OperationRetrieveStream    = "retrieve_stream"
+	OperationStat              = "stat"
+	OperationList              = "list"
+	OperationSignURL           = "sign_url"
+	OperationCreateMultipart   = "create_multipart"
+	OperationUploadPart        = "upload_part"
+	OperationCompleteMultipart = "complete_multipart"
+	OperationAbortMultipart    = "abort_multipart"
+	OperationListParts         = "list_parts"
+	OperationCopy              = "copy"
+	OperationMove              = "move"
+	OperationDeleteFiles       = "delete_files"
+	OperationDeletePrefix      = "delete_prefix"
+	OperationListVersions      = "list_versions"
+)
+
+const DefaultBackend = "default"
+
+// MetricsStorage record every call made to the wrapped storage,
+// results and errors are returned untouched
+type MetricsStorage struct {
+	Config  *MetricsConfig
+	Storage goseidon.Storage
+	Clock   clock.Clock
+}
+
+func (s *MetricsStorage) UploadFile(ctx context.Context, p goseidon.UploadFileParam) (*goseidon.UploadFileResult, error) {
+	start := s.Clock.Now()
+	res, err := s.Storage.UploadFile(ctx, p)
+	size := int64(0)
+	// a skipped upload didn't write anything
+	if err == nil && res != nil && !res.Skipped {
+		size = int64(len(p.FileData))
+	}
+	s.observe(OperationUpload, size, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) RetrieveFile(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+	start := s.Clock.Now()
+	res, err := s.Storage.RetrieveFile(ctx, p)
+	size := int64(0)
+	if res != nil {
+		size = int64(len(res.File))
+	}
+	s.observe(OperationRetrieve, size, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) DeleteFile(ctx context.Context, p goseidon.DeleteFileParam) (*goseidon.DeleteFileResult, error) {
+	start := s.Clock.Now()
+	res, err := s.Storage.DeleteFile(ctx, p)
+	s.observe(OperationDelete, 0, start, err)
+	return res, err
+}
+
+func (s *MetricsStorage) observe(op string, size int64, start time.Time, err error) {
+	s.Config.Recorder.Observe(Observation{
+		Backend:    s.Config.Backend,
+		Operation:  op,
+		Bytes:      size,
+		Duration:   s.Clock.Now().Sub(start),
+		ErrorClass: goseidon.ErrorClass(err),
+	})
+}
+
+func NewMetricsStorage(storage goseidon.Storage, opts ...MetricsStorageOption) (*MetricsStorage, error) {
+	if storage == nil {
+		return nil, fmt.Errorf("invalid storage")
+	}
+
+	cfg := &MetricsConfig{
+		Backend: DefaultBackend,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			return nil, fmt.Errorf("invalid metrics option")
+		}
+		err := opt.Apply(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Recorder == nil {
+		return nil, fmt.Errorf("invalid recorder")
+	}
+
+	clock, _ := clock.NewClock()
+	s := &MetricsStorage{
+		Config:  cfg,
+		Storage: storage,
+		Clock:   clock,
+	}
+	return s, nil
+}
@@ -0,0 +1,265 @@
+package metrics_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/pkg/metrics"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMetrics(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Metrics Package")
+}
+
+type fakeRecorder struct {
+	observations []metrics.Observation
+}
+
+func (r *fakeRecorder) Observe(o metrics.Observation) {
+	r.observations = append(r.observations, o)
+}
+
+var _ = Describe("Storage", func() {
+	var (
+		ctx context.Context
+		s   *metrics.MetricsStorage
+		st  *goseidon.MockStorage
+		clo *clock.MockClock
+		rec *fakeRecorder
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		st = goseidon.NewMockStorage(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		rec = &fakeRecorder{}
+		now = time.Now()
+		// every call to Now move the clock forward, so each call last one second
+		clo.EXPECT().Now().DoAndReturn(func() time.Time {
+			t := now
+			now = now.Add(time.Second)
+			return t
+		}).AnyTimes()
+		s = &metrics.MetricsStorage{
+			Config: &metrics.MetricsConfig{
+				Recorder: rec,
+				Backend:  "local",
+			},
+			Storage: st,
+			Clock:   clo,
+		}
+	})
+
+	Context("NewMetricsStorage function", func() {
+		When("storage is invalid", func() {
+			It("should return error", func() {
+				s, err := metrics.NewMetricsStorage(nil)
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid storage")))
+			})
+		})
+
+		When("option is invalid", func() {
+			It("should return error", func() {
+				s, err := metrics.NewMetricsStorage(st, nil)
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid metrics option")))
+			})
+		})
+
+		When("option is failed to apply", func() {
+			It("should return error", func() {
+				s, err := metrics.NewMetricsStorage(st, metrics.WithBackend(""))
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid backend")))
+			})
+		})
+
+		When("recorder is not given", func() {
+			It("should return error", func() {
+				s, err := metrics.NewMetricsStorage(st)
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid recorder")))
+			})
+		})
+
+		When("backend is not given", func() {
+			It("should use the default backend", func() {
+				s, err := metrics.NewMetricsStorage(st, metrics.WithRecorder(rec))
+
+				Expect(err).To(BeNil())
+				Expect(s.Config.Recorder).To(Equal(rec))
+				Expect(s.Config.Backend).To(Equal(metrics.DefaultBackend))
+				Expect(s.Clock).ToNot(BeNil())
+			})
+		})
+	})
+
+	Context("UploadFile function", func() {
+		var (
+			p goseidon.UploadFileParam
+		)
+
+		BeforeEach(func() {
+			p = goseidon.UploadFileParam{
+				FileId:   "a.txt",
+				FileData: []byte("hello"),
+			}
+		})
+
+		When("success upload file", func() {
+			It("should record the call", func() {
+				eRes := &goseidon.UploadFileResult{FileId: "a.txt"}
+				st.EXPECT().UploadFile(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.UploadFile(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(rec.observations).To(Equal([]metrics.Observation{
+					{
+						Backend:   "local",
+						Operation: metrics.OperationUpload,
+						Bytes:     5,
+						Duration:  time.Second,
+					},
+				}))
+			})
+		})
+
+		When("upload is skipped", func() {
+			It("should record the call without bytes", func() {
+				p.Overwrite = goseidon.OverwriteSkip
+				eRes := &goseidon.UploadFileResult{FileId: "a.txt", Skipped: true}
+				st.EXPECT().UploadFile(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.UploadFile(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(rec.observations).To(Equal([]metrics.Observation{
+					{
+						Backend:   "local",
+						Operation: metrics.OperationUpload,
+						Duration:  time.Second,
+					},
+				}))
+			})
+		})
+
+		When("failed upload file", func() {
+			It("should record the error class without bytes", func() {
+				eErr := goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("slow down"))
+				st.EXPECT().UploadFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, eErr).Times(1)
+
+				res, err := s.UploadFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(eErr))
+				Expect(rec.observations).To(Equal([]metrics.Observation{
+					{
+						Backend:    "local",
+						Operation:  metrics.OperationUpload,
+						Duration:   time.Second,
+						ErrorClass: "transient",
+					},
+				}))
+			})
+		})
+	})
+
+	Context("RetrieveFile function", func() {
+		var (
+			p goseidon.RetrieveFileParam
+		)
+
+		BeforeEach(func() {
+			p = goseidon.RetrieveFileParam{Id: "a.txt"}
+		})
+
+		When("success retrieve file", func() {
+			It("should record the retrieved size", func() {
+				eRes := &goseidon.RetrieveFileResult{File: []byte("hi")}
+				st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(rec.observations).To(HaveLen(1))
+				Expect(rec.observations[0].Operation).To(Equal(metrics.OperationRetrieve))
+				Expect(rec.observations[0].Bytes).To(Equal(int64(2)))
+			})
+		})
+
+		When("file is not found", func() {
+			It("should record the error class", func() {
+				eErr := goseidon.NewError(goseidon.ErrNotFound, fmt.Errorf("no such file"))
+				st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, eErr).Times(1)
+
+				res, err := s.RetrieveFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(eErr))
+				Expect(rec.observations).To(HaveLen(1))
+				Expect(rec.observations[0].ErrorClass).To(Equal("not_found"))
+			})
+		})
+	})
+
+	Context("DeleteFile function", func() {
+		var (
+			p goseidon.DeleteFileParam
+		)
+
+		BeforeEach(func() {
+			p = goseidon.DeleteFileParam{Id: "a.txt"}
+		})
+
+		When("success delete file", func() {
+			It("should record the call", func() {
+				eRes := &goseidon.DeleteFileResult{}
+				st.EXPECT().DeleteFile(gomock.Eq(ctx), gomock.Eq(p)).Return(eRes, nil).Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+				Expect(rec.observations).To(Equal([]metrics.Observation{
+					{
+						Backend:   "local",
+						Operation: metrics.OperationDelete,
+						Duration:  time.Second,
+					},
+				}))
+			})
+		})
+
+		When("context is canceled", func() {
+			It("should record the cancellation", func() {
+				st.EXPECT().DeleteFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, context.Canceled).Times(1)
+
+				res, err := s.DeleteFile(ctx, p)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(context.Canceled))
+				Expect(rec.observations).To(HaveLen(1))
+				Expect(rec.observations[0].ErrorClass).To(Equal("canceled"))
+			})
+		})
+	})
+})
@@ -0,0 +1,116 @@
+package retry
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 100 * time.Millisecond
+	defaultMaxDelay    = 5 * time.Second
+	defaultMultiplier  = 2
+	defaultJitter      = 0.2
+)
+
+// RetryConfig hold the retry policy, MaxAttempts include the first attempt.
+// the n-th retry wait BaseDelay * Multiplier^(n-1) capped at MaxDelay,
+// shortened by a random fraction up to Jitter of the delay
+type RetryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Multiplier  float64
+	Jitter      float64
+	Retryable   func(err error) bool
+}
+
+type RetryStorageOption interface {
+	Apply(c *RetryConfig) error
+}
+
+type withMaxAttempts struct {
+	maxAttempts int
+}
+
+func (o *withMaxAttempts) Apply(c *RetryConfig) error {
+	if o.maxAttempts < 1 {
+		return fmt.Errorf("invalid max attempts")
+	}
+	c.MaxAttempts = o.maxAttempts
+	return nil
+}
+
+// WithMaxAttempts limit the number of attempts of a single call, 1 disable the retry
+func WithMaxAttempts(maxAttempts int) RetryStorageOption {
+	return &withMaxAttempts{
+		maxAttempts: maxAttempts,
+	}
+}
+
+type withBackoff struct {
+	baseDelay, maxDelay time.Duration
+	multiplier          float64
+}
+
+func (o *withBackoff) Apply(c *RetryConfig) error {
+	if o.baseDelay <= 0 || o.maxDelay < o.baseDelay {
+		return fmt.Errorf("invalid backoff delay")
+	}
+	if o.multiplier < 1 {
+		return fmt.Errorf("invalid backoff multiplier")
+	}
+	c.BaseDelay = o.baseDelay
+	c.MaxDelay = o.maxDelay
+	c.Multiplier = o.multiplier
+	return nil
+}
+
+// WithBackoff set the delay before the first retry and the cap of the following ones,
+// every retry multiply the previous delay by multiplier
+func WithBackoff(baseDelay, maxDelay time.Duration, multiplier float64) RetryStorageOption {
+	return &withBackoff{
+		baseDelay:  baseDelay,
+		maxDelay:   maxDelay,
+		multiplier: multiplier,
+	}
+}
+
+type withJitter struct {
+	jitter float64
+}
+
+func (o *withJitter) Apply(c *RetryConfig) error {
+	if o.jitter < 0 || o.jitter > 1 {
+		return fmt.Errorf("invalid jitter")
+	}
+	c.Jitter = o.jitter
+	return nil
+}
+
+// WithJitter shorten every delay by a random fraction up to jitter,
+// so callers failing together don't retry together. 0 disable the jitter
+func WithJitter(jitter float64) RetryStorageOption {
+	return &withJitter{
+		jitter: jitter,
+	}
+}
+
+type withClassifier struct {
+	retryable func(err error) bool
+}
+
+func (o *withClassifier) Apply(c *RetryConfig) error {
+	if o.retryable == nil {
+		return fmt.Errorf("invalid retryable classifier")
+	}
+	c.Retryable = o.retryable
+	return nil
+}
+
+// WithClassifier decide which error is retried, IsRetryable is used by default
+func WithClassifier(retryable func(err error) bool) RetryStorageOption {
+	return &withClassifier{
+		retryable: retryable,
+	}
+}
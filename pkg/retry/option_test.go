@@ -0,0 +1,106 @@
+package retry_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-seidon/core/pkg/retry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retry Option", func() {
+	var (
+		cfg *retry.RetryConfig
+	)
+
+	BeforeEach(func() {
+		cfg = &retry.RetryConfig{}
+	})
+
+	Context("WithMaxAttempts option", func() {
+		When("max attempts is invalid", func() {
+			It("should return error", func() {
+				err := retry.WithMaxAttempts(0).Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid max attempts")))
+			})
+		})
+
+		When("success apply option", func() {
+			It("should set max attempts", func() {
+				err := retry.WithMaxAttempts(5).Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.MaxAttempts).To(Equal(5))
+			})
+		})
+	})
+
+	Context("WithBackoff option", func() {
+		When("delay is invalid", func() {
+			It("should return error", func() {
+				err := retry.WithBackoff(time.Second, time.Millisecond, 2).Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid backoff delay")))
+			})
+		})
+
+		When("multiplier is invalid", func() {
+			It("should return error", func() {
+				err := retry.WithBackoff(time.Millisecond, time.Second, 0.5).Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid backoff multiplier")))
+			})
+		})
+
+		When("success apply option", func() {
+			It("should set the backoff", func() {
+				err := retry.WithBackoff(time.Millisecond, time.Second, 3).Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.BaseDelay).To(Equal(time.Millisecond))
+				Expect(cfg.MaxDelay).To(Equal(time.Second))
+				Expect(cfg.Multiplier).To(Equal(float64(3)))
+			})
+		})
+	})
+
+	Context("WithJitter option", func() {
+		When("jitter is invalid", func() {
+			It("should return error", func() {
+				err := retry.WithJitter(1.5).Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid jitter")))
+			})
+		})
+
+		When("success apply option", func() {
+			It("should set the jitter", func() {
+				err := retry.WithJitter(0).Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.Jitter).To(Equal(float64(0)))
+			})
+		})
+	})
+
+	Context("WithClassifier option", func() {
+		When("classifier is invalid", func() {
+			It("should return error", func() {
+				err := retry.WithClassifier(nil).Apply(cfg)
+
+				Expect(err).To(Equal(fmt.Errorf("invalid retryable classifier")))
+			})
+		})
+
+		When("success apply option", func() {
+			It("should set the classifier", func() {
+				err := retry.WithClassifier(func(error) bool { return true }).Apply(cfg)
+
+				Expect(err).To(BeNil())
+				Expect(cfg.Retryable(fmt.Errorf("unknown"))).To(BeTrue())
+			})
+		})
+	})
+})
@@ -0,0 +1,225 @@
+package retry
+
+import (
+	"context"
+
+	goseidon "github.com/go-seidon/core"
+)
+
+// an optional call is retried only when an attempt can be replayed as is:
+// reads and listings are, a call consuming its body, creating or removing
+// something runs once. each method below says which kind it is
+
+// UploadStream is never retried, the first attempt has consumed FileData
+func (s *RetryStorage) UploadStream(ctx context.Context, p goseidon.UploadStreamParam) (*goseidon.UploadFileResult, error) {
+	storage, ok := s.Storage.(goseidon.StreamUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("stream upload")
+	}
+	return storage.UploadStream(ctx, p)
+}
+
+// RetrieveStream retry opening the stream, reading it is up to the caller
+func (s *RetryStorage) RetrieveStream(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveStreamResult, error) {
+	storage, ok := s.Storage.(goseidon.StreamRetriever)
+	if !ok {
+		return nil, goseidon.Unsupported("stream retrieve")
+	}
+
+	var res *goseidon.RetrieveStreamResult
+	err := s.do(ctx, func() error {
+		var err error
+		res, err = storage.RetrieveStream(ctx, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *RetryStorage) StatFile(ctx context.Context, p goseidon.StatFileParam) (*goseidon.StatFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Stater)
+	if !ok {
+		return nil, goseidon.Unsupported("stat")
+	}
+
+	var res *goseidon.StatFileResult
+	err := s.do(ctx, func() error {
+		var err error
+		res, err = storage.StatFile(ctx, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *RetryStorage) ListFiles(ctx context.Context, p goseidon.ListFileParam) (*goseidon.ListFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Lister)
+	if !ok {
+		return nil, goseidon.Unsupported("list")
+	}
+
+	var res *goseidon.ListFileResult
+	err := s.do(ctx, func() error {
+		var err error
+		res, err = storage.ListFiles(ctx, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *RetryStorage) SignURL(ctx context.Context, p goseidon.SignURLParam) (*goseidon.SignURLResult, error) {
+	storage, ok := s.Storage.(goseidon.URLSigner)
+	if !ok {
+		return nil, goseidon.Unsupported("sign url")
+	}
+
+	var res *goseidon.SignURLResult
+	err := s.do(ctx, func() error {
+		var err error
+		res, err = storage.SignURL(ctx, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CreateMultipartUpload is never retried, every attempt would start its own upload
+func (s *RetryStorage) CreateMultipartUpload(ctx context.Context, p goseidon.CreateMultipartParam) (*goseidon.CreateMultipartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	return storage.CreateMultipartUpload(ctx, p)
+}
+
+// UploadPart is never retried, the first attempt has consumed PartData
+func (s *RetryStorage) UploadPart(ctx context.Context, p goseidon.UploadPartParam) (*goseidon.UploadPartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	return storage.UploadPart(ctx, p)
+}
+
+// CompleteMultipartUpload is never retried, the upload is gone once an attempt
+// which looks failed has completed it
+func (s *RetryStorage) CompleteMultipartUpload(ctx context.Context, p goseidon.CompleteMultipartParam) (*goseidon.UploadFileResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+	return storage.CompleteMultipartUpload(ctx, p)
+}
+
+func (s *RetryStorage) AbortMultipartUpload(ctx context.Context, p goseidon.AbortMultipartParam) (*goseidon.AbortMultipartResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+
+	var res *goseidon.AbortMultipartResult
+	err := s.do(ctx, func() error {
+		var err error
+		res, err = storage.AbortMultipartUpload(ctx, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *RetryStorage) ListParts(ctx context.Context, p goseidon.ListPartsParam) (*goseidon.ListPartsResult, error) {
+	storage, ok := s.Storage.(goseidon.MultipartUploader)
+	if !ok {
+		return nil, goseidon.Unsupported("multipart upload")
+	}
+
+	var res *goseidon.ListPartsResult
+	err := s.do(ctx, func() error {
+		var err error
+		res, err = storage.ListParts(ctx, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CopyFile is only retried with OverwriteReplace, for the same reason as UploadFile
+func (s *RetryStorage) CopyFile(ctx context.Context, p goseidon.CopyFileParam) (*goseidon.CopyFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Copier)
+	if !ok {
+		return nil, goseidon.Unsupported("copy")
+	}
+	if p.Overwrite != goseidon.OverwriteReplace {
+		return storage.CopyFile(ctx, p)
+	}
+
+	var res *goseidon.CopyFileResult
+	err := s.do(ctx, func() error {
+		var err error
+		res, err = storage.CopyFile(ctx, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// MoveFile is never retried, the source is gone once an attempt which looks failed has moved it
+func (s *RetryStorage) MoveFile(ctx context.Context, p goseidon.MoveFileParam) (*goseidon.MoveFileResult, error) {
+	storage, ok := s.Storage.(goseidon.Mover)
+	if !ok {
+		return nil, goseidon.Unsupported("move")
+	}
+	return storage.MoveFile(ctx, p)
+}
+
+// DeleteFiles is never retried, for the same reason as DeleteFile
+func (s *RetryStorage) DeleteFiles(ctx context.Context, p goseidon.DeleteFilesParam) (*goseidon.DeleteFilesResult, error) {
+	storage, ok := s.Storage.(goseidon.BatchDeleter)
+	if !ok {
+		return nil, goseidon.Unsupported("batch delete")
+	}
+	return storage.DeleteFiles(ctx, p)
+}
+
+// DeletePrefix is never retried, the result gathered by an interrupted call
+// is returned along with its error
+func (s *RetryStorage) DeletePrefix(ctx context.Context, p goseidon.DeletePrefixParam) (*goseidon.DeletePrefixResult, error) {
+	storage, ok := s.Storage.(goseidon.PrefixDeleter)
+	if !ok {
+		return nil, goseidon.Unsupported("prefix delete")
+	}
+	return storage.DeletePrefix(ctx, p)
+}
+
+func (s *RetryStorage) ListVersions(ctx context.Context, p goseidon.ListVersionsParam) (*goseidon.ListVersionsResult, error) {
+	storage, ok := s.Storage.(goseidon.VersionLister)
+	if !ok {
+		return nil, goseidon.Unsupported("list versions")
+	}
+
+	var res *goseidon.ListVersionsResult
+	err := s.do(ctx, func() error {
+		var err error
+		res, err = storage.ListVersions(ctx, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,316 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/pkg/retry"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Optional", func() {
+	var (
+		ctx         context.Context
+		s           *retry.RetryStorage
+		st          *goseidon.MockFullStorage
+		clo         *clock.MockClock
+		currentTime time.Time
+		transient   error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		st = goseidon.NewMockFullStorage(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Now()
+		transient = goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("slow down"))
+		s = &retry.RetryStorage{
+			Config: &retry.RetryConfig{
+				MaxAttempts: 3,
+				BaseDelay:   100 * time.Millisecond,
+				MaxDelay:    150 * time.Millisecond,
+				Multiplier:  2,
+				Retryable:   retry.IsRetryable,
+			},
+			Storage: st,
+			Clock:   clo,
+			Random:  func() float64 { return 0 },
+		}
+	})
+
+	expectWait := func() {
+		clo.EXPECT().
+			After(gomock.Eq(100 * time.Millisecond)).
+			DoAndReturn(func(time.Duration) <-chan time.Time {
+				ch := make(chan time.Time, 1)
+				ch <- currentTime
+				return ch
+			}).
+			Times(1)
+	}
+
+	When("wrapped storage doesn't implement the interface", func() {
+		It("should return error without retrying", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			s.Storage = goseidon.NewMockStorage(ctrl)
+
+			r, err := s.StatFile(ctx, goseidon.StatFileParam{Id: "a.txt"})
+
+			Expect(r).To(BeNil())
+			Expect(errors.Is(err, goseidon.ErrUnsupported)).To(BeTrue())
+			Expect(err.Error()).To(Equal("stat is not supported by the storage"))
+		})
+	})
+
+	Context("UploadStream method", func() {
+		When("failure is transient", func() {
+			It("should not retry", func() {
+				p := goseidon.UploadStreamParam{FileId: "a.txt", FileData: strings.NewReader("a"), Overwrite: goseidon.OverwriteReplace}
+				st.MockStreamUploader.EXPECT().
+					UploadStream(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, transient).
+					Times(1)
+
+				r, err := s.UploadStream(ctx, p)
+
+				Expect(r).To(BeNil())
+				Expect(err).To(Equal(transient))
+			})
+		})
+	})
+
+	Context("RetrieveStream method", func() {
+		When("failure is transient", func() {
+			It("should retry opening the stream", func() {
+				p := goseidon.RetrieveFileParam{Id: "a.txt"}
+				res := &goseidon.RetrieveStreamResult{Size: 1}
+				gomock.InOrder(
+					st.MockStreamRetriever.EXPECT().RetrieveStream(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, transient),
+					st.MockStreamRetriever.EXPECT().RetrieveStream(gomock.Eq(ctx), gomock.Eq(p)).Return(res, nil),
+				)
+				expectWait()
+
+				r, err := s.RetrieveStream(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(r).To(Equal(res))
+			})
+		})
+	})
+
+	Context("StatFile method", func() {
+		When("failure is transient", func() {
+			It("should retry", func() {
+				p := goseidon.StatFileParam{Id: "a.txt"}
+				res := &goseidon.StatFileResult{Id: "a.txt"}
+				gomock.InOrder(
+					st.MockStater.EXPECT().StatFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, transient),
+					st.MockStater.EXPECT().StatFile(gomock.Eq(ctx), gomock.Eq(p)).Return(res, nil),
+				)
+				expectWait()
+
+				r, err := s.StatFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(r).To(Equal(res))
+			})
+		})
+	})
+
+	Context("ListFiles method", func() {
+		When("failure is not retryable", func() {
+			It("should return the error", func() {
+				p := goseidon.ListFileParam{Prefix: "a/"}
+				st.MockLister.EXPECT().
+					ListFiles(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, goseidon.ErrPermission).
+					Times(1)
+
+				r, err := s.ListFiles(ctx, p)
+
+				Expect(r).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrPermission))
+			})
+		})
+	})
+
+	Context("SignURL method", func() {
+		When("failure is transient", func() {
+			It("should retry", func() {
+				p := goseidon.SignURLParam{Id: "a.txt"}
+				res := &goseidon.SignURLResult{URL: "mock-url"}
+				gomock.InOrder(
+					st.MockURLSigner.EXPECT().SignURL(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, transient),
+					st.MockURLSigner.EXPECT().SignURL(gomock.Eq(ctx), gomock.Eq(p)).Return(res, nil),
+				)
+				expectWait()
+
+				r, err := s.SignURL(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(r).To(Equal(res))
+			})
+		})
+	})
+
+	Context("multipart methods", func() {
+		When("creating or sending parts fail", func() {
+			It("should not retry", func() {
+				st.MockMultipartUploader.EXPECT().
+					CreateMultipartUpload(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, transient).
+					Times(1)
+				st.MockMultipartUploader.EXPECT().
+					UploadPart(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, transient).
+					Times(1)
+				st.MockMultipartUploader.EXPECT().
+					CompleteMultipartUpload(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, transient).
+					Times(1)
+
+				_, err := s.CreateMultipartUpload(ctx, goseidon.CreateMultipartParam{FileId: "a.txt"})
+				Expect(err).To(Equal(transient))
+				_, err = s.UploadPart(ctx, goseidon.UploadPartParam{FileId: "a.txt", UploadId: "u1", PartNumber: 1})
+				Expect(err).To(Equal(transient))
+				_, err = s.CompleteMultipartUpload(ctx, goseidon.CompleteMultipartParam{FileId: "a.txt", UploadId: "u1"})
+				Expect(err).To(Equal(transient))
+			})
+		})
+
+		When("aborting or listing parts fail", func() {
+			It("should retry", func() {
+				abort := &goseidon.AbortMultipartResult{UploadId: "u1"}
+				parts := &goseidon.ListPartsResult{Parts: []goseidon.PartItem{}}
+				gomock.InOrder(
+					st.MockMultipartUploader.EXPECT().AbortMultipartUpload(gomock.Eq(ctx), gomock.Any()).Return(nil, transient),
+					st.MockMultipartUploader.EXPECT().AbortMultipartUpload(gomock.Eq(ctx), gomock.Any()).Return(abort, nil),
+					st.MockMultipartUploader.EXPECT().ListParts(gomock.Eq(ctx), gomock.Any()).Return(nil, transient),
+					st.MockMultipartUploader.EXPECT().ListParts(gomock.Eq(ctx), gomock.Any()).Return(parts, nil),
+				)
+				expectWait()
+				expectWait()
+
+				r, err := s.AbortMultipartUpload(ctx, goseidon.AbortMultipartParam{FileId: "a.txt", UploadId: "u1"})
+				Expect(err).To(BeNil())
+				Expect(r).To(Equal(abort))
+				l, err := s.ListParts(ctx, goseidon.ListPartsParam{FileId: "a.txt", UploadId: "u1"})
+				Expect(err).To(BeNil())
+				Expect(l).To(Equal(parts))
+			})
+		})
+	})
+
+	Context("CopyFile method", func() {
+		When("copy replace the destination", func() {
+			It("should retry", func() {
+				p := goseidon.CopyFileParam{SourceId: "a.txt", DestinationId: "b.txt", Overwrite: goseidon.OverwriteReplace}
+				res := &goseidon.CopyFileResult{SourceId: "a.txt", DestinationId: "b.txt"}
+				gomock.InOrder(
+					st.MockCopier.EXPECT().CopyFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, transient),
+					st.MockCopier.EXPECT().CopyFile(gomock.Eq(ctx), gomock.Eq(p)).Return(res, nil),
+				)
+				expectWait()
+
+				r, err := s.CopyFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(r).To(Equal(res))
+			})
+		})
+
+		When("copy doesn't replace the destination", func() {
+			It("should not retry", func() {
+				p := goseidon.CopyFileParam{SourceId: "a.txt", DestinationId: "b.txt"}
+				st.MockCopier.EXPECT().
+					CopyFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, transient).
+					Times(1)
+
+				r, err := s.CopyFile(ctx, p)
+
+				Expect(r).To(BeNil())
+				Expect(err).To(Equal(transient))
+			})
+		})
+	})
+
+	Context("MoveFile method", func() {
+		When("failure is transient", func() {
+			It("should not retry", func() {
+				p := goseidon.MoveFileParam{SourceId: "a.txt", DestinationId: "b.txt", Overwrite: goseidon.OverwriteReplace}
+				st.MockMover.EXPECT().
+					MoveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, transient).
+					Times(1)
+
+				r, err := s.MoveFile(ctx, p)
+
+				Expect(r).To(BeNil())
+				Expect(err).To(Equal(transient))
+			})
+		})
+	})
+
+	Context("DeleteFiles method", func() {
+		When("failure is transient", func() {
+			It("should not retry", func() {
+				p := goseidon.DeleteFilesParam{Ids: []string{"a.txt", "b.txt"}}
+				st.MockBatchDeleter.EXPECT().
+					DeleteFiles(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, transient).
+					Times(1)
+
+				r, err := s.DeleteFiles(ctx, p)
+
+				Expect(r).To(BeNil())
+				Expect(err).To(Equal(transient))
+			})
+		})
+	})
+
+	Context("DeletePrefix method", func() {
+		When("delete is interrupted", func() {
+			It("should return the partial result without retrying", func() {
+				p := goseidon.DeletePrefixParam{Prefix: "a/"}
+				res := &goseidon.DeletePrefixResult{Prefix: "a/", Matched: 2, Deleted: 1}
+				st.MockPrefixDeleter.EXPECT().
+					DeletePrefix(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(res, transient).
+					Times(1)
+
+				r, err := s.DeletePrefix(ctx, p)
+
+				Expect(r).To(Equal(res))
+				Expect(err).To(Equal(transient))
+			})
+		})
+	})
+
+	Context("ListVersions method", func() {
+		When("failure is transient", func() {
+			It("should retry", func() {
+				p := goseidon.ListVersionsParam{Id: "a.txt"}
+				res := &goseidon.ListVersionsResult{Versions: []goseidon.FileVersion{}}
+				gomock.InOrder(
+					st.MockVersionLister.EXPECT().ListVersions(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, transient),
+					st.MockVersionLister.EXPECT().ListVersions(gomock.Eq(ctx), gomock.Eq(p)).Return(res, nil),
+				)
+				expectWait()
+
+				r, err := s.ListVersions(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(r).To(Equal(res))
+			})
+		})
+	})
+})
@@ -0,0 +1,141 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+)
+
+// RetryStorage retry the idempotent calls of the wrapped storage,
+// the error of the last attempt is returned as it is
+type RetryStorage struct {
+	Config  *RetryConfig
+	Storage goseidon.Storage
+	Clock   clock.Clock
+	// Random return a number in [0, 1) used for the jitter
+	Random func() float64
+}
+
+// UploadFile is only retried with OverwriteReplace, with another mode a retry
+// could report the file stored by a failed looking attempt as already taken
+func (s *RetryStorage) UploadFile(ctx context.Context, p goseidon.UploadFileParam) (*goseidon.UploadFileResult, error) {
+	if p.Overwrite != goseidon.OverwriteReplace {
+		return s.Storage.UploadFile(ctx, p)
+	}
+
+	var res *goseidon.UploadFileResult
+	err := s.do(ctx, func() error {
+		var err error
+		res, err = s.Storage.UploadFile(ctx, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *RetryStorage) RetrieveFile(ctx context.Context, p goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+	var res *goseidon.RetrieveFileResult
+	err := s.do(ctx, func() error {
+		var err error
+		res, err = s.Storage.RetrieveFile(ctx, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteFile is never retried, it isn't idempotent: retrying an attempt which looks failed
+// but has deleted the file fails with ErrNotFound, or add another delete marker on a versioned bucket
+func (s *RetryStorage) DeleteFile(ctx context.Context, p goseidon.DeleteFileParam) (*goseidon.DeleteFileResult, error) {
+	return s.Storage.DeleteFile(ctx, p)
+}
+
+// do run op until it succeeds, fails with an error which isn't retryable
+// or runs out of attempts. it gives up early when the wait would outlast ctx
+func (s *RetryStorage) do(ctx context.Context, op func() error) error {
+	// the wrapped storage reports the invalid context
+	if ctx == nil {
+		return op()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= s.Config.MaxAttempts || !s.Config.Retryable(err) {
+			return err
+		}
+
+		delay := s.delay(attempt)
+		deadline, ok := ctx.Deadline()
+		if ok && s.Clock.Now().Add(delay).After(deadline) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.Clock.After(delay):
+		}
+	}
+}
+
+// delay return the wait before the given retry
+func (s *RetryStorage) delay(retry int) time.Duration {
+	delay := float64(s.Config.BaseDelay) * math.Pow(s.Config.Multiplier, float64(retry-1))
+	if delay > float64(s.Config.MaxDelay) {
+		delay = float64(s.Config.MaxDelay)
+	}
+	delay -= delay * s.Config.Jitter * s.Random()
+	return time.Duration(delay)
+}
+
+// IsRetryable report whether err is a transient failure,
+// a done context is never retried
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return errors.Is(err, goseidon.ErrTransient)
+}
+
+func NewRetryStorage(storage goseidon.Storage, opts ...RetryStorageOption) (*RetryStorage, error) {
+	if storage == nil {
+		return nil, fmt.Errorf("invalid storage")
+	}
+
+	cfg := &RetryConfig{
+		MaxAttempts: defaultMaxAttempts,
+		BaseDelay:   defaultBaseDelay,
+		MaxDelay:    defaultMaxDelay,
+		Multiplier:  defaultMultiplier,
+		Jitter:      defaultJitter,
+		Retryable:   IsRetryable,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			return nil, fmt.Errorf("invalid retry option")
+		}
+		err := opt.Apply(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clock, _ := clock.NewClock()
+	s := &RetryStorage{
+		Config:  cfg,
+		Storage: storage,
+		Clock:   clock,
+		Random:  rand.Float64,
+	}
+	return s, nil
+}
@@ -0,0 +1,298 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/go-seidon/core/internal/clock"
+	"github.com/go-seidon/core/pkg/retry"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRetry(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Retry Package")
+}
+
+var _ = Describe("Storage", func() {
+	var (
+		ctx         context.Context
+		s           *retry.RetryStorage
+		st          *goseidon.MockStorage
+		clo         *clock.MockClock
+		currentTime time.Time
+		transient   error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		st = goseidon.NewMockStorage(ctrl)
+		clo = clock.NewMockClock(ctrl)
+		currentTime = time.Now()
+		transient = goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("slow down"))
+		s = &retry.RetryStorage{
+			Config: &retry.RetryConfig{
+				MaxAttempts: 3,
+				BaseDelay:   100 * time.Millisecond,
+				MaxDelay:    150 * time.Millisecond,
+				Multiplier:  2,
+				Jitter:      0.5,
+				Retryable:   retry.IsRetryable,
+			},
+			Storage: st,
+			Clock:   clo,
+			Random:  func() float64 { return 0 },
+		}
+	})
+
+	expectWait := func(delay time.Duration) {
+		clo.EXPECT().
+			After(gomock.Eq(delay)).
+			DoAndReturn(func(time.Duration) <-chan time.Time {
+				ch := make(chan time.Time, 1)
+				ch <- currentTime
+				return ch
+			}).
+			Times(1)
+	}
+
+	Context("NewRetryStorage function", func() {
+		When("storage is invalid", func() {
+			It("should return error", func() {
+				s, err := retry.NewRetryStorage(nil)
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid storage")))
+			})
+		})
+
+		When("option is invalid", func() {
+			It("should return error", func() {
+				s, err := retry.NewRetryStorage(st, nil)
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid retry option")))
+			})
+		})
+
+		When("failed apply option", func() {
+			It("should return error", func() {
+				s, err := retry.NewRetryStorage(st, retry.WithMaxAttempts(0))
+
+				Expect(s).To(BeNil())
+				Expect(err).To(Equal(fmt.Errorf("invalid max attempts")))
+			})
+		})
+
+		When("option is not given", func() {
+			It("should use the default policy", func() {
+				s, err := retry.NewRetryStorage(st)
+
+				Expect(err).To(BeNil())
+				Expect(s.Config.MaxAttempts).To(Equal(3))
+				Expect(s.Config.BaseDelay).To(Equal(100 * time.Millisecond))
+				Expect(s.Config.MaxDelay).To(Equal(5 * time.Second))
+				Expect(s.Config.Multiplier).To(Equal(float64(2)))
+				Expect(s.Config.Jitter).To(Equal(0.2))
+				Expect(s.Config.Retryable(transient)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("RetrieveFile method", func() {
+		p := goseidon.RetrieveFileParam{Id: "a.txt"}
+
+		When("failure is transient", func() {
+			It("should retry with exponential backoff", func() {
+				res := &goseidon.RetrieveFileResult{File: []byte("a")}
+				gomock.InOrder(
+					st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, transient),
+					st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, transient),
+					st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(res, nil),
+				)
+				expectWait(100 * time.Millisecond)
+				expectWait(150 * time.Millisecond)
+
+				r, err := s.RetrieveFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(r).To(Equal(res))
+			})
+		})
+
+		When("attempts run out", func() {
+			It("should return the last error", func() {
+				last := goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("service unavailable"))
+				gomock.InOrder(
+					st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, transient),
+					st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, transient),
+					st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, last),
+				)
+				clo.EXPECT().
+					After(gomock.Any()).
+					DoAndReturn(func(time.Duration) <-chan time.Time {
+						ch := make(chan time.Time, 1)
+						ch <- currentTime
+						return ch
+					}).
+					Times(2)
+
+				r, err := s.RetrieveFile(ctx, p)
+
+				Expect(r).To(BeNil())
+				Expect(err).To(Equal(last))
+			})
+		})
+
+		When("failure is not retryable", func() {
+			It("should return error right away", func() {
+				st.EXPECT().
+					RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, goseidon.ErrNotFound).
+					Times(1)
+
+				r, err := s.RetrieveFile(ctx, p)
+
+				Expect(r).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrNotFound))
+			})
+		})
+
+		When("jitter is drawn", func() {
+			It("should shorten the delay", func() {
+				s.Random = func() float64 { return 0.5 }
+				gomock.InOrder(
+					st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, transient),
+					st.EXPECT().RetrieveFile(gomock.Eq(ctx), gomock.Eq(p)).Return(&goseidon.RetrieveFileResult{}, nil),
+				)
+				expectWait(75 * time.Millisecond)
+
+				_, err := s.RetrieveFile(ctx, p)
+
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("the wait would outlast the deadline", func() {
+			It("should return the last error", func() {
+				dctx, cancel := context.WithDeadline(ctx, currentTime.Add(50*time.Millisecond))
+				defer cancel()
+				st.EXPECT().
+					RetrieveFile(gomock.Eq(dctx), gomock.Eq(p)).
+					Return(nil, transient).
+					Times(1)
+				clo.EXPECT().Now().Return(currentTime).Times(1)
+
+				r, err := s.RetrieveFile(dctx, p)
+
+				Expect(r).To(BeNil())
+				Expect(err).To(Equal(transient))
+			})
+		})
+
+		When("context is done while waiting", func() {
+			It("should return context error", func() {
+				cctx, cancel := context.WithCancel(ctx)
+				st.EXPECT().
+					RetrieveFile(gomock.Eq(cctx), gomock.Eq(p)).
+					DoAndReturn(func(context.Context, goseidon.RetrieveFileParam) (*goseidon.RetrieveFileResult, error) {
+						cancel()
+						return nil, transient
+					}).
+					Times(1)
+				clo.EXPECT().
+					After(gomock.Any()).
+					Return(make(chan time.Time)).
+					Times(1)
+
+				r, err := s.RetrieveFile(cctx, p)
+
+				Expect(r).To(BeNil())
+				Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			})
+		})
+
+		When("context is invalid", func() {
+			It("should leave it to the storage", func() {
+				st.EXPECT().
+					RetrieveFile(nil, gomock.Eq(p)).
+					Return(nil, goseidon.ErrInvalidArgument).
+					Times(1)
+
+				r, err := s.RetrieveFile(nil, p)
+
+				Expect(r).To(BeNil())
+				Expect(err).To(Equal(goseidon.ErrInvalidArgument))
+			})
+		})
+	})
+
+	Context("UploadFile method", func() {
+		When("upload replace the file", func() {
+			It("should retry", func() {
+				p := goseidon.UploadFileParam{FileId: "a.txt", Overwrite: goseidon.OverwriteReplace}
+				res := &goseidon.UploadFileResult{FileId: "a.txt"}
+				gomock.InOrder(
+					st.EXPECT().UploadFile(gomock.Eq(ctx), gomock.Eq(p)).Return(nil, transient),
+					st.EXPECT().UploadFile(gomock.Eq(ctx), gomock.Eq(p)).Return(res, nil),
+				)
+				expectWait(100 * time.Millisecond)
+
+				r, err := s.UploadFile(ctx, p)
+
+				Expect(err).To(BeNil())
+				Expect(r).To(Equal(res))
+			})
+		})
+
+		When("upload doesn't replace the file", func() {
+			It("should not retry", func() {
+				p := goseidon.UploadFileParam{FileId: "a.txt"}
+				st.EXPECT().
+					UploadFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, transient).
+					Times(1)
+
+				r, err := s.UploadFile(ctx, p)
+
+				Expect(r).To(BeNil())
+				Expect(err).To(Equal(transient))
+			})
+		})
+	})
+
+	Context("DeleteFile method", func() {
+		When("failure is transient", func() {
+			It("should not retry", func() {
+				p := goseidon.DeleteFileParam{Id: "a.txt"}
+				st.EXPECT().
+					DeleteFile(gomock.Eq(ctx), gomock.Eq(p)).
+					Return(nil, transient).
+					Times(1)
+
+				r, err := s.DeleteFile(ctx, p)
+
+				Expect(r).To(BeNil())
+				Expect(err).To(Equal(transient))
+			})
+		})
+	})
+
+	DescribeTable("IsRetryable function",
+		func(err error, retryable bool) {
+			Expect(retry.IsRetryable(err)).To(Equal(retryable))
+		},
+		Entry("transient failure", goseidon.NewError(goseidon.ErrTransient, fmt.Errorf("slow down")), true),
+		Entry("not found", goseidon.ErrNotFound, false),
+		Entry("unclassified", fmt.Errorf("unknown"), false),
+		Entry("cancelled", goseidon.NewError(goseidon.ErrTransient, context.Canceled), false),
+		Entry("deadline exceeded", context.DeadlineExceeded, false),
+	)
+})
@@ -0,0 +1,77 @@
+package goseidon
+
+import (
+	"context"
+	"fmt"
+)
+
+// ValidatePrefix check the prefix of a prefix delete,
+// an empty prefix would match every stored file
+func ValidatePrefix(prefix string) error {
+	if prefix == "" {
+		return NewError(ErrInvalidArgument, fmt.Errorf("prefix is empty"))
+	}
+	return nil
+}
+
+// DeleteByPrefix page through the files under the prefix and delete each page as a batch,
+// a failed file is reported in the result and never retried within the same call.
+// an error met midway is returned along with the result gathered so far
+func DeleteByPrefix(ctx context.Context, s interface {
+	Lister
+	BatchDeleter
+}, p DeletePrefixParam) (*DeletePrefixResult, error) {
+	if ctx == nil {
+		return nil, NewError(ErrInvalidArgument, fmt.Errorf("invalid context"))
+	}
+	err := ValidatePrefix(p.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &DeletePrefixResult{
+		Prefix: p.Prefix,
+		Failed: []DeleteFileItem{},
+		DryRun: p.DryRun,
+	}
+	token := ""
+	for {
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+
+		page, err := s.ListFiles(ctx, ListFileParam{
+			Prefix:            p.Prefix,
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return res, err
+		}
+		res.Matched += len(page.Files)
+
+		if !p.DryRun && len(page.Files) > 0 {
+			ids := []string{}
+			for _, file := range page.Files {
+				ids = append(ids, file.Id)
+			}
+			deleted, err := s.DeleteFiles(ctx, DeleteFilesParam{
+				Ids: ids,
+			})
+			if err != nil {
+				return res, err
+			}
+			for _, item := range deleted.Items {
+				if item.Error != nil {
+					res.Failed = append(res.Failed, item)
+					continue
+				}
+				res.Deleted++
+			}
+		}
+
+		token = page.NextContinuationToken
+		if token == "" {
+			return res, nil
+		}
+	}
+}
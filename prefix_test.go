@@ -0,0 +1,230 @@
+package goseidon_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	goseidon "github.com/go-seidon/core"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type prefixStorage struct {
+	*goseidon.MockLister
+	*goseidon.MockBatchDeleter
+}
+
+var _ = Describe("Prefix", func() {
+	var (
+		ctx context.Context
+		s   *prefixStorage
+		p   goseidon.DeletePrefixParam
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		s = &prefixStorage{
+			MockLister:       goseidon.NewMockLister(ctrl),
+			MockBatchDeleter: goseidon.NewMockBatchDeleter(ctrl),
+		}
+		p = goseidon.DeletePrefixParam{
+			Prefix: "tenant-42/",
+		}
+	})
+
+	Context("DeleteByPrefix function", func() {
+		When("context is invalid", func() {
+			It("should return error", func() {
+				res, err := goseidon.DeleteByPrefix(nil, s, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("invalid context"))
+			})
+		})
+
+		When("prefix is empty", func() {
+			It("should return error", func() {
+				p.Prefix = ""
+				res, err := goseidon.DeleteByPrefix(ctx, s, p)
+
+				Expect(res).To(BeNil())
+				Expect(err.Error()).To(Equal("prefix is empty"))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			})
+		})
+
+		When("failed list files", func() {
+			It("should return error", func() {
+				s.MockLister.EXPECT().
+					ListFiles(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := goseidon.DeleteByPrefix(ctx, s, p)
+
+				eRes := &goseidon.DeletePrefixResult{
+					Prefix: p.Prefix,
+					Failed: []goseidon.DeleteFileItem{},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+
+		When("dry run", func() {
+			It("should count without deleting", func() {
+				p.DryRun = true
+				s.MockLister.EXPECT().
+					ListFiles(gomock.Eq(ctx), gomock.Eq(goseidon.ListFileParam{Prefix: p.Prefix})).
+					Return(&goseidon.ListFileResult{
+						Files:                 []goseidon.ListFileItem{{Id: "tenant-42/a"}, {Id: "tenant-42/b"}},
+						NextContinuationToken: "tenant-42/b",
+					}, nil).
+					Times(1)
+				s.MockLister.EXPECT().
+					ListFiles(gomock.Eq(ctx), gomock.Eq(goseidon.ListFileParam{Prefix: p.Prefix, ContinuationToken: "tenant-42/b"})).
+					Return(&goseidon.ListFileResult{
+						Files: []goseidon.ListFileItem{{Id: "tenant-42/c"}},
+					}, nil).
+					Times(1)
+
+				res, err := goseidon.DeleteByPrefix(ctx, s, p)
+
+				eRes := &goseidon.DeletePrefixResult{
+					Prefix:  p.Prefix,
+					Matched: 3,
+					Failed:  []goseidon.DeleteFileItem{},
+					DryRun:  true,
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("success delete prefix", func() {
+			It("should delete every page and report failures", func() {
+				s.MockLister.EXPECT().
+					ListFiles(gomock.Eq(ctx), gomock.Eq(goseidon.ListFileParam{Prefix: p.Prefix})).
+					Return(&goseidon.ListFileResult{
+						Files:                 []goseidon.ListFileItem{{Id: "tenant-42/a"}, {Id: "tenant-42/b"}},
+						NextContinuationToken: "tenant-42/b",
+					}, nil).
+					Times(1)
+				s.MockBatchDeleter.EXPECT().
+					DeleteFiles(gomock.Eq(ctx), gomock.Eq(goseidon.DeleteFilesParam{Ids: []string{"tenant-42/a", "tenant-42/b"}})).
+					Return(&goseidon.DeleteFilesResult{
+						Items: []goseidon.DeleteFileItem{
+							{Id: "tenant-42/a"},
+							{Id: "tenant-42/b", Error: goseidon.ErrPermission},
+						},
+					}, nil).
+					Times(1)
+				s.MockLister.EXPECT().
+					ListFiles(gomock.Eq(ctx), gomock.Eq(goseidon.ListFileParam{Prefix: p.Prefix, ContinuationToken: "tenant-42/b"})).
+					Return(&goseidon.ListFileResult{
+						Files: []goseidon.ListFileItem{{Id: "tenant-42/c"}},
+					}, nil).
+					Times(1)
+				s.MockBatchDeleter.EXPECT().
+					DeleteFiles(gomock.Eq(ctx), gomock.Eq(goseidon.DeleteFilesParam{Ids: []string{"tenant-42/c"}})).
+					Return(&goseidon.DeleteFilesResult{
+						Items: []goseidon.DeleteFileItem{{Id: "tenant-42/c"}},
+					}, nil).
+					Times(1)
+
+				res, err := goseidon.DeleteByPrefix(ctx, s, p)
+
+				eRes := &goseidon.DeletePrefixResult{
+					Prefix:  p.Prefix,
+					Matched: 3,
+					Deleted: 2,
+					Failed: []goseidon.DeleteFileItem{
+						{Id: "tenant-42/b", Error: goseidon.ErrPermission},
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("context is cancelled between pages", func() {
+			It("should return the context error", func() {
+				cctx, cancel := context.WithCancel(ctx)
+				s.MockLister.EXPECT().
+					ListFiles(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, p goseidon.ListFileParam) (*goseidon.ListFileResult, error) {
+						cancel()
+						return &goseidon.ListFileResult{NextContinuationToken: "next"}, nil
+					}).
+					Times(1)
+
+				res, err := goseidon.DeleteByPrefix(cctx, s, p)
+
+				Expect(res.Matched).To(Equal(0))
+				Expect(err).To(Equal(context.Canceled))
+			})
+		})
+
+		When("context is cancelled after a deleted page", func() {
+			It("should return what is deleted so far", func() {
+				cctx, cancel := context.WithCancel(ctx)
+				s.MockLister.EXPECT().
+					ListFiles(gomock.Eq(cctx), gomock.Eq(goseidon.ListFileParam{Prefix: p.Prefix})).
+					Return(&goseidon.ListFileResult{
+						Files:                 []goseidon.ListFileItem{{Id: "tenant-42/a"}, {Id: "tenant-42/b"}},
+						NextContinuationToken: "tenant-42/b",
+					}, nil).
+					Times(1)
+				s.MockBatchDeleter.EXPECT().
+					DeleteFiles(gomock.Eq(cctx), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, p goseidon.DeleteFilesParam) (*goseidon.DeleteFilesResult, error) {
+						cancel()
+						return &goseidon.DeleteFilesResult{
+							Items: []goseidon.DeleteFileItem{
+								{Id: "tenant-42/a"},
+								{Id: "tenant-42/b", Error: context.Canceled},
+							},
+						}, nil
+					}).
+					Times(1)
+
+				res, err := goseidon.DeleteByPrefix(cctx, s, p)
+
+				eRes := &goseidon.DeletePrefixResult{
+					Prefix:  p.Prefix,
+					Matched: 2,
+					Deleted: 1,
+					Failed: []goseidon.DeleteFileItem{
+						{Id: "tenant-42/b", Error: context.Canceled},
+					},
+				}
+				Expect(res).To(Equal(eRes))
+				Expect(err).To(Equal(context.Canceled))
+			})
+		})
+
+		When("failed delete a page", func() {
+			It("should return the result of the previous pages", func() {
+				s.MockLister.EXPECT().
+					ListFiles(gomock.Eq(ctx), gomock.Eq(goseidon.ListFileParam{Prefix: p.Prefix})).
+					Return(&goseidon.ListFileResult{
+						Files: []goseidon.ListFileItem{{Id: "tenant-42/a"}},
+					}, nil).
+					Times(1)
+				s.MockBatchDeleter.EXPECT().
+					DeleteFiles(gomock.Eq(ctx), gomock.Any()).
+					Return(nil, fmt.Errorf("network error")).
+					Times(1)
+
+				res, err := goseidon.DeleteByPrefix(ctx, s, p)
+
+				Expect(res.Matched).To(Equal(1))
+				Expect(res.Deleted).To(Equal(0))
+				Expect(err).To(Equal(fmt.Errorf("network error")))
+			})
+		})
+	})
+})
@@ -0,0 +1,34 @@
+package goseidon
+
+import (
+	"fmt"
+)
+
+// ValidateRange check the range of a retrieve param before it's sent to the provider
+func ValidateRange(offset, length int64) error {
+	if offset < 0 {
+		return NewError(ErrInvalidArgument, fmt.Errorf("range offset must not be negative"))
+	}
+	if length < 0 {
+		return NewError(ErrInvalidArgument, fmt.Errorf("range length must not be negative"))
+	}
+	return nil
+}
+
+// ResolveRange return the length of the range within a file of the given size,
+// the range is clamped to the end of the file and must start inside it
+func ResolveRange(offset, length, size int64) (int64, error) {
+	err := ValidateRange(offset, length)
+	if err != nil {
+		return 0, err
+	}
+	if offset > 0 && offset >= size {
+		return 0, NewError(ErrInvalidArgument, fmt.Errorf("range is not satisfiable"))
+	}
+
+	rest := size - offset
+	if length == 0 || length > rest {
+		return rest, nil
+	}
+	return length, nil
+}
@@ -0,0 +1,41 @@
+package goseidon_test
+
+import (
+	"errors"
+
+	goseidon "github.com/go-seidon/core"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Range", func() {
+	Context("ResolveRange function", func() {
+		DescribeTable("range is valid",
+			func(offset, length, size, expected int64) {
+				res, err := goseidon.ResolveRange(offset, length, size)
+
+				Expect(err).To(BeNil())
+				Expect(res).To(Equal(expected))
+			},
+			Entry("whole file", int64(0), int64(0), int64(10), int64(10)),
+			Entry("empty file", int64(0), int64(0), int64(0), int64(0)),
+			Entry("until the end", int64(4), int64(0), int64(10), int64(6)),
+			Entry("inner range", int64(4), int64(3), int64(10), int64(3)),
+			Entry("clamped range", int64(4), int64(100), int64(10), int64(6)),
+			Entry("last byte", int64(9), int64(1), int64(10), int64(1)),
+		)
+
+		DescribeTable("range is invalid",
+			func(offset, length, size int64, msg string) {
+				_, err := goseidon.ResolveRange(offset, length, size)
+
+				Expect(err.Error()).To(Equal(msg))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			},
+			Entry("negative offset", int64(-1), int64(0), int64(10), "range offset must not be negative"),
+			Entry("negative length", int64(0), int64(-1), int64(10), "range length must not be negative"),
+			Entry("offset at the end", int64(10), int64(0), int64(10), "range is not satisfiable"),
+			Entry("offset past the end", int64(11), int64(1), int64(10), "range is not satisfiable"),
+		)
+	})
+})
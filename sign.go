@@ -0,0 +1,35 @@
+package goseidon
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// MaxSignURLExpiry is the longest lifetime of a signed url,
+// it's the presign limit of s3 and google cloud storage
+const MaxSignURLExpiry = 7 * 24 * time.Hour
+
+// ValidateSignURL check a sign url param before it's handed to the storage provider
+func ValidateSignURL(p SignURLParam) error {
+	err := ValidateKey(p.Id)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case p.Method != http.MethodGet && p.Method != http.MethodPut:
+		return invalidSignURL("unsupported sign url method %q", p.Method)
+	case p.Expiry <= 0:
+		return invalidSignURL("sign url expiry must be positive")
+	case p.Expiry > MaxSignURLExpiry:
+		return invalidSignURL("sign url expiry is longer than %s", MaxSignURLExpiry)
+	case p.ContentType != "" && p.Method != http.MethodPut:
+		return invalidSignURL("content type is only allowed for PUT")
+	}
+	return nil
+}
+
+func invalidSignURL(format string, a ...interface{}) error {
+	return NewError(ErrInvalidArgument, fmt.Errorf(format, a...))
+}
@@ -0,0 +1,41 @@
+package goseidon_test
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	goseidon "github.com/go-seidon/core"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sign", func() {
+	Context("ValidateSignURL function", func() {
+		DescribeTable("param is valid",
+			func(p goseidon.SignURLParam) {
+				err := goseidon.ValidateSignURL(p)
+
+				Expect(err).To(BeNil())
+			},
+			Entry("get", goseidon.SignURLParam{Id: "image.jpg", Method: http.MethodGet, Expiry: time.Minute}),
+			Entry("put", goseidon.SignURLParam{Id: "image.jpg", Method: http.MethodPut, Expiry: time.Minute}),
+			Entry("put with content type", goseidon.SignURLParam{Id: "image.jpg", Method: http.MethodPut, Expiry: time.Minute, ContentType: "image/jpeg"}),
+			Entry("longest expiry", goseidon.SignURLParam{Id: "image.jpg", Method: http.MethodGet, Expiry: goseidon.MaxSignURLExpiry}),
+		)
+
+		DescribeTable("param is invalid",
+			func(p goseidon.SignURLParam, msg string) {
+				err := goseidon.ValidateSignURL(p)
+
+				Expect(err.Error()).To(Equal(msg))
+				Expect(errors.Is(err, goseidon.ErrInvalidArgument)).To(BeTrue())
+			},
+			Entry("invalid id", goseidon.SignURLParam{Id: "../image.jpg", Method: http.MethodGet, Expiry: time.Minute}, `file id contains ".." segment`),
+			Entry("unsupported method", goseidon.SignURLParam{Id: "image.jpg", Method: http.MethodDelete, Expiry: time.Minute}, `unsupported sign url method "DELETE"`),
+			Entry("zero expiry", goseidon.SignURLParam{Id: "image.jpg", Method: http.MethodGet}, "sign url expiry must be positive"),
+			Entry("too long expiry", goseidon.SignURLParam{Id: "image.jpg", Method: http.MethodGet, Expiry: goseidon.MaxSignURLExpiry + time.Second}, "sign url expiry is longer than 168h0m0s"),
+			Entry("content type on get", goseidon.SignURLParam{Id: "image.jpg", Method: http.MethodGet, Expiry: time.Minute, ContentType: "image/jpeg"}, "content type is only allowed for PUT"),
+		)
+	})
+})